@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/utils"
+)
+
+// throttleSettings extracts the live-tunable settings from cfg.
+func throttleSettings(cfg *config.Config) utils.ThrottleSettings {
+	return utils.ThrottleSettings{
+		RateLimitMs:    cfg.RateLimitMs,
+		MaxConcurrency: cfg.MaxConcurrency,
+		SectionFilter:  cfg.SectionFilter,
+	}
+}
+
+// reloadOnSIGHUP re-reads .env on every SIGHUP and applies the new rate
+// limit, concurrency and section filter to the running scrape.
+func reloadOnSIGHUP(ctx context.Context, logger *utils.Logger, throttle *utils.Throttle) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		}
+		cfg, err := config.Reload()
+		if err != nil {
+			logger.Error("[admin] SIGHUP reload failed: %v", err)
+			continue
+		}
+		applyThrottle(logger, throttle, throttleSettings(cfg), "SIGHUP")
+	}
+}
+
+func applyThrottle(logger *utils.Logger, throttle *utils.Throttle, s utils.ThrottleSettings, source string) error {
+	if err := throttle.Update(s); err != nil {
+		logger.Error("[admin] %s: %v — keeping current settings", source, err)
+		return err
+	}
+	logger.Info("[admin] %s: rate limit %dms | concurrency %d | section filter %q",
+		source, s.RateLimitMs, s.MaxConcurrency, s.SectionFilter)
+	return nil
+}
+
+// serveAdmin exposes GET/POST /admin/throttle and GET /admin/status on addr
+// until ctx ends. POST takes a partial JSON object; omitted fields keep their
+// current value. When token is set, requests must send
+// "Authorization: Bearer <token>".
+func serveAdmin(ctx context.Context, logger *utils.Logger, addr, token string, throttle *utils.Throttle, status *utils.StatusBoard) {
+	authorized := func(w http.ResponseWriter, r *http.Request) bool {
+		if token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return false
+		}
+		return true
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin/status", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(w, r) {
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, status.Snapshot())
+	})
+	mux.HandleFunc("/admin/throttle", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(w, r) {
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			s := throttle.Settings()
+			if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+				http.Error(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := applyThrottle(logger, throttle, s, "admin API"); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, throttle.Settings())
+	})
+
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	logger.Info("[admin] Listening on %s", addr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Error("[admin] %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/scraper/airbnb"
+	"airbnb-scraper/services"
+	"airbnb-scraper/utils"
+)
+
+// verifyCanaries scrapes the configured canary listings before the main run.
+// Any failure aborts the run: broken extraction would otherwise overwrite the
+// listings table with a garbage dataset.
+func verifyCanaries(cfg *config.Config, logger *utils.Logger, configure func(*airbnb.Scraper)) error {
+	if len(cfg.CanaryURLs) == 0 {
+		return nil
+	}
+	logger.Info("[canary] Verifying %d canary listings…", len(cfg.CanaryURLs))
+
+	sc := airbnb.New(cfg, logger)
+	configure(sc)
+	canaries := sc.FetchCanaries(cfg.CanaryURLs)
+
+	checker := services.NewCanaryChecker(cfg.CanaryMinPrice, cfg.CanaryMaxPrice, logger)
+	failures := checker.Check(canaries)
+	if len(failures) == 0 {
+		logger.Info("[canary] All %d canaries passed", len(canaries))
+		return nil
+	}
+
+	sep := strings.Repeat("═", 55)
+	logger.Error("[canary] %s", sep)
+	logger.Error("[canary] ALERT: %d/%d canaries failed — aborting run", len(failures), len(canaries))
+	for _, f := range failures {
+		logger.Error("[canary]   %s", f)
+	}
+	logger.Error("[canary] Airbnb markup has likely changed; check the extractors.")
+	logger.Error("[canary] %s", sep)
+	return fmt.Errorf("%d of %d canaries failed", len(failures), len(canaries))
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/scraper/airbnb"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdBackfill replays archived room pages through the current detail-page
+// extractor and the Cleaner, then fills coordinates, descriptions and
+// locations of the stored listings from the result. The recovered values
+// are kept across runs and fill the same listings' empty columns after
+// every later scrape. Archives default to every .warc/.warc.gz file in the
+// WARC_OUTPUT_PATH directory, so all runs are replayed; pass files as
+// arguments to replay only those.
+func cmdBackfill(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	overwrite := fs.Bool("overwrite", false, "replace stored values too, not just empty ones")
+	dryRun := fs.Bool("dry-run", false, "re-extract and report, without updating the database")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	paths := fs.Args()
+	if len(paths) == 0 && cfg.WARCOutputPath != "" {
+		dir := filepath.Dir(cfg.WARCOutputPath)
+		archives, err := storage.ListArchives(dir)
+		if err != nil {
+			return err
+		}
+		if len(archives) == 0 {
+			return fmt.Errorf("no archives in %s", dir)
+		}
+		// Newest run first: the Cleaner keeps a room's first capture.
+		slices.Reverse(archives)
+		paths = archives
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no archives given and WARC_OUTPUT_PATH is not set")
+	}
+
+	var pages []*models.PageCapture
+	for _, p := range paths {
+		captures, err := storage.ReadWARC(p)
+		if err != nil {
+			return err
+		}
+		logger.Info("[backfill] %s: %d captured responses", p, len(captures))
+		pages = append(pages, captures...)
+	}
+
+	raw, err := airbnb.New(cfg, logger).ReplayDetailPages(pages)
+	if err != nil {
+		return err
+	}
+	live := raw[:0]
+	for _, r := range raw {
+		if r.Status != models.ListingStatusRemoved {
+			live = append(live, r)
+		}
+	}
+	logger.Info("[backfill] Re-extracted %d room pages (%d showed the listing as removed)", len(live), len(raw)-len(live))
+	if len(live) == 0 {
+		return nil
+	}
+
+	listings := services.NewCleaner(logger).Clean(live)
+	var withCoords int
+	for _, l := range listings {
+		if l.Latitude != 0 {
+			withCoords++
+		}
+	}
+	logger.Info("[backfill] %d listings carry coordinates", withCoords)
+	if *dryRun {
+		return nil
+	}
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+
+	matched, err := pg.Backfill(listings, *overwrite)
+	if err != nil {
+		return err
+	}
+	logger.Info("[backfill] Updated %d stored listings (%d archived rooms are no longer stored)", matched, int64(len(listings))-matched)
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/services"
+	"airbnb-scraper/utils"
+)
+
+// cmdBench times Clean and Generate over synthetic datasets and reports
+// time, allocations and throughput per size, so a performance change can be
+// compared before and after on the same machine. Component logs are
+// silenced; neither the browser nor the database is used. The same
+// measurements are available as Go benchmarks:
+//
+//	go test ./services -run '^$' -bench 'Clean|Generate' -benchmem
+func cmdBench(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	sizes := fs.String("sizes", "10000,100000", "comma-separated dataset sizes")
+	runs := fs.Int("runs", 3, "timed runs per measurement (the average is reported)")
+	workers := fs.Int("workers", cfg.CleanWorkers, "cleaner workers (0 = one per CPU)")
+	seed := fs.Int64("seed", 1, "seed for the synthetic data")
+	cluster := fs.Bool("cluster", false, "include DBSCAN clustering (CLUSTER_EPS_KM) in Generate; quadratic in size")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var ns []int
+	for _, s := range strings.Split(*sizes, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n < 1 {
+			return fmt.Errorf("--sizes: invalid size %q", s)
+		}
+		ns = append(ns, n)
+	}
+	if *runs < 1 {
+		return fmt.Errorf("--runs must be at least 1")
+	}
+
+	quiet := utils.NewLoggerTo(io.Discard)
+	cleaner := services.NewCleaner(quiet)
+	cleaner.SetWorkers(*workers)
+	insightSvc := services.NewInsightService(quiet)
+	if *cluster {
+		insightSvc.SetClustering(cfg.ClusterEpsKm, cfg.ClusterMinPoints)
+	}
+
+	logger.Info("[bench] %d runs per measurement, %d CPUs, cleaner workers %d, clustering %v",
+		*runs, runtime.NumCPU(), *workers, *cluster)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "stage\tlistings\ttime/op\tallocs/op\tMB/op\tlistings/s\t")
+	for _, n := range ns {
+		raw := services.SyntheticListings(n, *seed)
+
+		var cleaned []*models.Listing
+		clean := measure(*runs, func() { cleaned = cleaner.Clean(raw) })
+		clean.print(tw, "clean", n)
+
+		generate := measure(*runs, func() { insightSvc.Generate(cleaned) })
+		generate.print(tw, "generate", len(cleaned))
+	}
+	return tw.Flush()
+}
+
+// benchResult is the average cost of one run of a measured function.
+type benchResult struct {
+	perOp  time.Duration
+	allocs uint64
+	bytes  uint64
+}
+
+// measure runs fn runs times after a garbage collection and averages wall
+// time, heap allocations and allocated bytes.
+func measure(runs int, fn func()) benchResult {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for i := 0; i < runs; i++ {
+		fn()
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	return benchResult{
+		perOp:  elapsed / time.Duration(runs),
+		allocs: (after.Mallocs - before.Mallocs) / uint64(runs),
+		bytes:  (after.TotalAlloc - before.TotalAlloc) / uint64(runs),
+	}
+}
+
+func (r benchResult) print(w io.Writer, stage string, n int) {
+	fmt.Fprintf(w, "%s\t%d\t%v\t%d\t%.1f\t%.0f\t\n", stage, n, r.perOp.Round(time.Microsecond),
+		r.allocs, float64(r.bytes)/(1<<20), float64(n)/r.perOp.Seconds())
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdBudget lists stored listings matching a nightly budget and minimum
+// rating, ranked by value score, and exports the shortlist to CSV.
+func cmdBudget(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("budget", flag.ContinueOnError)
+	maxPrice := fs.Float64("max-price", 0, "maximum nightly price (required)")
+	minRating := fs.Float64("min-rating", 0, "minimum rating (0-5)")
+	out := fs.String("out", cfg.ProjectPath("./output/budget_shortlist.csv"), "CSV export path (empty to skip)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *maxPrice <= 0 {
+		return fmt.Errorf("--max-price must be greater than 0")
+	}
+	money, err := moneyFormat(cfg)
+	if err != nil {
+		return err
+	}
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+
+	listings, err := pg.FetchAll()
+	if err != nil {
+		return err
+	}
+
+	insightSvc := services.NewInsightService(logger)
+	insightSvc.SetMoney(money)
+	entries := insightSvc.Shortlist(listings, *maxPrice, *minRating)
+	insightSvc.PrintShortlist(entries)
+
+	if *out != "" {
+		format, err := csvFormat(cfg)
+		if err != nil {
+			return err
+		}
+		if err := storage.WriteShortlistCSV(*out, entries, format, cfg.ShortlistFields); err != nil {
+			return err
+		}
+		logger.Info("Shortlist saved to %s", *out)
+	}
+	return nil
+}
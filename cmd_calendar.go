@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"path/filepath"
+	"time"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// defaultICalDir is where `calendar` writes without --out or WATCH_ICAL_DIR.
+const defaultICalDir = "./output/calendars"
+
+// cmdCalendar writes one iCal file per watched listing with calendar data,
+// from today on: all-day "Blocked" and "Available" events read from the
+// room pages by `watch`, ready to subscribe to or import in a calendar app.
+//
+//	calendar --out ./output/calendars
+func cmdCalendar(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("calendar", flag.ContinueOnError)
+	dir := cfg.WatchICalDir
+	if dir == "" {
+		dir = cfg.ProjectPath(defaultICalDir)
+	}
+	out := fs.String("out", dir, "directory for the .ics files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+	if err := pg.EnsureWatchTable(); err != nil {
+		return err
+	}
+
+	n, err := writeCalendars(pg, *out, logger)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		logger.Warn("[calendar] No availability data yet — run `watch` first")
+	}
+	return nil
+}
+
+// writeCalendars writes <room id>.ics into dir for every watched listing
+// with stored calendar days from today on, and returns how many it wrote.
+func writeCalendars(pg *storage.PostgresWriter, dir string, logger *utils.Logger) (int, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	calendars, err := pg.WatchCalendars(today)
+	if err != nil {
+		return 0, err
+	}
+	states, err := pg.LatestWatchStates()
+	if err != nil {
+		return 0, err
+	}
+
+	for url, days := range calendars {
+		title := url
+		if s := states[url]; s != nil && s.Title != "" {
+			title = s.Title
+		}
+		path := filepath.Join(dir, utils.ListingID(url)+".ics")
+		if err := storage.WriteICal(path, title, url, days, now); err != nil {
+			return 0, err
+		}
+	}
+	if len(calendars) > 0 {
+		logger.Info("[calendar] Wrote %d availability calendars to %s", len(calendars), dir)
+	}
+	return len(calendars), nil
+}
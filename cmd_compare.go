@@ -0,0 +1,249 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/scraper/airbnb"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// compareColumnWidth is the width of one listing column of `compare`.
+const compareColumnWidth = 28
+
+// compared is one column of the comparison table; details is nil for
+// listings loaded with --stored.
+type compared struct {
+	listing *models.Listing
+	details *models.ListingDetails
+}
+
+// cmdCompare scrapes the given listings' room pages and prints them side by
+// side: price, fees, rating, capacity and amenities. Listings are room URLs,
+// room IDs or stored short IDs; --stored reads them from the database
+// instead, without the detail-page facts.
+//
+//	compare https://www.airbnb.com/rooms/7100001 7100002 7K2M-Q9XD
+func cmdCompare(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	stored := fs.Bool("stored", false, "compare stored listings instead of scraping them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return fmt.Errorf("usage: compare [--stored] URL|ROOM_ID|SHORT_ID URL|ROOM_ID|SHORT_ID...")
+	}
+	money, err := moneyFormat(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Short IDs, and every argument under --stored, need the database.
+	var saved []*models.Listing
+	if *stored || needsLookup(fs.Args()) {
+		pg, err := storage.OpenPostgres(cfg.DSN())
+		if err != nil {
+			return err
+		}
+		saved, err = pg.FetchAll()
+		pg.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	var columns []compared
+	if *stored {
+		for _, arg := range fs.Args() {
+			l := findStored(saved, arg)
+			if l == nil {
+				return fmt.Errorf("%q is not a stored listing", arg)
+			}
+			columns = append(columns, compared{listing: l})
+		}
+	} else {
+		urls := make([]string, 0, fs.NArg())
+		for _, arg := range fs.Args() {
+			url, err := roomURL(cfg, saved, arg)
+			if err != nil {
+				return err
+			}
+			urls = append(urls, url)
+		}
+		logger.Info("[compare] Loading %d room pages…", len(urls))
+		cleaner := services.NewCleaner(logger)
+		for _, c := range airbnb.New(cfg, logger).FetchComparison(urls) {
+			if c.Err != nil {
+				return fmt.Errorf("compare: %s: %w", c.URL, c.Err)
+			}
+			cleaned := cleaner.Clean([]*models.RawListing{c.Listing})
+			if len(cleaned) == 0 {
+				return fmt.Errorf("compare: %s: listing did not pass cleaning", c.URL)
+			}
+			columns = append(columns, compared{listing: cleaned[0], details: c.Details})
+		}
+	}
+
+	printComparison(columns, money)
+	return nil
+}
+
+// needsLookup reports whether any argument is neither a room URL nor a
+// room ID, i.e. a short ID to resolve through the database.
+func needsLookup(args []string) bool {
+	for _, arg := range args {
+		if utils.ListingID(arg) == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// roomURL turns a room URL, room ID or stored short ID into a room URL.
+func roomURL(cfg *config.Config, saved []*models.Listing, arg string) (string, error) {
+	if strings.Contains(arg, "/rooms/") {
+		return arg, nil
+	}
+	if id := utils.ListingID(arg); id != "" {
+		return roomURLFor(cfg, id), nil
+	}
+	if l := findByShortID(saved, arg); l != nil {
+		return l.URL, nil
+	}
+	return "", fmt.Errorf("%q is neither a room URL, a room ID nor a stored short ID", arg)
+}
+
+// roomURLFor is the room page of a room ID under AIRBNB_BASE_URL.
+func roomURLFor(cfg *config.Config, id string) string {
+	return strings.TrimRight(cfg.BaseURL, "/") + "/rooms/" + id
+}
+
+// findStored finds a stored listing by short ID, room ID or URL.
+func findStored(saved []*models.Listing, arg string) *models.Listing {
+	if l := findByShortID(saved, arg); l != nil {
+		return l
+	}
+	if id := utils.ListingID(arg); id != "" {
+		for _, l := range saved {
+			if utils.ListingID(l.URL) == id {
+				return l
+			}
+		}
+	}
+	return nil
+}
+
+// printComparison prints one row per attribute and one column per listing,
+// then the listings' URLs, which are too long for a column.
+// Fee rows follow the labels in the order they first appear; amenities
+// every listing has are left out of "Only here".
+func printComparison(columns []compared, money *utils.Money) {
+	var feeLabels []string
+	amenityCount := make(map[string]int)
+	for _, c := range columns {
+		if c.details == nil {
+			continue
+		}
+		for _, f := range c.details.Fees {
+			if !slices.Contains(feeLabels, f.Label) {
+				feeLabels = append(feeLabels, f.Label)
+			}
+		}
+		for _, a := range c.details.Amenities {
+			amenityCount[a]++
+		}
+	}
+
+	type row struct {
+		label string
+		value func(c compared) string
+	}
+	detail := func(fn func(d *models.ListingDetails) string) func(c compared) string {
+		return func(c compared) string {
+			if c.details == nil {
+				return "—"
+			}
+			return fn(c.details)
+		}
+	}
+	count := func(n int) string {
+		if n == 0 {
+			return "—"
+		}
+		return strconv.Itoa(n)
+	}
+
+	rows := []row{
+		{"Title", func(c compared) string { return c.listing.Title }},
+		{"Location", func(c compared) string { return c.listing.Location }},
+		{"Price/night", func(c compared) string { return priceCell(money, c.listing) }},
+	}
+	for _, label := range feeLabels {
+		label := label
+		rows = append(rows, row{label, detail(func(d *models.ListingDetails) string {
+			for _, f := range d.Fees {
+				if f.Label == label {
+					return f.Amount
+				}
+			}
+			return "—"
+		})})
+	}
+	rows = append(rows,
+		row{"Total", detail(func(d *models.ListingDetails) string {
+			if d.Total == "" {
+				return "—"
+			}
+			return d.Total
+		})},
+		row{"Rating", func(c compared) string { return ratingCell(c.listing) }},
+		row{"Reviews", func(c compared) string { return strconv.Itoa(c.listing.ReviewCount) }},
+		row{"Guests", detail(func(d *models.ListingDetails) string { return count(d.Guests) })},
+		row{"Bedrooms", detail(func(d *models.ListingDetails) string { return count(d.Bedrooms) })},
+		row{"Beds", detail(func(d *models.ListingDetails) string { return count(d.Beds) })},
+		row{"Baths", detail(func(d *models.ListingDetails) string {
+			if d.Baths == 0 {
+				return "—"
+			}
+			return strconv.FormatFloat(d.Baths, 'f', -1, 64)
+		})},
+		row{"Amenities", detail(func(d *models.ListingDetails) string { return count(d.AmenityCount) })},
+		row{"Only here", detail(func(d *models.ListingDetails) string {
+			var only []string
+			for _, a := range d.Amenities {
+				if amenityCount[a] < len(columns) {
+					only = append(only, a)
+				}
+			}
+			if len(only) == 0 {
+				return "—"
+			}
+			return strings.Join(only, ", ")
+		})},
+	)
+
+	fmt.Printf("\n%-18s", "")
+	for _, c := range columns {
+		fmt.Printf("  %-*s", compareColumnWidth, c.listing.ShortID)
+	}
+	fmt.Println()
+	for _, r := range rows {
+		fmt.Printf("%-18s", clip(r.label, 18))
+		for _, c := range columns {
+			fmt.Printf("  %-*s", compareColumnWidth, clip(r.value(c), compareColumnWidth))
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+	for _, c := range columns {
+		fmt.Printf("%-9s  %s\n", c.listing.ShortID, c.listing.URL)
+	}
+	fmt.Println()
+}
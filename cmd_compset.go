@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdCompSet prints the weekly competitor-set report: the host's nightly
+// price, occupancy and rating against the comp-set average, from what
+// `watch` recorded for COMPSET_LISTING and the rooms in COMPSET_PATH.
+//
+//	compset --weeks 12 --json output/compset.json
+func cmdCompSet(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("compset", flag.ContinueOnError)
+	weeks := fs.Int("weeks", cfg.CompSetWeeks, "weeks to report, the current one included")
+	jsonPath := fs.String("json", "", "also write the report to this JSON file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *weeks < 1 {
+		return fmt.Errorf("invalid --weeks %d (want at least 1)", *weeks)
+	}
+	money, err := moneyFormat(cfg)
+	if err != nil {
+		return err
+	}
+	own, comps, err := loadCompSet(cfg)
+	if err != nil {
+		return err
+	}
+	if own == "" {
+		return fmt.Errorf("COMPSET_LISTING is not set")
+	}
+	if len(comps) == 0 {
+		return fmt.Errorf("no competitors in %q", cfg.CompSetPath)
+	}
+	ownURL := roomURLFor(cfg, own)
+	compURLs := make([]string, len(comps))
+	for i, c := range comps {
+		compURLs[i] = roomURLFor(cfg, c)
+	}
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+	if err := pg.EnsureWatchTable(); err != nil {
+		return err
+	}
+	history, err := pg.WatchHistory(append([]string{ownURL}, compURLs...))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	calendars, err := pg.WatchCalendars(now.AddDate(0, 0, -7*(*weeks+1)))
+	if err != nil {
+		return err
+	}
+	if len(history[ownURL]) == 0 {
+		logger.Warn("[compset] No watch data for %s yet — run `watch` first", ownURL)
+	}
+
+	report := services.BuildCompSetReport(ownURL, compURLs, history, calendars, *weeks, now)
+	printCompSet(report, money)
+	if *jsonPath == "" {
+		return nil
+	}
+	if err := writeCompSetJSON(*jsonPath, report); err != nil {
+		return err
+	}
+	logger.Info("[compset] Report written to %s", *jsonPath)
+	return nil
+}
+
+// loadCompSet returns COMPSET_LISTING's room ID and the competitor room IDs
+// of COMPSET_PATH, without the host's own room; own is "" when no comp set
+// is configured.
+func loadCompSet(cfg *config.Config) (own string, comps []string, err error) {
+	if cfg.CompSetListing == "" {
+		return "", nil, nil
+	}
+	own = utils.ListingID(cfg.CompSetListing)
+	if own == "" {
+		return "", nil, fmt.Errorf("invalid COMPSET_LISTING %q (want a room URL or ID)", cfg.CompSetListing)
+	}
+	list, err := utils.LoadIDList(cfg.CompSetPath)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, id := range list.IDs() {
+		if id != own {
+			comps = append(comps, id)
+		}
+	}
+	return own, comps, nil
+}
+
+// printCompSet prints the weekly rows, then the latest state of each
+// listing. Unknown figures show as "—".
+func printCompSet(r *models.CompSetReport, m *utils.Money) {
+	money := func(v float64) string {
+		if v == 0 {
+			return "—"
+		}
+		return m.Format(v)
+	}
+	share := func(v float64, days int) string {
+		if days == 0 {
+			return "—"
+		}
+		return fmt.Sprintf("%.0f%%", v*100)
+	}
+	rating := func(v float64) string {
+		if v == 0 {
+			return "—"
+		}
+		return fmt.Sprintf("%.2f", v)
+	}
+
+	fmt.Printf("\nComp set: %s vs %d competitors\n\n", r.Listing, len(r.Competitors))
+	fmt.Printf("%-10s  %9s  %9s  %6s  %8s  %8s  %6s  %6s  %5s\n",
+		"WEEK", "PRICE", "COMP", "INDEX", "OCC", "COMP OCC", "RATING", "COMP", "COMPS")
+	for _, w := range r.Weeks {
+		index := "—"
+		if w.PriceIndex > 0 {
+			index = fmt.Sprintf("%.0f", w.PriceIndex)
+		}
+		fmt.Printf("%-10s  %9s  %9s  %6s  %8s  %8s  %6s  %6s  %5d\n",
+			w.Start.Format("2006-01-02"), money(w.Own.Price), money(w.CompSet.Price), index,
+			share(w.Own.Occupancy, w.Own.CalendarDays), share(w.CompSet.Occupancy, w.CompSet.CalendarDays),
+			rating(w.Own.Rating), rating(w.CompSet.Rating), w.CompSet.Listings)
+	}
+
+	fmt.Printf("\n%-30s  %9s  %-11s  %6s  %8s\n", "LATEST", "PRICE", "STATUS", "RATING", "UPCOMING")
+	for i, m := range r.Latest {
+		name := m.Title
+		if name == "" {
+			name = m.URL
+		}
+		if i == 0 {
+			name = utils.Style("★ ") + name
+		}
+		status := m.Status
+		if status == "" {
+			status = "not watched"
+		}
+		fmt.Printf("%-30s  %9s  %-11s  %6s  %8s\n", clip(name, 30), money(m.Price), status, rating(m.Rating),
+			share(m.Occupancy, m.CalendarDays))
+	}
+	fmt.Println()
+}
+
+// writeCompSetJSON writes the report as indented JSON.
+func writeCompSetJSON(path string, r *models.CompSetReport) error {
+	doc, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("compset: encode: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("compset: create output dir: %w", err)
+	}
+	if err := os.WriteFile(path, append(doc, '\n'), 0644); err != nil {
+		return fmt.Errorf("compset: write %q: %w", path, err)
+	}
+	return nil
+}
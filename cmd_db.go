@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdDB runs database housekeeping for the selected project's schema.
+//
+//	db maintain [--no-vacuum] [--no-reindex]   vacuum/analyze, reindex hot
+//	                                           tables, refresh materialized
+//	                                           views, then print table sizes
+//	db sizes                                   print table sizes only
+func cmdDB(cfg *config.Config, logger *utils.Logger, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: db maintain|sizes [flags]")
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("db "+action, flag.ContinueOnError)
+	noVacuum := fs.Bool("no-vacuum", false, "skip VACUUM (ANALYZE); statistics are still refreshed with ANALYZE")
+	noReindex := fs.Bool("no-reindex", false, "skip rebuilding indexes of the hot tables")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+
+	switch action {
+	case "maintain":
+		if err := maintain(pg, logger, !*noVacuum, !*noReindex); err != nil {
+			return err
+		}
+	case "sizes":
+	default:
+		return fmt.Errorf("unknown db action %q (want maintain or sizes)", action)
+	}
+
+	sizes, err := pg.TableSizes()
+	if err != nil {
+		return err
+	}
+	printTableSizes(sizes)
+	return nil
+}
+
+func maintain(pg *storage.PostgresWriter, logger *utils.Logger, vacuum, reindex bool) error {
+	if vacuum {
+		tables, err := pg.Vacuum()
+		if err != nil {
+			return err
+		}
+		logger.Info("[db] Vacuumed and analyzed %d tables", len(tables))
+	} else {
+		tables, err := pg.Analyze()
+		if err != nil {
+			return err
+		}
+		logger.Info("[db] Analyzed %d tables", len(tables))
+	}
+
+	if reindex {
+		tables, err := pg.ReindexHot()
+		if err != nil {
+			return err
+		}
+		logger.Info("[db] Reindexed %s", strings.Join(tables, ", "))
+	}
+
+	views, err := pg.RefreshMaterializedViews()
+	if err != nil {
+		return err
+	}
+	if len(views) > 0 {
+		logger.Info("[db] Refreshed materialized views: %s", strings.Join(views, ", "))
+	}
+	return nil
+}
+
+func printTableSizes(sizes []storage.TableSize) {
+	var total int64
+	fmt.Printf("\n%-24s %12s %10s %10s\n", "TABLE", "ROWS", "DATA", "INDEXES")
+	for _, s := range sizes {
+		fmt.Printf("%-24s %12d %10s %10s\n", s.Name, s.Rows, formatBytes(s.TableBytes), formatBytes(s.IndexBytes))
+		total += s.TableBytes + s.IndexBytes
+	}
+	fmt.Printf("\nTotal: %s\n\n", formatBytes(total))
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "12.3 MB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdExplore opens an interactive shell over the stored listings for
+// filtering, sorting, grouping and opening listings in the browser. Type
+// help at the prompt for its commands.
+func cmdExplore(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("explore", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	money, err := moneyFormat(cfg)
+	if err != nil {
+		return err
+	}
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	listings, err := pg.FetchAll()
+	pg.Close()
+	if err != nil {
+		return err
+	}
+
+	e := services.NewExplorer(listings, os.Stdout, utils.OpenURL)
+	e.SetMoney(money)
+	return e.Run(os.Stdin)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/schema"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdExport writes the stored listings to CSV, either with chosen listing
+// fields or in a fixed profile such as the Inside Airbnb layouts, so the
+// file drops straight into notebooks built around that format. --anonymize
+// prepares the file for publication; see services.Anonymizer. --format json
+// writes full listing objects instead, checked against the published
+// listing schema before the file is written. Exports carry the listings'
+// tags and notes (see the tag command); --tag keeps only listings with
+// that tag and --view exports a saved view instead of every listing.
+func cmdExport(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	profile := fs.String("profile", "", "fixed column layout: "+strings.Join(profileNames(), ", "))
+	columns := fs.String("fields", "", "comma-separated listing fields (default all); ignored with --profile")
+	out := fs.String("out", cfg.ProjectPath("./output/listings_export.csv"), "output path (.json by default with --format json)")
+	anonymize := fs.Bool("anonymize", false, "redact host names, snap coordinates to ~500 m, drop URLs and hash IDs")
+	outFormat := fs.String("format", "csv", "csv, or json for full listing objects (see the schema command)")
+	tag := fs.String("tag", "", "export only listings with this tag")
+	view := fs.String("view", "", "export the listings of this saved view")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	switch *outFormat {
+	case "csv":
+	case "json":
+		if *profile != "" || *columns != "" {
+			return fmt.Errorf("--profile and --fields apply to CSV exports only")
+		}
+		outSet := false
+		fs.Visit(func(f *flag.Flag) { outSet = outSet || f.Name == "out" })
+		if !outSet {
+			*out = strings.TrimSuffix(*out, ".csv") + ".json"
+		}
+	default:
+		return fmt.Errorf("unknown --format %q (want csv or json)", *outFormat)
+	}
+
+	var fields []storage.Field[*models.Listing]
+	if *profile != "" {
+		var ok bool
+		if fields, ok = storage.ListingProfiles[*profile]; !ok {
+			return fmt.Errorf("unknown profile %q (available: %s)", *profile, strings.Join(profileNames(), ", "))
+		}
+	} else {
+		var err error
+		if fields, err = storage.SelectFields(storage.ListingFields, splitFields(*columns)); err != nil {
+			return err
+		}
+	}
+	format, err := csvFormat(cfg)
+	if err != nil {
+		return err
+	}
+	if *tag != "" {
+		if *tag, err = storage.NormalizeTag(*tag); err != nil {
+			return err
+		}
+	}
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+
+	if err := pg.EnsureTagTables(); err != nil {
+		return err
+	}
+	var listings []*models.Listing
+	if *view != "" {
+		listings, err = viewListings(pg, *view)
+	} else if listings, err = pg.FetchAll(); err == nil {
+		err = pg.AttachTags(listings)
+	}
+	if err != nil {
+		return err
+	}
+	if *tag != "" {
+		listings = slices.DeleteFunc(listings, func(l *models.Listing) bool { return !slices.Contains(l.Tags, *tag) })
+	}
+	if *anonymize {
+		if cfg.AnonymizeSalt == "" {
+			logger.Warn("[export] ANONYMIZE_SALT is not set — IDs are hashed with a random salt and will not match other exports")
+		}
+		listings = services.NewAnonymizer(cfg.AnonymizeSalt).Apply(listings)
+	}
+	if *outFormat == "json" {
+		if err := writeListingsJSON(*out, listings); err != nil {
+			return err
+		}
+		logger.Info("[export] %d listings written to %s", len(listings), *out)
+		return nil
+	}
+	if err := storage.WriteListingsCSV(*out, listings, format, fields); err != nil {
+		return err
+	}
+	logger.Info("[export] %d listings written to %s", len(listings), *out)
+	return nil
+}
+
+// writeListingsJSON writes listings as a JSON array after validating it
+// against the listing schema, so a model change that breaks the published
+// contract fails the export instead of reaching integrators.
+func writeListingsJSON(path string, listings []*models.Listing) error {
+	if listings == nil {
+		listings = []*models.Listing{}
+	}
+	doc, err := json.MarshalIndent(listings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("export: encode: %w", err)
+	}
+	if err := schema.Validate("listing", doc); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("export: create output dir: %w", err)
+	}
+	if err := os.WriteFile(path, append(doc, '\n'), 0644); err != nil {
+		return fmt.Errorf("export: write %q: %w", path, err)
+	}
+	return nil
+}
+
+func profileNames() []string {
+	names := make([]string, 0, len(storage.ListingProfiles))
+	for name := range storage.ListingProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// splitFields parses a comma-separated --fields value; empty means all.
+func splitFields(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/scraper/airbnb"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdFingerprint captures the structural fingerprint of a detail page, diffs
+// it against the one stored by the previous invocation and saves the new one.
+// It exits non-zero when the structure changed so cron/CI can alert on it.
+func cmdFingerprint(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("fingerprint", flag.ContinueOnError)
+	url := fs.String("url", "", "detail page to fingerprint (default: first CANARY_URLS entry)")
+	path := fs.String("store", cfg.FingerprintPath, "file holding the previous fingerprint")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *url == "" && len(cfg.CanaryURLs) > 0 {
+		*url = cfg.CanaryURLs[0]
+	}
+	if *url == "" {
+		return fmt.Errorf("--url is required when CANARY_URLS is empty")
+	}
+
+	prev, err := storage.LoadFingerprint(*path)
+	if err != nil {
+		return err
+	}
+
+	curr, err := airbnb.New(cfg, logger).FetchFingerprint(*url)
+	if err != nil {
+		return err
+	}
+	logger.Info("[fingerprint] %d sections, %d test ids, headings %v",
+		len(curr.SectionIDs), len(curr.TestIDs), curr.Headings)
+
+	if err := storage.SaveFingerprint(*path, curr); err != nil {
+		return err
+	}
+
+	if prev == nil {
+		logger.Info("[fingerprint] Baseline saved to %s", *path)
+		return nil
+	}
+	changes := services.DiffFingerprints(prev, curr)
+	if len(changes) == 0 {
+		logger.Info("[fingerprint] Page structure unchanged since %s", prev.CapturedAt.Format("2006-01-02 15:04"))
+		return nil
+	}
+
+	logger.Error("[fingerprint] ALERT: detail page structure changed since %s — check the extractors",
+		prev.CapturedAt.Format("2006-01-02 15:04"))
+	for _, c := range changes {
+		logger.Error("[fingerprint]   %s", c)
+	}
+	return fmt.Errorf("%d structural changes detected", len(changes))
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdImport loads third-party listing datasets, such as Inside Airbnb's
+// listings.csv.gz, through the Cleaner into the listings table next to the
+// scraped rows, then rescores the combined set. Rooms already stored keep
+// their scraped values. The listings table is rebuilt by every scrape, so
+// import after the run you want to compare against.
+func cmdImport(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	city := fs.String("city", "", "target city to tag the imported rows with (for per-city reports)")
+	dryRun := fs.Bool("dry-run", false, "read and clean the files, without storing anything")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: import [--city NAME] FILE...")
+	}
+
+	cleaner := services.NewCleaner(logger)
+	cleaner.SetWorkers(cfg.CleanWorkers)
+	cleaner.SetStrict(cfg.CleanStrict)
+
+	var pg *storage.PostgresWriter
+	if !*dryRun {
+		var err error
+		if pg, err = storage.OpenPostgres(cfg.DSN()); err != nil {
+			return err
+		}
+		defer pg.Close()
+	}
+
+	for _, path := range fs.Args() {
+		raw, err := storage.ReadExternalListings(path)
+		if err != nil {
+			return err
+		}
+		for _, r := range raw {
+			r.TargetCity = *city
+		}
+		listings := cleaner.Clean(raw)
+		logger.Info("[import] %s: %d rows → %d listings", path, len(raw), len(listings))
+		if pg == nil {
+			continue
+		}
+		if err := pg.Write(listings); err != nil {
+			return err
+		}
+	}
+	if pg == nil {
+		return nil
+	}
+
+	// Scores are normalised over the whole dataset, so they change for the
+	// scraped rows too.
+	all, err := pg.FetchAll()
+	if err != nil {
+		return err
+	}
+	weights, err := config.LoadScoringWeights(cfg.ScoringConfigPath)
+	if err != nil {
+		logger.Warn("Scoring config ignored, using defaults: %v", err)
+		weights = config.DefaultScoringWeights()
+	}
+	services.NewScorer(weights, logger).Apply(all)
+	if err := pg.UpdateScores(all); err != nil {
+		return err
+	}
+	logger.Info("[import] %d listings stored in total", len(all))
+	return nil
+}
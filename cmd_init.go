@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/scraper/airbnb"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// initSetting is one value the setup wizard asks for.
+type initSetting struct {
+	key      string
+	prompt   string
+	fallback string
+	secret   bool
+}
+
+var initSettings = []initSetting{
+	{key: "POSTGRES_HOST", prompt: "PostgreSQL host", fallback: "localhost"},
+	{key: "POSTGRES_PORT", prompt: "PostgreSQL port", fallback: "5432"},
+	{key: "POSTGRES_USER", prompt: "PostgreSQL user", fallback: "scraper"},
+	{key: "POSTGRES_PASSWORD", prompt: "PostgreSQL password", secret: true},
+	{key: "POSTGRES_DB", prompt: "PostgreSQL database", fallback: "rental_db"},
+	{key: "POSTGRES_SSLMODE", prompt: "PostgreSQL sslmode", fallback: "disable"},
+	{key: "CHROME_BIN", prompt: "Chrome/Chromium binary"},
+	{key: "CITIES", prompt: "Cities to scrape, comma-separated (empty = homepage sections)"},
+	{key: "PAGES_TO_SCRAPE", prompt: "Search result pages per query", fallback: "2"},
+}
+
+// cmdInit is the first-run setup wizard. It asks for the essential settings
+// (current values are the defaults), writes them to .env — or to the
+// profile file when --env is given — and then checks each piece a run
+// needs: the browser launches, PostgreSQL accepts the credentials, and one
+// listing scrapes with a title and a plausible price.
+func cmdInit(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "accept every default without prompting")
+	url := fs.String("url", "", "room URL for the smoke scrape (default: first CANARY_URLS entry, else asked)")
+	skipSmoke := fs.Bool("skip-smoke", false, "skip the single-listing smoke scrape")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := ".env"
+	if cfg.Profile != "" {
+		path = config.ProfileFile(cfg.Profile)
+	}
+	in := bufio.NewReader(os.Stdin)
+	ask := func(label, def string, secret bool) string {
+		if *yes {
+			return def
+		}
+		return promptLine(in, os.Stdout, label, def, secret)
+	}
+
+	// ── 1. Config file ───────────────────────────────────────────────────
+	fmt.Printf("Setting up %s — press Enter to keep the value in brackets.\n\n", path)
+	values := make(map[string]string, len(initSettings))
+	for _, s := range initSettings {
+		def := os.Getenv(s.key)
+		if def == "" {
+			def = s.fallback
+		}
+		if s.key == "CHROME_BIN" && !fileExists(def) {
+			def = airbnb.BrowserBinary()
+		}
+		values[s.key] = ask(s.prompt, def, s.secret)
+	}
+	if err := config.UpdateEnvFile(path, values); err != nil {
+		return err
+	}
+	logger.Info("[init] Wrote %d settings to %s", len(values), path)
+
+	cfg, err := config.Reload()
+	if err != nil {
+		return err
+	}
+
+	// ── 2. Checks ────────────────────────────────────────────────────────
+	var failed []string
+	check := func(name string, err error, detail string) {
+		if err != nil {
+			logger.Error("[init] ✗ %s: %v", name, err)
+			failed = append(failed, name)
+			return
+		}
+		logger.Info("[init] ✓ %s — %s", name, detail)
+	}
+
+	product, err := airbnb.New(cfg, logger).CheckBrowser()
+	check("browser", err, product)
+	browserOK := err == nil
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err == nil {
+		pg.Close()
+	}
+	check("database", err, fmt.Sprintf("%s@%s:%s/%s", cfg.PostgresUser, cfg.PostgresHost, cfg.PostgresPort, cfg.PostgresDB))
+
+	switch {
+	case *skipSmoke:
+		logger.Info("[init] Smoke scrape skipped")
+	case !browserOK:
+		logger.Warn("[init] Smoke scrape skipped — the browser did not start")
+	default:
+		if *url == "" && len(cfg.CanaryURLs) > 0 {
+			*url = cfg.CanaryURLs[0]
+		}
+		if *url == "" {
+			*url = ask("Room URL for a smoke scrape (empty = skip)", "", false)
+		}
+		if *url == "" {
+			logger.Info("[init] Smoke scrape skipped — no room URL")
+			break
+		}
+		check("smoke scrape", smokeScrape(cfg, logger, *url), *url)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d checks failed (%s); fix %s and re-run init", len(failed), strings.Join(failed, ", "), path)
+	}
+	fmt.Printf("\nAll set. Start a run with: go run .\n")
+	return nil
+}
+
+// smokeScrape scrapes a single detail page and applies the canary
+// expectations to it.
+func smokeScrape(cfg *config.Config, logger *utils.Logger, url string) error {
+	listings := airbnb.New(cfg, logger).FetchCanaries([]string{url})
+	failures := services.NewCanaryChecker(cfg.CanaryMinPrice, cfg.CanaryMaxPrice, logger).Check(listings)
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	l := listings[0]
+	logger.Info("[init] %q — %s, %s", l.Title, l.RawPrice, l.Location)
+	return nil
+}
+
+// promptLine asks for one value, returning def on an empty answer or EOF.
+// Secret defaults are masked; the answer itself is still echoed.
+func promptLine(in *bufio.Reader, out io.Writer, label, def string, secret bool) string {
+	shown := def
+	if secret && def != "" {
+		shown = "********"
+	}
+	if shown != "" {
+		fmt.Fprintf(out, "%s [%s]: ", label, shown)
+	} else {
+		fmt.Fprintf(out, "%s: ", label)
+	}
+	line, _ := in.ReadString('\n')
+	if line = strings.TrimSpace(line); line != "" {
+		return line
+	}
+	return def
+}
+
+func fileExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdLocations lists the stored listings' locations, with spellings that
+// differ only in case, spacing, punctuation or accents merged, or the
+// ones fuzzily matching a query — the strings to give `query --location`.
+//
+//	locations
+//	locations bangrak          # → Bang Rak
+func cmdLocations(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("locations", flag.ContinueOnError)
+	limit := fs.Int("limit", 20, "maximum rows (0 = all)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	query := strings.Join(fs.Args(), " ")
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+	listings, err := pg.FetchAll()
+	if err != nil {
+		return err
+	}
+
+	known := services.KnownLocations(listings)
+	matches := known
+	if query != "" {
+		matches = services.MatchLocations(known, query, *limit)
+	} else if *limit > 0 && len(matches) > *limit {
+		matches = matches[:*limit]
+	}
+
+	fmt.Printf("\n%-32s  %6s  %5s  %s\n", "LOCATION", "COUNT", "SCORE", "ALSO SPELT")
+	for _, m := range matches {
+		score := "—"
+		if query != "" {
+			score = fmt.Sprintf("%.2f", m.Score)
+		}
+		fmt.Printf("%-32s  %6d  %5s  %s\n", clip(m.Name, 32), m.Count, score, strings.Join(m.Variants, " | "))
+	}
+	if query != "" && len(matches) == 0 {
+		fmt.Printf("No location matches %q\n", query)
+	}
+	fmt.Printf("\n%d of %d locations\n\n", len(matches), len(known))
+	return nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/scraper/airbnb/mocksite"
+	"airbnb-scraper/utils"
+)
+
+// cmdMocksite serves the static Airbnb imitation the end-to-end tests use,
+// so a whole run can be pointed at it by hand:
+//
+//	go run . mocksite --addr :8089
+//	go run . --set AIRBNB_BASE_URL=http://localhost:8089
+func cmdMocksite(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("mocksite", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8089", "listen address")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	logger.Info("[mocksite] Serving %d mock listings on http://%s — run with AIRBNB_BASE_URL pointing here", len(mocksite.Rooms()), *addr)
+	return http.ListenAndServe(*addr, mocksite.Handler())
+}
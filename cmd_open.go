@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdOpen opens listings from the latest run in the default browser: the
+// first N entries of a report section (top-rated, top-scored,
+// most-expensive) or listings given by short ID.
+//
+//	open -n 3 top-rated
+//	open 7K2M-Q9XD 4HX1-0PZA
+func cmdOpen(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("open", flag.ContinueOnError)
+	n := fs.Int("n", 5, "how many entries of a report section to open")
+	printOnly := fs.Bool("print", false, "print the URLs instead of opening them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: open [-n N] %s|SHORT_ID...", strings.Join(services.ReportEntryNames(), "|"))
+	}
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	listings, err := pg.FetchAll()
+	pg.Close()
+	if err != nil {
+		return err
+	}
+
+	var targets []*models.Listing
+	var report *models.InsightReport
+	for _, arg := range fs.Args() {
+		if entry, ok := services.ReportEntries[arg]; ok {
+			if report == nil {
+				insightSvc := services.NewInsightService(logger)
+				insightSvc.SetMinConfidence(cfg.MinFieldConfidence)
+				report = insightSvc.Generate(listings)
+			}
+			section := entry(report)
+			if len(section) > *n {
+				section = section[:*n]
+			}
+			targets = append(targets, section...)
+			continue
+		}
+		l := findByShortID(listings, arg)
+		if l == nil {
+			return fmt.Errorf("%q is neither a report section (%s) nor a stored short ID",
+				arg, strings.Join(services.ReportEntryNames(), ", "))
+		}
+		targets = append(targets, l)
+	}
+
+	for _, l := range targets {
+		if *printOnly {
+			fmt.Printf("%s  %s\n", l.URL, l.Title)
+			continue
+		}
+		if err := utils.OpenURL(l.URL); err != nil {
+			return err
+		}
+		logger.Info("[open] %s — %s", l.ShortID, l.Title)
+	}
+	if len(targets) == 0 {
+		logger.Warn("[open] Nothing to open — the report section is empty")
+	}
+	return nil
+}
+
+func findByShortID(listings []*models.Listing, id string) *models.Listing {
+	for _, l := range listings {
+		if strings.EqualFold(l.ShortID, id) {
+			return l
+		}
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"flag"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/utils"
+)
+
+// cmdPreflight prints the pre-flight checklist a run starts with and fails
+// when any check does, e.g. to test a new host or container before
+// scheduling it.
+func cmdPreflight(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("preflight", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return preflight(cfg, logger)
+}
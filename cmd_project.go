@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdProject lists the projects stored in the database and switches the
+// selected one. Each project keeps its tables in its own schema and its
+// files under output/<project>/, so searches never mix.
+//
+//	project list          show projects, * marks the selected one
+//	project use NAME      select NAME for later runs ("default" clears)
+func cmdProject(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("project", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch fs.Arg(0) {
+	case "", "list":
+		return listProjects(cfg)
+	case "use":
+		name := fs.Arg(1)
+		if name == "" {
+			return fmt.Errorf("usage: project use NAME")
+		}
+		if name == "default" {
+			name = ""
+		}
+		if err := config.SaveProject(name); err != nil {
+			return err
+		}
+		logger.Info("[project] Selected %s (saved to %s)", projectLabel(name), config.ProjectFile)
+		return nil
+	default:
+		return fmt.Errorf("unknown project action %q (want list or use)", fs.Arg(0))
+	}
+}
+
+func listProjects(cfg *config.Config) error {
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+
+	schemas, err := pg.Schemas()
+	if err != nil {
+		return err
+	}
+
+	names := []string{""}
+	for _, s := range schemas {
+		if name, ok := config.ProjectFromSchema(s); ok {
+			names = append(names, name)
+		}
+	}
+	if cfg.Project != "" && !slices.Contains(names, cfg.Project) {
+		names = append(names, cfg.Project) // selected but not run yet
+	}
+	for _, name := range names {
+		marker := " "
+		if name == cfg.Project {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\n", marker, projectLabel(name))
+	}
+	return nil
+}
+
+func projectLabel(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return name
+}
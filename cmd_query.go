@@ -0,0 +1,153 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// defaultQueryColumns are the CSV columns of `query --csv` without --fields.
+var defaultQueryColumns = []string{"short_id", "title", "price", "rating", "review_count", "location", "url"}
+
+// cmdQuery prints stored listings matching simple filters, as a table or as
+// CSV on standard output, so everyday lookups need no psql. --view starts
+// from a saved view (see the view command); other filter flags override
+// its values:
+//
+//	query --location Bangkok --max-price 80 --min-rating 4.5 --sort price
+//	query --view bangkok-cheap --sort -rating
+func cmdQuery(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	var q storage.ListingQuery
+	queryFlags(fs, &q, 20)
+	view := fs.String("view", "", "start from this saved view")
+	asCSV := fs.Bool("csv", false, "print CSV instead of a table")
+	columns := fs.String("fields", strings.Join(defaultQueryColumns, ","), "CSV columns")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := normalizeQuery(&q); err != nil {
+		return err
+	}
+	money, err := moneyFormat(cfg)
+	if err != nil {
+		return err
+	}
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+	if err := pg.EnsureTagTables(); err != nil {
+		return err
+	}
+	if *view != "" {
+		if err := pg.EnsureViewTable(); err != nil {
+			return err
+		}
+		if q, err = pg.View(*view); err != nil {
+			return err
+		}
+		// Parsing again applies the explicit flags over the view; flags
+		// left out keep the view's values.
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if err := normalizeQuery(&q); err != nil {
+			return err
+		}
+	}
+
+	listings, err := pg.QueryListings(q)
+	if err != nil {
+		return err
+	}
+	if err := pg.AttachTags(listings); err != nil {
+		return err
+	}
+
+	if *asCSV {
+		fields, err := storage.SelectFields(storage.ListingFields, splitFields(*columns))
+		if err != nil {
+			return err
+		}
+		format, err := csvFormat(cfg)
+		if err != nil {
+			return err
+		}
+		return storage.EncodeListingsCSV(os.Stdout, listings, format, fields)
+	}
+
+	fmt.Printf("\n%-9s  %-30s  %-18s  %8s  %6s  %7s  %s\n", "ID", "TITLE", "LOCATION", "PRICE", "RATING", "REVIEWS", "TAGS")
+	for _, l := range listings {
+		fmt.Printf("%-9s  %-30s  %-18s  %8s  %6s  %7d  %s\n",
+			l.ShortID, clip(l.Title, 30), clip(l.Location, 18), priceCell(money, l), ratingCell(l), l.ReviewCount,
+			strings.Join(l.Tags, ","))
+	}
+	fmt.Printf("\n%d listings\n\n", len(listings))
+	return nil
+}
+
+// queryFlags registers the ListingQuery filter flags shared by query and
+// view save, with limit as the --limit default.
+func queryFlags(fs *flag.FlagSet, q *storage.ListingQuery, limit int) {
+	fs.StringVar(&q.Location, "location", "", "location contains (case-insensitive)")
+	fs.StringVar(&q.City, "city", "", "target city (multi-city runs)")
+	fs.StringVar(&q.Text, "text", "", "title or description contains")
+	fs.Float64Var(&q.MinPrice, "min-price", 0, "minimum nightly price")
+	fs.Float64Var(&q.MaxPrice, "max-price", 0, "maximum nightly price")
+	fs.Float64Var(&q.MinRating, "min-rating", 0, "minimum rating (0-5)")
+	fs.IntVar(&q.MinReviews, "min-reviews", 0, "minimum review count")
+	fs.StringVar(&q.Tag, "tag", "", "tagged with this tag (see the tag command)")
+	fs.StringVar(&q.Sort, "sort", "", "price, rating, reviews, score or title; prefix - to reverse (default score)")
+	fs.IntVar(&q.Limit, "limit", limit, "maximum rows (0 = all)")
+}
+
+// normalizeQuery normalizes q's tag and checks its sort key.
+func normalizeQuery(q *storage.ListingQuery) error {
+	if q.Tag != "" {
+		var err error
+		if q.Tag, err = storage.NormalizeTag(q.Tag); err != nil {
+			return err
+		}
+	}
+	_, _, err := q.SQL()
+	return err
+}
+
+// clip shortens s to n runes, marking the cut with an ellipsis.
+func clip(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// priceCell is a listing's nightly price for tables; an unknown price shows
+// as "—" so it is not mistaken for a free stay.
+func priceCell(money *utils.Money, l *models.Listing) string {
+	if !l.HasPrice() {
+		return "—"
+	}
+	return money.Format(l.Price)
+}
+
+// ratingCell is a listing's rating for tables: "New" for listings too new
+// to rate, "—" for any other listing without a rating.
+func ratingCell(l *models.Listing) string {
+	switch {
+	case l.IsNewListing:
+		return "New"
+	case l.Rating <= 0:
+		return "—"
+	}
+	return fmt.Sprintf("%.2f", l.Rating)
+}
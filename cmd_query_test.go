@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+func TestPriceCell(t *testing.T) {
+	money, err := utils.NewMoney("en-US", "USD", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		listing models.Listing
+		want    string
+	}{
+		{"unknown", models.Listing{}, "—"},
+		{"free", models.Listing{PriceKnown: true}, money.Format(0)},
+		{"priced", models.Listing{Price: 120, PriceKnown: true}, money.Format(120)},
+	}
+	for _, tt := range tests {
+		if got := priceCell(money, &tt.listing); got != tt.want {
+			t.Errorf("%s: priceCell = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRatingCell(t *testing.T) {
+	tests := []struct {
+		name    string
+		listing models.Listing
+		want    string
+	}{
+		{"unrated", models.Listing{}, "—"},
+		{"new", models.Listing{IsNewListing: true}, "New"},
+		{"rated", models.Listing{Rating: 4.876}, "4.88"},
+	}
+	for _, tt := range tests {
+		if got := ratingCell(&tt.listing); got != tt.want {
+			t.Errorf("%s: ratingCell = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
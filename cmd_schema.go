@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/schema"
+	"airbnb-scraper/utils"
+)
+
+// cmdSchema prints the JSON Schemas of the scraper's JSON outputs, or checks
+// a file against one:
+//
+//	schema                        list the schemas
+//	schema listing                print one
+//	schema validate listing FILE  validate FILE (an object or an array)
+func cmdSchema(cfg *config.Config, logger *utils.Logger, args []string) error {
+	switch {
+	case len(args) == 0:
+		fmt.Println(strings.Join(schema.Names(), "\n"))
+		return nil
+	case args[0] == "validate":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: schema validate NAME FILE")
+		}
+		doc, err := os.ReadFile(args[2])
+		if err != nil {
+			return err
+		}
+		if err := schema.Validate(args[1], doc); err != nil {
+			return err
+		}
+		logger.Info("[schema] %s is a valid %s document", args[2], args[1])
+		return nil
+	case len(args) == 1:
+		doc, err := schema.Get(args[0])
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(doc)
+		return err
+	}
+	return fmt.Errorf("usage: schema [NAME | validate NAME FILE]")
+}
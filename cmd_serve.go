@@ -0,0 +1,342 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdServe exposes the stored listings over a small JSON API. Apart from
+// tags and notes, which analysts edit while reviewing, it is read-only.
+func cmdServe(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", cfg.APIAddr, "listen address")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+	if err := pg.EnsureTagTables(); err != nil {
+		return err
+	}
+	if err := pg.EnsureViewTable(); err != nil {
+		return err
+	}
+
+	insightSvc := services.NewInsightService(logger)
+	insightSvc.SetLandmarks(cfg.Landmarks, cfg.LandmarkRadiusKm)
+	insightSvc.SetClustering(cfg.ClusterEpsKm, cfg.ClusterMinPoints)
+	insightSvc.SetMinConfidence(cfg.MinFieldConfidence)
+	var fields []storage.Field[*models.Listing]
+	if len(cfg.APIFields) > 0 {
+		if fields, err = storage.SelectFields(storage.ListingFields, cfg.APIFields); err != nil {
+			return fmt.Errorf("API_FIELDS: %w", err)
+		}
+	}
+	api := &apiServer{cfg: cfg, pg: pg, logger: logger, insights: insightSvc, fields: fields}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/listings", api.listings)
+	mux.HandleFunc("/api/report", api.report)
+	mux.HandleFunc("/api/lifecycle", api.lifecycle)
+	mux.HandleFunc("/api/locations", api.locations)
+	mux.HandleFunc("/api/tags", api.tags)
+	mux.HandleFunc("/api/notes", api.notes)
+
+	logger.Info("[api] Listening on %s", *addr)
+	return http.ListenAndServe(*addr, mux)
+}
+
+type apiServer struct {
+	cfg      *config.Config
+	pg       *storage.PostgresWriter
+	logger   *utils.Logger
+	insights *services.InsightService
+	fields   []storage.Field[*models.Listing] // nil = full listing objects
+}
+
+// listings returns stored listings ranked by composite score, with their
+// tags and notes. Query params: limit (default 50, 0 = all), fields
+// (comma-separated columns, overriding API_FIELDS), short_id (return only
+// that listing), tag (only listings with that tag), view (only the
+// listings of that saved view).
+func (a *apiServer) listings(w http.ResponseWriter, r *http.Request) {
+	all, ok := a.scope(w, r)
+	if !ok {
+		return
+	}
+	if tag := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("tag"))); tag != "" {
+		all = slices.DeleteFunc(all, func(l *models.Listing) bool { return !slices.Contains(l.Tags, tag) })
+	}
+	ranked := services.Rank(all)
+	if id := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("short_id"))); id != "" {
+		var match []*models.Listing
+		for _, l := range ranked {
+			if l.ShortID == id {
+				match = append(match, l)
+			}
+		}
+		ranked = match
+	}
+
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			limit = n
+		}
+	}
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	fields := a.fields
+	if v := r.URL.Query().Get("fields"); v != "" {
+		var err error
+		if fields, err = storage.SelectFields(storage.ListingFields, strings.Split(v, ",")); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if fields == nil {
+		writeJSON(w, ranked)
+		return
+	}
+	records := make([]storage.Record, len(ranked))
+	for i, l := range ranked {
+		records[i] = storage.NewRecord(fields, l)
+	}
+	writeJSON(w, records)
+}
+
+// report returns the insight report over all stored listings, or those of
+// the saved view given by the view query param, with price recommendations
+// for the watched ones and occupancy estimates.
+func (a *apiServer) report(w http.ResponseWriter, r *http.Request) {
+	all, ok := a.scope(w, r)
+	if !ok {
+		return
+	}
+	report := a.insights.Generate(all)
+	if view := r.URL.Query().Get("view"); view != "" {
+		report.Scope = "view " + view
+	}
+	if counts, err := a.pg.LifecycleCounts(); err == nil {
+		report.StatusCounts = counts
+	}
+	if a.cfg.ChurnWeeks > 0 {
+		if churn, err := a.pg.FetchChurn(a.cfg.ChurnWeeks); err == nil {
+			report.Churn = churn
+		}
+	}
+	if recs, err := priceRecommendations(a.cfg, a.pg, a.insights, all); err == nil {
+		report.Recommendations = recs
+	}
+	if perListing, perLocation, err := occupancyEstimates(a.cfg, a.pg, a.insights, all); err == nil {
+		report.Occupancy, report.LocationOccupancy = perListing, perLocation
+	}
+	writeJSON(w, report)
+}
+
+// lifecycle returns every tracked listing with its cross-run status.
+// Query params: status (active, stale, removed or suspect; default all).
+func (a *apiServer) lifecycle(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != "" && !slices.Contains(models.ListingStatuses, status) {
+		http.Error(w, fmt.Sprintf("unknown status %q (want one of %s)",
+			status, strings.Join(models.ListingStatuses, ", ")), http.StatusBadRequest)
+		return
+	}
+	entries, err := a.pg.FetchLifecycle(status)
+	if err != nil {
+		a.fail(w, err)
+		return
+	}
+	writeJSON(w, entries)
+}
+
+// locations returns the canonical locations of the stored listings with
+// their counts, most listings first, for filter autocomplete. Query params:
+// q (fuzzy match, best first: "bangrak" finds "Bang Rak"), limit (default
+// 20, 0 = all).
+func (a *apiServer) locations(w http.ResponseWriter, r *http.Request) {
+	all, err := a.pg.FetchAll()
+	if err != nil {
+		a.fail(w, err)
+		return
+	}
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			limit = n
+		}
+	}
+	known := services.KnownLocations(all)
+	if q := r.URL.Query().Get("q"); q != "" {
+		known = services.MatchLocations(known, q, limit)
+	} else if limit > 0 && len(known) > limit {
+		known = known[:limit]
+	}
+	if known == nil {
+		known = []*models.LocationSuggestion{}
+	}
+	writeJSON(w, known)
+}
+
+// tags returns the listing count per tag (GET), or adds (POST) or removes
+// (DELETE) the tag given by the tag query param on the listing given by
+// short_id. Writes need ADMIN_TOKEN as a bearer token when it is set.
+func (a *apiServer) tags(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		counts, err := a.pg.TagCounts()
+		if err != nil {
+			a.fail(w, err)
+			return
+		}
+		writeJSON(w, counts)
+		return
+	}
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	l, ok := a.annotated(w, r)
+	if !ok {
+		return
+	}
+	tag, err := storage.NormalizeTag(r.URL.Query().Get("tag"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.Method == http.MethodPost {
+		err = a.pg.AddTags(l.URL, []string{tag})
+	} else {
+		_, err = a.pg.RemoveTags(l.URL, []string{tag})
+	}
+	if err != nil {
+		a.fail(w, err)
+		return
+	}
+	a.writeAnnotated(w, l)
+}
+
+// notes replaces (PUT) or clears (DELETE) the note of the listing given by
+// short_id; the request body is the note as plain text. Writes need
+// ADMIN_TOKEN as a bearer token when it is set.
+func (a *apiServer) notes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	l, ok := a.annotated(w, r)
+	if !ok {
+		return
+	}
+	note := ""
+	if r.Method == http.MethodPut {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxNoteBytes+1))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(body) > maxNoteBytes {
+			http.Error(w, fmt.Sprintf("note longer than %d bytes", maxNoteBytes), http.StatusRequestEntityTooLarge)
+			return
+		}
+		note = strings.TrimSpace(string(body))
+	}
+	if err := a.pg.SetNote(l.URL, note); err != nil {
+		a.fail(w, err)
+		return
+	}
+	a.writeAnnotated(w, l)
+}
+
+// maxNoteBytes caps the size of a note sent to /api/notes.
+const maxNoteBytes = 8 << 10
+
+// annotated authorizes a tag or note write and finds the listing given by
+// the short_id query param, answering the request itself when it cannot.
+func (a *apiServer) annotated(w http.ResponseWriter, r *http.Request) (*models.Listing, bool) {
+	if token := a.cfg.AdminToken; token != "" &&
+		subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	id := strings.TrimSpace(r.URL.Query().Get("short_id"))
+	if id == "" {
+		http.Error(w, "missing short_id", http.StatusBadRequest)
+		return nil, false
+	}
+	all, err := a.pg.FetchAll()
+	if err != nil {
+		a.fail(w, err)
+		return nil, false
+	}
+	l := findStored(all, id)
+	if l == nil {
+		http.Error(w, fmt.Sprintf("%q is not a stored listing", id), http.StatusNotFound)
+		return nil, false
+	}
+	return l, true
+}
+
+// writeAnnotated answers a tag or note write with the listing's current
+// tags and note.
+func (a *apiServer) writeAnnotated(w http.ResponseWriter, l *models.Listing) {
+	if err := a.pg.AttachTags([]*models.Listing{l}); err != nil {
+		a.fail(w, err)
+		return
+	}
+	writeJSON(w, map[string]any{"short_id": l.ShortID, "tags": l.Tags, "note": l.Note})
+}
+
+// scope returns the stored listings with their tags and notes, or only
+// those of the saved view given by the view query param, answering the
+// request itself when it cannot.
+func (a *apiServer) scope(w http.ResponseWriter, r *http.Request) ([]*models.Listing, bool) {
+	var listings []*models.Listing
+	var err error
+	if view := r.URL.Query().Get("view"); view != "" {
+		listings, err = a.pg.ViewListings(view)
+		if errors.Is(err, storage.ErrViewNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return nil, false
+		}
+	} else if listings, err = a.pg.FetchAll(); err == nil {
+		err = a.pg.AttachTags(listings)
+	}
+	if err != nil {
+		a.fail(w, err)
+		return nil, false
+	}
+	return listings, true
+}
+
+func (a *apiServer) fail(w http.ResponseWriter, err error) {
+	a.logger.Error("[api] %v", err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdTag annotates stored listings for review. Listings are short IDs, room
+// IDs or URLs; tags are single words and stay attached to the listing's URL
+// across runs. `query --tag`, `export --tag` and /api/listings?tag= filter
+// on them.
+//
+//	tag add 7K2M-Q9XD shortlisted quiet
+//	tag rm 7K2M-Q9XD quiet
+//	tag note 7K2M-Q9XD "Host offers 10% off weekly stays"   # no text clears
+//	tag list [TAG]
+func cmdTag(cfg *config.Config, logger *utils.Logger, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: tag add|rm ID TAG... | tag note ID [TEXT] | tag list [TAG]")
+	}
+	action, args := args[0], args[1:]
+	switch action {
+	case "add", "rm":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: tag %s ID TAG...", action)
+		}
+	case "note":
+		if len(args) < 1 {
+			return fmt.Errorf("usage: tag note ID [TEXT]")
+		}
+	case "list":
+		if len(args) > 1 {
+			return fmt.Errorf("usage: tag list [TAG]")
+		}
+	default:
+		return fmt.Errorf("unknown tag action %q (want add, rm, note or list)", action)
+	}
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+	if err := pg.EnsureTagTables(); err != nil {
+		return err
+	}
+	saved, err := pg.FetchAll()
+	if err != nil {
+		return err
+	}
+
+	if action == "list" {
+		if err := pg.AttachTags(saved); err != nil {
+			return err
+		}
+		tag := ""
+		if len(args) == 1 {
+			if tag, err = storage.NormalizeTag(args[0]); err != nil {
+				return err
+			}
+		}
+		printTagged(saved, tag)
+		return nil
+	}
+
+	l := findStored(saved, args[0])
+	if l == nil {
+		return fmt.Errorf("%q is not a stored listing", args[0])
+	}
+	switch action {
+	case "note":
+		note := strings.TrimSpace(strings.Join(args[1:], " "))
+		if err := pg.SetNote(l.URL, note); err != nil {
+			return err
+		}
+		if note == "" {
+			logger.Info("[tag] Note cleared on %s", l.ShortID)
+		} else {
+			logger.Info("[tag] Note set on %s", l.ShortID)
+		}
+		return nil
+	}
+
+	tags := make([]string, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		tag, err := storage.NormalizeTag(arg)
+		if err != nil {
+			return err
+		}
+		tags = append(tags, tag)
+	}
+	if action == "add" {
+		if err := pg.AddTags(l.URL, tags); err != nil {
+			return err
+		}
+		logger.Info("[tag] %s tagged %s", l.ShortID, strings.Join(tags, ", "))
+		return nil
+	}
+	n, err := pg.RemoveTags(l.URL, tags)
+	if err != nil {
+		return err
+	}
+	logger.Info("[tag] Removed %d tags from %s", n, l.ShortID)
+	return nil
+}
+
+// printTagged prints the listings with tags or a note, or only those
+// tagged tag, followed by the tag totals.
+func printTagged(listings []*models.Listing, tag string) {
+	counts := make(map[string]int)
+	n := 0
+	fmt.Printf("\n%-9s  %-30s  %-24s  %s\n", "ID", "TITLE", "TAGS", "NOTE")
+	for _, l := range listings {
+		for _, t := range l.Tags {
+			counts[t]++
+		}
+		if (len(l.Tags) == 0 && l.Note == "") || (tag != "" && !slices.Contains(l.Tags, tag)) {
+			continue
+		}
+		n++
+		fmt.Printf("%-9s  %-30s  %-24s  %s\n", l.ShortID, clip(l.Title, 30), clip(strings.Join(l.Tags, ","), 24), l.Note)
+	}
+
+	names := make([]string, 0, len(counts))
+	for t := range counts {
+		names = append(names, t)
+	}
+	sort.Strings(names)
+	totals := make([]string, len(names))
+	for i, t := range names {
+		totals[i] = fmt.Sprintf("%s (%d)", t, counts[t])
+	}
+	fmt.Printf("\n%d listings", n)
+	if len(totals) > 0 {
+		fmt.Printf(" | tags: %s", strings.Join(totals, ", "))
+	}
+	fmt.Print("\n\n")
+}
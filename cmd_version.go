@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/utils"
+)
+
+// cmdVersion prints the build metadata stamped at link time (see
+// utils.BuildVersion), falling back to the VCS revision. The same string is
+// logged at startup and recorded on every run in the runs table.
+func cmdVersion(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	b := utils.Build()
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			utils.BuildInfo
+			Go string `json:"go"`
+		}{b, runtime.Version()})
+	}
+	fmt.Printf("version: %s\ncommit:  %s\nbuilt:   %s\ngo:      %s\n", b.Version, b.Commit, b.Date, runtime.Version())
+	return nil
+}
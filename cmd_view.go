@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdView manages saved views: named listing filters stored in the
+// database, which `query --view`, `export --view`, `watch --view`,
+// REPORT_VIEWS and the API's ?view= refer to by name.
+//
+//	view save bangkok-cheap --location Bangkok --max-price 60 --min-rating 4.7
+//	view list
+//	view rm bangkok-cheap
+func cmdView(cfg *config.Config, logger *utils.Logger, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: view save NAME [filter flags] | view list | view rm NAME")
+	}
+	action, args := args[0], args[1:]
+	var name string
+	var q storage.ListingQuery
+	switch action {
+	case "save":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: view save NAME [filter flags]")
+		}
+		name = args[0]
+		fs := flag.NewFlagSet("view save", flag.ContinueOnError)
+		queryFlags(fs, &q, 0)
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if err := normalizeQuery(&q); err != nil {
+			return err
+		}
+	case "rm":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: view rm NAME")
+		}
+		name = args[0]
+	case "list":
+	default:
+		return fmt.Errorf("unknown view action %q (want save, list or rm)", action)
+	}
+	if name != "" {
+		if err := storage.CheckViewName(name); err != nil {
+			return err
+		}
+	}
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+	if err := pg.EnsureViewTable(); err != nil {
+		return err
+	}
+
+	switch action {
+	case "save":
+		if err := pg.SaveView(name, q); err != nil {
+			return err
+		}
+		logger.Info("[view] Saved %s: %s", name, q)
+	case "rm":
+		if err := pg.DeleteView(name); err != nil {
+			return err
+		}
+		logger.Info("[view] Deleted %s", name)
+	case "list":
+		views, err := pg.Views()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n%-20s  %-16s  %s\n", "VIEW", "UPDATED", "FILTERS")
+		for _, v := range views {
+			fmt.Printf("%-20s  %-16s  %s\n", clip(v.Name, 20), v.UpdatedAt.Format("2006-01-02 15:04"), v.Query)
+		}
+		fmt.Printf("\n%d views\n\n", len(views))
+	}
+	return nil
+}
+
+// viewListings returns the listings of the saved view name, with a hint
+// to `view list` when there is no such view.
+func viewListings(pg *storage.PostgresWriter, name string) ([]*models.Listing, error) {
+	if err := pg.EnsureViewTable(); err != nil {
+		return nil, err
+	}
+	listings, err := pg.ViewListings(name)
+	if errors.Is(err, storage.ErrViewNotFound) {
+		return nil, fmt.Errorf("%w (see `view list`)", err)
+	}
+	return listings, err
+}
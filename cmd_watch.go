@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/scraper/airbnb"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdWatch re-scrapes a fixed set of room pages on an interval, without
+// discovery, and stores every price, availability or rating change in
+// watch_history. Availability changes and price moves of at least
+// --min-change percent are logged as alerts and passed to
+// HOOK_WATCH_CHANGE; the first check of a listing only records its
+// baseline. The comp-set listings (COMPSET_*) are watched too. Each check
+// also stores the availability calendar of the room pages, which
+// `calendar` (or WATCH_ICAL_DIR) turns into iCal files. --view adds the
+// listings of a saved view, as matched at start-up.
+//
+//	watch --urls watch.txt --interval 6h
+//	watch --view bangkok-cheap --min-change 10
+//	watch --once --min-change 5      # one check, e.g. from cron
+func cmdWatch(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	path := fs.String("urls", cfg.WatchURLsPath, "file of room URLs or IDs, one per line")
+	interval := fs.Duration("interval", cfg.WatchInterval, "time between checks")
+	minChange := fs.Float64("min-change", cfg.WatchMinChange, "smallest price move in percent that alerts (0 = any)")
+	once := fs.Bool("once", false, "check once and exit")
+	view := fs.String("view", "", "also watch the listings of this saved view")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !*once && *interval <= 0 {
+		return fmt.Errorf("invalid --interval %v (want a positive duration)", *interval)
+	}
+	if cfg.HookWatchChange != "" && cfg.HookTimeout <= 0 {
+		return fmt.Errorf("invalid HOOK_TIMEOUT %v (want a positive duration)", cfg.HookTimeout)
+	}
+
+	list, err := utils.LoadIDList(*path)
+	if err != nil {
+		return err
+	}
+	own, comps, err := loadCompSet(cfg)
+	if err != nil {
+		return err
+	}
+	if own != "" {
+		list.Add(own)
+		for _, c := range comps {
+			list.Add(c)
+		}
+	}
+
+	pg, err := storage.OpenPostgres(cfg.DSN())
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+	if *view != "" {
+		listings, err := viewListings(pg, *view)
+		if err != nil {
+			return err
+		}
+		for _, l := range listings {
+			list.Add(l.URL)
+		}
+	}
+	if list.Size() == 0 {
+		return fmt.Errorf("no listings to watch in %q", *path)
+	}
+	var urls []string
+	for _, id := range list.IDs() {
+		urls = append(urls, roomURLFor(cfg, id))
+	}
+	if err := pg.EnsureWatchTable(); err != nil {
+		return err
+	}
+
+	w := &watch{
+		icalDir: cfg.WatchICalDir,
+		logger:  logger,
+		pg:      pg,
+		sc:      airbnb.New(cfg, logger),
+		watcher: services.NewWatcher(*minChange, logger),
+		hooks:   newHooks(cfg, logger),
+	}
+	if *once {
+		return w.check(urls)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	logger.Info("[watch] Watching %d listings every %v", len(urls), *interval)
+	for {
+		started := time.Now()
+		if err := w.check(urls); err != nil {
+			logger.Warn("[watch] Check did not complete: %v", err)
+		}
+		next := started.Add(*interval)
+		logger.Info("[watch] Next check at %s", next.Format("2006-01-02 15:04:05"))
+		select {
+		case <-ctx.Done():
+			logger.Info("[watch] Shutdown requested — exiting")
+			return nil
+		case <-time.After(time.Until(next)):
+		}
+	}
+}
+
+// watch is one watch session's dependencies.
+type watch struct {
+	icalDir string // WATCH_ICAL_DIR
+	logger  *utils.Logger
+	pg      *storage.PostgresWriter
+	sc      *airbnb.Scraper
+	watcher *services.Watcher
+	hooks   *hooks
+}
+
+// check scrapes every URL once, stores the changes since the last recorded
+// state and alerts on them. Pages that fail to load are skipped, so a
+// blocked check is not mistaken for a change.
+func (w *watch) check(urls []string) error {
+	prev, err := w.pg.LatestWatchStates()
+	if err != nil {
+		return err
+	}
+
+	var changes []*models.WatchChange
+	failed := 0
+	for _, c := range w.sc.FetchComparison(urls) {
+		removed := errors.Is(c.Err, airbnb.ErrListingRemoved)
+		if c.Err != nil && !removed {
+			failed++
+			w.logger.Warn("[watch] %s: %v", c.URL, c.Err)
+			continue
+		}
+		raw := c.Listing
+		if raw == nil {
+			raw = &models.RawListing{URL: c.URL, ScrapedAt: time.Now()}
+		}
+		if c.Details != nil {
+			if err := w.pg.RecordWatchCalendar(c.URL, c.Details.Calendar, raw.ScrapedAt); err != nil {
+				return err
+			}
+		}
+		change := w.watcher.Diff(prev[c.URL], w.watcher.State(raw, removed))
+		if change == nil {
+			continue
+		}
+		changes = append(changes, change)
+		if change.Previous == nil {
+			w.logger.Info("[watch] %s — %s", c.URL, services.DescribeWatchChange(*change))
+			continue
+		}
+		if !w.watcher.Alert(change) {
+			w.logger.Debug("[watch] %s — %s", c.URL, services.DescribeWatchChange(*change))
+			continue
+		}
+		w.logger.Warn("[watch] ALERT: %s — %s (%s)", change.Current.Title, services.DescribeWatchChange(*change), c.URL)
+		if err := w.hooks.watchChange(change); err != nil {
+			w.logger.Error("%v", err)
+		}
+	}
+
+	if err := w.pg.RecordWatchChanges(changes); err != nil {
+		return err
+	}
+	w.logger.Info("[watch] Checked %d listings: %d changes recorded, %d failed", len(urls)-failed, len(changes), failed)
+	if w.icalDir == "" {
+		return nil
+	}
+	_, err = writeCalendars(w.pg, w.icalDir, w.logger)
+	return err
+}
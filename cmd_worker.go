@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// cmdWorker is the container entrypoint: it waits for PostgreSQL and the
+// WAIT_FOR addresses on the DB_WAIT_* back-off, then runs the pipeline
+// once, or every --schedule interval as the scheduler does. The compose
+// profiles `worker` and `scheduler` start it in either mode.
+//
+//	worker                   # wait, scrape once, exit
+//	worker --schedule 6h     # wait, then scrape every 6h until SIGTERM
+//	worker --wait-only       # exit 0 once the dependencies are up
+func cmdWorker(cfg *config.Config, logger *utils.Logger, args []string) error {
+	fs := flag.NewFlagSet("worker", flag.ContinueOnError)
+	schedule := fs.Duration("schedule", cfg.ScheduleInterval, "run on this interval instead of once (0 = once)")
+	waitOnly := fs.Bool("wait-only", false, "exit once the dependencies are ready")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *schedule < 0 {
+		return fmt.Errorf("invalid --schedule %v (want 0 or a positive duration)", *schedule)
+	}
+
+	deps := []utils.Dependency{storage.PostgresDependency(cfg.DSN())}
+	for _, addr := range cfg.WaitFor {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("invalid WAIT_FOR address %q: %w", addr, err)
+		}
+		deps = append(deps, utils.TCPDependency(addr))
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	logger.Info("[worker] Waiting for %d dependencies (up to %v)", len(deps), cfg.DBWaitTimeout)
+	err := utils.WaitFor(ctx, dbWait(cfg), logger, deps...)
+	stop()
+	if err != nil {
+		return err
+	}
+	logger.Info("[worker] Dependencies ready")
+	if *waitOnly {
+		return nil
+	}
+
+	cfg.ScheduleInterval = *schedule
+	startPipeline(cfg, logger)
+	return nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/scraper/airbnb"
+	"airbnb-scraper/utils"
+)
+
+// command is a CLI subcommand: `airbnb-scraper <name> [flags]`.
+// Running with no subcommand performs a full scrape.
+type command struct {
+	summary string
+	run     func(cfg *config.Config, logger *utils.Logger, args []string) error
+}
+
+var commands = map[string]command{
+	"backfill":    {"Re-extract archived room pages (WARC) and fill missing columns of stored listings", cmdBackfill},
+	"bench":       {"Time Clean and Generate on synthetic 10k/100k-listing datasets, with allocations", cmdBench},
+	"budget":      {"List stored listings within a nightly budget and minimum rating", cmdBudget},
+	"calendar":    {"Write iCal files of watched listings' blocked and available days (from watch)", cmdCalendar},
+	"compare":     {"Scrape listings (URLs, room IDs or short IDs) and compare price, fees, rating, capacity and amenities side by side", cmdCompare},
+	"compset":     {"Weekly report of your listing's price, occupancy and rating against its competitor set (from watch)", cmdCompSet},
+	"db":          {"Vacuum, analyze and reindex the database and report table sizes (db maintain | db sizes)", cmdDB},
+	"explore":     {"Interactive shell to filter, sort, group and open stored listings", cmdExplore},
+	"export":      {"Export stored listings to CSV, optionally in the Inside Airbnb layout (--profile insideairbnb)", cmdExport},
+	"fingerprint": {"Diff a detail page's structure against the previous run to spot redesigns", cmdFingerprint},
+	"init":        {"First-run setup: write .env interactively, then check the browser, database and a one-listing scrape", cmdInit},
+	"import":      {"Load external listing datasets (Inside Airbnb CSV, JSON) through the Cleaner", cmdImport},
+	"locations":   {"List the stored listings' locations with counts, or fuzzy-match one (locations bangrak → Bang Rak)", cmdLocations},
+	"mocksite":    {"Serve the mock Airbnb site used by the end-to-end tests (point AIRBNB_BASE_URL at it)", cmdMocksite},
+	"open":        {"Open report entries (open -n 3 top-rated) or listings by short ID in the browser", cmdOpen},
+	"preflight":   {"Check that Chrome launches, AIRBNB_BASE_URL is reachable, the database is writable and output dirs are writable", cmdPreflight},
+	"project":     {"List projects or switch the selected one (project list | project use NAME)", cmdProject},
+	"query":       {"Print stored listings matching filters, e.g. query --location Bangkok --max-price 80 --sort price", cmdQuery},
+	"schema":      {"Print the JSON Schemas of the JSON outputs, or check a file (schema validate listing FILE)", cmdSchema},
+	"serve":       {"Serve stored listings and the insight report as a JSON API", cmdServe},
+	"tag":         {"Tag and annotate stored listings (tag add ID TAG... | tag rm ID TAG... | tag note ID TEXT | tag list [TAG])", cmdTag},
+	"version":     {"Print the scraper version, commit and build date", cmdVersion},
+	"view":        {"Save named listing filters for query, export, watch and reports (view save NAME --max-price 60 | view list | view rm NAME)", cmdView},
+	"watch":       {"Re-scrape the listings in a file on an interval, store price/availability changes and alert on them", cmdWatch},
+	"worker":      {"Container entrypoint: wait for PostgreSQL (DB_WAIT_*, WAIT_FOR), then scrape once or every --schedule", cmdWorker},
+}
+
+func runCommand(cfg *config.Config, logger *utils.Logger, name string, args []string) error {
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage()
+		return nil
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		printUsage()
+		return fmt.Errorf("unknown command %q", name)
+	}
+	return cmd.run(cfg, logger, args)
+}
+
+// globalFlags are accepted before any command and shape the config itself:
+// --env NAME selects a profile (.env.NAME) and --set KEY=VALUE overrides a
+// single setting above every file and the environment. --demo runs the
+// pipeline on the embedded sample pages instead of the live site. --search
+// QUERY, repeatable, scrapes the search results of each query instead of
+// the homepage (DISCOVERY_MODE=search). --no-emoji drops emoji from
+// banners, reports and logs; --plain also drops colours, as NO_COLOR does.
+type globalFlags struct {
+	profile   string
+	overrides map[string]string
+	demo      bool
+	plain     bool
+	noEmoji   bool
+}
+
+// parseGlobalFlags consumes leading global flags and returns the remaining
+// arguments (command and its flags).
+func parseGlobalFlags(args []string) (globalFlags, []string, error) {
+	g := globalFlags{overrides: make(map[string]string)}
+	bools := map[string]*bool{"demo": &g.demo, "plain": &g.plain, "no-emoji": &g.noEmoji}
+	var searches []string
+	var err error
+	for len(args) > 0 {
+		name, value, inline := strings.Cut(strings.TrimLeft(args[0], "-"), "=")
+		if !strings.HasPrefix(args[0], "-") || (name != "env" && name != "set" && name != "search" && bools[name] == nil) {
+			break
+		}
+		args = args[1:]
+		if b := bools[name]; b != nil {
+			if !inline {
+				*b = true
+			} else if *b, err = strconv.ParseBool(value); err != nil {
+				return g, nil, fmt.Errorf("flag --%s: %w", name, err)
+			}
+			continue
+		}
+		if !inline {
+			if len(args) == 0 {
+				return g, nil, fmt.Errorf("flag --%s needs a value", name)
+			}
+			value, args = args[0], args[1:]
+		}
+		if name == "env" {
+			g.profile = value
+			continue
+		}
+		if name == "search" {
+			searches = append(searches, value)
+			continue
+		}
+		key, val, err := config.ParseOverride(value)
+		if err != nil {
+			return g, nil, err
+		}
+		g.overrides[key] = val
+	}
+	if len(searches) > 0 {
+		g.overrides["DISCOVERY_MODE"] = "search"
+		g.overrides["SEARCH_QUERY"] = strings.Join(searches, airbnb.SearchQuerySeparator+" ")
+	}
+	return g, args, nil
+}
+
+func printUsage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(os.Stderr, "Usage: %s [--env PROFILE] [--set KEY=VALUE]... [--search QUERY]... [--demo] [--plain | --no-emoji] [command] [flags]\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "With no command, runs the full scrape → clean → store → report pipeline\n")
+	fmt.Fprintf(os.Stderr, "(--demo: on embedded sample pages, no network or database).\n")
+	fmt.Fprintf(os.Stderr, "--plain prints without colours or emoji (also NO_COLOR=1 for colours); --no-emoji keeps colours.\n\nCommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for command flags.\n", os.Args[0])
+}
@@ -1,15 +1,26 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds all application configuration loaded from environment variables.
 type Config struct {
+	// Project scopes tables (a PostgreSQL schema) and output files so
+	// several searches can share one install; empty is the default project.
+	Project string
+
+	// Profile is the named config profile (APP_ENV or --env) layered over
+	// .env; empty when none is selected.
+	Profile string
+
 	PostgresHost     string
 	PostgresPort     string
 	PostgresUser     string
@@ -17,39 +28,455 @@ type Config struct {
 	PostgresDB       string
 	PostgresSSLMode  string
 
+	// Waiting for PostgreSQL to accept connections, e.g. while its
+	// container starts: the first retry after DBWaitBackoff, doubling up to
+	// DBWaitMaxBackoff, giving up after DBWaitTimeout. The worker also
+	// waits for the WaitFor host:port addresses before starting.
+	DBWaitTimeout    time.Duration
+	DBWaitBackoff    time.Duration
+	DBWaitMaxBackoff time.Duration
+	WaitFor          []string
+
+	// Preflight checks the browser, network, database and output
+	// directories before the pipeline starts, failing fast on any of them.
+	Preflight bool
+
 	MaxConcurrency  int
+	CleanWorkers    int
+	CleanStrict     bool // drop listings whose price or location did not parse
+	PipelineBuffer  int
+	DBBatchSize     int
+	DBFlushInterval time.Duration
 	RateLimitMs     int
 	MaxRetries      int
+	RetryBudget     int // total retries allowed per run; 0 = unlimited
+	StaleAfterRuns  int // healthy runs a listing may be missing before it is stale
+	ChurnWeeks      int // weeks of new/disappeared listings in the report; 0 = off
 	PagesToScrape   int
 	ListingsPerPage int
 
-	CSVOutputPath string
-	ChromeBin     string
+	// CheckIn and CheckOut (YYYY-MM-DD, both or neither) and Guests are the
+	// stay that search results and detail pages are priced for; empty and
+	// 0 leave them to Airbnb's defaults.
+	CheckIn  string
+	CheckOut string
+	Guests   int
+
+	DiscoveryMode  string
+	SitemapURL     string
+	SitemapFilter  string
+	SitemapMaxURLs int
+	SearchQuery    string
+	SectionFilter  string // regex on discovered section names; "" = all
+
+	// BaseURL is the site root the scraper opens (homepage, search,
+	// allowlisted rooms); end-to-end tests point it at the mock site.
+	BaseURL string
+
+	// Randomness: RandomSeed fixes every random choice (0 = seed from the
+	// clock); RateJitter varies pauses by ±that fraction; UserAgents are
+	// rotated per browser launch; SampleCards picks a random subset of
+	// oversized sections instead of their first cards.
+	RandomSeed  int64
+	RateJitter  float64
+	UserAgents  []string
+	SampleCards bool
+
+	Cities          []string
+	CityParallelism int
+
+	SimilarCrawlDepth int
+	SimilarCrawlLimit int
+
+	BlocklistPath    string
+	AllowlistPath    string
+	PriorityListPath string // tracked listings, enriched before bulk discovery
+
+	// VisitedPath persists the visited-URL set across runs ("" = per run);
+	// a URL is scraped again once its entry is older than VisitedTTL
+	// (0 = never).
+	VisitedPath string
+	VisitedTTL  time.Duration
+	// VisitedBloomSize > 0 tracks visited URLs in a bloom filter sized for
+	// that many URLs instead of an exact set, bounding memory on
+	// sitemap-scale crawls at VisitedBloomFPRate false positives.
+	VisitedBloomSize   int
+	VisitedBloomFPRate float64
+
+	ScrapeWindow     string
+	ScheduleInterval time.Duration
+	AdminAddr        string // daemon-mode admin endpoint; "" = off
+	AdminToken       string
+
+	// Watch mode re-scrapes the listings in WatchURLsPath every
+	// WatchInterval and alerts on availability changes and on price moves
+	// of at least WatchMinChange percent. With WatchICalDir set each check
+	// also rewrites one .ics availability calendar per listing there.
+	WatchURLsPath  string
+	WatchInterval  time.Duration
+	WatchMinChange float64
+	WatchICalDir   string
+
+	// CompSetListing is the host's own room (URL or ID) and CompSetPath a
+	// file of its competitors, one per line; `watch` checks both and
+	// `compset` compares them over the last CompSetWeeks weeks.
+	CompSetListing string
+	CompSetPath    string
+	CompSetWeeks   int
+
+	Landmarks        []Landmark
+	LandmarkRadiusKm float64
+	ClusterEpsKm     float64
+	ClusterMinPoints int
+
+	ForecastWeeks     int
+	ForecastMinPoints int
+
+	OccupancyReviewRate  float64 // share of stays that leave a review
+	OccupancyAverageStay float64 // nights per stay
+	OccupancyCap         float64 // highest review-based occupancy estimate
+
+	ReportViews []string // saved views printed as their own reports after each run
+
+	// Printed reports show prices in ReportCurrency, converted from the
+	// scraped currency at ReportCurrencyRate, formatted for ReportLocale.
+	ReportLocale       string
+	ReportCurrency     string
+	ReportCurrencyRate float64
+
+	AnomalyPriceChange float64
+	AnomalyCountDrop   float64
+	AnomalyMinListings int
+
+	RetentionPolicy string // "target=action:age,…"; "" keeps everything
+
+	AnonymizeSalt string // HMAC key for anonymized export IDs; "" = random per export
+
+	CanaryURLs     []string
+	CanaryMinPrice float64
+	CanaryMaxPrice float64
+
+	MinFieldConfidence float64
+	FingerprintPath    string
+
+	ScoringConfigPath string
+	APIAddr           string
+
+	CSVOutputPath  string
+	DeadLetterPath string
+	CSVSanitize    bool
+	CSVDelimiter   string
+	CSVBOM         bool
+	CSVQuoting     string
+	WARCOutputPath string
+	ChromeBin      string
+
+	// DownloadImages saves each listing's gallery photos under
+	// ImagesPath/<short ID>/, ImageConcurrency downloads at a time.
+	DownloadImages   bool
+	ImagesPath       string
+	ImageConcurrency int
+
+	// RunManifestPath receives a JSON summary of each run (config, timings,
+	// counts, errors, outputs) for orchestration tools; "" disables it.
+	RunManifestPath string
+
+	// Hooks are shell commands run with a JSON document on stdin: the
+	// redacted config before a run (a failure skips the run), the run
+	// manifest after it and each cleaned listing before it is stored;
+	// HookWatchChange gets each change `watch` alerts on.
+	HookPreRun      string
+	HookPostRun     string
+	HookListing     string
+	HookWatchChange string
+	HookTimeout     time.Duration
+
+	// WatchdogFactor force-closes a browser tab still busy after this many
+	// times its page-load timeout (wedged tabs can ignore the deadline);
+	// 0 disables the watchdog.
+	WatchdogFactor float64
+	// JobTimeout frees a detail-page worker whose whole job (every retry
+	// included) is still running after it; 0 disables.
+	JobTimeout time.Duration
+
+	// Page-load caps, a cost guardrail for metered proxies: per run and per
+	// rolling hour (shared by scheduled runs); 0 = unlimited.
+	MaxPagesPerRun  int
+	MaxPagesPerHour int
+
+	// BlockResources are request classes (image, font, media, stylesheet,
+	// analytics) failed before sending during detail-page loads; "none"
+	// blocks nothing.
+	BlockResources []string
+
+	// BrowserCacheDir persists Chrome's disk cache across browsers and runs
+	// so static assets are not re-downloaded; "none" keeps the cache in the
+	// browser's throwaway profile.
+	BrowserCacheDir string
+	BrowserCacheMB  int
+
+	// Rate-limit cool-down: a 429 or throttle page pauses every worker for
+	// CooldownBase, doubling on repeats up to CooldownMax; 0 disables.
+	CooldownBase time.Duration
+	CooldownMax  time.Duration
+
+	// Warm-up: before scraping, browse WarmupPaths, accept the cookie
+	// banner and set currency/locale, so the session looks like a visitor's.
+	Warmup         bool
+	WarmupPaths    []string
+	WarmupCurrency string
+	WarmupLocale   string
+
+	// Auto-tune: start detail pages at AutoTuneStart concurrency and step
+	// up to MaxConcurrency while the success rate over each AutoTuneWindow
+	// pages stays at or above AutoTuneMinSuccess and throughput improves.
+	AutoTune           bool
+	AutoTuneStart      int
+	AutoTuneMinSuccess float64
+	AutoTuneWindow     int
+
+	// Export columns, in output order; empty keeps each export's defaults.
+	RawCSVFields    []string
+	ShortlistFields []string
+	APIFields       []string
 }
 
-// Load reads the .env file and returns a populated Config struct.
-func Load() *Config {
+// Load reads the .env file and returns a populated Config struct. It fails
+// when a *_FILE or *_COMMAND secret source is set but cannot be read.
+func Load() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		log.Println("[config] No .env file found, falling back to system env vars")
 	}
+	return fromEnv()
+}
+
+// FromEnv returns a Config from the process environment alone, without
+// reading .env — for programs embedding the scraper as a library.
+func FromEnv() (*Config, error) {
+	return fromEnv()
+}
+
+// Reload re-reads the .env file and the active profile and returns a fresh
+// Config. The process environment and command-line overrides still win, as
+// at startup. Used by the daemon on SIGHUP.
+func Reload() (*Config, error) {
+	if err := reloadFiles(); err != nil {
+		return nil, err
+	}
+	cfg, err := fromEnv()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Profile = loadState.profile
+	return cfg, nil
+}
+
+func fromEnv() (*Config, error) {
+	secrets := &secretReader{}
+	cfg := &Config{
+		Project: selectedProject(),
 
-	return &Config{
 		PostgresHost:     getEnv("POSTGRES_HOST", "localhost"),
 		PostgresPort:     getEnv("POSTGRES_PORT", "5432"),
-		PostgresUser:     getEnv("POSTGRES_USER", "scraper"),
-		PostgresPassword: getEnv("POSTGRES_PASSWORD", "scraper123"),
+		PostgresUser:     secrets.get("POSTGRES_USER", "scraper"),
+		PostgresPassword: secrets.get("POSTGRES_PASSWORD", "scraper123"),
 		PostgresDB:       getEnv("POSTGRES_DB", "rental_db"),
 		PostgresSSLMode:  getEnv("POSTGRES_SSLMODE", "disable"),
 
+		DBWaitTimeout:    getEnvDuration("DB_WAIT_TIMEOUT", 30*time.Second),
+		DBWaitBackoff:    getEnvDuration("DB_WAIT_BACKOFF", time.Second),
+		DBWaitMaxBackoff: getEnvDuration("DB_WAIT_MAX_BACKOFF", 8*time.Second),
+		WaitFor:          getEnvList("WAIT_FOR"),
+
+		Preflight: getEnvBool("PREFLIGHT", true),
+
 		MaxConcurrency:  getEnvInt("MAX_CONCURRENCY", 3),
+		CleanWorkers:    getEnvInt("CLEAN_WORKERS", 0),
+		CleanStrict:     getEnvBool("CLEAN_STRICT", false),
+		PipelineBuffer:  getEnvInt("PIPELINE_BUFFER", 4),
+		DBBatchSize:     getEnvInt("DB_BATCH_SIZE", 100),
+		DBFlushInterval: getEnvDuration("DB_FLUSH_INTERVAL", 2*time.Second),
 		RateLimitMs:     getEnvInt("RATE_LIMIT_MS", 2000),
 		MaxRetries:      getEnvInt("MAX_RETRIES", 3),
+		RetryBudget:     getEnvInt("RETRY_BUDGET", 50),
+		StaleAfterRuns:  getEnvInt("STALE_AFTER_RUNS", 3),
+		ChurnWeeks:      getEnvInt("CHURN_WEEKS", 4),
 		PagesToScrape:   getEnvInt("PAGES_TO_SCRAPE", 2),
 		ListingsPerPage: getEnvInt("LISTINGS_PER_PAGE", 5),
 
-		CSVOutputPath: getEnv("CSV_OUTPUT_PATH", "./output/raw_listings.csv"),
-		ChromeBin:     getEnv("CHROME_BIN", ""),
+		CheckIn:  getEnv("CHECK_IN", ""),
+		CheckOut: getEnv("CHECK_OUT", ""),
+		Guests:   getEnvInt("GUESTS", 0),
+
+		DiscoveryMode:  getEnv("DISCOVERY_MODE", "homepage"),
+		SitemapURL:     getEnv("SITEMAP_URL", "https://www.airbnb.com/sitemap-master-index.xml.gz"),
+		SitemapFilter:  getEnv("SITEMAP_FILTER", ""),
+		SitemapMaxURLs: getEnvInt("SITEMAP_MAX_URLS", 500),
+		SearchQuery:    getEnv("SEARCH_QUERY", ""),
+		SectionFilter:  getEnv("SECTION_FILTER", ""),
+
+		BaseURL: getEnv("AIRBNB_BASE_URL", "https://www.airbnb.com"),
+
+		RandomSeed:  int64(getEnvInt("RANDOM_SEED", 0)),
+		RateJitter:  getEnvFloat("RATE_JITTER", 0),
+		UserAgents:  getEnvSplit("USER_AGENTS", "|"), // user agents contain commas
+		SampleCards: getEnvBool("SAMPLE_CARDS", false),
+
+		Cities:          getEnvList("CITIES"),
+		CityParallelism: getEnvInt("CITY_PARALLELISM", 1),
+
+		SimilarCrawlDepth: getEnvInt("SIMILAR_CRAWL_DEPTH", 0),
+		SimilarCrawlLimit: getEnvInt("SIMILAR_CRAWL_LIMIT", 50),
+
+		BlocklistPath:    getEnv("BLOCKLIST_PATH", "./config/blocklist.txt"),
+		AllowlistPath:    getEnv("ALLOWLIST_PATH", ""),
+		PriorityListPath: getEnv("PRIORITY_LIST_PATH", ""),
+
+		VisitedPath: getEnv("VISITED_PATH", ""),
+		VisitedTTL:  getEnvDuration("VISITED_TTL", 0),
+
+		VisitedBloomSize:   getEnvInt("VISITED_BLOOM_SIZE", 0),
+		VisitedBloomFPRate: getEnvFloat("VISITED_BLOOM_FP_RATE", 0.001),
+
+		ScrapeWindow:     getEnv("SCRAPE_WINDOW", ""),
+		ScheduleInterval: getEnvDuration("SCHEDULE_INTERVAL", 0),
+		AdminAddr:        getEnv("ADMIN_ADDR", ""),
+		AdminToken:       secrets.get("ADMIN_TOKEN", ""),
+
+		WatchURLsPath:  getEnv("WATCH_URLS_PATH", "./watch.txt"),
+		WatchInterval:  getEnvDuration("WATCH_INTERVAL", 6*time.Hour),
+		WatchMinChange: getEnvFloat("WATCH_MIN_CHANGE", 0),
+		WatchICalDir:   getEnv("WATCH_ICAL_DIR", ""),
+
+		CompSetListing: getEnv("COMPSET_LISTING", ""),
+		CompSetPath:    getEnv("COMPSET_PATH", "./compset.txt"),
+		CompSetWeeks:   getEnvInt("COMPSET_WEEKS", 8),
+
+		Landmarks:        parseLandmarks(os.Getenv("LANDMARKS")),
+		LandmarkRadiusKm: getEnvFloat("LANDMARK_RADIUS_KM", 2),
+		ClusterEpsKm:     getEnvFloat("CLUSTER_EPS_KM", 1),
+		ClusterMinPoints: getEnvInt("CLUSTER_MIN_POINTS", 3),
+
+		ForecastWeeks:     getEnvInt("FORECAST_WEEKS", 4),
+		ForecastMinPoints: getEnvInt("FORECAST_MIN_POINTS", 3),
+
+		OccupancyReviewRate:  getEnvFloat("OCCUPANCY_REVIEW_RATE", 0.5),
+		OccupancyAverageStay: getEnvFloat("OCCUPANCY_AVERAGE_STAY", 3),
+		OccupancyCap:         getEnvFloat("OCCUPANCY_CAP", 0.7),
+
+		ReportViews: getEnvList("REPORT_VIEWS"),
+
+		ReportLocale:       getEnv("REPORT_LOCALE", "en-US"),
+		ReportCurrency:     getEnv("REPORT_CURRENCY", "USD"),
+		ReportCurrencyRate: getEnvFloat("REPORT_CURRENCY_RATE", 1),
+
+		AnomalyPriceChange: getEnvFloat("ANOMALY_PRICE_CHANGE", 0.4),
+		AnomalyCountDrop:   getEnvFloat("ANOMALY_COUNT_DROP", 0.5),
+		AnomalyMinListings: getEnvInt("ANOMALY_MIN_LISTINGS", 3),
+
+		RetentionPolicy: getEnv("RETENTION_POLICY", ""),
+
+		AnonymizeSalt: secrets.get("ANONYMIZE_SALT", ""),
+
+		CanaryURLs:     getEnvList("CANARY_URLS"),
+		CanaryMinPrice: getEnvFloat("CANARY_MIN_PRICE", 10),
+		CanaryMaxPrice: getEnvFloat("CANARY_MAX_PRICE", 5000),
+
+		MinFieldConfidence: getEnvFloat("MIN_FIELD_CONFIDENCE", 0.5),
+		FingerprintPath:    getEnv("FINGERPRINT_PATH", "./output/page_fingerprint.json"),
+
+		ScoringConfigPath: getEnv("SCORING_CONFIG_PATH", "./config/scoring.yaml"),
+		APIAddr:           getEnv("API_ADDR", ":8080"),
+
+		CSVOutputPath:  getEnv("CSV_OUTPUT_PATH", "./output/raw_listings.csv"),
+		DeadLetterPath: getEnv("DEAD_LETTER_PATH", "./output/dead_letter.ndjson"),
+		CSVSanitize:    getEnvBool("CSV_SANITIZE", true),
+		CSVDelimiter:   getEnv("CSV_DELIMITER", "comma"),
+		CSVBOM:         getEnvBool("CSV_BOM", false),
+		CSVQuoting:     getEnv("CSV_QUOTING", "minimal"),
+		WARCOutputPath: getEnv("WARC_OUTPUT_PATH", ""),
+		ChromeBin:      getEnv("CHROME_BIN", ""),
+
+		DownloadImages:   getEnvBool("DOWNLOAD_IMAGES", false),
+		ImagesPath:       getEnv("IMAGES_PATH", "./output/images"),
+		ImageConcurrency: getEnvInt("IMAGE_CONCURRENCY", 4),
+
+		RunManifestPath: getEnv("RUN_MANIFEST_PATH", "./output/run.json"),
+
+		HookPreRun:      getEnv("HOOK_PRE_RUN", ""),
+		HookPostRun:     getEnv("HOOK_POST_RUN", ""),
+		HookListing:     getEnv("HOOK_LISTING", ""),
+		HookWatchChange: getEnv("HOOK_WATCH_CHANGE", ""),
+		HookTimeout:     getEnvDuration("HOOK_TIMEOUT", 30*time.Second),
+
+		WatchdogFactor: getEnvFloat("WATCHDOG_FACTOR", 3),
+		JobTimeout:     getEnvDuration("JOB_TIMEOUT", 15*time.Minute),
+
+		MaxPagesPerRun:  getEnvInt("MAX_PAGES_PER_RUN", 0),
+		MaxPagesPerHour: getEnvInt("MAX_PAGES_PER_HOUR", 0),
+
+		BlockResources: strings.Split(getEnv("BLOCK_RESOURCES", "image,font,media,analytics"), ","),
+
+		BrowserCacheDir: getEnv("BROWSER_CACHE_DIR", "./output/browser-cache"),
+		BrowserCacheMB:  getEnvInt("BROWSER_CACHE_MB", 512),
+
+		CooldownBase: getEnvDuration("COOLDOWN_BASE", 30*time.Second),
+		CooldownMax:  getEnvDuration("COOLDOWN_MAX", 10*time.Minute),
+
+		Warmup:         getEnvBool("WARMUP", false),
+		WarmupPaths:    strings.Split(getEnv("WARMUP_PATHS", "/,/s/experiences,/help"), ","),
+		WarmupCurrency: getEnv("WARMUP_CURRENCY", "USD"),
+		WarmupLocale:   getEnv("WARMUP_LOCALE", "en"),
+
+		AutoTune:           getEnvBool("AUTOTUNE", false),
+		AutoTuneStart:      getEnvInt("AUTOTUNE_START", 1),
+		AutoTuneMinSuccess: getEnvFloat("AUTOTUNE_MIN_SUCCESS", 0.9),
+		AutoTuneWindow:     getEnvInt("AUTOTUNE_WINDOW", 10),
+
+		RawCSVFields:    getEnvList("RAW_CSV_FIELDS"),
+		ShortlistFields: getEnvList("SHORTLIST_FIELDS"),
+		APIFields:       getEnvList("API_FIELDS"),
+	}
+	if err := secrets.err(); err != nil {
+		return nil, err
 	}
+	if _, _, err := ParseStayDates(cfg.CheckIn, cfg.CheckOut, cfg.Guests); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	cfg.scopeOutputs()
+	return cfg, nil
+}
+
+// Landmark is a named point of interest used for distance enrichment.
+type Landmark struct {
+	Name string
+	Lat  float64
+	Lng  float64
+}
+
+// parseLandmarks reads "Name:lat:lng;Name:lat:lng". Malformed entries are
+// logged and skipped.
+func parseLandmarks(raw string) []Landmark {
+	var out []Landmark
+	for _, entry := range strings.Split(raw, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			log.Printf("[config] Ignoring malformed landmark %q (want Name:lat:lng)", entry)
+			continue
+		}
+		lat, err1 := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		lng, err2 := strconv.ParseFloat(strings.TrimSpace(parts[2]), 64)
+		if err1 != nil || err2 != nil {
+			log.Printf("[config] Ignoring landmark %q: bad coordinates", entry)
+			continue
+		}
+		out = append(out, Landmark{Name: strings.TrimSpace(parts[0]), Lat: lat, Lng: lng})
+	}
+	return out
 }
 
 // DSN returns the PostgreSQL connection string.
@@ -57,9 +484,10 @@ func (c *Config) DSN() string {
 	return "host=" + c.PostgresHost +
 		" port=" + c.PostgresPort +
 		" user=" + c.PostgresUser +
-		" password=" + c.PostgresPassword +
+		" password=" + dsnQuote(c.PostgresPassword) +
 		" dbname=" + c.PostgresDB +
-		" sslmode=" + c.PostgresSSLMode
+		" sslmode=" + c.PostgresSSLMode +
+		" search_path=" + ProjectSchema(c.Project)
 }
 
 func getEnv(key, fallback string) string {
@@ -78,3 +506,49 @@ func getEnvInt(key string, fallback int) int {
 	}
 	return fallback
 }
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if val := os.Getenv(key); val != "" {
+		d, err := time.ParseDuration(val)
+		if err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if val := os.Getenv(key); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err == nil {
+			return b
+		}
+	}
+	return fallback
+}
+
+// getEnvList splits a comma-separated variable, dropping empty entries.
+func getEnvList(key string) []string {
+	return getEnvSplit(key, ",")
+}
+
+// getEnvSplit splits on sep, for lists whose items contain commas.
+func getEnvSplit(key, sep string) []string {
+	var out []string
+	for _, part := range strings.Split(os.Getenv(key), sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	if val := os.Getenv(key); val != "" {
+		f, err := strconv.ParseFloat(val, 64)
+		if err == nil {
+			return f
+		}
+	}
+	return fallback
+}
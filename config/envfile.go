@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// UpdateEnvFile writes values into a dotenv file, keeping its comments and
+// layout: an existing KEY= line is replaced in place, keys the file lacks
+// are appended, and a missing file is created.
+func UpdateEnvFile(path string, values map[string]string) error {
+	var lines []string
+	b, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		lines = strings.Split(strings.TrimRight(string(b), "\n"), "\n")
+	case !os.IsNotExist(err):
+		return fmt.Errorf("config: read %s: %w", path, err)
+	}
+
+	done := make(map[string]bool, len(values))
+	for i, line := range lines {
+		key, _, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || strings.HasPrefix(key, "#") || done[key] {
+			continue
+		}
+		if v, set := values[key]; set {
+			lines[i] = key + "=" + quoteEnvValue(v)
+			done[key] = true
+		}
+	}
+
+	var added []string
+	for k := range values {
+		if !done[k] {
+			added = append(added, k)
+		}
+	}
+	sort.Strings(added)
+	for _, k := range added {
+		lines = append(lines, k+"="+quoteEnvValue(values[k]))
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		return fmt.Errorf("config: write %s: %w", path, err)
+	}
+	return nil
+}
+
+// quoteEnvValue double-quotes values dotenv would otherwise misread:
+// whitespace, comment markers and quotes.
+func quoteEnvValue(v string) string {
+	if !strings.ContainsAny(v, " \t#\"'\\$") {
+		return v
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`)
+	return `"` + r.Replace(v) + `"`
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joho/godotenv"
+)
+
+func TestUpdateEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	orig := "# PostgreSQL\nPOSTGRES_HOST=localhost\n# POSTGRES_USER=commented\nPOSTGRES_USER=scraper\n\nPAGES_TO_SCRAPE=2\n"
+	if err := os.WriteFile(path, []byte(orig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := UpdateEnvFile(path, map[string]string{
+		"POSTGRES_HOST":     "db.internal",
+		"POSTGRES_PASSWORD": `p@ss "word" #$1`,
+		"CHROME_BIN":        "/usr/bin/chromium",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := os.ReadFile(path)
+	want := "# PostgreSQL\nPOSTGRES_HOST=db.internal\n# POSTGRES_USER=commented\nPOSTGRES_USER=scraper\n\nPAGES_TO_SCRAPE=2\n" +
+		"CHROME_BIN=/usr/bin/chromium\nPOSTGRES_PASSWORD=\"p@ss \\\"word\\\" #\\$1\"\n"
+	if string(b) != want {
+		t.Errorf("file =\n%s\nwant\n%s", b, want)
+	}
+
+	env, err := godotenv.Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env["POSTGRES_PASSWORD"] != `p@ss "word" #$1` {
+		t.Errorf("password round trip = %q", env["POSTGRES_PASSWORD"])
+	}
+}
+
+func TestUpdateEnvFileCreates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env.dev")
+	if err := UpdateEnvFile(path, map[string]string{"POSTGRES_DB": "dev"}); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "POSTGRES_DB=dev\n" {
+		t.Errorf("file = %q", b)
+	}
+}
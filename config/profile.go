@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// ProfileEnv names the variable that selects a config profile when no
+// --env flag is given, e.g. APP_ENV=prod.
+const ProfileEnv = "APP_ENV"
+
+var profileNameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,39}$`)
+
+// ProfileFile returns the file holding a profile's settings: .env.<name>,
+// next to .env.
+func ProfileFile(name string) string {
+	return ".env." + name
+}
+
+// loadState remembers how the running Config was layered, so Reload can
+// rebuild it the same way.
+var loadState struct {
+	profile   string
+	overrides map[string]string
+	process   []string // the process environment before any file was read
+}
+
+// LoadProfile builds the Config from layered sources, lowest first:
+// built-in defaults < .env < the profile file < process environment <
+// overrides (command-line --set KEY=VALUE). profile "" falls back to
+// APP_ENV from the environment, then from .env; with none set only .env is
+// read. A selected profile must have its file.
+func LoadProfile(profile string, overrides map[string]string) (*Config, error) {
+	loadState.process = os.Environ()
+	if profile == "" {
+		profile = strings.TrimSpace(os.Getenv(ProfileEnv))
+	}
+	if profile == "" {
+		if env, err := godotenv.Read(); err == nil {
+			profile = strings.TrimSpace(env[ProfileEnv])
+		}
+	}
+	if profile != "" {
+		if !profileNameRegexp.MatchString(profile) {
+			return nil, fmt.Errorf("config: invalid profile name %q", profile)
+		}
+		// godotenv.Load never overrides a variable that is already set, so
+		// loading the profile before .env gives it precedence over .env
+		// while the process environment still wins over both.
+		if err := godotenv.Load(ProfileFile(profile)); err != nil {
+			return nil, fmt.Errorf("config: profile %s: %w", profile, err)
+		}
+	}
+	loadState.profile, loadState.overrides = profile, overrides
+	if err := applyOverrides(overrides); err != nil {
+		return nil, err
+	}
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Profile = profile
+	return cfg, nil
+}
+
+// applyOverrides sets command-line overrides in the environment, above
+// every file.
+func applyOverrides(overrides map[string]string) error {
+	for k, v := range overrides {
+		if err := os.Setenv(k, v); err != nil {
+			return fmt.Errorf("config: --set %s: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// reloadFiles re-reads .env and the active profile file over the values
+// they set before, then re-applies the process environment captured at
+// startup and the command-line overrides, so the layering matches
+// LoadProfile: a file never beats a variable the process was started with.
+func reloadFiles() error {
+	if err := godotenv.Overload(); err != nil {
+		return fmt.Errorf("config: reload .env: %w", err)
+	}
+	if loadState.profile != "" {
+		if err := godotenv.Overload(ProfileFile(loadState.profile)); err != nil {
+			return fmt.Errorf("config: reload profile %s: %w", loadState.profile, err)
+		}
+	}
+	for _, kv := range loadState.process {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			os.Setenv(k, v)
+		}
+	}
+	return applyOverrides(loadState.overrides)
+}
+
+// ParseOverride splits a --set argument of the form KEY=VALUE.
+func ParseOverride(s string) (key, value string, err error) {
+	key, value, ok := strings.Cut(s, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("--set %q: want KEY=VALUE", s)
+	}
+	return key, value, nil
+}
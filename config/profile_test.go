@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadProfileLayering(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".env", "POSTGRES_HOST=dev-db\nPOSTGRES_DB=dev\nPAGES_TO_SCRAPE=2\n")
+	write(".env.prod", "POSTGRES_HOST=prod-db\nPAGES_TO_SCRAPE=10\n")
+
+	chdir(t, dir)
+	for _, k := range []string{"POSTGRES_HOST", "POSTGRES_DB", "PAGES_TO_SCRAPE", "LISTINGS_PER_PAGE"} {
+		t.Setenv(k, "")
+		os.Unsetenv(k)
+	}
+	t.Setenv(ProfileEnv, "prod")
+	t.Setenv("LISTINGS_PER_PAGE", "7")
+
+	cfg, err := LoadProfile("", map[string]string{"PAGES_TO_SCRAPE": "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Profile != "prod" {
+		t.Errorf("Profile = %q, want prod from %s", cfg.Profile, ProfileEnv)
+	}
+	if cfg.PostgresHost != "prod-db" {
+		t.Errorf("POSTGRES_HOST = %q, profile should beat .env", cfg.PostgresHost)
+	}
+	if cfg.PostgresDB != "dev" {
+		t.Errorf("POSTGRES_DB = %q, .env should fill what the profile leaves out", cfg.PostgresDB)
+	}
+	if cfg.ListingsPerPage != 7 {
+		t.Errorf("LISTINGS_PER_PAGE = %d, environment should beat files", cfg.ListingsPerPage)
+	}
+	if cfg.PagesToScrape != 3 {
+		t.Errorf("PAGES_TO_SCRAPE = %d, --set should beat everything", cfg.PagesToScrape)
+	}
+
+	write(".env.prod", "POSTGRES_HOST=prod-db-2\nPAGES_TO_SCRAPE=10\nLISTINGS_PER_PAGE=9\n")
+	cfg, err = Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PostgresHost != "prod-db-2" || cfg.PagesToScrape != 3 || cfg.Profile != "prod" {
+		t.Errorf("after reload: host %q, pages %d, profile %q", cfg.PostgresHost, cfg.PagesToScrape, cfg.Profile)
+	}
+	if cfg.ListingsPerPage != 7 {
+		t.Errorf("after reload: LISTINGS_PER_PAGE = %d, environment should still beat files", cfg.ListingsPerPage)
+	}
+	loadState.profile, loadState.overrides, loadState.process = "", nil, nil
+}
+
+func TestLoadProfileErrors(t *testing.T) {
+	chdir(t, t.TempDir())
+	if _, err := LoadProfile("staging", nil); err == nil {
+		t.Error("missing profile file: expected error")
+	}
+	if _, err := LoadProfile("../prod", nil); err == nil {
+		t.Error("path-like profile name: expected error")
+	}
+}
+
+func TestLoadProfileFromDotEnv(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	t.Setenv(ProfileEnv, "")
+	t.Setenv("POSTGRES_SSLMODE", "")
+	os.Unsetenv("POSTGRES_SSLMODE")
+	if err := os.WriteFile(".env", []byte("APP_ENV=staging\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(".env.staging", []byte("POSTGRES_SSLMODE=require\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadProfile("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Profile != "staging" || cfg.PostgresSSLMode != "require" {
+		t.Errorf("profile %q, sslmode %q", cfg.Profile, cfg.PostgresSSLMode)
+	}
+	loadState.profile, loadState.overrides, loadState.process = "", nil, nil
+}
+
+func TestParseOverride(t *testing.T) {
+	k, v, err := ParseOverride("SCRAPE_WINDOW=01:00-06:00")
+	if err != nil || k != "SCRAPE_WINDOW" || v != "01:00-06:00" {
+		t.Errorf("got %q=%q, %v", k, v, err)
+	}
+	if k, v, err := ParseOverride("ANONYMIZE_SALT="); err != nil || k != "ANONYMIZE_SALT" || v != "" {
+		t.Errorf("empty value: got %q=%q, %v", k, v, err)
+	}
+	for _, bad := range []string{"NOVALUE", "=x"} {
+		if _, _, err := ParseOverride(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ProjectFile remembers the project selected with `project use`, so runs
+// stay scoped without exporting PROJECT every time. PROJECT overrides it.
+const ProjectFile = ".project"
+
+// projectSchemaPrefix namespaces project schemas so `project list` can tell
+// them apart from unrelated schemas in the same database.
+const projectSchemaPrefix = "project_"
+
+var projectNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,39}$`)
+
+// ValidateProject checks a project name: lowercase letters, digits and
+// dashes, at most 40 characters. The empty name is the default project.
+func ValidateProject(name string) error {
+	if name == "" || projectNameRegexp.MatchString(name) {
+		return nil
+	}
+	return fmt.Errorf("invalid project name %q (use lowercase letters, digits and dashes, max 40)", name)
+}
+
+// ProjectSchema returns the PostgreSQL schema holding a project's tables;
+// the default project uses the public schema.
+func ProjectSchema(name string) string {
+	if name == "" {
+		return "public"
+	}
+	return projectSchemaPrefix + strings.ReplaceAll(name, "-", "_")
+}
+
+// ProjectFromSchema is the inverse of ProjectSchema. ok is false for
+// schemas that do not belong to a project.
+func ProjectFromSchema(schema string) (name string, ok bool) {
+	if !strings.HasPrefix(schema, projectSchemaPrefix) {
+		return "", false
+	}
+	name = strings.ReplaceAll(strings.TrimPrefix(schema, projectSchemaPrefix), "_", "-")
+	return name, ValidateProject(name) == nil && name != ""
+}
+
+// ProjectPath places an output file in the project's own directory:
+// ./output/raw.csv becomes ./output/<project>/raw.csv. Paths are unchanged
+// for the default project.
+func (c *Config) ProjectPath(path string) string {
+	if c.Project == "" || path == "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(path), c.Project, filepath.Base(path))
+}
+
+// SaveProject records name as the selected project; "" clears the selection.
+func SaveProject(name string) error {
+	if err := ValidateProject(name); err != nil {
+		return err
+	}
+	if name == "" {
+		if err := os.Remove(ProjectFile); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("project: clear selection: %w", err)
+		}
+		return nil
+	}
+	if err := os.WriteFile(ProjectFile, []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("project: save selection: %w", err)
+	}
+	return nil
+}
+
+// selectedProject returns PROJECT, falling back to the saved selection.
+func selectedProject() string {
+	if name := strings.TrimSpace(os.Getenv("PROJECT")); name != "" {
+		return name
+	}
+	b, err := os.ReadFile(ProjectFile)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// scopeOutputs moves every output file under the project's directory,
+// along with the state a run writes back: the visited set and the watch
+// calendars. Input lists and the scoring config stay shared.
+func (c *Config) scopeOutputs() {
+	if ValidateProject(c.Project) != nil {
+		return // reported by the caller; never build paths from a bad name
+	}
+	c.CSVOutputPath = c.ProjectPath(c.CSVOutputPath)
+	c.DeadLetterPath = c.ProjectPath(c.DeadLetterPath)
+	c.WARCOutputPath = c.ProjectPath(c.WARCOutputPath)
+	c.FingerprintPath = c.ProjectPath(c.FingerprintPath)
+	c.RunManifestPath = c.ProjectPath(c.RunManifestPath)
+	c.ImagesPath = c.ProjectPath(c.ImagesPath)
+	c.VisitedPath = c.ProjectPath(c.VisitedPath)
+	c.WatchICalDir = c.ProjectPath(c.WatchICalDir)
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateProject(t *testing.T) {
+	for _, ok := range []string{"", "bangkok-condos", "bali2024"} {
+		if err := ValidateProject(ok); err != nil {
+			t.Errorf("%q: unexpected error %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"Bali", "bali_villas", "../etc", "-x", "a b"} {
+		if err := ValidateProject(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestProjectSchemaRoundTrip(t *testing.T) {
+	if got := ProjectSchema(""); got != "public" {
+		t.Errorf("default schema = %q, want public", got)
+	}
+	schema := ProjectSchema("bangkok-condos")
+	if schema != "project_bangkok_condos" {
+		t.Errorf("schema = %q", schema)
+	}
+	if name, ok := ProjectFromSchema(schema); !ok || name != "bangkok-condos" {
+		t.Errorf("ProjectFromSchema(%q) = %q, %v", schema, name, ok)
+	}
+	for _, s := range []string{"public", "project_", "analytics"} {
+		if _, ok := ProjectFromSchema(s); ok {
+			t.Errorf("%q should not be a project schema", s)
+		}
+	}
+}
+
+func TestProjectPathScopesOutputs(t *testing.T) {
+	c := &Config{
+		Project:        "bali",
+		CSVOutputPath:  "./output/raw_listings.csv",
+		WARCOutputPath: "",
+		ImagesPath:     "./output/images",
+		VisitedPath:    "./output/visited.tsv",
+		WatchICalDir:   "./output/calendars",
+	}
+	c.scopeOutputs()
+	if want := filepath.Join("output", "bali", "raw_listings.csv"); c.CSVOutputPath != want {
+		t.Errorf("csv path = %q, want %q", c.CSVOutputPath, want)
+	}
+	if want := filepath.Join("output", "bali", "images"); c.ImagesPath != want {
+		t.Errorf("images path = %q, want %q", c.ImagesPath, want)
+	}
+	if want := filepath.Join("output", "bali", "visited.tsv"); c.VisitedPath != want {
+		t.Errorf("visited path = %q, want %q", c.VisitedPath, want)
+	}
+	if want := filepath.Join("output", "bali", "calendars"); c.WatchICalDir != want {
+		t.Errorf("calendar dir = %q, want %q", c.WatchICalDir, want)
+	}
+	if c.WARCOutputPath != "" {
+		t.Errorf("disabled WARC path should stay empty, got %q", c.WARCOutputPath)
+	}
+
+	def := &Config{CSVOutputPath: "./output/raw_listings.csv"}
+	def.scopeOutputs()
+	if def.CSVOutputPath != "./output/raw_listings.csv" {
+		t.Errorf("default project changed path to %q", def.CSVOutputPath)
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ScoringWeights controls the composite listing score. Weights are relative;
+// a zero weight drops that component. The distance component only applies
+// when a reference point is set and the listing has coordinates.
+type ScoringWeights struct {
+	Price    float64
+	Rating   float64
+	Reviews  float64
+	Distance float64
+
+	PointLat float64
+	PointLng float64
+	HasPoint bool
+}
+
+// DefaultScoringWeights favours price and rating equally, with review volume
+// as a tie-breaker.
+func DefaultScoringWeights() ScoringWeights {
+	return ScoringWeights{Price: 0.4, Rating: 0.4, Reviews: 0.2}
+}
+
+// LoadScoringWeights reads weights from a YAML file such as:
+//
+//	weights:
+//	  price: 0.4
+//	  rating: 0.4
+//	  reviews: 0.1
+//	  distance: 0.1
+//	point:
+//	  lat: 13.7563
+//	  lng: 100.5018
+//
+// A missing file yields the defaults.
+func LoadScoringWeights(path string) (ScoringWeights, error) {
+	w := DefaultScoringWeights()
+	if path == "" {
+		return w, nil
+	}
+	values, err := readFlatYAML(path)
+	if os.IsNotExist(err) {
+		return w, nil
+	}
+	if err != nil {
+		return w, fmt.Errorf("scoring: %w", err)
+	}
+
+	fields := map[string]*float64{
+		"weights.price":    &w.Price,
+		"weights.rating":   &w.Rating,
+		"weights.reviews":  &w.Reviews,
+		"weights.distance": &w.Distance,
+		"point.lat":        &w.PointLat,
+		"point.lng":        &w.PointLng,
+	}
+	for key, dst := range fields {
+		raw, ok := values[key]
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return w, fmt.Errorf("scoring: %s: %q is not a number", key, raw)
+		}
+		if v < 0 && strings.HasPrefix(key, "weights.") {
+			return w, fmt.Errorf("scoring: %s must not be negative", key)
+		}
+		*dst = v
+	}
+	_, hasLat := values["point.lat"]
+	_, hasLng := values["point.lng"]
+	w.HasPoint = hasLat && hasLng
+	return w, nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadScoringWeights(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scoring.yaml")
+	content := `# composite score
+weights:
+  price: 0.5   # cheaper is better
+  rating: 0.3
+  distance: 0.2
+point:
+  lat: 13.7563
+  lng: "100.5018"
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := LoadScoringWeights(path)
+	if err != nil {
+		t.Fatalf("LoadScoringWeights: %v", err)
+	}
+	if w.Price != 0.5 || w.Rating != 0.3 || w.Distance != 0.2 {
+		t.Errorf("weights: got %+v", w)
+	}
+	if w.Reviews != DefaultScoringWeights().Reviews {
+		t.Errorf("unset weight should keep default, got %.2f", w.Reviews)
+	}
+	if !w.HasPoint || w.PointLng != 100.5018 {
+		t.Errorf("point: got %+v", w)
+	}
+}
+
+func TestLoadScoringWeightsMissingFile(t *testing.T) {
+	w, err := LoadScoringWeights(filepath.Join(t.TempDir(), "absent.yaml"))
+	if err != nil || w != DefaultScoringWeights() {
+		t.Errorf("missing file: got %+v, %v", w, err)
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// secretCommandTimeout bounds how long a *_COMMAND secret helper may run.
+const secretCommandTimeout = 10 * time.Second
+
+// getSecret resolves a credential without requiring it in plain env, in
+// order of precedence:
+//
+//	KEY_FILE     path to a file holding the value (Docker/Kubernetes secrets)
+//	KEY_COMMAND  shell command printing the value (vault, pass, aws ssm …)
+//	KEY          plain environment variable
+//
+// Trailing newlines are trimmed. A source that is set but fails is an
+// error rather than a reason to try the next one, so a broken mount never
+// silently yields the plain variable or the default password.
+func getSecret(key, fallback string) (string, error) {
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("config: %s_FILE: %w", key, err)
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+	if cmd := os.Getenv(key + "_COMMAND"); cmd != "" {
+		out, err := runSecretCommand(cmd)
+		if err != nil {
+			return "", fmt.Errorf("config: %s_COMMAND: %w", key, err)
+		}
+		return out, nil
+	}
+	return getEnv(key, fallback), nil
+}
+
+// secretReader resolves the secrets of one config load, collecting the
+// failures so fromEnv can report them together.
+type secretReader struct {
+	errs []error
+}
+
+func (r *secretReader) get(key, fallback string) string {
+	v, err := getSecret(key, fallback)
+	if err != nil {
+		r.errs = append(r.errs, err)
+	}
+	return v
+}
+
+func (r *secretReader) err() error {
+	return errors.Join(r.errs...)
+}
+
+func runSecretCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretCommandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// dsnQuote quotes a libpq connection-string value so passwords read from
+// secret stores may contain spaces, quotes or backslashes.
+func dsnQuote(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
+
+// redactedMask replaces a set secret in Redacted copies.
+const redactedMask = "********"
+
+// Redacted returns a copy of c with credentials masked, safe to write to
+// logs and run manifests. Unset secrets stay empty so the copy still shows
+// which ones were configured.
+func (c *Config) Redacted() Config {
+	out := *c
+	for _, v := range []*string{&out.PostgresUser, &out.PostgresPassword, &out.AdminToken, &out.AnonymizeSalt} {
+		if *v != "" {
+			*v = redactedMask
+		}
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetSecretSources(t *testing.T) {
+	const key = "TEST_SECRET_VALUE"
+	if got, err := getSecret(key, "fallback"); err != nil || got != "fallback" {
+		t.Errorf("unset: got %q, %v", got, err)
+	}
+	t.Setenv(key, "from-env")
+	if got, err := getSecret(key, "fallback"); err != nil || got != "from-env" {
+		t.Errorf("env: got %q, %v", got, err)
+	}
+
+	t.Setenv(key+"_COMMAND", "printf 'from-command\\n'")
+	if got, err := getSecret(key, "fallback"); err != nil || got != "from-command" {
+		t.Errorf("command: got %q, %v", got, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte("p@ss word\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(key+"_FILE", path)
+	if got, err := getSecret(key, "fallback"); err != nil || got != "p@ss word" {
+		t.Errorf("file: got %q, %v", got, err)
+	}
+}
+
+func TestGetSecretFailingSource(t *testing.T) {
+	const key = "TEST_SECRET_VALUE"
+	t.Setenv(key, "from-env")
+
+	// A broken source is an error, never the next source or the default.
+	t.Setenv(key+"_COMMAND", "exit 3")
+	if got, err := getSecret(key, "fallback"); err == nil || got != "" {
+		t.Errorf("failing command: got %q, %v", got, err)
+	}
+	t.Setenv(key+"_FILE", filepath.Join(t.TempDir(), "missing"))
+	if got, err := getSecret(key, "fallback"); err == nil || got != "" {
+		t.Errorf("missing file: got %q, %v", got, err)
+	}
+}
+
+func TestFromEnvFailingSecret(t *testing.T) {
+	t.Setenv("POSTGRES_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+	cfg, err := FromEnv()
+	if err == nil || !strings.Contains(err.Error(), "POSTGRES_PASSWORD_FILE") {
+		t.Fatalf("FromEnv error = %v, want POSTGRES_PASSWORD_FILE", err)
+	}
+	if cfg != nil {
+		t.Errorf("FromEnv returned a config with a failed secret: %+v", cfg.Redacted())
+	}
+}
+
+func TestDSNQuotesPassword(t *testing.T) {
+	c := &Config{PostgresPassword: `it's a \secret`}
+	want := ` password='it\'s a \\secret' `
+	if dsn := c.DSN(); !strings.Contains(dsn, want) {
+		t.Errorf("DSN %q does not contain %q", dsn, want)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := &Config{PostgresHost: "db", PostgresPassword: "hunter2", AdminToken: "tok"}
+	r := cfg.Redacted()
+	if r.PostgresPassword != redactedMask || r.AdminToken != redactedMask {
+		t.Errorf("secrets not masked: %+v", r)
+	}
+	if r.AnonymizeSalt != "" {
+		t.Errorf("unset secret = %q, want empty", r.AnonymizeSalt)
+	}
+	if r.PostgresHost != "db" {
+		t.Errorf("PostgresHost = %q, want db", r.PostgresHost)
+	}
+	if cfg.PostgresPassword != "hunter2" {
+		t.Error("Redacted modified the original config")
+	}
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StayDateLayout is the date format of CHECK_IN and CHECK_OUT.
+const StayDateLayout = "2006-01-02"
+
+// ParseStayDates reads CHECK_IN, CHECK_OUT (YYYY-MM-DD, both or neither)
+// and GUESTS. Without dates both times are zero. Config loading runs it, so
+// every command and the library fail on a bad stay instead of scraping
+// Airbnb's default dates.
+func ParseStayDates(checkIn, checkOut string, guests int) (in, out time.Time, err error) {
+	if guests < 0 {
+		return in, out, fmt.Errorf("invalid GUESTS %d (want 0 or more)", guests)
+	}
+	checkIn, checkOut = strings.TrimSpace(checkIn), strings.TrimSpace(checkOut)
+	if checkIn == "" && checkOut == "" {
+		return in, out, nil
+	}
+	if checkIn == "" || checkOut == "" {
+		return in, out, fmt.Errorf("CHECK_IN and CHECK_OUT must be set together")
+	}
+	if in, err = time.Parse(StayDateLayout, checkIn); err != nil {
+		return in, out, fmt.Errorf("invalid CHECK_IN %q (want YYYY-MM-DD)", checkIn)
+	}
+	if out, err = time.Parse(StayDateLayout, checkOut); err != nil {
+		return in, out, fmt.Errorf("invalid CHECK_OUT %q (want YYYY-MM-DD)", checkOut)
+	}
+	if !out.After(in) {
+		return in, out, fmt.Errorf("CHECK_OUT %s is not after CHECK_IN %s", checkOut, checkIn)
+	}
+	return in, out, nil
+}
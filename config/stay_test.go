@@ -0,0 +1,23 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFromEnvRejectsBadStay(t *testing.T) {
+	t.Setenv("CHECK_IN", "2026-13-01")
+	t.Setenv("CHECK_OUT", "2026-12-05")
+	if _, err := FromEnv(); err == nil || !strings.Contains(err.Error(), "CHECK_IN") {
+		t.Errorf("FromEnv error = %v, want invalid CHECK_IN", err)
+	}
+
+	t.Setenv("CHECK_IN", "2026-12-01")
+	cfg, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv with a valid stay: %v", err)
+	}
+	if cfg.CheckIn != "2026-12-01" || cfg.CheckOut != "2026-12-05" {
+		t.Errorf("stay = %s → %s", cfg.CheckIn, cfg.CheckOut)
+	}
+}
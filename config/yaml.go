@@ -0,0 +1,59 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// readFlatYAML parses the small YAML subset used by this project's config
+// files: "key: value" pairs, one level of nesting by indentation, and "#"
+// comments. Nested keys are returned dotted ("weights.price"). Lists and
+// multi-line values are not supported.
+func readFlatYAML(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	out := make(map[string]string)
+	parent := ""
+	lineNo := 0
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		lineNo++
+		raw := sc.Text()
+		if i := strings.Index(raw, "#"); i >= 0 {
+			raw = raw[:i]
+		}
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		indented := raw[0] == ' ' || raw[0] == '\t'
+		key, val, ok := strings.Cut(strings.TrimSpace(raw), ":")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected \"key: value\"", path, lineNo)
+		}
+		key = strings.TrimSpace(key)
+		val = strings.Trim(strings.TrimSpace(val), `"'`)
+
+		switch {
+		case !indented && val == "":
+			parent = key
+		case !indented:
+			parent = ""
+			out[key] = val
+		case parent == "":
+			return nil, fmt.Errorf("%s:%d: indented key without parent", path, lineNo)
+		default:
+			out[parent+"."+key] = val
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// csvFormat builds the CSV dialect shared by every CSV export from config.
+func csvFormat(cfg *config.Config) (storage.CSVFormat, error) {
+	delim, err := storage.ParseCSVDelimiter(cfg.CSVDelimiter)
+	if err != nil {
+		return storage.CSVFormat{}, err
+	}
+	quoteAll, err := storage.ParseCSVQuoting(cfg.CSVQuoting)
+	if err != nil {
+		return storage.CSVFormat{}, err
+	}
+	return storage.CSVFormat{
+		Delimiter: delim,
+		BOM:       cfg.CSVBOM,
+		QuoteAll:  quoteAll,
+		Sanitize:  cfg.CSVSanitize,
+	}, nil
+}
+
+// moneyFormat builds the locale and display currency of printed reports
+// from config.
+func moneyFormat(cfg *config.Config) (*utils.Money, error) {
+	m, err := utils.NewMoney(cfg.ReportLocale, cfg.ReportCurrency, cfg.ReportCurrencyRate)
+	if err != nil {
+		return nil, fmt.Errorf("REPORT_LOCALE / REPORT_CURRENCY: %w", err)
+	}
+	return m, nil
+}
@@ -0,0 +1,38 @@
+// Package demo bundles a small sample of scraped listings, which --demo
+// uses in place of scraping the embedded sample pages when no browser is
+// installed.
+package demo
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+// Source is the provenance strategy recorded on demo listings.
+const Source = "demo"
+
+//go:embed listings.json
+var listingsJSON []byte
+
+// Listings returns the bundled raw listings as a scrape at the given time
+// would have produced them: three cities' worth of cards with the price,
+// rating and review formats the live extractor sees, plus one duplicate
+// card. Each call returns fresh copies.
+func Listings(at time.Time) ([]*models.RawListing, error) {
+	var out []*models.RawListing
+	if err := json.Unmarshal(listingsJSON, &out); err != nil {
+		return nil, fmt.Errorf("demo: decode listings: %w", err)
+	}
+	for _, l := range out {
+		l.ScrapedAt = at
+		l.SchemaVersion = models.RawSchemaVersion
+		l.SetSource("price", Source, models.ConfidenceHigh, at)
+		l.SetSource("rating", Source, models.ConfidenceHigh, at)
+		l.SetSource("location", Source, models.ConfidenceHigh, at)
+	}
+	return out, nil
+}
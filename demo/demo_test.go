@@ -0,0 +1,47 @@
+package demo
+
+import (
+	"testing"
+	"time"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/services"
+	"airbnb-scraper/utils"
+)
+
+func TestListingsClean(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	raw, err := Listings(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(raw) != 25 {
+		t.Fatalf("got %d raw listings, want 25", len(raw))
+	}
+	for _, r := range raw {
+		if !r.ScrapedAt.Equal(at) || r.SchemaVersion != models.RawSchemaVersion {
+			t.Fatalf("%s: ScrapedAt %v, schema %d", r.URL, r.ScrapedAt, r.SchemaVersion)
+		}
+	}
+
+	cleaned := services.NewCleaner(utils.NewLogger()).Clean(raw)
+	if len(cleaned) != 24 {
+		t.Fatalf("got %d cleaned listings, want 24 (one duplicate dropped)", len(cleaned))
+	}
+	locations := make(map[string]int)
+	for _, l := range cleaned {
+		if l.Price <= 0 {
+			t.Errorf("%s: price did not parse", l.Title)
+		}
+		locations[l.Location]++
+	}
+	if len(locations) != 3 {
+		t.Errorf("locations = %v, want 3", locations)
+	}
+
+	again, _ := Listings(at)
+	again[0].Title = "changed"
+	if raw[0].Title == "changed" {
+		t.Error("Listings should return fresh copies")
+	}
+}
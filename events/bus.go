@@ -0,0 +1,113 @@
+// Package events is the in-process bus on which the scraper core announces
+// what happened — listings scraped, sections completed, failures, the end
+// of a run — so cross-cutting features (run manifest, metrics, notifiers,
+// live streams) subscribe instead of being called from the core.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+// Event is one of the event types below.
+type Event interface {
+	event()
+}
+
+// ListingScraped is published for each listing once its detail page has
+// been merged in, just before its section is handed to the pipeline.
+type ListingScraped struct {
+	Section string
+	Listing *models.RawListing
+}
+
+// SectionCompleted is published when a section's listings have been handed
+// to the pipeline.
+type SectionCompleted struct {
+	Section  string
+	Listings int
+	Total    int // listings so far in this scraper's run
+}
+
+// ErrorOccurred is published for each detail-page failure.
+type ErrorOccurred struct {
+	URL   string
+	Class string // the scraper's failure class, e.g. "timeout"
+	Err   error
+}
+
+// RunFinished is published once per pipeline run, after every stage.
+type RunFinished struct {
+	Started  time.Time
+	Finished time.Time
+	Err      error // nil for a successful run
+}
+
+func (ListingScraped) event()   {}
+func (SectionCompleted) event() {}
+func (ErrorOccurred) event()    {}
+func (RunFinished) event()      {}
+
+// Bus delivers each published event to every subscriber, synchronously and
+// in subscription order, on the publishing goroutine: handlers must be
+// quick and hand slow work off themselves. It is safe for concurrent use,
+// and a nil *Bus drops every event.
+type Bus struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   []subscriber
+}
+
+type subscriber struct {
+	id int
+	fn func(Event)
+}
+
+// NewBus returns a bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers fn for every event and returns a function that
+// removes it.
+func (b *Bus) Subscribe(fn func(Event)) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	id := b.nextID
+	b.subs = append(b.subs, subscriber{id: id, fn: fn})
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, s := range b.subs {
+			if s.id == id {
+				b.subs = append(b.subs[:i:i], b.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// On subscribes fn to the events of type T only.
+func On[T Event](b *Bus, fn func(T)) (unsubscribe func()) {
+	return b.Subscribe(func(e Event) {
+		if t, ok := e.(T); ok {
+			fn(t)
+		}
+	})
+}
+
+// Publish delivers e to the current subscribers.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	subs := b.subs
+	b.mu.RUnlock()
+	for _, s := range subs {
+		s.fn(e)
+	}
+}
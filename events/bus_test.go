@@ -0,0 +1,57 @@
+package events
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"airbnb-scraper/models"
+)
+
+func TestBusDelivers(t *testing.T) {
+	b := NewBus()
+	var all []Event
+	var sections []string
+	b.Subscribe(func(e Event) { all = append(all, e) })
+	stop := On(b, func(e SectionCompleted) { sections = append(sections, e.Section) })
+
+	b.Publish(ListingScraped{Section: "Paris", Listing: &models.RawListing{URL: "u"}})
+	b.Publish(SectionCompleted{Section: "Paris", Listings: 1, Total: 1})
+	stop()
+	b.Publish(SectionCompleted{Section: "Rome", Listings: 2, Total: 3})
+	b.Publish(ErrorOccurred{URL: "u", Class: "timeout", Err: errors.New("slow")})
+
+	if len(all) != 4 {
+		t.Errorf("catch-all got %d events, want 4", len(all))
+	}
+	if len(sections) != 1 || sections[0] != "Paris" {
+		t.Errorf("typed subscriber got %v, want [Paris] before unsubscribing", sections)
+	}
+}
+
+func TestBusConcurrentPublish(t *testing.T) {
+	b := NewBus()
+	var mu sync.Mutex
+	n := 0
+	On(b, func(ListingScraped) { mu.Lock(); n++; mu.Unlock() })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.Publish(ListingScraped{})
+			}
+		}()
+	}
+	wg.Wait()
+	if n != 800 {
+		t.Errorf("delivered %d events, want 800", n)
+	}
+}
+
+func TestNilBus(t *testing.T) {
+	var b *Bus
+	b.Publish(RunFinished{}) // must not panic
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"airbnb-scraper/scraper/airbnb"
+)
+
+// Exit codes of a pipeline run, so cron, CI and orchestrators can branch on
+// the outcome instead of parsing logs.
+const (
+	exitOK      = 0 // the run completed without failures
+	exitFailed  = 1 // bad settings, a failed hook or canary, or no usable listings
+	exitPartial = 2 // the run completed, but some pages or rows failed
+	exitBlocked = 3 // nothing was scraped and Airbnb blocked or challenged the scraper
+	exitStorage = 4 // PostgreSQL could not be reached or written
+)
+
+// Run outcomes that map to their own exit code; run wraps them.
+var (
+	errPartial = errors.New("run completed with failures")
+	errBlocked = errors.New("scrape blocked")
+	errStorage = errors.New("storage failure")
+)
+
+// exitCode is the process exit code for a run's error.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errStorage):
+		return exitStorage
+	case errors.Is(err, errBlocked):
+		return exitBlocked
+	case errors.Is(err, errPartial):
+		return exitPartial
+	}
+	return exitFailed
+}
+
+// scrapeBlocked reports whether a run's scrape error or detail-page
+// failures show Airbnb refusing the scraper: 403/429 answers or bot
+// challenges.
+func scrapeBlocked(scrapeErr error, failures map[string]int) bool {
+	if errors.Is(scrapeErr, airbnb.ErrBlocked) || errors.Is(scrapeErr, airbnb.ErrBotChallenge) {
+		return true
+	}
+	for class, n := range failures {
+		if n > 0 && (class == "bot-challenge" || strings.HasPrefix(class, "blocked-")) {
+			return true
+		}
+	}
+	return false
+}
+
+// pageFailures counts the detail-page failures that lost data; delisted
+// rooms and the configured page budget are expected outcomes, not failures.
+func pageFailures(failures map[string]int) int {
+	n := 0
+	for class, count := range failures {
+		if class != "removed" && class != "page-budget" {
+			n += count
+		}
+	}
+	return n
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"airbnb-scraper/scraper/airbnb"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"ok", nil, exitOK},
+		{"failed", errors.New("no usable listings"), exitFailed},
+		{"partial", fmt.Errorf("%w: 3 pages failed", errPartial), exitPartial},
+		{"blocked", fmt.Errorf("%w: %w", errBlocked, airbnb.ErrBlocked), exitBlocked},
+		{"storage", fmt.Errorf("%w: connection refused", errStorage), exitStorage},
+		// Storage failures outrank a partial run they happen in.
+		{"storage over partial", fmt.Errorf("%w: %w", errPartial, errStorage), exitStorage},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("%s: exitCode(%v) = %d, want %d", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestScrapeBlocked(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		failures map[string]int
+		want     bool
+	}{
+		{"clean run", nil, nil, false},
+		{"blocked error", fmt.Errorf("homepage: %w", airbnb.ErrBlocked), nil, true},
+		{"bot challenge error", airbnb.ErrBotChallenge, nil, true},
+		{"other error", errors.New("timeout"), nil, false},
+		{"blocked pages", nil, map[string]int{"blocked-429": 2}, true},
+		{"challenged pages", nil, map[string]int{"bot-challenge": 1}, true},
+		{"zero count", nil, map[string]int{"blocked-403": 0}, false},
+		{"network failures", nil, map[string]int{"net-dns": 4, "removed": 1}, false},
+	}
+	for _, tt := range tests {
+		if got := scrapeBlocked(tt.err, tt.failures); got != tt.want {
+			t.Errorf("%s: scrapeBlocked = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPageFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures map[string]int
+		want     int
+	}{
+		{"none", nil, 0},
+		{"expected only", map[string]int{"removed": 3, "page-budget": 5}, 0},
+		{"mixed", map[string]int{"removed": 3, "net-dns": 2, "blocked-429": 1}, 3},
+	}
+	for _, tt := range tests {
+		if got := pageFailures(tt.failures); got != tt.want {
+			t.Errorf("%s: pageFailures = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
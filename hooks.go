@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/events"
+	"airbnb-scraper/models"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// hooks runs the user's HOOK_* shell commands: pre-run, post-run, once per
+// cleaned listing and once per watch alert. Each receives a JSON document on
+// stdin and HOOK set to its kind in the environment; its output is logged
+// under [hook].
+type hooks struct {
+	cfg    *config.Config
+	logger *utils.Logger
+}
+
+func newHooks(cfg *config.Config, logger *utils.Logger) *hooks {
+	return &hooks{cfg: cfg, logger: logger}
+}
+
+// run executes command with input as JSON on stdin, within HOOK_TIMEOUT.
+func (h *hooks) run(kind, command string, input any) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("hook %s: encode input: %w", kind, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), h.cfg.HookTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(), "HOOK="+kind)
+	cmd.WaitDelay = time.Second // don't wait on children still holding the output pipe
+	out, err := cmd.CombinedOutput()
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		if line := strings.TrimSpace(sc.Text()); line != "" {
+			h.logger.Info("[hook] %s: %s", kind, line)
+		}
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("hook %s: timed out after %v", kind, h.cfg.HookTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("hook %s: %w", kind, err)
+	}
+	return nil
+}
+
+// preRun runs HOOK_PRE_RUN with the run's redacted config. A failing
+// pre-run hook aborts the run, so it can veto one (e.g. a maintenance flag).
+func (h *hooks) preRun() error {
+	if h.cfg.HookPreRun == "" {
+		return nil
+	}
+	return h.run("pre_run", h.cfg.HookPreRun, h.cfg.Redacted())
+}
+
+// listen runs HOOK_POST_RUN with the finished run manifest once RunFinished
+// is published. Subscribe it after the run recorder, which completes the
+// manifest. A failure is logged; the run is over either way.
+func (h *hooks) listen(bus *events.Bus, manifest *models.RunManifest) {
+	if h.cfg.HookPostRun == "" {
+		return
+	}
+	events.On(bus, func(events.RunFinished) {
+		if err := h.run("post_run", h.cfg.HookPostRun, manifest); err != nil {
+			h.logger.Error("%v", err)
+		}
+	})
+}
+
+// watchChange runs HOOK_WATCH_CHANGE with a change `watch` alerts on.
+func (h *hooks) watchChange(c *models.WatchChange) error {
+	if h.cfg.HookWatchChange == "" {
+		return nil
+	}
+	return h.run("watch_change", h.cfg.HookWatchChange, c)
+}
+
+// listings runs HOOK_LISTING for each cleaned listing on its way from in
+// to the store, one at a time, so a slow hook slows the pipeline rather
+// than queueing unbounded work. The listing is sent as its export record.
+// Failures are logged and the listing is stored regardless. Without
+// HOOK_LISTING in is returned as is.
+func (h *hooks) listings(in <-chan []*models.Listing, buffer int) <-chan []*models.Listing {
+	if h.cfg.HookListing == "" {
+		return in
+	}
+	out := make(chan []*models.Listing, buffer)
+	go func() {
+		defer close(out)
+		failed := 0
+		for batch := range in {
+			for _, l := range batch {
+				if err := h.run("listing", h.cfg.HookListing, storage.NewRecord(storage.ListingFields, l)); err != nil {
+					failed++
+					h.logger.Warn("%s (%s)", err, l.URL)
+				}
+			}
+			out <- batch
+		}
+		if failed > 0 {
+			h.logger.Warn("[hook] Listing hook failed for %d listings", failed)
+		}
+	}()
+	return out
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// imageTimeout bounds one photo download.
+const imageTimeout = time.Minute
+
+// Two photos match when their perceptual hashes differ in at most
+// photoMatchBits bits; two listings are likely the same place when at least
+// photoDuplicateShare of either one's photos match the other's.
+const (
+	photoMatchBits      = 10
+	photoDuplicateShare = 0.5
+)
+
+// photoDuplicate is a pair of listings under different URLs whose photos
+// mostly match: a relisting, or the same place listed twice.
+type photoDuplicate struct {
+	a, b  *models.Listing
+	share float64
+}
+
+// downloadImages saves the gallery photos of listings under
+// IMAGES_PATH/<short id>/ and returns how many are on disk and how many
+// failed. Failures are logged, never fatal: the photos are a by-product.
+func downloadImages(cfg *config.Config, logger *utils.Logger, listings []*models.Listing) (saved, failed int) {
+	jobs := imageDownloads(cfg.ImagesPath, listings)
+	if len(jobs) == 0 {
+		return 0, 0
+	}
+	logger.Info("[images] Downloading %d photos to %s (%d at a time)", len(jobs), cfg.ImagesPath, cfg.ImageConcurrency)
+	saved, errs := utils.NewDownloader(cfg.ImageConcurrency, imageTimeout).Fetch(context.Background(), jobs)
+	for _, err := range errs {
+		logger.Warn("[images] %v", err)
+	}
+	logger.Info("[images] %d of %d photos saved", saved, len(jobs))
+	return saved, len(errs)
+}
+
+// imageDownloads names each listing's photos 01, 02, ... in gallery order,
+// keeping the extension of their URL.
+func imageDownloads(dir string, listings []*models.Listing) []utils.Download {
+	var jobs []utils.Download
+	for _, l := range listings {
+		if l.ShortID == "" {
+			continue
+		}
+		for i, u := range l.Images {
+			jobs = append(jobs, utils.Download{
+				URL:  u,
+				Path: filepath.Join(dir, l.ShortID, fmt.Sprintf("%02d%s", i+1, imageExt(u))),
+			})
+		}
+	}
+	return jobs
+}
+
+// imageExt is the extension of a photo URL's path, ".jpg" when it has none
+// that looks like one.
+func imageExt(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ".jpg"
+	}
+	ext := path.Ext(u.Path)
+	if len(ext) < 2 || len(ext) > 5 {
+		return ".jpg"
+	}
+	return ext
+}
+
+// photoHashes hashes the downloaded photos of each listing, keyed by
+// ShortID. Files that are missing or cannot be decoded are skipped.
+func photoHashes(dir string, listings []*models.Listing) map[string][]uint64 {
+	hashes := make(map[string][]uint64)
+	for _, l := range listings {
+		if l.ShortID == "" {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(dir, l.ShortID))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			f, err := os.Open(filepath.Join(dir, l.ShortID, e.Name()))
+			if err != nil {
+				continue
+			}
+			h, err := utils.PerceptualHash(f)
+			f.Close()
+			if err == nil {
+				hashes[l.ShortID] = append(hashes[l.ShortID], h)
+			}
+		}
+	}
+	return hashes
+}
+
+// findPhotoDuplicates compares the photo hashes of every pair of listings.
+// URL dedup in the Cleaner cannot see a place relisted under a new URL;
+// matching photos can.
+func findPhotoDuplicates(listings []*models.Listing, hashes map[string][]uint64) []photoDuplicate {
+	var dups []photoDuplicate
+	for i, a := range listings {
+		ha := hashes[a.ShortID]
+		if len(ha) == 0 {
+			continue
+		}
+		for _, b := range listings[i+1:] {
+			hb := hashes[b.ShortID]
+			if len(hb) == 0 || a.URL == b.URL {
+				continue
+			}
+			share := max(utils.HashOverlap(ha, hb, photoMatchBits), utils.HashOverlap(hb, ha, photoMatchBits))
+			if share >= photoDuplicateShare {
+				dups = append(dups, photoDuplicate{a, b, share})
+			}
+		}
+	}
+	return dups
+}
+
+// reportPhotoDuplicates logs the listings whose downloaded photos mostly
+// match and returns how many pairs it found.
+func reportPhotoDuplicates(cfg *config.Config, logger *utils.Logger, listings []*models.Listing) int {
+	dups := findPhotoDuplicates(listings, photoHashes(cfg.ImagesPath, listings))
+	for _, d := range dups {
+		logger.Warn("[images] %s and %s share %.0f%% of their photos — likely the same place", d.a.URL, d.b.URL, d.share*100)
+	}
+	return len(dups)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"airbnb-scraper/models"
+)
+
+// writePhoto saves a 64×64 PNG whose pattern depends on seed.
+func writePhoto(t *testing.T, path string, seed int) {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			img.SetGray(x, y, color.Gray{uint8((x*seed + y*y*(seed+3)) % 256)})
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindPhotoDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	listings := []*models.Listing{
+		{ShortID: "aaa", URL: "https://www.airbnb.com/rooms/1"},
+		{ShortID: "bbb", URL: "https://www.airbnb.com/rooms/2"}, // relisted rooms/1
+		{ShortID: "ccc", URL: "https://www.airbnb.com/rooms/3"},
+		{ShortID: "ddd", URL: "https://www.airbnb.com/rooms/4"}, // no photos
+	}
+	writePhoto(t, filepath.Join(dir, "aaa", "01.png"), 1)
+	writePhoto(t, filepath.Join(dir, "aaa", "02.png"), 5)
+	writePhoto(t, filepath.Join(dir, "bbb", "01.png"), 5)
+	writePhoto(t, filepath.Join(dir, "ccc", "01.png"), 11)
+	if err := os.WriteFile(filepath.Join(dir, "ccc", "02.jpg"), []byte("not an image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	hashes := photoHashes(dir, listings)
+	if len(hashes["aaa"]) != 2 || len(hashes["ccc"]) != 1 || len(hashes["ddd"]) != 0 {
+		t.Fatalf("hashed photos: aaa=%d ccc=%d ddd=%d, want 2, 1, 0",
+			len(hashes["aaa"]), len(hashes["ccc"]), len(hashes["ddd"]))
+	}
+	dups := findPhotoDuplicates(listings, hashes)
+	if len(dups) != 1 || dups[0].a.ShortID != "aaa" || dups[0].b.ShortID != "bbb" {
+		t.Fatalf("duplicates = %+v, want aaa and bbb", dups)
+	}
+	if dups[0].share != 1 {
+		t.Errorf("share = %v, want 1 (bbb's only photo is one of aaa's)", dups[0].share)
+	}
+}
@@ -1,10 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"airbnb-scraper/config"
+	"airbnb-scraper/events"
+	"airbnb-scraper/scraper"
 	"airbnb-scraper/scraper/airbnb"
 	"airbnb-scraper/services"
 	"airbnb-scraper/storage"
@@ -14,17 +20,177 @@ import (
 func main() {
 	// ── Bootstrap ────────────────────────────────────────────────────────────
 	logger := utils.NewLogger()
-	cfg := config.Load()
+	globals, args, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		logger.Error("%v", err)
+		os.Exit(exitFailed)
+	}
+	if globals.plain || globals.noEmoji {
+		utils.SetStyle(!globals.plain && os.Getenv("NO_COLOR") == "", false)
+	}
+	cfg, err := config.LoadProfile(globals.profile, globals.overrides)
+	if err != nil {
+		logger.Error("Failed to load config: %v", err)
+		os.Exit(exitFailed)
+	}
+	if err := config.ValidateProject(cfg.Project); err != nil {
+		logger.Error("Invalid PROJECT: %v", err)
+		os.Exit(exitFailed)
+	}
+	storage.SetConnectWait(dbWait(cfg), logger)
+
+	if globals.demo {
+		if err := runDemo(cfg, logger); err != nil {
+			logger.Error("Demo failed: %v", err)
+			os.Exit(exitFailed)
+		}
+		return
+	}
 
+	if len(args) > 0 {
+		if err := runCommand(cfg, logger, args[0], args[1:]); err != nil {
+			logger.Error("%s: %v", args[0], err)
+			os.Exit(exitFailed)
+		}
+		return
+	}
+
+	startPipeline(cfg, logger)
+}
+
+// dbWait is the back-off for waiting on PostgreSQL (DB_WAIT_*).
+func dbWait(cfg *config.Config) utils.Backoff {
+	return utils.Backoff{Initial: cfg.DBWaitBackoff, Max: cfg.DBWaitMaxBackoff, Timeout: cfg.DBWaitTimeout}
+}
+
+// startPipeline validates the scrape settings and runs the pipeline once,
+// or on SCHEDULE_INTERVAL until shutdown. Invalid settings and a failed
+// single run exit the process.
+func startPipeline(cfg *config.Config, logger *utils.Logger) {
 	logger.Info("=== Airbnb Scraping System starting ===")
+	logger.Info("Version: %s", utils.Version())
+	if cfg.Profile != "" {
+		logger.Info("Profile: %s (%s)", cfg.Profile, config.ProfileFile(cfg.Profile))
+	}
+	if cfg.Project != "" {
+		logger.Info("Project: %s (schema %s, outputs under %s)",
+			cfg.Project, config.ProjectSchema(cfg.Project), filepath.Dir(cfg.CSVOutputPath))
+	}
 	logger.Info("Config — pages: %d | listings/page: %d | concurrency: %d | rate: %dms",
 		cfg.PagesToScrape, cfg.ListingsPerPage, cfg.MaxConcurrency, cfg.RateLimitMs)
 
+	window, err := utils.ParseTimeWindow(cfg.ScrapeWindow)
+	if err != nil {
+		logger.Error("Invalid SCRAPE_WINDOW: %v", err)
+		os.Exit(exitFailed)
+	}
+
+	if _, err := storage.ParseRetentionPolicy(cfg.RetentionPolicy); err != nil {
+		logger.Error("Invalid RETENTION_POLICY: %v", err)
+		os.Exit(exitFailed)
+	}
+
+	if _, err := airbnb.BlockPatterns(cfg.BlockResources); err != nil {
+		logger.Error("Invalid BLOCK_RESOURCES: %v", err)
+		os.Exit(exitFailed)
+	}
+
+	stay, err := airbnb.ParseStay(cfg.CheckIn, cfg.CheckOut, cfg.Guests)
+	if err != nil {
+		logger.Error("Invalid stay: %v", err)
+		os.Exit(exitFailed)
+	}
+	logger.Info("Stay: %s", stay)
+
+	if cfg.AutoTune && (cfg.AutoTuneMinSuccess <= 0 || cfg.AutoTuneMinSuccess > 1) {
+		logger.Error("Invalid AUTOTUNE_MIN_SUCCESS: %v (want a share between 0 and 1)", cfg.AutoTuneMinSuccess)
+		os.Exit(exitFailed)
+	}
+	if cfg.VisitedBloomSize > 0 && (cfg.VisitedBloomFPRate <= 0 || cfg.VisitedBloomFPRate >= 1) {
+		logger.Error("Invalid VISITED_BLOOM_FP_RATE: %v (want a rate between 0 and 1)", cfg.VisitedBloomFPRate)
+		os.Exit(exitFailed)
+	}
+	if cfg.HookTimeout <= 0 && (cfg.HookPreRun != "" || cfg.HookPostRun != "" || cfg.HookListing != "" || cfg.HookWatchChange != "") {
+		logger.Error("Invalid HOOK_TIMEOUT: %v (want a positive duration)", cfg.HookTimeout)
+		os.Exit(exitFailed)
+	}
+	if cfg.VisitedBloomSize > 0 && cfg.VisitedPath != "" {
+		logger.Error("VISITED_BLOOM_SIZE and VISITED_PATH cannot be combined: the bloom filter is not persisted")
+		os.Exit(exitFailed)
+	}
+
+	throttle, err := utils.NewThrottle(throttleSettings(cfg))
+	if err != nil {
+		logger.Error("Invalid throttle settings: %v", err)
+		os.Exit(exitFailed)
+	}
+
+	status := utils.NewStatusBoard()
+	go dumpStatusOnSignal(logger, status)
+	pages := utils.NewPageBudget(cfg.MaxPagesPerRun, cfg.MaxPagesPerHour)
+
+	if cfg.Preflight {
+		if err := preflight(cfg, logger); err != nil {
+			logger.Error("%v", err)
+			os.Exit(exitFailed)
+		}
+	}
+
+	if cfg.ScheduleInterval > 0 {
+		runScheduler(cfg, logger, window, throttle, status, pages)
+		return
+	}
+
+	os.Exit(exitCode(run(context.Background(), cfg, logger, window, throttle, status, pages)))
+}
+
+// run executes one full scrape → clean → store → report cycle. Failures are
+// logged where they happen; the returned error signals that the run did not
+// complete, or wraps errPartial when it did with some pages or rows lost,
+// errBlocked or errStorage (see exitCode). throttle carries the live rate
+// limit, concurrency and section filter; status is reset and kept current
+// for status dumps; pages caps browser page loads, its hourly window
+// spanning runs. Either way RunFinished is published on return, which writes
+// the run manifest. Once ctx is done the window wait and the scrape stop,
+// and what was scraped is still cleaned and stored.
+func run(ctx context.Context, cfg *config.Config, logger *utils.Logger, window *utils.TimeWindow, throttle *utils.Throttle, status *utils.StatusBoard, pages *utils.PageBudget) (err error) {
+	bus := events.NewBus()
+	rec := newRunRecorder(cfg)
+	rec.listen(bus, logger)
+	hooks := newHooks(cfg, logger)
+	hooks.listen(bus, &rec.m)
+	defer func() {
+		bus.Publish(events.RunFinished{Started: rec.m.StartedAt, Finished: time.Now(), Err: err})
+	}()
+	status.Reset()
+	defer status.SetStage("idle")
+
+	status.SetStage("waiting for scrape window")
+	if err := window.Wait(ctx, logger); err != nil {
+		return err
+	}
+	rec.lap("wait")
+	if err := hooks.preRun(); err != nil {
+		logger.Error("Pre-run hook failed — skipping this run: %v", err)
+		return err
+	}
+	status.SetStage("setup")
+
 	// ── CSV writer (raw data) ─────────────────────────────────────────────
-	csvWriter, err := storage.NewCSVWriter(cfg.CSVOutputPath)
+	format, err := csvFormat(cfg)
+	if err != nil {
+		logger.Error("Invalid CSV options: %v", err)
+		return err
+	}
+	money, err := moneyFormat(cfg)
+	if err != nil {
+		logger.Error("Invalid report options: %v", err)
+		return err
+	}
+	csvWriter, err := storage.NewCSVWriter(cfg.CSVOutputPath, format, cfg.RawCSVFields)
 	if err != nil {
 		logger.Error("Failed to create CSV writer: %v", err)
-		os.Exit(1)
+		return err
 	}
 	defer csvWriter.Close()
 
@@ -33,64 +199,378 @@ func main() {
 	if err != nil {
 		logger.Error("Failed to connect to PostgreSQL: %v", err)
 		logger.Error("Make sure Docker is running: docker compose up -d")
-		os.Exit(1)
+		return fmt.Errorf("%w: %w", errStorage, err)
 	}
 	defer pgWriter.Close()
 
-	// ── Scrape ────────────────────────────────────────────────────────────
-	airbnbScraper := airbnb.New(cfg, logger)
-	rawListings, err := airbnbScraper.Scrape()
+	deadLetter, err := storage.NewDeadLetterWriter(cfg.DeadLetterPath)
 	if err != nil {
-		logger.Error("Airbnb scrape failed: %v", err)
-		// Continue with whatever was collected rather than hard-exiting
+		logger.Error("Failed to create dead-letter file: %v", err)
+		return err
 	}
+	defer deadLetter.Close()
+	pgWriter.SetScraperVersion(utils.Version())
 
-	if len(rawListings) == 0 {
-		logger.Error("No listings were scraped. Exiting.")
-		os.Exit(1)
+	// ── Listing blocklist / allowlist ────────────────────────────────────
+	blocklist, err := utils.LoadIDList(cfg.BlocklistPath)
+	if err != nil {
+		logger.Error("Failed to load blocklist: %v", err)
+		return err
+	}
+	allowlist, err := utils.LoadIDList(cfg.AllowlistPath)
+	if err != nil {
+		logger.Error("Failed to load allowlist: %v", err)
+		return err
+	}
+	priority, err := utils.LoadIDList(cfg.PriorityListPath)
+	if err != nil {
+		logger.Error("Failed to load priority list: %v", err)
+		return err
+	}
+	if blocklist.Size() > 0 || allowlist.Size() > 0 || priority.Size() > 0 {
+		logger.Info("Listing filters — blocklisted: %d | allowlisted: %d | prioritised: %d",
+			blocklist.Size(), allowlist.Size(), priority.Size())
+	}
+	visited, err := utils.LoadURLSet(cfg.VisitedPath, cfg.VisitedTTL)
+	if err != nil {
+		logger.Error("Failed to load visited URLs: %v", err)
+		return err
+	}
+	if cfg.VisitedPath != "" && cfg.VisitedTTL > 0 {
+		logger.Info("Visited URLs: %d enriched within the last %v keep their card data, without a detail page", visited.Size(), cfg.VisitedTTL)
+	} else if cfg.VisitedPath != "" {
+		logger.Info("Visited URLs: %d enriched by earlier runs keep their card data, without a detail page", visited.Size())
+	}
+	var seen utils.SeenSet
+	if cfg.VisitedBloomSize > 0 {
+		bloom := utils.NewBloomFilter(cfg.VisitedBloomSize, cfg.VisitedBloomFPRate)
+		logger.Info("Visited URLs tracked in a %.1f MB bloom filter (%d URLs at %.2f%% false positives)",
+			float64(bloom.Bytes())/(1<<20), cfg.VisitedBloomSize, cfg.VisitedBloomFPRate*100)
+		seen = bloom
 	}
 
-	logger.Info("Scraped %d raw listings — writing to CSV …", len(rawListings))
+	// ── WARC archive (optional, raw page captures) ───────────────────────
+	var warcWriter *storage.WARCWriter
+	var warcPath string
+	if cfg.WARCOutputPath != "" {
+		warcPath = storage.RunArchivePath(cfg.WARCOutputPath, rec.m.StartedAt)
+		warcWriter, err = storage.NewWARCWriter(warcPath)
+		if err != nil {
+			logger.Error("Failed to create WARC writer: %v", err)
+			return err
+		}
+		defer warcWriter.Close()
+		logger.Info("Archiving navigated pages to %s", warcPath)
+	}
+	rec.output("raw_csv", cfg.CSVOutputPath)
+	rec.output("dead_letter", cfg.DeadLetterPath)
+	rec.output("warc", warcPath)
 
-	// ── Persist raw data to CSV ───────────────────────────────────────────
-	if err := csvWriter.WriteRaw(rawListings); err != nil {
-		logger.Error("CSV write failed: %v", err)
-	} else {
-		logger.Info("Raw listings saved to %s", cfg.CSVOutputPath)
+	retryBudget := utils.NewRetryBudget(cfg.RetryBudget)
+	cooldown := utils.NewCooldown(cfg.CooldownBase, cfg.CooldownMax)
+	pages.StartRun()
+	if cfg.MaxPagesPerRun > 0 || cfg.MaxPagesPerHour > 0 {
+		logger.Info("Page budget: %s", pages)
+	}
+	rng := utils.NewRandom(cfg.RandomSeed)
+	logger.Info("Random seed: %d (set RANDOM_SEED to repeat this run's choices)", rng.Seed())
+	configure := func(sc *airbnb.Scraper) {
+		sc.SetContext(ctx)
+		sc.SetRandom(rng)
+		sc.SetTimeWindow(window)
+		sc.SetListFilters(blocklist, allowlist)
+		sc.SetPriorityList(priority)
+		if seen != nil {
+			sc.SetSeen(seen)
+		}
+		if cfg.VisitedPath != "" {
+			sc.SetVisited(visited)
+		}
+		sc.SetThrottle(throttle)
+		sc.SetRetryBudget(retryBudget)
+		sc.SetStatus(status)
+		sc.SetPageBudget(pages)
+		sc.SetCooldown(cooldown)
+		if warcWriter != nil {
+			sc.SetArchiver(warcWriter)
+		}
+		sc.SetEvents(bus)
 	}
+	rec.lap("setup")
+	status.Gauge("retries_used", retryBudget.Used)
+	status.Gauge("pages_loaded", pages.Used)
 
-	// ── Clean ────────────────────────────────────────────────────────────
+	// ── Canaries — abort early if extraction is broken ────────────────────
+	status.SetStage("canaries")
+	if err := verifyCanaries(cfg, logger, configure); err != nil {
+		return err
+	}
+	rec.lap("canaries")
+
+	// ── Scrape → CSV → clean → PostgreSQL, streamed ───────────────────────
 	cleaner := services.NewCleaner(logger)
-	cleanListings := cleaner.Clean(rawListings)
+	cleaner.SetWorkers(cfg.CleanWorkers)
+	cleaner.SetStrict(cfg.CleanStrict)
+	status.SetStage("scrape")
+	newScraper := func(c *config.Config) scraper.Scraper {
+		sc := airbnb.New(c, logger)
+		configure(sc)
+		return sc
+	}
+	counts := streamListings(cfg, logger, newScraper, csvWriter, cleaner, hooks, pgWriter, deadLetter, status)
+	if err := visited.Save(); err != nil {
+		logger.Error("Failed to save visited URLs: %v", err)
+	}
+	rec.lap("scrape")
+	rec.m.Counts["raw"] = counts.Raw
+	rec.m.Counts["removed"] = counts.Removed
+	rec.m.Counts["cleaned"] = counts.Cleaned
+	rec.m.Counts["stored"] = counts.Stored
+	rec.m.Counts["dead_lettered"] = counts.DeadLettered
+	rejected := cleaner.Rejected()
+	for reason, n := range rejected {
+		rec.m.Counts["rejected_"+reason] = n
+	}
+	rec.m.Counts["retries_used"] = retryBudget.Used()
+	rec.m.Counts["pages_loaded"] = pages.Used()
+	pauses, paused := cooldown.Stats()
+	rec.m.Counts["cooldowns"] = pauses
+	rec.m.Counts["cooldown_seconds"] = int(paused.Seconds())
 
-	if len(cleanListings) == 0 {
-		logger.Error("All listings were dropped during cleaning. Exiting.")
-		os.Exit(1)
+	rec.mu.Lock()
+	failed, blocked := pageFailures(rec.m.Failures), scrapeBlocked(counts.ScrapeErr, rec.m.Failures)
+	rec.mu.Unlock()
+
+	if counts.Raw == 0 {
+		logger.Error("No listings were scraped. Exiting.")
+		if blocked {
+			return fmt.Errorf("%w: no listings scraped", errBlocked)
+		}
+		return fmt.Errorf("no listings scraped")
 	}
+	logger.Info("Raw listings saved to %s (%d rows)", cfg.CSVOutputPath, counts.Raw)
 
-	logger.Info("Cleaned dataset: %d listings", len(cleanListings))
+	if counts.Cleaned == 0 {
+		logger.Error("All listings were dropped during cleaning. Exiting.")
+		return fmt.Errorf("all listings dropped during cleaning")
+	}
+	logger.Info("Clean listings stored in PostgreSQL (table: listings) — %d of %d rows", counts.Stored, counts.Cleaned)
+	if counts.Stored == 0 {
+		logger.Error("No listings could be stored — see %s", cfg.DeadLetterPath)
+		return fmt.Errorf("%w: none of %d listings stored", errStorage, counts.Cleaned)
+	}
+	if counts.Removed > 0 {
+		logger.Info("%d listings are no longer available — stored with status 'removed'", counts.Removed)
+	}
+	if n := rejected[services.RejectNoPrice] + rejected[services.RejectNoLocation]; n > 0 {
+		logger.Info("%d listings without a price or location were dropped (CLEAN_STRICT)", n)
+	}
+	if counts.DeadLettered > 0 {
+		logger.Warn("%d rows could not be stored — see %s", counts.DeadLettered, cfg.DeadLetterPath)
+	}
+	if retryBudget.Exhausted() {
+		logger.Warn("Retry budget of %d exhausted — enrichment stopped early, later listings carry card data only",
+			retryBudget.Limit())
+	} else if retryBudget.Used() > 0 {
+		logger.Info("Retries used: %d", retryBudget.Used())
+	}
+	if pauses > 0 {
+		logger.Warn("Rate limited %d times — workers paused %v in total", pauses, paused)
+	}
+	if pages.Exhausted() {
+		logger.Warn("Page budget (%s) exhausted after %d page loads — the run ended early", pages, pages.Used())
+	}
 
-	// ── Persist clean data to PostgreSQL ─────────────────────────────────
-	if err := pgWriter.Write(cleanListings); err != nil {
-		logger.Error("PostgreSQL write failed: %v", err)
-	} else {
-		logger.Info("Clean listings stored in PostgreSQL (table: listings)")
+	// ── Merge values recovered by earlier backfills ──────────────────────
+	if n, err := pgWriter.ApplyBackfill(); err != nil {
+		logger.Warn("Failed to apply backfilled values: %v", err)
+	} else if n > 0 {
+		logger.Info("[backfill] Filled %d listings from archived pages", n)
 	}
 
-	// ── Generate insights from the database ──────────────────────────────
+	// ── Load the stored dataset for dataset-wide steps ───────────────────
 	dbListings, err := pgWriter.FetchAll()
 	if err != nil {
-		logger.Error("Failed to fetch listings from DB for insights: %v", err)
-		// Fall back to in-memory cleaned listings
-		dbListings = cleanListings
+		logger.Error("Failed to fetch listings from DB: %v", err)
+		return fmt.Errorf("%w: %w", errStorage, err)
 	}
 
+	// ── Download listing photos ──────────────────────────────────────────
+	if cfg.DownloadImages {
+		status.SetStage("images")
+		saved, failedImages := downloadImages(cfg, logger, dbListings)
+		rec.m.Counts["images_downloaded"] = saved
+		rec.m.Counts["images_failed"] = failedImages
+		rec.m.Counts["photo_duplicates"] = reportPhotoDuplicates(cfg, logger, dbListings)
+		rec.output("images", cfg.ImagesPath)
+		rec.lap("images")
+	}
+
+	// ── Score ────────────────────────────────────────────────────────────
+	status.SetStage("score")
+	weights, err := config.LoadScoringWeights(cfg.ScoringConfigPath)
+	if err != nil {
+		logger.Warn("Scoring config ignored, using defaults: %v", err)
+		weights = config.DefaultScoringWeights()
+	}
+	services.NewScorer(weights, logger).Apply(dbListings)
+	if err := pgWriter.UpdateScores(dbListings); err != nil {
+		logger.Error("Failed to store scores: %v", err)
+	}
+	rec.lap("score")
+
+	// ── Record price history for trend analysis ──────────────────────────
+	status.SetStage("history")
+	var anomalies []string
+	var statusCounts map[string]int
+	if runID, err := pgWriter.RecordRun(dbListings); err != nil {
+		logger.Error("Failed to record run history: %v", err)
+	} else {
+		logger.Info("Price history recorded (run #%d)", runID)
+		rec.m.RunID = runID
+		anomalies = checkRunAnomalies(cfg, logger, pgWriter, runID)
+		rec.m.Anomalies = anomalies
+		statusCounts, err = pgWriter.UpdateLifecycle(runID, len(anomalies) > 0, cfg.StaleAfterRuns)
+		if err != nil {
+			logger.Error("Failed to update listing lifecycle: %v", err)
+		}
+	}
+	applyRetention(cfg, logger, pgWriter)
+	rec.lap("history")
+
+	// ── Generate insights from the database ──────────────────────────────
+	status.SetStage("report")
 	insightSvc := services.NewInsightService(logger)
+	insightSvc.SetLandmarks(cfg.Landmarks, cfg.LandmarkRadiusKm)
+	insightSvc.SetClustering(cfg.ClusterEpsKm, cfg.ClusterMinPoints)
+	insightSvc.SetMinConfidence(cfg.MinFieldConfidence)
+	insightSvc.SetMoney(money)
 	report := insightSvc.Generate(dbListings)
+	report.Anomalies = anomalies
+	report.Removed = counts.Removed
+	report.StatusCounts = statusCounts
+	if cfg.ChurnWeeks > 0 {
+		if churn, err := pgWriter.FetchChurn(cfg.ChurnWeeks); err != nil {
+			logger.Warn("Inventory churn unavailable: %v", err)
+		} else {
+			report.Churn = churn
+		}
+	}
+	if series, err := pgWriter.FetchWeeklyPrices(); err != nil {
+		logger.Warn("Price history unavailable for forecasting: %v", err)
+	} else {
+		report.Forecasts = insightSvc.Forecast(series, cfg.ForecastWeeks, cfg.ForecastMinPoints)
+	}
+	if recs, err := priceRecommendations(cfg, pgWriter, insightSvc, dbListings); err != nil {
+		logger.Warn("Price recommendations unavailable: %v", err)
+	} else {
+		report.Recommendations = recs
+	}
+	if perListing, perLocation, err := occupancyEstimates(cfg, pgWriter, insightSvc, dbListings); err != nil {
+		logger.Warn("Occupancy estimates unavailable: %v", err)
+	} else {
+		report.Occupancy, report.LocationOccupancy = perListing, perLocation
+	}
 
 	// ── Print report ─────────────────────────────────────────────────────
 	insightSvc.Print(report)
+	for _, cityReport := range insightSvc.GeneratePerCity(dbListings, cfg.Cities) {
+		insightSvc.Print(cityReport)
+	}
+	for _, name := range cfg.ReportViews {
+		listings, err := viewListings(pgWriter, name)
+		if err != nil {
+			logger.Warn("Report for view %s unavailable: %v", name, err)
+			continue
+		}
+		viewReport := insightSvc.Generate(listings)
+		viewReport.Scope = "view " + name
+		insightSvc.Print(viewReport)
+	}
+	rec.lap("report")
 
 	fmt.Printf("Done. Raw CSV -> %s | Clean data -> PostgreSQL (listings table)\n\n",
 		cfg.CSVOutputPath)
-}
\ No newline at end of file
+
+	var problems []string
+	if counts.ScrapeErr != nil {
+		problems = append(problems, "scrape stopped early: "+counts.ScrapeErr.Error())
+	}
+	if failed > 0 {
+		problems = append(problems, fmt.Sprintf("%d detail pages failed", failed))
+	}
+	if counts.DeadLettered > 0 {
+		problems = append(problems, fmt.Sprintf("%d rows dead-lettered", counts.DeadLettered))
+	}
+	if len(problems) > 0 {
+		logger.Warn("Run completed with failures: %s", strings.Join(problems, "; "))
+		return fmt.Errorf("%w: %s", errPartial, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// applyRetention prunes or rolls up data older than RETENTION_POLICY allows.
+// It runs after every run, so daemon mode enforces the policy on its schedule.
+func applyRetention(cfg *config.Config, logger *utils.Logger, pg *storage.PostgresWriter) {
+	rules, _ := storage.ParseRetentionPolicy(cfg.RetentionPolicy) // validated at startup
+	if len(rules) == 0 {
+		return
+	}
+	now := time.Now()
+	results, err := pg.ApplyRetention(rules, now)
+	if err != nil {
+		logger.Error("Retention: %v", err)
+	}
+	for _, r := range results {
+		if r.Affected > 0 {
+			logger.Info("Retention: %s %s — %d rows", r.Target, r.Action, r.Affected)
+		}
+	}
+	for _, r := range rules {
+		if r.Target != "archives" || cfg.WARCOutputPath == "" {
+			continue
+		}
+		n, err := storage.PruneArchives(filepath.Dir(cfg.WARCOutputPath), r.MaxAge, now)
+		if err != nil {
+			logger.Error("Retention: %v", err)
+		}
+		if n > 0 {
+			logger.Info("Retention: archives delete — %d files", n)
+		}
+	}
+}
+
+// checkRunAnomalies compares the run with the previous healthy one and marks
+// it suspect when the differences look like extraction breakage.
+func checkRunAnomalies(cfg *config.Config, logger *utils.Logger, pg *storage.PostgresWriter, runID int64) []string {
+	prevID, err := pg.PreviousRunID(runID)
+	if err != nil || prevID == 0 {
+		return nil
+	}
+	prev, err := pg.FetchRunSnapshot(prevID)
+	if err != nil {
+		logger.Warn("Anomaly check skipped: %v", err)
+		return nil
+	}
+	curr, err := pg.FetchRunSnapshot(runID)
+	if err != nil {
+		logger.Warn("Anomaly check skipped: %v", err)
+		return nil
+	}
+
+	detector := services.NewAnomalyDetector(services.AnomalyThresholds{
+		MaxPriceChange:      cfg.AnomalyPriceChange,
+		MaxCountDrop:        cfg.AnomalyCountDrop,
+		MinLocationListings: cfg.AnomalyMinListings,
+	}, logger)
+	anomalies := detector.Compare(prev, curr)
+	if len(anomalies) == 0 {
+		return nil
+	}
+
+	logger.Warn("Run #%d marked SUSPECT — %d anomalies vs run #%d", runID, len(anomalies), prevID)
+	if err := pg.MarkRun(runID, "suspect", anomalies); err != nil {
+		logger.Error("%v", err)
+	}
+	return anomalies
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// PageCapture is a single HTTP request/response exchange recorded from the
+// browser while navigating. It is written to WARC archives for research use.
+type PageCapture struct {
+	URL             string
+	Method          string
+	RequestHeaders  map[string]string
+	StatusCode      int
+	StatusText      string
+	Protocol        string
+	ResponseHeaders map[string]string
+	Body            []byte
+	CapturedAt      time.Time
+}
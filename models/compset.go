@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// CompSetReport compares a host's listing with its competitor set ("comp
+// set") week by week, from the states and calendars `watch` recorded.
+type CompSetReport struct {
+	Listing     string          `json:"listing"`     // the host's room URL
+	Competitors []string        `json:"competitors"` // room URLs
+	GeneratedAt time.Time       `json:"generated_at"`
+	Weeks       []CompSetWeek   `json:"weeks"`  // oldest first; the last one is the current week
+	Latest      []CompSetMember `json:"latest"` // the host's listing first, then the competitors
+}
+
+// CompSetWeek is one calendar week (Monday to Sunday, UTC) of the report.
+type CompSetWeek struct {
+	Start   time.Time    `json:"start"`
+	Own     CompSetStats `json:"own"`
+	CompSet CompSetStats `json:"comp_set"` // mean over the competitors with data
+
+	// PriceIndex is the host's price as a percentage of the comp-set
+	// average (100 = at par); 0 without both prices.
+	PriceIndex float64 `json:"price_index"`
+}
+
+// CompSetStats are one listing's (or the comp-set average's) figures for a
+// week. Zero price or rating means unknown.
+type CompSetStats struct {
+	Price        float64 `json:"price"`         // mean nightly price over the days it was available
+	Occupancy    float64 `json:"occupancy"`     // share of calendar days blocked, 0–1
+	CalendarDays int     `json:"calendar_days"` // days Occupancy is based on; 0 = unknown
+	Rating       float64 `json:"rating"`        // at the end of the week
+	Listings     int     `json:"listings"`      // comp set only: competitors with data
+}
+
+// CompSetMember is the latest known state of one listing of the report.
+type CompSetMember struct {
+	WatchState
+	Occupancy    float64 `json:"occupancy"`     // share of blocked days from today on, 0–1
+	CalendarDays int     `json:"calendar_days"` // days Occupancy is based on; 0 = unknown
+}
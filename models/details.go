@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// ListingDetails are detail-page facts beyond the scraped columns — capacity,
+// the price breakdown and amenities — read on demand for side-by-side
+// comparisons. They are not stored.
+type ListingDetails struct {
+	Guests   int // 0 when the overview does not say
+	Bedrooms int // 0 for studios
+	Beds     int
+	Baths    float64
+
+	Fees  []Fee  // price-breakdown lines after the nightly subtotal, in page order
+	Total string // breakdown total as shown, e.g. "$725"; empty without dates
+
+	Amenities    []string // every amenity when the dialog opened, else the ten or so the page shows
+	AmenityCount int      // from "Show all N amenities", else len(Amenities)
+
+	Calendar []CalendarDay // days of the availability calendar the page renders, in order
+}
+
+// CalendarDay is one day of a listing's availability calendar. Date is
+// midnight UTC of the calendar day.
+type CalendarDay struct {
+	Date    time.Time
+	Blocked bool // booked or closed by the host
+}
+
+// Fee is one line of the price breakdown, e.g. {"Cleaning fee", "$40"}.
+type Fee struct {
+	Label  string
+	Amount string
+}
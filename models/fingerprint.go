@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// PageFingerprint is a structural summary of an Airbnb detail page. Comparing
+// fingerprints between runs reveals redesigns before extraction degrades.
+type PageFingerprint struct {
+	URL        string         `json:"url"`
+	CapturedAt time.Time      `json:"captured_at"`
+	SectionIDs []string       `json:"section_ids"` // sorted data-section-id values
+	TestIDs    []string       `json:"test_ids"`    // sorted data-testid values
+	Headings   map[string]int `json:"headings"`    // tag ("h1".."h4") → count
+}
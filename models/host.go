@@ -0,0 +1,13 @@
+package models
+
+// Host is the host of a listing as its detail page describes them. ID is
+// the Airbnb user ID from the profile link; hosts without one are kept on
+// the listing but not stored in the hosts table.
+type Host struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Superhost    bool   `json:"superhost"`
+	YearsHosting int    `json:"years_hosting"` // 0 for under a year or when not shown
+	ResponseRate int    `json:"response_rate"` // percent; 0 when not shown
+	ListingCount int    `json:"listing_count"` // the host's listings; 0 when not shown
+}
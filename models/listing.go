@@ -9,27 +9,132 @@ type RawListing struct {
 	RawPrice    string
 	Location    string
 	Rating      string
+	ReviewCount string
+	Latitude    string
+	Longitude   string
 	URL         string
 	Description string
 	ScrapedAt   time.Time
 	Platform    string
+	TargetCity  string // set by the multi-city orchestrator; empty otherwise
+
+	SchemaVersion int        // RawSchemaVersion at extraction time
+	Provenance    Provenance // per-field extraction strategy and timestamp
+	Status        string     // ListingStatus*; "" means active
+
+	// OriginalDescription is the host's own text when the page showed
+	// Description as Airbnb's auto-translation; empty otherwise.
+	OriginalDescription string
+	// Amenities from the detail page's amenities dialog, or the ten or so
+	// its amenities section shows when the dialog did not open.
+	Amenities []string
+
+	Host *Host // from the detail page; nil when it was not visited or showed none
+
+	// Images are the detail page's gallery photo URLs, full size.
+	Images []string
+}
+
+// Listing lifecycle states. listings.status holds active/removed for the
+// current run; listing_lifecycle tracks all four across runs.
+const (
+	ListingStatusActive  = "active"
+	ListingStatusRemoved = "removed" // room redirected away or is no longer available
+	ListingStatusStale   = "stale"   // not seen for STALE_AFTER_RUNS healthy runs
+	ListingStatusSuspect = "suspect" // last seen in a run flagged as suspect
+)
+
+// ListingStatuses lists the lifecycle states in display order.
+var ListingStatuses = []string{ListingStatusActive, ListingStatusStale, ListingStatusRemoved, ListingStatusSuspect}
+
+// LifecycleEntry is a listing's cross-run lifecycle record.
+type LifecycleEntry struct {
+	URL             string    `json:"url"`
+	ShortID         string    `json:"short_id"`
+	Platform        string    `json:"platform"`
+	Title           string    `json:"title"`
+	Location        string    `json:"location"`
+	Status          string    `json:"status"`
+	FirstSeenRun    int64     `json:"first_seen_run"`
+	LastSeenRun     int64     `json:"last_seen_run"`
+	FirstSeenAt     time.Time `json:"first_seen_at"` // start of FirstSeenRun: the listing's age
+	LastSeenAt      time.Time `json:"last_seen_at"`
+	StatusChangedAt time.Time `json:"status_changed_at"`
+}
+
+// LocationChurn is one location's inventory churn in one week, from
+// listing_lifecycle.
+type LocationChurn struct {
+	Location    string    `json:"location"`
+	Week        time.Time `json:"week"`        // Monday 00:00 starting the week
+	New         int       `json:"new"`         // listings first seen that week
+	Disappeared int       `json:"disappeared"` // listings that went removed or stale that week
 }
 
 // Listing is the cleaned, validated record ready for PostgreSQL storage.
 type Listing struct {
 	ID          int64
+	ShortID     string // stable cross-run reference, see utils.ShortID
 	Platform    string
 	Title       string
-	Price       float64
+	Price       float64 // per night; see HasPrice
 	Location    string
 	Rating      float64
+	ReviewCount int
+	Latitude    float64 // 0 when unknown
+	Longitude   float64
+	Score       float64 // composite score 0-100, see services.Scorer
 	URL         string
 	Description string
+	TargetCity  string
+	Status      string // ListingStatus*
 	CreatedAt   time.Time
+
+	// PriceKnown is set when the cleaner read a price, so a free stay
+	// (Price 0, known) is not mistaken for a missing one. Stored as a NULL
+	// price when unset.
+	PriceKnown bool
+
+	// IsNewListing marks a listing Airbnb shows as "New": it has no rating
+	// yet, so its Rating of 0 is not missing data. Unrated listings of
+	// either kind store a NULL rating.
+	IsNewListing bool
+
+	// Extractor confidence per cleaned value (models.Confidence*); 0 = unknown.
+	PriceConfidence    float64
+	RatingConfidence   float64
+	LocationConfidence float64
+
+	// OriginalDescription is set when Description is an auto-translation.
+	OriginalDescription string
+
+	// Amenities as the detail page listed them, deduplicated; nil when
+	// the page was not visited or listed none.
+	Amenities []string
+
+	// Host is stored in the hosts table, keyed by Host.ID; the listings
+	// row links to it through host_id.
+	Host *Host
+
+	// Images are the gallery photo URLs; DOWNLOAD_IMAGES saves them under
+	// IMAGES_PATH/<ShortID>.
+	Images []string
+
+	// Analyst annotations from the tag command, keyed by URL across runs;
+	// filled only where a command asks for them.
+	Tags []string
+	Note string
+}
+
+// HasPrice reports whether Price is a real price: read by the cleaner, free
+// stays included, or positive (listings built without the cleaner).
+func (l *Listing) HasPrice() bool {
+	return l.PriceKnown || l.Price > 0
 }
 
 // InsightReport holds the computed analytics over the cleaned dataset.
 type InsightReport struct {
+	Scope              string // e.g. a target city; empty for the combined report
 	TotalListings      int
 	AirbnbListings     int
 	AveragePrice       float64
@@ -37,5 +142,134 @@ type InsightReport struct {
 	MaxPrice           float64
 	MostExpensive      *Listing
 	TopRated           []*Listing
+	TopScored          []*Listing
 	ListingsByLocation map[string]int
+	CityComparison     []*CityStats
+	Landmarks          []*LandmarkStats
+	Clusters           []*ClusterStats
+	Forecasts          []*PriceForecast
+	Recommendations    []*PriceRecommendation // watched listings only; nil without watch data
+	Occupancy          []*OccupancyEstimate   // per listing; nil without history or calendars
+	LocationOccupancy  []*LocationOccupancy
+	Anomalies          []string         // non-empty when the run was flagged suspect
+	LowConfidence      int              // prices/ratings left out of the stats for low confidence
+	UnknownPrice       int              // listings without a price, left out of the price stats
+	NewListings        int              // listings shown as "New", not rated yet
+	Removed            int              // listings found delisted this run (stored with status removed)
+	StatusCounts       map[string]int   // listing_lifecycle totals by status; nil when unavailable
+	Churn              []*LocationChurn // newest week first; nil when unavailable
+}
+
+// RunSnapshot summarises one stored run for run-to-run comparison.
+type RunSnapshot struct {
+	RunID        int64
+	ListingCount int
+	Locations    map[string]LocationSnapshot
+}
+
+// LocationSnapshot is a location's priced-listing count and average price in a run.
+type LocationSnapshot struct {
+	Count        int
+	AveragePrice float64
+}
+
+// PricePoint is the average nightly price of a location in one week.
+type PricePoint struct {
+	Week         time.Time
+	AveragePrice float64
+}
+
+// PriceForecast projects a location's average nightly price forward.
+type PriceForecast struct {
+	Location  string
+	Method    string // "holt" or "moving-average"
+	History   int    // number of weekly observations used
+	LastPrice float64
+	Weeks     []float64 // projected price for each following week
+}
+
+// PriceRecommendation is a suggested nightly price band for a watched
+// listing: the interquartile range and median of comparable listings'
+// prices.
+type PriceRecommendation struct {
+	URL         string
+	Title       string
+	Location    string
+	Current     float64 // latest watched price; 0 while unavailable
+	Low         float64 // 25th percentile of the comparables
+	Suggested   float64 // median
+	High        float64 // 75th percentile
+	Comparables int
+	Basis       string // "comp set", "location" or "comp set + location"
+}
+
+// ReviewPoint is a listing's review count as recorded by one run.
+type ReviewPoint struct {
+	At      time.Time
+	Reviews int
+}
+
+// OccupancyEstimate is a listing's estimated booked share of nights, from
+// its watched availability calendar or, failing that, its review velocity.
+type OccupancyEstimate struct {
+	URL             string
+	Title           string
+	Location        string
+	Method          string  // "calendar" or "reviews"
+	ReviewsPerMonth float64 // 0 for calendar estimates
+	NightsPerMonth  float64
+	Occupancy       float64 // 0..1
+	Days            int     // calendar days seen, or days between the first and last review count
+}
+
+// LocationOccupancy averages a location's occupancy estimates.
+type LocationOccupancy struct {
+	Location        string
+	Listings        int
+	ReviewsPerMonth float64 // mean over review-based estimates
+	Occupancy       float64
+}
+
+// ClusterStats describes a geographic cluster of listings found by DBSCAN,
+// labelled by its dominant location string.
+type ClusterStats struct {
+	Label        string
+	Size         int
+	CenterLat    float64
+	CenterLng    float64
+	AveragePrice float64
+	MedianPrice  float64
+	MinPrice     float64
+	MaxPrice     float64
+}
+
+// LandmarkStats summarises the listings within a radius of a point of interest.
+type LandmarkStats struct {
+	Name         string
+	RadiusKm     float64
+	Count        int
+	AveragePrice float64
+	Nearest      []*LandmarkDistance // closest first, at most 5
+}
+
+// LandmarkDistance is a listing's straight-line distance to a landmark.
+type LandmarkDistance struct {
+	Listing    *Listing
+	DistanceKm float64
+}
+
+// CityStats is one row of the cross-city comparison table.
+type CityStats struct {
+	City               string
+	Inventory          int
+	MedianPrice        float64
+	UpperQuartilePrice float64 // 75th percentile nightly price
+	AverageRating      float64
+}
+
+// ShortlistEntry is a listing matched by the budget finder with its value score.
+type ShortlistEntry struct {
+	Rank       int // 1-based position in the shortlist
+	Listing    *Listing
+	ValueScore float64 // rating points per $100 of nightly price
 }
@@ -0,0 +1,11 @@
+package models
+
+// LocationSuggestion is one canonical location of the stored listings: the
+// most common spelling of the strings that fold to the same key, with the
+// listing count over all of them.
+type LocationSuggestion struct {
+	Name     string   `json:"name"`
+	Count    int      `json:"count"`
+	Variants []string `json:"variants,omitempty"` // other spellings seen, most common first
+	Score    float64  `json:"score,omitempty"`    // match quality 0..1; 0 when listing without a query
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// RawSchemaVersion identifies the RawListing layout written to raw exports.
+// Bump it whenever a field is added, removed or changes meaning so consumers
+// of old CSVs can tell which columns to expect.
+const RawSchemaVersion = 6
+
+// Confidence levels assigned by extractors. Zero means "unknown" (e.g. rows
+// written before confidence was tracked) and is never treated as low.
+const (
+	ConfidenceLow    = 0.3
+	ConfidenceMedium = 0.6
+	ConfidenceHigh   = 0.9
+)
+
+// FieldSource records how and when a single raw field was extracted, and how
+// much the extractor trusts that strategy.
+type FieldSource struct {
+	Strategy    string    `json:"strategy"` // e.g. "card:title-element", "detail:book-it-sidebar"
+	Confidence  float64   `json:"confidence"`
+	ExtractedAt time.Time `json:"extracted_at"`
+}
+
+// Provenance maps a RawListing field name ("title", "price", …) to its source.
+type Provenance map[string]FieldSource
+
+// SetSource records that field was produced by strategy, with the given
+// confidence, at time at. Empty strategies are ignored so extractors can pass
+// through "not found".
+func (r *RawListing) SetSource(field, strategy string, confidence float64, at time.Time) {
+	if strategy == "" {
+		return
+	}
+	if r.Provenance == nil {
+		r.Provenance = make(Provenance)
+	}
+	r.Provenance[field] = FieldSource{Strategy: strategy, Confidence: confidence, ExtractedAt: at}
+}
+
+// Confidence returns the extractor's confidence in field, or 0 if unknown.
+func (p Provenance) Confidence(field string) float64 {
+	return p[field].Confidence
+}
+
+// CopySource carries field's provenance over from another listing, used when
+// a detail-page value replaces the card value.
+func (r *RawListing) CopySource(from *RawListing, field string) {
+	if src, ok := from.Provenance[field]; ok {
+		r.SetSource(field, src.Strategy, src.Confidence, src.ExtractedAt)
+	}
+}
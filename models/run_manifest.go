@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// RunManifest is the machine-readable summary of one run, written at the end
+// of every run (successful or not) for pipeline orchestration tools.
+type RunManifest struct {
+	Version    string        `json:"version"` // scraper version, commit and build date
+	Status     string        `json:"status"`  // "ok", "partial" or "failed"
+	ExitCode   int           `json:"exit_code"`
+	Error      string        `json:"error,omitempty"`
+	RunID      int64         `json:"run_id,omitempty"` // price-history run, once recorded
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Seconds    float64       `json:"seconds"`
+	Stages     []StageTiming `json:"stages"`
+
+	Counts    map[string]int    `json:"counts"`   // raw, cleaned, stored, …
+	Failures  map[string]int    `json:"failures"` // scraper failures by class
+	Anomalies []string          `json:"anomalies,omitempty"`
+	Outputs   map[string]string `json:"outputs"` // kind → file path
+
+	Config any `json:"config"` // the effective config, secrets redacted
+}
+
+// StageTiming is the wall time one stage of a run took.
+type StageTiming struct {
+	Stage   string  `json:"stage"`
+	Seconds float64 `json:"seconds"`
+}
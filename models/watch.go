@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Availability of a watched listing at one check.
+const (
+	WatchAvailable   = "available"   // room page shows a price
+	WatchUnavailable = "unavailable" // room page loads but quotes no price
+	WatchRemoved     = "removed"     // room redirected away or is no longer available
+)
+
+// WatchState is a watched listing's price, availability and rating at one
+// check.
+type WatchState struct {
+	URL       string    `json:"url"`
+	Title     string    `json:"title"`
+	Location  string    `json:"location"`
+	Price     float64   `json:"price"` // nightly; 0 unless available
+	Status    string    `json:"status"`
+	Rating    float64   `json:"rating"` // 0 when not shown
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// WatchChange is a difference between two checks of a watched listing.
+// Previous is nil on the first check, which is stored but not alerted on.
+type WatchChange struct {
+	Previous *WatchState `json:"previous"`
+	Current  WatchState  `json:"current"`
+}
+
+// PriceDelta is the change of the nightly price, 0 without two prices.
+func (c WatchChange) PriceDelta() float64 {
+	if c.Previous == nil || c.Previous.Price <= 0 || c.Current.Price <= 0 {
+		return 0
+	}
+	return c.Current.Price - c.Previous.Price
+}
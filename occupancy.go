@@ -0,0 +1,35 @@
+package main
+
+import (
+	"time"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+)
+
+// occupancyEstimates estimates the stored listings' occupancy from the
+// calendars `watch` recorded and the review counts in price_history.
+func occupancyEstimates(cfg *config.Config, pg *storage.PostgresWriter, svc *services.InsightService,
+	listings []*models.Listing) ([]*models.OccupancyEstimate, []*models.LocationOccupancy, error) {
+	reviews, err := pg.FetchReviewHistory()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := pg.EnsureWatchTable(); err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	calendars, err := pg.WatchCalendars(now.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, nil, err
+	}
+	model := services.OccupancyModel{
+		ReviewRate:  cfg.OccupancyReviewRate,
+		AverageStay: cfg.OccupancyAverageStay,
+		Cap:         cfg.OccupancyCap,
+	}
+	perListing, perLocation := svc.EstimateOccupancy(reviews, calendars, listings, model, now)
+	return perListing, perLocation, nil
+}
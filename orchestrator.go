@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/scraper"
+	"airbnb-scraper/utils"
+)
+
+// scrapeCities runs the search-mode scraper once per configured city, at most
+// CITY_PARALLELISM at a time, tags every listing with its target city and
+// streams the batches to out. A failing city is logged and skipped so the
+// others still contribute. Returns once every city has finished, with the
+// failed cities' errors joined; out is left open for the caller to close.
+func scrapeCities(cfg *config.Config, logger *utils.Logger, newScraper scraper.Factory, out chan<- []*models.RawListing) error {
+	parallelism := cfg.CityParallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	pool := utils.NewWorkerPool(parallelism, 0)
+	var mu sync.Mutex
+	var errs []error
+
+	for _, city := range cfg.Cities {
+		city := city
+		pool.Submit(func() {
+			cityCfg := *cfg
+			cityCfg.DiscoveryMode = "search"
+			cityCfg.SearchQuery = city
+
+			// Tag batches on their way out; the forwarder shares out's
+			// backpressure with the city's scraper.
+			cityOut := make(chan []*models.RawListing)
+			done := make(chan int)
+			go func() {
+				count := 0
+				for batch := range cityOut {
+					for _, l := range batch {
+						l.TargetCity = city
+					}
+					count += len(batch)
+					out <- batch
+				}
+				done <- count
+			}()
+
+			logger.Info("[orchestrator] Scraping city %q", city)
+			sc := newScraper(&cityCfg)
+			sc.SetOutput(cityOut)
+			if _, err := sc.Scrape(); err != nil {
+				logger.Error("[orchestrator] City %q failed: %v", city, err)
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("city %q: %w", city, err))
+				mu.Unlock()
+			}
+			close(cityOut)
+			logger.Info("[orchestrator] City %q done — %d raw listings", city, <-done)
+		})
+	}
+	pool.Wait()
+	return errors.Join(errs...)
+}
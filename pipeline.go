@@ -0,0 +1,105 @@
+package main
+
+import (
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/scraper"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// pipelineCounts reports how many rows passed each stage of a run.
+type pipelineCounts struct {
+	Raw          int
+	Removed      int // delisted rooms, stored with status removed
+	Cleaned      int
+	Stored       int
+	DeadLettered int
+	ScrapeErr    error // why the scraper (or some cities) stopped early
+}
+
+// streamListings runs scrape → raw CSV → clean → (listing hook) → PostgreSQL
+// as concurrent stages joined by bounded channels. Each section flows
+// through as soon as it is scraped; when a later stage falls behind, sends
+// block and the scraper waits, so memory stays bounded by PIPELINE_BUFFER
+// batches per stage. The depth of each channel and the rows seen so far are
+// published to status.
+func streamListings(
+	cfg *config.Config,
+	logger *utils.Logger,
+	newScraper scraper.Factory,
+	csvWriter *storage.CSVWriter,
+	cleaner *services.Cleaner,
+	hooks *hooks,
+	pg storage.ListingWriter,
+	deadLetter *storage.DeadLetterWriter,
+	status *utils.StatusBoard,
+) pipelineCounts {
+	buffer := cfg.PipelineBuffer
+	if buffer < 0 {
+		buffer = 0
+	}
+	scraped := make(chan []*models.RawListing, buffer)
+	toClean := make(chan []*models.RawListing, buffer)
+	cleaned := make(chan []*models.Listing, buffer)
+	status.Gauge("to_csv", func() int { return len(scraped) })
+	status.Gauge("to_clean", func() int { return len(toClean) })
+	status.Gauge("to_store", func() int { return len(cleaned) })
+
+	var counts pipelineCounts
+
+	// ── Stage 1: scrape ──────────────────────────────────────────────────
+	go func() {
+		defer close(scraped)
+		if len(cfg.Cities) > 0 {
+			logger.Info("Multi-city run — %d cities, parallelism %d", len(cfg.Cities), cfg.CityParallelism)
+			counts.ScrapeErr = scrapeCities(cfg, logger, newScraper, scraped)
+			return
+		}
+		sc := newScraper(cfg)
+		sc.SetOutput(scraped)
+		if _, err := sc.Scrape(); err != nil {
+			logger.Error("Airbnb scrape failed: %v", err)
+			counts.ScrapeErr = err
+			// Continue with whatever was collected rather than hard-exiting
+		}
+	}()
+
+	// ── Stage 2: persist raw rows to CSV ─────────────────────────────────
+	go func() {
+		defer close(toClean)
+		for batch := range scraped {
+			counts.Raw += len(batch)
+			status.Add("raw", len(batch))
+			for _, l := range batch {
+				if l.Status == models.ListingStatusRemoved {
+					counts.Removed++
+				}
+			}
+			if err := csvWriter.WriteRaw(batch); err != nil {
+				logger.Error("CSV write failed: %v", err)
+			}
+			toClean <- batch
+		}
+	}()
+
+	// ── Stage 3: clean ───────────────────────────────────────────────────
+	go cleaner.CleanStream(toClean, cleaned)
+
+	// ── Stage 4: store (buffered, size/interval flushes with retries) ───────
+	sink := storage.NewPostgresSink(pg, cfg.DBBatchSize, cfg.DBFlushInterval, buffer, cfg.MaxRetries, logger)
+	sink.SetDeadLetter(func(rows []*models.Listing, err error) {
+		logger.Error("[pg-sink] Row %s rejected: %v", rows[0].URL, err)
+		if dlErr := deadLetter.Write(rows, err); dlErr != nil {
+			logger.Error("[pg-sink] %v", dlErr)
+		}
+	})
+	stats := sink.Run(hooks.listings(cleaned, buffer))
+
+	counts.Cleaned = stats.Received
+	counts.Stored = stats.Stored
+	counts.DeadLettered = stats.DeadLettered
+	logger.Info("[pg-sink] %d flushes — stored %d, dead-lettered %d", stats.Flushes, counts.Stored, counts.DeadLettered)
+	return counts
+}
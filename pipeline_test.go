@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/demo"
+	"airbnb-scraper/models"
+	"airbnb-scraper/scraper"
+	"airbnb-scraper/scraper/scrapertest"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// memoryWriter is a storage.ListingWriter keeping rows in memory.
+type memoryWriter struct {
+	mu   sync.Mutex
+	rows []*models.Listing
+}
+
+func (w *memoryWriter) Write(rows []*models.Listing) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.rows = append(w.rows, rows...)
+	return nil
+}
+
+func (w *memoryWriter) Close() error { return nil }
+
+// TestStreamListingsWithMockScraper runs the binary's streaming pipeline —
+// scrape, raw CSV, clean, store — on the demo listings from a mock scraper,
+// without a browser or database.
+func TestStreamListingsWithMockScraper(t *testing.T) {
+	at := time.Now()
+	raw, err := demo.Listings(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := demo.Listings(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := utils.NewLogger()
+	want := services.NewCleaner(logger).Clean(expected)
+
+	dir := t.TempDir()
+	cfg := &config.Config{
+		CSVOutputPath:   filepath.Join(dir, "raw_listings.csv"),
+		PipelineBuffer:  1,
+		DBBatchSize:     4,
+		DBFlushInterval: time.Hour,
+		MaxRetries:      1,
+	}
+	csvWriter, err := storage.NewCSVWriter(cfg.CSVOutputPath, storage.DefaultCSVFormat(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadLetter, err := storage.NewDeadLetterWriter(filepath.Join(dir, "dead_letter.ndjson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deadLetter.Close()
+	newScraper := func(*config.Config) scraper.Scraper {
+		return scrapertest.NewMock(raw[:3], raw[3:])
+	}
+	pg := &memoryWriter{}
+
+	counts := streamListings(cfg, logger, newScraper, csvWriter, services.NewCleaner(logger),
+		newHooks(cfg, logger), pg, deadLetter, utils.NewStatusBoard())
+	if err := csvWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if counts.ScrapeErr != nil {
+		t.Fatalf("scrape: %v", counts.ScrapeErr)
+	}
+	if counts.Raw != len(raw) {
+		t.Errorf("Raw = %d, want %d", counts.Raw, len(raw))
+	}
+	if rows := countRecords(t, cfg.CSVOutputPath) - 1; rows != len(raw) {
+		t.Errorf("raw CSV has %d rows, want %d", rows, len(raw))
+	}
+	if counts.Cleaned != len(want) || counts.Stored != len(want) || counts.DeadLettered != 0 {
+		t.Errorf("counts = %+v, want %d cleaned and stored", counts, len(want))
+	}
+	if len(pg.rows) != len(want) {
+		t.Fatalf("writer got %d listings, Clean gives %d", len(pg.rows), len(want))
+	}
+	report := services.NewInsightService(logger).Generate(pg.rows)
+	if report.AveragePrice <= 0 || len(report.ListingsByLocation) == 0 {
+		t.Errorf("report has no prices or locations: average %.2f, %d locations",
+			report.AveragePrice, len(report.ListingsByLocation))
+	}
+}
+
+func countRecords(t *testing.T, path string) int {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(records)
+}
@@ -0,0 +1,160 @@
+// Package airbnbscraper embeds the scraper in other Go programs: scrape
+// listings with a headless browser, clean them and build the insight
+// report, without running the binary or needing PostgreSQL.
+//
+//	c, err := airbnbscraper.New(airbnbscraper.Options{DiscoveryMode: "search", SearchQuery: "Lisbon"})
+//	raw, err := c.Scrape(ctx, nil)
+//	listings, err := c.Clean(ctx, raw)
+//	report := c.Insights(listings)
+package airbnbscraper
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/scraper/airbnb"
+	"airbnb-scraper/services"
+	"airbnb-scraper/utils"
+)
+
+// The data types are the scraper's own.
+type (
+	Config        = config.Config
+	RawListing    = models.RawListing
+	Listing       = models.Listing
+	InsightReport = models.InsightReport
+)
+
+// Options configures a Client. Zero fields keep Config's value.
+type Options struct {
+	// Config is the full configuration, as the binary reads it from .env;
+	// nil uses the process environment with the binary's defaults.
+	Config *Config
+	// Log receives the scraper's log lines; nil discards them.
+	Log io.Writer
+
+	DiscoveryMode  string // "homepage", "search", "sitemap" or "allowlist"
+	SearchQuery    string // with DiscoveryMode "search"
+	MaxConcurrency int    // detail pages loaded at once
+	MaxPages       int    // browser page loads per Scrape call
+}
+
+// Client runs the pipeline stages. Its methods may be called concurrently.
+type Client struct {
+	cfg    *config.Config
+	logger *utils.Logger
+}
+
+// New returns a Client for opts.
+func New(opts Options) (*Client, error) {
+	var cfg *config.Config
+	if opts.Config != nil {
+		copied := *opts.Config
+		cfg = &copied
+		if _, _, err := config.ParseStayDates(cfg.CheckIn, cfg.CheckOut, cfg.Guests); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if cfg, err = config.FromEnv(); err != nil {
+			return nil, err
+		}
+	}
+	if opts.DiscoveryMode != "" {
+		cfg.DiscoveryMode = opts.DiscoveryMode
+	}
+	if opts.SearchQuery != "" {
+		cfg.SearchQuery = opts.SearchQuery
+	}
+	if opts.MaxConcurrency > 0 {
+		cfg.MaxConcurrency = opts.MaxConcurrency
+	}
+	if opts.MaxPages > 0 {
+		cfg.MaxPagesPerRun = opts.MaxPages
+	}
+	switch cfg.DiscoveryMode {
+	case "homepage", "sitemap", "allowlist":
+	case "search":
+		if cfg.SearchQuery == "" {
+			return nil, fmt.Errorf("airbnbscraper: discovery mode search needs a search query")
+		}
+	default:
+		return nil, fmt.Errorf("airbnbscraper: unknown discovery mode %q", cfg.DiscoveryMode)
+	}
+	w := opts.Log
+	if w == nil {
+		w = io.Discard
+	}
+	return &Client{cfg: cfg, logger: utils.NewLoggerTo(w)}, nil
+}
+
+// Scrape discovers listings and enriches them from their detail pages.
+// When onSection is non-nil it receives each section's listings as soon as
+// they are done, on the scraping goroutine. Once ctx is done the browser is
+// closed and the listings collected so far are returned with ctx's error.
+func (c *Client) Scrape(ctx context.Context, onSection func([]*RawListing)) ([]*RawListing, error) {
+	sc := airbnb.New(c.cfg, c.logger)
+	if c.cfg.MaxPagesPerRun > 0 || c.cfg.MaxPagesPerHour > 0 {
+		pages := utils.NewPageBudget(c.cfg.MaxPagesPerRun, c.cfg.MaxPagesPerHour)
+		pages.StartRun()
+		sc.SetPageBudget(pages)
+	}
+	if onSection == nil {
+		return sc.ScrapeContext(ctx)
+	}
+
+	batches := make(chan []*models.RawListing)
+	sc.SetOutput(batches)
+	done := make(chan struct{})
+	var all []*RawListing
+	go func() {
+		defer close(done)
+		for batch := range batches {
+			all = append(all, batch...)
+			onSection(batch)
+		}
+	}()
+	_, err := sc.ScrapeContext(ctx)
+	close(batches)
+	<-done
+	return all, err
+}
+
+// Clean deduplicates raw listings by URL and parses prices, ratings and
+// locations. It stops between batches once ctx is done, returning what was
+// cleaned with ctx's error.
+func (c *Client) Clean(ctx context.Context, raw []*RawListing) ([]*Listing, error) {
+	const batchSize = 500
+	cleaner := services.NewCleaner(c.logger)
+	cleaner.SetWorkers(c.cfg.CleanWorkers)
+	cleaner.SetStrict(c.cfg.CleanStrict)
+	in := make(chan []*models.RawListing)
+	out := make(chan []*models.Listing)
+	go cleaner.CleanStream(in, out)
+	go func() {
+		defer close(in)
+		for start := 0; start < len(raw) && ctx.Err() == nil; start += batchSize {
+			select {
+			case in <- raw[start:min(start+batchSize, len(raw))]:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	var listings []*Listing
+	for batch := range out {
+		listings = append(listings, batch...)
+	}
+	return listings, ctx.Err()
+}
+
+// Insights summarises cleaned listings: price and rating statistics, the
+// best-value and top-rated listings and per-location breakdowns.
+func (c *Client) Insights(listings []*Listing) *InsightReport {
+	svc := services.NewInsightService(c.logger)
+	svc.SetMinConfidence(c.cfg.MinFieldConfidence)
+	return svc.Generate(listings)
+}
@@ -0,0 +1,84 @@
+package airbnbscraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNewValidatesDiscovery(t *testing.T) {
+	if _, err := New(Options{DiscoveryMode: "search"}); err == nil {
+		t.Error("search without a query should fail")
+	}
+	if _, err := New(Options{DiscoveryMode: "guess"}); err == nil {
+		t.Error("unknown discovery mode should fail")
+	}
+	c, err := New(Options{DiscoveryMode: "search", SearchQuery: "Lisbon", MaxConcurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.cfg.SearchQuery != "Lisbon" || c.cfg.MaxConcurrency != 2 {
+		t.Errorf("options not applied: %q, %d", c.cfg.SearchQuery, c.cfg.MaxConcurrency)
+	}
+}
+
+func TestNewCopiesConfig(t *testing.T) {
+	base, _ := New(Options{})
+	cfg := *base.cfg
+	c, err := New(Options{Config: &cfg, DiscoveryMode: "sitemap"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DiscoveryMode == "sitemap" || c.cfg.DiscoveryMode != "sitemap" {
+		t.Error("options must apply to a copy of the caller's config")
+	}
+}
+
+func rawListings(n int) []*RawListing {
+	raw := make([]*RawListing, n)
+	for i := range raw {
+		raw[i] = &RawListing{
+			Platform: "airbnb",
+			URL:      fmt.Sprintf("https://www.airbnb.com/rooms/%d", i%(n-1)+1), // one duplicate
+			Title:    fmt.Sprintf("Flat %d", i),
+			RawPrice: fmt.Sprintf("$%d night", 100+i),
+			Rating:   "4.8",
+			Location: "Lisbon, Portugal",
+		}
+	}
+	return raw
+}
+
+func TestCleanAndInsights(t *testing.T) {
+	c, err := New(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listings, err := c.Clean(context.Background(), rawListings(1200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listings) != 1199 {
+		t.Fatalf("cleaned %d listings, want 1199 after dedup across batches", len(listings))
+	}
+	if listings[0].Price != 100 || listings[0].Rating != 4.8 {
+		t.Errorf("first listing parsed as price %.2f rating %.2f", listings[0].Price, listings[0].Rating)
+	}
+	if r := c.Insights(listings); r.TotalListings != 1199 {
+		t.Errorf("report covers %d listings, want 1199", r.TotalListings)
+	}
+}
+
+func TestCleanCancelled(t *testing.T) {
+	c, _ := New(Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	listings, err := c.Clean(ctx, rawListings(1200))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if len(listings) != 0 {
+		t.Errorf("cleaned %d listings despite cancellation", len(listings))
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/scraper/airbnb"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// preflightTimeout bounds the network check's request.
+const preflightTimeout = 15 * time.Second
+
+// preflightCheck is one line of the pre-flight checklist: run returns what
+// was found, and hint says what to do when it fails.
+type preflightCheck struct {
+	name string
+	run  func() (string, error)
+	hint string
+}
+
+// preflight checks everything a scrape needs — the browser launches,
+// AIRBNB_BASE_URL answers, PostgreSQL accepts writes and the output
+// directories are writable — and prints a checklist. Every check runs, so
+// one pass shows everything to fix; the error names the failed ones.
+func preflight(cfg *config.Config, logger *utils.Logger) error {
+	checks := []preflightCheck{
+		{"browser", func() (string, error) {
+			return airbnb.New(cfg, logger).CheckBrowser()
+		}, "install Chrome or Chromium, or point CHROME_BIN at it"},
+		{"network", func() (string, error) {
+			return checkReachable(cfg.BaseURL)
+		}, "check the connection, DNS and any HTTPS_PROXY; AIRBNB_BASE_URL is " + cfg.BaseURL},
+		{"database", func() (string, error) {
+			pg, err := storage.OpenPostgres(cfg.DSN())
+			if err != nil {
+				return "", err
+			}
+			defer pg.Close()
+			if err := pg.CheckWritable(); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s@%s:%s/%s writable", cfg.PostgresUser, cfg.PostgresHost, cfg.PostgresPort, cfg.PostgresDB), nil
+		}, "start PostgreSQL (docker compose up -d) or fix POSTGRES_* in .env; the user needs CREATE on the schema"},
+		{"output", func() (string, error) {
+			return checkWritableDirs(cfg)
+		}, "create the directory or fix its permissions, or move CSV_OUTPUT_PATH and the other *_PATH outputs"},
+	}
+
+	utils.Printf("\nPre-flight checks\n")
+	var failed []string
+	for _, c := range checks {
+		detail, err := c.run()
+		if err != nil {
+			failed = append(failed, c.name)
+			utils.Printf("  ✗ %-9s %v\n              → %s\n", c.name, err, c.hint)
+			continue
+		}
+		utils.Printf("  ✓ %-9s %s\n", c.name, detail)
+	}
+	fmt.Println()
+	if len(failed) > 0 {
+		return fmt.Errorf("pre-flight failed: %s (set PREFLIGHT=false to skip)", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// checkReachable requests url and reports its HTTP status. Any response
+// counts: a 403 still proves the network path works.
+func checkReachable(url string) (string, error) {
+	client := &http.Client{Timeout: preflightTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	return fmt.Sprintf("%s reachable (%s)", url, resp.Status), nil
+}
+
+// checkWritableDirs creates the directories of the run's output files and
+// writes a scratch file to each.
+func checkWritableDirs(cfg *config.Config) (string, error) {
+	var dirs []string
+	paths := []string{cfg.CSVOutputPath, cfg.DeadLetterPath, cfg.RunManifestPath, cfg.WARCOutputPath}
+	if cfg.DownloadImages {
+		paths = append(paths, filepath.Join(cfg.ImagesPath, "photo")) // the directory itself
+	}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if dir := filepath.Dir(path); !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", err
+		}
+		f, err := os.CreateTemp(dir, ".preflight-*")
+		if err != nil {
+			return "", err
+		}
+		f.Close()
+		if err := os.Remove(f.Name()); err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(dirs, ", ") + " writable", nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"sort"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+)
+
+// priceRecommendations suggests a price band for every listing `watch`
+// has checked, against the stored listings and, for COMPSET_LISTING, the
+// comp set's latest available prices. It returns nil when nothing is
+// watched.
+func priceRecommendations(cfg *config.Config, pg *storage.PostgresWriter, svc *services.InsightService, listings []*models.Listing) ([]*models.PriceRecommendation, error) {
+	if err := pg.EnsureWatchTable(); err != nil {
+		return nil, err
+	}
+	states, err := pg.LatestWatchStates()
+	if err != nil || len(states) == 0 {
+		return nil, err
+	}
+
+	compSets := make(map[string][]float64)
+	own, comps, err := loadCompSet(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if own != "" {
+		ownURL := roomURLFor(cfg, own)
+		for _, c := range comps {
+			if s := states[roomURLFor(cfg, c)]; s != nil && s.Status == models.WatchAvailable {
+				compSets[ownURL] = append(compSets[ownURL], s.Price)
+			}
+		}
+	}
+
+	watched := make([]*models.WatchState, 0, len(states))
+	for _, s := range states {
+		watched = append(watched, s)
+	}
+	sort.Slice(watched, func(i, j int) bool { return watched[i].URL < watched[j].URL })
+	return svc.Recommend(watched, listings, compSets), nil
+}
@@ -95,7 +95,8 @@ print_ok "Dependencies ready"
 # ── 5. Build ─────────────────────────────────────────────────
 print_step "Building project"
 BUILD_OUTPUT="$PROJECT_ROOT/airbnb-scraper-demo"
-if go build -o "$BUILD_OUTPUT" . 2>&1 | sed 's/^/  /'; then
+LDFLAGS="-X airbnb-scraper/utils.BuildCommit=$(git rev-parse --short HEAD 2>/dev/null || true) -X airbnb-scraper/utils.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+if go build -ldflags "$LDFLAGS" -o "$BUILD_OUTPUT" . 2>&1 | sed 's/^/  /'; then
   print_ok "Build successful → $BUILD_OUTPUT"
 else
   print_err "Build failed. Fix compilation errors above and re-run."
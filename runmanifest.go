@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/events"
+	"airbnb-scraper/models"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// runRecorder collects what one run did — stage timings, counts, scraper
+// failures, outputs — and writes it to RUN_MANIFEST_PATH when the run ends.
+type runRecorder struct {
+	cfg  *config.Config
+	m    models.RunManifest
+	last time.Time
+
+	mu sync.Mutex // guards m.Failures, fed by every scraper of the run
+}
+
+func newRunRecorder(cfg *config.Config) *runRecorder {
+	now := time.Now()
+	return &runRecorder{
+		cfg:  cfg,
+		last: now,
+		m: models.RunManifest{
+			Version:   utils.Version(),
+			StartedAt: now,
+			Counts:    map[string]int{},
+			Failures:  map[string]int{},
+			Outputs:   map[string]string{},
+			Config:    cfg.Redacted(),
+		},
+	}
+}
+
+// lap records the time since the previous lap (or the start) as stage.
+func (r *runRecorder) lap(stage string) {
+	now := time.Now()
+	r.m.Stages = append(r.m.Stages, models.StageTiming{Stage: stage, Seconds: now.Sub(r.last).Seconds()})
+	r.last = now
+}
+
+// listen counts the detail-page failures published on bus, from every
+// scraper of the run, and saves the manifest when the run finishes.
+func (r *runRecorder) listen(bus *events.Bus, logger *utils.Logger) {
+	events.On(bus, func(e events.ErrorOccurred) {
+		r.mu.Lock()
+		r.m.Failures[e.Class]++
+		r.mu.Unlock()
+	})
+	events.On(bus, func(e events.RunFinished) {
+		r.save(e.Err, logger)
+	})
+}
+
+// output records a file the run wrote; empty paths are skipped.
+func (r *runRecorder) output(kind, path string) {
+	if path != "" {
+		r.m.Outputs[kind] = path
+	}
+}
+
+// save finishes the manifest with the run's outcome and writes it. A write
+// failure is logged and does not fail the run.
+func (r *runRecorder) save(runErr error, logger *utils.Logger) {
+	r.m.FinishedAt = time.Now()
+	r.m.Seconds = r.m.FinishedAt.Sub(r.m.StartedAt).Seconds()
+	r.m.Status = "ok"
+	if runErr != nil {
+		r.m.Status = "failed"
+		if errors.Is(runErr, errPartial) {
+			r.m.Status = "partial"
+		}
+		r.m.Error = runErr.Error()
+	}
+	r.m.ExitCode = exitCode(runErr)
+	if r.cfg.RunManifestPath == "" {
+		return
+	}
+	if err := storage.SaveRunManifest(r.cfg.RunManifestPath, &r.m); err != nil {
+		logger.Error("%v", err)
+		return
+	}
+	logger.Info("Run manifest written to %s", r.cfg.RunManifestPath)
+}
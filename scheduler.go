@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/utils"
+)
+
+// runScheduler repeats the pipeline every SCHEDULE_INTERVAL until the process
+// receives SIGINT/SIGTERM, which also cuts short a window wait or scrape in
+// progress. Each run waits for the scraping window first.
+// SIGHUP and the optional admin endpoint retune throttle mid-run; the admin
+// endpoint also serves the status board. The page budget's hourly cap spans
+// runs.
+func runScheduler(cfg *config.Config, logger *utils.Logger, window *utils.TimeWindow, throttle *utils.Throttle, status *utils.StatusBoard, pages *utils.PageBudget) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("[scheduler] Running every %v (window: %s)", cfg.ScheduleInterval, window)
+	go reloadOnSIGHUP(ctx, logger, throttle)
+	if cfg.AdminAddr != "" {
+		go serveAdmin(ctx, logger, cfg.AdminAddr, cfg.AdminToken, throttle, status)
+	}
+
+	for runNum := 1; ; runNum++ {
+		started := time.Now()
+		logger.Info("[scheduler] Starting run #%d", runNum)
+		if err := run(ctx, cfg, logger, window, throttle, status, pages); ctx.Err() != nil {
+			logger.Info("[scheduler] Shutdown requested — exiting")
+			return
+		} else if errors.Is(err, errPartial) {
+			logger.Warn("[scheduler] Run #%d completed with failures (exit code %d)", runNum, exitPartial)
+		} else if err != nil {
+			logger.Warn("[scheduler] Run #%d did not complete (exit code %d): %v", runNum, exitCode(err), err)
+		}
+
+		next := started.Add(cfg.ScheduleInterval)
+		if !window.Contains(next) {
+			next = window.NextOpen(next)
+		}
+		logger.Info("[scheduler] Next run at %s", next.Format("2006-01-02 15:04:05"))
+
+		select {
+		case <-ctx.Done():
+			logger.Info("[scheduler] Shutdown requested — exiting")
+			return
+		case <-time.After(time.Until(next)):
+		}
+	}
+}
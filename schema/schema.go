@@ -0,0 +1,89 @@
+// Package schema publishes the JSON Schemas of the scraper's JSON outputs —
+// raw listings, cleaned listings and the insight report — as a contract for
+// downstream integrators, and validates documents against them.
+//
+// The schemas are generated from the models and embedded; a test fails
+// when a model changes without them. Regenerate with
+//
+//	go test ./schema -update
+package schema
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+
+	"airbnb-scraper/models"
+)
+
+//go:embed *.schema.json
+var files embed.FS
+
+// documents are the published schemas in listing order.
+var documents = []struct {
+	name        string
+	value       any
+	description string
+}{
+	{"raw_listing", models.RawListing{}, "A listing as extracted from the page, before cleaning (raw CSV rows, demo data)."},
+	{"listing", models.Listing{}, "A cleaned listing, as stored and served by /api/listings without a field selection."},
+	{"insight_report", models.InsightReport{}, "The insight report over the cleaned listings, as served by /api/report."},
+}
+
+// Names lists the published schemas.
+func Names() []string {
+	names := make([]string, len(documents))
+	for i, d := range documents {
+		names[i] = d.name
+	}
+	return names
+}
+
+// Get returns the schema document called name.
+func Get(name string) ([]byte, error) {
+	for _, d := range documents {
+		if d.name == name {
+			return files.ReadFile(name + ".schema.json")
+		}
+	}
+	return nil, fmt.Errorf("schema: unknown schema %q (available: %v)", name, Names())
+}
+
+// Validate checks a JSON document against schema name. A top-level array
+// is validated element by element, so whole exports can be checked.
+func Validate(name string, doc []byte) error {
+	raw, err := Get(name)
+	if err != nil {
+		return err
+	}
+	var root map[string]any
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return fmt.Errorf("schema: %s: %w", name, err)
+	}
+	val, err := decode(doc)
+	if err != nil {
+		return fmt.Errorf("schema: decode document: %w", err)
+	}
+	v := &validator{root: root}
+	if items, ok := val.([]any); ok {
+		for i, item := range items {
+			if err := v.check(root, item, fmt.Sprintf("/%d", i)); err != nil {
+				return fmt.Errorf("schema: %s%w", name, err)
+			}
+		}
+		return nil
+	}
+	if err := v.check(root, val, ""); err != nil {
+		return fmt.Errorf("schema: %s%w", name, err)
+	}
+	return nil
+}
+
+// ValidateValue encodes v as JSON and validates it against schema name.
+func ValidateValue(name string, v any) error {
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("schema: encode: %w", err)
+	}
+	return Validate(name, doc)
+}
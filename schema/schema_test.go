@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"airbnb-scraper/demo"
+	"airbnb-scraper/services"
+	"airbnb-scraper/utils"
+)
+
+var update = flag.Bool("update", false, "rewrite the schema files from the models")
+
+func TestSchemasMatchModels(t *testing.T) {
+	for _, d := range documents {
+		want, err := generate(d.value, d.name, d.description)
+		if err != nil {
+			t.Fatalf("%s: %v", d.name, err)
+		}
+		path := d.name + ".schema.json"
+		if *update {
+			if err := os.WriteFile(path, want, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		got, err := files.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v", d.name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date with the models; run go test ./schema -update and review the diff", path)
+		}
+	}
+}
+
+func TestValidateOutputs(t *testing.T) {
+	raw, err := demo.Listings(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateValue("raw_listing", raw); err != nil {
+		t.Errorf("demo raw listings: %v", err)
+	}
+	logger := utils.NewLoggerTo(&bytes.Buffer{})
+	listings := services.NewCleaner(logger).Clean(raw)
+	if err := ValidateValue("listing", listings); err != nil {
+		t.Errorf("cleaned listings: %v", err)
+	}
+	if err := ValidateValue("insight_report", services.NewInsightService(logger).Generate(listings)); err != nil {
+		t.Errorf("insight report: %v", err)
+	}
+}
+
+func TestValidateRejects(t *testing.T) {
+	tests := []struct {
+		name, doc, want string
+	}{
+		{"listing", `{"Title": 5}`, "/Title: got integer, want string"},
+		{"listing", `[{}]`, `/0: missing required property`},
+		{"raw_listing", `"x"`, "got string, want object"},
+		{"insight_report", `{"Scope":"","TotalListings":1.5}`, "/TotalListings: got number, want integer"},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.name, []byte(tt.doc))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Validate(%s, %s) = %v, want error containing %q", tt.name, tt.doc, err, tt.want)
+		}
+	}
+
+	listing, _ := Get("listing")
+	if err := Validate("listing", listing); err == nil || !strings.Contains(err.Error(), "unexpected property") {
+		t.Errorf("a schema document is not a listing: %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// validator checks a decoded JSON value against the subset of JSON Schema
+// the generated documents use: type, format date-time, properties,
+// required, additionalProperties, items, anyOf and local $refs.
+type validator struct {
+	root map[string]any
+}
+
+func (v *validator) check(s map[string]any, val any, path string) error {
+	if ref, ok := s["$ref"].(string); ok {
+		def, err := v.resolve(ref)
+		if err != nil {
+			return err
+		}
+		return v.check(def, val, path)
+	}
+	if alts, ok := s["anyOf"].([]any); ok {
+		var errs []string
+		for _, alt := range alts {
+			err := v.check(alt.(map[string]any), val, path)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err.Error())
+		}
+		return fmt.Errorf("%s: matches no alternative (%s)", path, strings.Join(errs, "; "))
+	}
+	if typ, ok := s["type"]; ok {
+		if !typeMatches(typ, val) {
+			return fmt.Errorf("%s: got %s, want %v", path, jsonType(val), typ)
+		}
+	}
+	switch val := val.(type) {
+	case string:
+		if s["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, val); err != nil {
+				return fmt.Errorf("%s: %q is not an RFC 3339 date-time", path, val)
+			}
+		}
+	case []any:
+		if items, ok := s["items"].(map[string]any); ok {
+			for i, item := range val {
+				if err := v.check(items, item, fmt.Sprintf("%s/%d", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]any:
+		return v.object(s, val, path)
+	}
+	return nil
+}
+
+func (v *validator) object(s map[string]any, obj map[string]any, path string) error {
+	props, _ := s["properties"].(map[string]any)
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if p, ok := props[k]; ok {
+			if err := v.check(p.(map[string]any), obj[k], path+"/"+k); err != nil {
+				return err
+			}
+			continue
+		}
+		switch extra := s["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				return fmt.Errorf("%s: unexpected property %q", path, k)
+			}
+		case map[string]any:
+			if err := v.check(extra, obj[k], path+"/"+k); err != nil {
+				return err
+			}
+		}
+	}
+	if req, ok := s["required"].([]any); ok {
+		for _, name := range req {
+			if _, ok := obj[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+	}
+	return nil
+}
+
+func (v *validator) resolve(ref string) (map[string]any, error) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	defs, _ := v.root["$defs"].(map[string]any)
+	def, found := defs[name].(map[string]any)
+	if !ok || !found {
+		return nil, fmt.Errorf("schema: unresolvable $ref %q", ref)
+	}
+	return def, nil
+}
+
+func typeMatches(typ any, val any) bool {
+	switch typ := typ.(type) {
+	case string:
+		return typeIs(typ, val)
+	case []any:
+		for _, t := range typ {
+			if typeIs(t.(string), val) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeIs(typ string, val any) bool {
+	got := jsonType(val)
+	return got == typ || (typ == "number" && got == "integer")
+}
+
+// jsonType names val's JSON type; numbers must be decoded as json.Number.
+func jsonType(val any) string {
+	switch val := val.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if strings.ContainsAny(val.String(), ".eE") {
+			return "number"
+		}
+		return "integer"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", val)
+}
+
+// decode parses JSON keeping numbers exact, so integers can be told apart.
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
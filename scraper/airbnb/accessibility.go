@@ -0,0 +1,143 @@
+package airbnb
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/chromedp/cdproto/accessibility"
+	"github.com/chromedp/chromedp"
+)
+
+// The accessibility tree is Chrome's semantic view of the page: roles
+// (heading, button, StaticText) and accessible names, computed from the
+// markup and ARIA attributes. Airbnb must keep it usable for screen
+// readers, so it survives redesigns that rename the classes and test ids
+// the DOM extractor relies on. It is read only for fields the extractor
+// left empty.
+
+// axNode is the part of an accessibility node the fallback reads.
+type axNode struct {
+	Role  string
+	Name  string
+	Level int // heading level; 0 for other roles
+}
+
+// axFields are the values found in the accessibility tree; empty when absent.
+type axFields struct {
+	Title, Location, Price, Rating string
+}
+
+var (
+	axPriceRe    = regexp.MustCompile(`(?i)\$\s?[\d,]+(?:\.\d{2})?\s*(?:for\s+\d+\s*nights?|/?\s*night|per\s+night)`)
+	axRatingRe   = regexp.MustCompile(`(?i)\brated\s+([1-5]\.\d{1,2})\s+out of 5`)
+	axLocationRe = regexp.MustCompile(`\bin\s+([A-Z][^,\n]{2,50}(?:,\s*[A-Z][^\n]{2,40})?)`)
+)
+
+// axExtract picks the detail fields out of nodes in document order: the
+// first level-1 heading is the title, the first level-2 heading after it
+// reading "<kind> in <place>" gives the location (overlay headings come
+// before the title), the first per-night amount is the price and the
+// first "Rated 4.9 out of 5" name the rating.
+func axExtract(nodes []axNode) axFields {
+	var f axFields
+	for _, n := range nodes {
+		name := strings.TrimSpace(n.Name)
+		if name == "" {
+			continue
+		}
+		if n.Role == "heading" {
+			if n.Level == 1 && f.Title == "" {
+				f.Title = name
+			}
+			if m := axLocationRe.FindStringSubmatch(name); n.Level == 2 && m != nil && f.Title != "" && f.Location == "" {
+				f.Location = strings.TrimSpace(m[1])
+			}
+			continue
+		}
+		if m := axPriceRe.FindString(name); m != "" && f.Price == "" {
+			f.Price = strings.Join(strings.Fields(m), " ")
+		}
+		if m := axRatingRe.FindStringSubmatch(name); m != nil && f.Rating == "" {
+			f.Rating = m[1]
+		}
+	}
+	return f
+}
+
+// axNodes flattens the CDP nodes, skipping ignored ones. Roles and names
+// arrive as JSON values.
+func axNodes(raw []*accessibility.Node) []axNode {
+	out := make([]axNode, 0, len(raw))
+	for _, n := range raw {
+		if n.Ignored {
+			continue
+		}
+		node := axNode{Role: axString(n.Role), Name: axString(n.Name)}
+		for _, p := range n.Properties {
+			if p.Name == accessibility.PropertyNameLevel && p.Value != nil {
+				json.Unmarshal(p.Value.Value, &node.Level)
+			}
+		}
+		out = append(out, node)
+	}
+	return out
+}
+
+func axString(v *accessibility.Value) string {
+	if v == nil {
+		return ""
+	}
+	var s string
+	json.Unmarshal(v.Value, &s)
+	return s
+}
+
+// fillFromAX copies found values into the fields the extractor left empty
+// and returns the names of the fields it filled.
+func (d *detailData) fillFromAX(found axFields) []string {
+	if d.Src == nil {
+		d.Src = make(map[string]string)
+	}
+	var filled []string
+	for _, f := range []struct {
+		name  string
+		dst   *string
+		value string
+	}{
+		{"title", &d.Title, found.Title},
+		{"location", &d.Location, found.Location},
+		{"price", &d.Price, found.Price},
+		{"rating", &d.Rating, found.Rating},
+	} {
+		if *f.dst == "" && f.value != "" {
+			*f.dst = f.value
+			d.Src[f.name] = "ax-tree"
+			filled = append(filled, f.name)
+		}
+	}
+	return filled
+}
+
+// axFallback reads the accessibility tree when the DOM extractor missed
+// the title, location, price or rating. Failures are logged and ignored.
+func (s *Scraper) axFallback(t *tab, d *detailData) {
+	if d.Title != "" && d.Location != "" && d.Price != "" && d.Rating != "" {
+		return
+	}
+	var raw []*accessibility.Node
+	err := t.Run(chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		raw, err = accessibility.GetFullAXTree().Do(ctx)
+		return err
+	}))
+	if err != nil {
+		s.logger.Debug("[airbnb] Accessibility tree unavailable on %s: %v", t.url, err)
+		return
+	}
+	if filled := d.fillFromAX(axExtract(axNodes(raw))); len(filled) > 0 {
+		s.logger.Debug("[airbnb] Accessibility tree filled %s on %s", strings.Join(filled, ", "), t.url)
+		s.status.Add("ax_fallback", 1)
+	}
+}
@@ -0,0 +1,66 @@
+package airbnb
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/accessibility"
+)
+
+func axValue(json string) *accessibility.Value {
+	return &accessibility.Value{Value: []byte(json)}
+}
+
+func TestAXNodes(t *testing.T) {
+	raw := []*accessibility.Node{
+		{Role: axValue(`"heading"`), Name: axValue(`"Sunny loft"`),
+			Properties: []*accessibility.Property{{Name: accessibility.PropertyNameLevel, Value: axValue(`1`)}}},
+		{Ignored: true, Role: axValue(`"StaticText"`), Name: axValue(`"hidden"`)},
+		{Role: axValue(`"StaticText"`), Name: axValue(`"$95 per night"`)},
+		{Role: axValue(`"generic"`)},
+	}
+	got := axNodes(raw)
+	want := []axNode{
+		{Role: "heading", Name: "Sunny loft", Level: 1},
+		{Role: "StaticText", Name: "$95 per night"},
+		{Role: "generic"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d nodes %+v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("node %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestAXExtract(t *testing.T) {
+	nodes := []axNode{
+		{Role: "heading", Name: "Translated in English", Level: 2},
+		{Role: "heading", Name: "Sunny Alfama apartment", Level: 1},
+		{Role: "heading", Name: "Entire rental unit in Lisbon, Portugal", Level: 2},
+		{Role: "image", Name: "Rated 4.92 out of 5 stars."},
+		{Role: "StaticText", Name: "$600  for 5 nights"},
+		{Role: "StaticText", Name: "$95 per night"},
+		{Role: "heading", Name: "Another title", Level: 1},
+	}
+	got := axExtract(nodes)
+	want := axFields{Title: "Sunny Alfama apartment", Location: "Lisbon, Portugal", Price: "$600 for 5 nights", Rating: "4.92"}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestFillFromAX(t *testing.T) {
+	d := detailData{Title: "From the DOM", Src: map[string]string{"title": "lcp-h1"}}
+	filled := d.fillFromAX(axFields{Title: "From the tree", Price: "$95 per night"})
+	if len(filled) != 1 || filled[0] != "price" {
+		t.Errorf("filled = %v, want [price]", filled)
+	}
+	if d.Title != "From the DOM" || d.Src["title"] != "lcp-h1" {
+		t.Errorf("DOM title overwritten: %q (%s)", d.Title, d.Src["title"])
+	}
+	if d.Price != "$95 per night" || d.Src["price"] != "ax-tree" {
+		t.Errorf("price = %q (%s)", d.Price, d.Src["price"])
+	}
+}
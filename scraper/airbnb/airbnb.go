@@ -2,9 +2,11 @@ package airbnb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -12,139 +14,387 @@ import (
 	"github.com/chromedp/chromedp"
 
 	"airbnb-scraper/config"
+	"airbnb-scraper/events"
 	"airbnb-scraper/models"
+	"airbnb-scraper/storage"
 	"airbnb-scraper/utils"
 )
 
 const (
-	startURL           = "https://www.airbnb.com/"
+	defaultBaseURL     = "https://www.airbnb.com"
 	platform           = "airbnb"
 	listingsPerSection = 10
+	userAgent          = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 " +
+		"(KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
 )
 
 // cardInfo holds data scraped directly from a homepage listing card.
 type cardInfo struct {
-	URL    string `json:"url"`
-	Title  string `json:"title"`
-	Price  string `json:"price"`  // non-strikethrough price e.g. "$125 for 2 nights"
-	Rating string `json:"rating"` // e.g. "4.88"
+	URL     string            `json:"url"`
+	Title   string            `json:"title"`
+	Price   string            `json:"price"`   // non-strikethrough price e.g. "$125 for 2 nights"
+	Rating  string            `json:"rating"`  // e.g. "4.88"
+	Reviews string            `json:"reviews"` // e.g. "3,215"
+	Sources map[string]string `json:"src"`     // field → extraction strategy
 }
 
-// section represents a named homepage section with full card data.
+// section represents a named group of listing cards — a homepage section or
+// a batch of sitemap URLs. Location is empty when the name carries no place.
+// Paged sections were already capped page by page, so all their cards are
+// scraped instead of the first listingsPerSection.
 type section struct {
-	Name  string
-	Cards []cardInfo
+	Name     string
+	Location string
+	Cards    []cardInfo
+	Paged    bool
 }
 
 type Scraper struct {
 	cfg        *config.Config
 	logger     *utils.Logger
 	pool       *utils.WorkerPool
-	visitedURL *utils.URLSet
+	details    *utils.Pool[detailResult]
+	seen       utils.SeenSet // cards taken this run
+	visitedURL utils.SeenSet // listings enriched by earlier runs; nil = none
 	retry      *utils.RetryConfig
+	archiver   storage.PageArchiver
+	blocklist  *utils.IDList
+	allowlist  *utils.IDList
+	window     *utils.TimeWindow
+	throttle   *utils.Throttle
+	rand       *utils.Random
+	status     *utils.StatusBoard
+	events     *events.Bus
+	pages      *utils.PageBudget
+	cooldown   *utils.Cooldown
+	tuner      *utils.AutoTuner
+	canaries   *utils.IDList
+	tracked    *utils.IDList
+	stay       Stay // CHECK_IN, CHECK_OUT and GUESTS
+
+	ctx context.Context // set by SetContext or ScrapeContext
+
+	degradeOnce sync.Once
+	pagesOnce   sync.Once
 
 	mu       sync.Mutex
+	failures map[string]int // detail-page failures by errorClass
 	listings []*models.RawListing
+	total    int
+	output   chan<- []*models.RawListing
 }
 
 func New(cfg *config.Config, logger *utils.Logger) *Scraper {
-	return &Scraper{
-		cfg:        cfg,
-		logger:     logger,
-		pool:       utils.NewWorkerPool(cfg.MaxConcurrency, cfg.RateLimitMs),
-		visitedURL: utils.NewURLSet(),
+	s := &Scraper{
+		cfg:    cfg,
+		logger: logger,
+		pool:   utils.NewWorkerPool(cfg.MaxConcurrency, cfg.RateLimitMs),
+		seen:   utils.NewURLSet(),
 		retry: &utils.RetryConfig{
 			MaxAttempts: cfg.MaxRetries,
 			BaseDelay:   2 * time.Second,
 			Logger:      logger,
+			Retryable:   retryable,
 		},
 		listings: make([]*models.RawListing, 0),
 	}
+	s.retry.OnFailure = s.noteThrottle
+	s.SetRandom(utils.NewRandom(cfg.RandomSeed))
+	s.SetCooldown(utils.NewCooldown(cfg.CooldownBase, cfg.CooldownMax))
+	s.pool.SetJobTimeout(cfg.JobTimeout)
+	s.pool.SetBeforeJob(func() { s.window.Wait(s.parent(), s.logger) })
+	s.pool.SetErrorHandler(s.jobFailed)
+	s.details = utils.NewPool[detailResult](s.pool)
+	s.details.SetPriority(s.priority)
+	s.stay, _ = ParseStay(cfg.CheckIn, cfg.CheckOut, cfg.Guests) // validated when the config is loaded
+	s.canaries, _ = utils.LoadIDList("")
+	for _, u := range cfg.CanaryURLs {
+		s.canaries.Add(u)
+	}
+	if cfg.AutoTune {
+		s.tuner = utils.NewAutoTuner(cfg.AutoTuneStart, cfg.MaxConcurrency, cfg.AutoTuneMinSuccess, cfg.AutoTuneWindow)
+		s.pool.SetAutoTune(s.tuner)
+	}
+	return s
+}
+
+// SetArchiver makes the scraper record every navigated page to the archiver.
+func (s *Scraper) SetArchiver(a storage.PageArchiver) {
+	s.archiver = a
+}
+
+// SetListFilters installs the persisted blocklist (never scraped) and
+// allowlist (when non-empty, the only listings scraped). Either may be nil.
+func (s *Scraper) SetListFilters(blocklist, allowlist *utils.IDList) {
+	s.blocklist = blocklist
+	s.allowlist = allowlist
+}
+
+// SetSeen replaces the set that drops cards already taken this run, e.g.
+// with a bloom filter for sitemap-scale crawls.
+func (s *Scraper) SetSeen(v utils.SeenSet) {
+	s.seen = v
+}
+
+// SetVisited installs the set of listings enriched by earlier runs (and
+// other scrapers sharing it). Their cards are still emitted, with the card's
+// price and rating, but their detail pages are not loaded again until their
+// entries expire.
+func (s *Scraper) SetVisited(v utils.SeenSet) {
+	s.visitedURL = v
+}
+
+// SetOutput streams each finished section to ch instead of accumulating
+// listings for Scrape's return value. Sends block when the consumer falls
+// behind, which throttles the scraper. The caller owns and closes ch.
+func (s *Scraper) SetOutput(ch chan<- []*models.RawListing) {
+	s.output = ch
+}
+
+// emit hands a finished batch to the output channel, or keeps it for Scrape's
+// return value when no output is set, and returns the running total.
+func (s *Scraper) emit(batch []*models.RawListing) int {
+	s.mu.Lock()
+	s.total += len(batch)
+	total := s.total
+	if s.output == nil {
+		s.listings = append(s.listings, batch...)
+	}
+	s.mu.Unlock()
+
+	if s.output != nil {
+		s.output <- batch
+	}
+	return total
+}
+
+// SetThrottle makes rate limit, concurrency and the section filter follow t,
+// so an operator can slow down or narrow a scrape while it runs.
+func (s *Scraper) SetThrottle(t *utils.Throttle) {
+	s.throttle = t
+	s.pool.SetThrottle(t)
+}
+
+// rateLimit is the pause between crawl levels and warm-up pages; detail
+// pages are paced by the pool.
+func (s *Scraper) rateLimit() time.Duration {
+	d := time.Duration(s.cfg.RateLimitMs) * time.Millisecond
+	if s.throttle != nil {
+		d = s.throttle.RateLimit()
+	}
+	return s.rand.Jitter(d, s.cfg.RateJitter)
+}
+
+// SetRandom makes r the source of every random choice: RATE_JITTER on
+// pauses and retry back-off, USER_AGENTS rotation and SAMPLE_CARDS. Share
+// one seeded source across a run to make it reproducible.
+func (s *Scraper) SetRandom(r *utils.Random) {
+	s.rand = r
+	jitter := func(d time.Duration) time.Duration { return r.Jitter(d, s.cfg.RateJitter) }
+	s.pool.SetJitter(jitter)
+	s.retry.Jitter = jitter
+}
+
+// pickUserAgent returns one of USER_AGENTS, or the built-in user agent.
+func (s *Scraper) pickUserAgent() string {
+	if len(s.cfg.UserAgents) == 0 {
+		return userAgent
+	}
+	return s.rand.Pick(s.cfg.UserAgents)
+}
+
+// pickCards caps a section at listingsPerSection cards: its first ones, or
+// with SAMPLE_CARDS a random subset in page order.
+func (s *Scraper) pickCards(cards []cardInfo) []cardInfo {
+	if len(cards) <= listingsPerSection {
+		return cards
+	}
+	if !s.cfg.SampleCards {
+		return cards[:listingsPerSection]
+	}
+	out := make([]cardInfo, 0, listingsPerSection)
+	for _, i := range s.rand.Sample(len(cards), listingsPerSection) {
+		out = append(out, cards[i])
+	}
+	return out
+}
+
+// SetRetryBudget shares a run-wide retry budget with this scraper. Once it is
+// spent the scraper stops visiting detail pages and emits what the cards
+// already gave it, rather than hammering a site that is blocking it.
+func (s *Scraper) SetRetryBudget(b *utils.RetryBudget) {
+	s.retry.Budget = b
+}
+
+// degraded reports whether the retry budget is spent, logging the switch once.
+func (s *Scraper) degraded() bool {
+	b := s.retry.Budget
+	if b == nil || !b.Exhausted() {
+		return false
+	}
+	s.degradeOnce.Do(func() {
+		s.logger.Warn("[airbnb] Retry budget of %d spent — skipping detail pages, keeping card data", b.Limit())
+	})
+	return true
+}
+
+// SetPageBudget caps the browser page loads this scraper may make, shared
+// with the rest of the run. Once it is spent, pending detail pages are
+// skipped and no further sections are started; what was scraped is kept.
+func (s *Scraper) SetPageBudget(b *utils.PageBudget) {
+	s.pages = b
+	s.pool.SetPageBudget(b)
+}
+
+// pagesSpent reports whether the page budget is exhausted, logging it once.
+func (s *Scraper) pagesSpent() bool {
+	if s.pages == nil || !s.pages.Exhausted() {
+		return false
+	}
+	s.pagesOnce.Do(func() {
+		s.logger.Warn("[airbnb] Page budget (%s) spent — ending the scrape with what was collected", s.pages)
+	})
+	return true
+}
+
+// SetStatus reports page loads in flight and their outcomes to b, for the
+// run's status dump.
+func (s *Scraper) SetStatus(b *utils.StatusBoard) {
+	s.status = b
+}
+
+// SetEvents publishes the scraper's listings, completed sections and
+// detail-page failures on b. A nil bus publishes nothing.
+func (s *Scraper) SetEvents(b *events.Bus) {
+	s.events = b
+}
+
+// SetTimeWindow restricts scraping to a daily time window; work pauses
+// automatically while outside it. A nil window means no restriction.
+func (s *Scraper) SetTimeWindow(w *utils.TimeWindow) {
+	s.window = w
 }
 
 // Scrape is the main entry point:
 //  1. Opens airbnb.com, discovers all named sections
 //  2. Reads price + rating directly from cards on homepage (no detail page needed for those)
 //  3. Visits detail page only for title, location, description
+//
+// When an output channel is set, listings are streamed section by section and
+// the returned slice is empty.
 func (s *Scraper) Scrape() ([]*models.RawListing, error) {
 	s.logger.Info("[airbnb] Starting scrape — %d listings per section", listingsPerSection)
 
-	chromeBin := findChromeBinary()
-	s.logger.Info("[airbnb] Using browser binary: %s", chromeBin)
+	allocCtx, closeBrowser := s.newBrowser()
+	defer closeBrowser()
 
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", true),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.Flag("disable-setuid-sandbox", true),
-		chromedp.UserAgent("Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 "+
-			"(KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"),
-	)
-	if chromeBin != "" {
-		opts = append(opts, chromedp.ExecPath(chromeBin))
+	if s.cfg.Warmup {
+		s.warmUp(allocCtx)
 	}
 
-	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), opts...)
-	defer cancelAlloc()
-
-	silentCtx, cancelSilent := chromedp.NewContext(allocCtx,
-		chromedp.WithLogf(func(string, ...interface{}) {}),
-		chromedp.WithErrorf(func(string, ...interface{}) {}),
-		chromedp.WithDebugf(func(string, ...interface{}) {}),
-	)
-	defer cancelSilent()
-	allocCtx = silentCtx
-
 	// ── Step 1: discover sections + card data ─────────────────────────────
-	s.logger.Info("[airbnb] Loading homepage to discover sections…")
-	sections, err := s.discoverSections(allocCtx)
+	var sections []section
+	var err error
+	switch s.cfg.DiscoveryMode {
+	case "allowlist":
+		s.logger.Info("[airbnb] Scraping allowlisted listings only…")
+		sections = s.allowlistSections()
+	case "search":
+		s.logger.Info("[airbnb] Loading search results for %q…", s.cfg.SearchQuery)
+		sections, err = s.discoverFromSearch(allocCtx)
+	case "sitemap":
+		s.logger.Info("[airbnb] Reading sitemaps to discover room URLs…")
+		sections, err = s.discoverFromSitemaps()
+	default:
+		s.logger.Info("[airbnb] Loading homepage to discover sections…")
+		sections, err = s.discoverSections(allocCtx)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("could not discover homepage sections: %w", err)
+		return nil, fmt.Errorf("could not discover %s sections: %w", s.cfg.DiscoveryMode, err)
 	}
 	if len(sections) == 0 {
-		return nil, fmt.Errorf("no sections found on homepage")
+		return nil, fmt.Errorf("no sections found via %s discovery", s.cfg.DiscoveryMode)
 	}
 
-	s.logger.Info("[airbnb] Found %d sections on homepage", len(sections))
+	s.logger.Info("[airbnb] Found %d sections via %s discovery", len(sections), s.cfg.DiscoveryMode)
 	for i, sec := range sections {
 		s.logger.Info("[airbnb]   Section %d: %q (%d cards)", i+1, sec.Name, len(sec.Cards))
 	}
 
 	// ── Step 2: process each section ──────────────────────────────────────
+	// Sections are submitted back to back and finished in order as their
+	// detail pages complete, so workers move on to the next section's pages
+	// instead of idling while the slowest page of this one loads.
+	var frontier []string
+	type pendingSection struct {
+		name     string
+		listings []*models.RawListing
+		batch    *enrichment
+	}
+	pending := make(chan pendingSection, len(sections))
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		for p := range pending {
+			frontier = append(frontier, p.batch.wait()...)
+			s.finishSection(p.name, p.listings)
+		}
+	}()
+
 	totalSections := len(sections)
 	for secIdx, sec := range sections {
 		secNum := secIdx + 1
-		s.printSectionBanner(secNum, totalSections, sec.Name, len(sec.Cards))
+		if s.pagesSpent() || s.cancelled() {
+			break
+		}
+		if s.window.Wait(s.parent(), s.logger) != nil {
+			break
+		}
+		if s.throttle != nil && !s.throttle.SectionAllowed(sec.Name) {
+			s.logger.Info("[airbnb] Section %q excluded by section filter — skipping", sec.Name)
+			continue
+		}
+		cards := sec.Cards
+		if !sec.Paged {
+			cards = s.pickCards(cards)
+		}
+		s.printSectionBanner(secNum, totalSections, sec.Name, len(sec.Cards), len(cards))
 
 		if len(sec.Cards) == 0 {
 			s.logger.Warn("[airbnb] Section %q has no cards — skipping", sec.Name)
 			continue
 		}
 
-		cards := sec.Cards
-		if len(cards) > listingsPerSection {
-			cards = cards[:listingsPerSection]
-		}
-
-		sectionLocation := extractLocationFromSection(sec.Name)
+		sectionLocation := sec.Location
 
 		// Build RawListings directly from card data — price + rating already extracted
 		var sectionListings []*models.RawListing
 		for _, card := range cards {
-			if !s.visitedURL.Add(card.URL) {
-				s.logger.Debug("[airbnb] Duplicate URL skipped: %s", card.URL)
+			if !s.permitted(card.URL) {
 				continue
 			}
-			sectionListings = append(sectionListings, &models.RawListing{
-				URL:       card.URL,
-				Title:     card.Title,
-				RawPrice:  card.Price,
-				Rating:    card.Rating,
-				Location:  sectionLocation,
-				ScrapedAt: time.Now(),
-				Platform:  platform,
-			})
+			if !s.seen.Add(card.URL) {
+				s.logger.Debug("[airbnb] Duplicate card skipped: %s", card.URL)
+				continue
+			}
+			raw := &models.RawListing{
+				URL:           card.URL,
+				Title:         card.Title,
+				RawPrice:      card.Price,
+				Rating:        card.Rating,
+				ReviewCount:   card.Reviews,
+				Location:      sectionLocation,
+				ScrapedAt:     time.Now(),
+				Platform:      platform,
+				SchemaVersion: models.RawSchemaVersion,
+			}
+			for field, strategy := range card.Sources {
+				recordSource(raw, field, "card:"+strategy, raw.ScrapedAt)
+			}
+			if sectionLocation != "" {
+				recordSource(raw, "location", "section-heading", raw.ScrapedAt)
+			}
+			sectionListings = append(sectionListings, raw)
 		}
 
 		if len(sectionListings) == 0 {
@@ -154,50 +404,199 @@ func (s *Scraper) Scrape() ([]*models.RawListing, error) {
 		}
 
 		// ── Step 3: visit detail pages for title, location, description only
-		s.logger.Info("[airbnb]   Enriching %d listings (title/location/desc from detail pages)…", len(sectionListings))
-		s.enrichListings(allocCtx, sectionListings)
-
-		for i, l := range sectionListings {
-			pricePreview := l.RawPrice
-			if len(pricePreview) > 30 {
-				pricePreview = pricePreview[:30]
-			}
-			s.logger.Info("[airbnb]   ✓ [%d/%d] %s | %s | price=%s | rating=%s",
-				i+1, len(sectionListings),
-				truncateStr(l.Title, 35),
-				l.Location,
-				pricePreview,
-				l.Rating,
-			)
+		enrich := s.unvisited(sectionListings)
+		if skipped := len(sectionListings) - len(enrich); skipped > 0 {
+			s.logger.Info("[airbnb]   %d listings visited by earlier runs keep their card data only", skipped)
 		}
+		s.logger.Info("[airbnb]   Enriching %d listings (title/location/desc from detail pages)…", len(enrich))
+		pending <- pendingSection{sec.Name, sectionListings, s.submitEnrichment(allocCtx, enrich)}
+	}
+	close(pending)
+	<-finished
 
-		s.mu.Lock()
-		s.listings = append(s.listings, sectionListings...)
-		total := len(s.listings)
-		s.mu.Unlock()
-
-		s.printSectionDone(sec.Name)
-		s.logger.Info("[airbnb] Running total: %d listings", total)
-
-		time.Sleep(time.Duration(s.cfg.RateLimitMs) * time.Millisecond)
+	// ── Step 4: optional BFS over "Similar listings" links ────────────────
+	if s.cfg.SimilarCrawlDepth > 0 && !s.degraded() && !s.pagesSpent() && !s.cancelled() {
+		s.crawlSimilar(allocCtx, frontier)
 	}
 
 	s.logger.Info("[airbnb] ══════════════════════════════════════════")
-	s.logger.Info("[airbnb] Scrape complete — total raw listings: %d", len(s.listings))
+	s.logger.Info("[airbnb] Scrape complete — total raw listings: %d", s.total)
+	if f := s.Failures(); len(f) > 0 {
+		s.logger.Info("[airbnb] Detail page failures: %s", formatFailures(f))
+	}
 	s.logger.Info("[airbnb] ══════════════════════════════════════════")
 	return s.listings, nil
 }
 
+// finishSection logs a section's enriched listings and emits them.
+func (s *Scraper) finishSection(name string, listings []*models.RawListing) {
+	for i, l := range listings {
+		s.events.Publish(events.ListingScraped{Section: name, Listing: l})
+		pricePreview := l.RawPrice
+		if len(pricePreview) > 30 {
+			pricePreview = pricePreview[:30]
+		}
+		s.logger.Info("[airbnb]   ✓ [%d/%d] %s | %s | price=%s | rating=%s",
+			i+1, len(listings),
+			truncateStr(l.Title, 35),
+			l.Location,
+			pricePreview,
+			l.Rating,
+		)
+	}
+
+	total := s.emit(listings)
+	s.events.Publish(events.SectionCompleted{Section: name, Listings: len(listings), Total: total})
+
+	s.printSectionDone(name)
+	s.logger.Info("[airbnb] Running total: %d listings", total)
+}
+
 // ── Section + card discovery ─────────────────────────────────────────────────
 
+// cardExtractorJS defines extractCard(a), which reads URL, title, price and
+// rating from the listing card around a /rooms/ anchor. Shared by the
+// homepage and search-results scripts; globalSeen dedupes across calls.
+const cardExtractorJS = `
+	var globalSeen = {};
+
+	// ── Extract price + rating from a single card anchor element ──
+	function extractCard(a) {
+		var url = a.href.split('?')[0];
+		if (!url || globalSeen[url]) return null;
+
+		// Walk up to find the card container
+		var card = a;
+		for (var up = 0; up < 8; up++) {
+			if (!card.parentElement) break;
+			card = card.parentElement;
+			// Stop when we have a sizeable container with the listing info
+			if (card.querySelectorAll('a[href*="/rooms/"]').length === 1 &&
+			    card.innerText && card.innerText.length > 30) break;
+		}
+
+		var title   = '';
+		var price   = '';
+		var rating  = '';
+		var reviews = '';
+		var src     = {};
+
+		// ── Rating ──
+		// Appears as "4.88" or "★ 4.88 (3215)" or aria-label="Rated 4.88 out of 5"
+		var ratingEl = card.querySelector('[aria-label*="out of 5"]') ||
+		               card.querySelector('[aria-label*="Rated"]');
+		if (ratingEl) {
+			var rt = ratingEl.getAttribute('aria-label') || ratingEl.innerText || '';
+			var rm = rt.match(/([1-5]\.\d{1,2})/);
+			if (rm) { rating = rm[1]; src.rating = 'aria-label'; }
+		}
+		if (!rating) {
+			// Scan text lines for standalone "4.xx" or "4.xx (NNN)"
+			var lines = (card.innerText || '').split('\n');
+			for (var li = 0; li < lines.length; li++) {
+				var l = lines[li].trim();
+				var rm2 = l.match(/^([1-5]\.\d{2})(?:\s*\(|$)/);
+				if (rm2) { rating = rm2[1]; src.rating = 'text-line'; break; }
+			}
+		}
+		if (!rating && /(^|\n)\s*(★\s*)?New\s*(\n|$)/.test(card.innerText || '')) {
+			// Listed too recently to be rated: "★ New" stands in for the rating
+			rating = 'New'; src.rating = 'new-badge';
+		}
+
+		// ── Review count ──
+		// "4.88 (3215)" on the card, or "... 3,215 reviews" in the aria-label
+		var rcText = (ratingEl && ratingEl.getAttribute('aria-label')) || '';
+		var rc = rcText.match(/([\d,]+)\s+reviews?/i);
+		if (rc) {
+			src.reviews = 'aria-label';
+		} else {
+			rc = (card.innerText || '').match(/[1-5]\.\d{1,2}\s*\(([\d,]+)\)/);
+			if (rc) src.reviews = 'rating-parenthesis';
+		}
+		if (rc) reviews = rc[1];
+
+		// ── Price ──
+		// Card shows: [strikethrough $142] $125 for 2 nights
+		// We want the NON-strikethrough price.
+		// Strategy: walk all child elements, collect $ amounts NOT inside <s>/<del>
+		// and NOT having computed text-decoration:line-through
+		var nights = 0;
+		var cardText = card.innerText || '';
+		var nm = cardText.match(/for\s+(\d+)\s*nights?/i);
+		if (nm) nights = parseInt(nm[1]);
+
+		var nonStruckAmounts = [];
+		var allEls = card.querySelectorAll('*');
+		for (var ei = 0; ei < allEls.length; ei++) {
+			var el = allEls[ei];
+			// Only consider leaf text nodes with a dollar sign
+			if (el.children.length > 0) continue;
+			var txt = (el.innerText || '').trim();
+			if (!txt.match(/^\$\d/)) continue;
+
+			// Check this element and up to 4 ancestors for strikethrough
+			var struck = false;
+			var check = el;
+			for (var d = 0; d < 5; d++) {
+				if (!check) break;
+				var tag = (check.tagName || '').toLowerCase();
+				if (tag === 's' || tag === 'del') { struck = true; break; }
+				try {
+					var cs = window.getComputedStyle(check);
+					if (cs && cs.textDecorationLine &&
+					    cs.textDecorationLine.includes('line-through')) {
+						struck = true; break;
+					}
+				} catch(e) {}
+				check = check.parentElement;
+			}
+
+			if (!struck) {
+				var val = parseFloat(txt.replace(/[$,]/g, ''));
+				if (val > 0 && val < 50000) nonStruckAmounts.push(val);
+			}
+		}
+
+		if (nonStruckAmounts.length > 0) {
+			// Take the smallest non-struck amount = current nightly/stay price
+			var currentPrice = nonStruckAmounts.reduce(function(a, b) { return a < b ? a : b; });
+			src.price = 'non-struck-amount';
+			if (nights > 1) {
+				price = '$' + currentPrice + ' for ' + nights + ' nights';
+			} else if (nights === 1) {
+				price = '$' + currentPrice + ' per night';
+			} else {
+				price = '$' + currentPrice;
+			}
+		}
+
+		// ── Title ──
+		var titleEl = card.querySelector('[data-testid="listing-card-title"]') ||
+		              card.querySelector('span[class*="t1jojoys"]') ||
+		              card.querySelector('div[id*="title"]');
+		if (titleEl) {
+			title = titleEl.innerText.trim();
+			src.title = 'title-element';
+		} else {
+			// Fallback: first bold/strong text in card
+			var boldEl = card.querySelector('strong, b, span[class*="title"]');
+			if (boldEl) { title = boldEl.innerText.trim(); src.title = 'bold-text'; }
+		}
+
+		globalSeen[url] = true;
+		return { url: url, title: title, price: price, rating: rating, reviews: reviews, src: src };
+	}
+`
+
 func (s *Scraper) discoverSections(allocCtx context.Context) ([]section, error) {
 	var sections []section
 
+	defer s.status.Begin(s.startURL())()
 	err := s.retry.Do("discover-sections", func() error {
-		ctx, cancel := chromedp.NewContext(allocCtx)
-		defer cancel()
-		ctx, cancelTimeout := context.WithTimeout(ctx, 90*time.Second)
-		defer cancelTimeout()
+		ctx, tab := s.openTab(allocCtx, "homepage", s.startURL(), 90*time.Second)
+		defer tab.Close()
+		flush := s.startCapture(ctx)
 
 		type jsSection struct {
 			Name  string     `json:"name"`
@@ -205,9 +604,10 @@ func (s *Scraper) discoverSections(allocCtx context.Context) ([]section, error)
 		}
 		var jsSections []jsSection
 
-		err := chromedp.Run(ctx,
-			chromedp.Navigate(startURL),
+		err := tab.Run(
+			chromedp.Navigate(s.startURL()),
 			chromedp.Sleep(6*time.Second),
+			s.dismissPopups(),
 			chromedp.Evaluate(`window.scrollTo(0, document.body.scrollHeight * 0.3)`, nil),
 			chromedp.Sleep(2*time.Second),
 			chromedp.Evaluate(`window.scrollTo(0, document.body.scrollHeight * 0.6)`, nil),
@@ -218,115 +618,7 @@ func (s *Scraper) discoverSections(allocCtx context.Context) ([]section, error)
 			chromedp.Evaluate(`
 				(function() {
 					var results = [];
-					var globalSeen = {};
-
-					// ── Extract price + rating from a single card anchor element ──
-					function extractCard(a) {
-						var url = a.href.split('?')[0];
-						if (!url || globalSeen[url]) return null;
-
-						// Walk up to find the card container
-						var card = a;
-						for (var up = 0; up < 8; up++) {
-							if (!card.parentElement) break;
-							card = card.parentElement;
-							// Stop when we have a sizeable container with the listing info
-							if (card.querySelectorAll('a[href*="/rooms/"]').length === 1 &&
-							    card.innerText && card.innerText.length > 30) break;
-						}
-
-						var title  = '';
-						var price  = '';
-						var rating = '';
-
-						// ── Rating ──
-						// Appears as "4.88" or "★ 4.88 (3215)" or aria-label="Rated 4.88 out of 5"
-						var ratingEl = card.querySelector('[aria-label*="out of 5"]') ||
-						               card.querySelector('[aria-label*="Rated"]');
-						if (ratingEl) {
-							var rt = ratingEl.getAttribute('aria-label') || ratingEl.innerText || '';
-							var rm = rt.match(/([1-5]\.\d{1,2})/);
-							if (rm) rating = rm[1];
-						}
-						if (!rating) {
-							// Scan text lines for standalone "4.xx" or "4.xx (NNN)"
-							var lines = (card.innerText || '').split('\n');
-							for (var li = 0; li < lines.length; li++) {
-								var l = lines[li].trim();
-								var rm2 = l.match(/^([1-5]\.\d{2})(?:\s*\(|$)/);
-								if (rm2) { rating = rm2[1]; break; }
-							}
-						}
-
-						// ── Price ──
-						// Card shows: [strikethrough $142] $125 for 2 nights
-						// We want the NON-strikethrough price.
-						// Strategy: walk all child elements, collect $ amounts NOT inside <s>/<del>
-						// and NOT having computed text-decoration:line-through
-						var nights = 0;
-						var cardText = card.innerText || '';
-						var nm = cardText.match(/for\s+(\d+)\s*nights?/i);
-						if (nm) nights = parseInt(nm[1]);
-
-						var nonStruckAmounts = [];
-						var allEls = card.querySelectorAll('*');
-						for (var ei = 0; ei < allEls.length; ei++) {
-							var el = allEls[ei];
-							// Only consider leaf text nodes with a dollar sign
-							if (el.children.length > 0) continue;
-							var txt = (el.innerText || '').trim();
-							if (!txt.match(/^\$\d/)) continue;
-
-							// Check this element and up to 4 ancestors for strikethrough
-							var struck = false;
-							var check = el;
-							for (var d = 0; d < 5; d++) {
-								if (!check) break;
-								var tag = (check.tagName || '').toLowerCase();
-								if (tag === 's' || tag === 'del') { struck = true; break; }
-								try {
-									var cs = window.getComputedStyle(check);
-									if (cs && cs.textDecorationLine &&
-									    cs.textDecorationLine.includes('line-through')) {
-										struck = true; break;
-									}
-								} catch(e) {}
-								check = check.parentElement;
-							}
-
-							if (!struck) {
-								var val = parseFloat(txt.replace(/[$,]/g, ''));
-								if (val > 0 && val < 50000) nonStruckAmounts.push(val);
-							}
-						}
-
-						if (nonStruckAmounts.length > 0) {
-							// Take the smallest non-struck amount = current nightly/stay price
-							var currentPrice = nonStruckAmounts.reduce(function(a, b) { return a < b ? a : b; });
-							if (nights > 1) {
-								price = '$' + currentPrice + ' for ' + nights + ' nights';
-							} else if (nights === 1) {
-								price = '$' + currentPrice + ' per night';
-							} else {
-								price = '$' + currentPrice;
-							}
-						}
-
-						// ── Title ──
-						var titleEl = card.querySelector('[data-testid="listing-card-title"]') ||
-						              card.querySelector('span[class*="t1jojoys"]') ||
-						              card.querySelector('div[id*="title"]');
-						if (titleEl) {
-							title = titleEl.innerText.trim();
-						} else {
-							// Fallback: first bold/strong text in card
-							var boldEl = card.querySelector('strong, b, span[class*="title"]');
-							if (boldEl) title = boldEl.innerText.trim();
-						}
-
-						globalSeen[url] = true;
-						return { url: url, title: title, price: price, rating: rating };
-					}
+					`+cardExtractorJS+`
 
 					function addSection(name, cards) {
 						if (!name || cards.length === 0) return;
@@ -404,12 +696,18 @@ func (s *Scraper) discoverSections(allocCtx context.Context) ([]section, error)
 		)
 
 		if err != nil {
-			return fmt.Errorf("chromedp discover sections: %w", err)
+			return navError("chromedp discover sections", err)
 		}
+		flush()
 
 		if len(jsSections) == 0 {
+			var state string
+			_ = tab.Run(chromedp.Evaluate(`(function() {`+pageStateJS+` return pageState(false); })()`, &state))
+			if err := stateError(state, s.startURL()); err != nil {
+				return err
+			}
 			var debugInfo string
-			_ = chromedp.Run(ctx, chromedp.Evaluate(`
+			_ = tab.Run(chromedp.Evaluate(`
 				(function() {
 					var h = Array.from(document.querySelectorAll('h2,h3')).slice(0,10).map(function(e){ return e.innerText.trim(); }).join(' | ');
 					var links = document.querySelectorAll('a[href*="/rooms/"]').length;
@@ -421,9 +719,11 @@ func (s *Scraper) discoverSections(allocCtx context.Context) ([]section, error)
 		}
 
 		for _, js := range jsSections {
+			name := strings.TrimSpace(js.Name)
 			sections = append(sections, section{
-				Name:  strings.TrimSpace(js.Name),
-				Cards: js.Cards,
+				Name:     name,
+				Location: extractLocationFromSection(name),
+				Cards:    js.Cards,
 			})
 		}
 		return nil
@@ -432,219 +732,338 @@ func (s *Scraper) discoverSections(allocCtx context.Context) ([]section, error)
 	return sections, err
 }
 
-// ── Detail page enrichment (title, location, description only) ───────────────
+// newBrowser starts a headless browser and returns a context that detail and
+// discovery pages are opened from, plus the function that shuts it down.
+func (s *Scraper) newBrowser() (context.Context, context.CancelFunc) {
+	chromeBin := findChromeBinary()
+	s.logger.Info("[airbnb] Using browser binary: %s", chromeBin)
+	ua := s.pickUserAgent()
+	if len(s.cfg.UserAgents) > 0 {
+		s.logger.Info("[airbnb] User agent: %s", ua)
+	}
 
-func (s *Scraper) enrichListings(allocCtx context.Context, listings []*models.RawListing) {
-	for _, listing := range listings {
-		l := listing
-		if l.URL == "" {
-			continue
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", true),
+		chromedp.Flag("disable-gpu", true),
+		chromedp.Flag("no-sandbox", true),
+		chromedp.Flag("disable-dev-shm-usage", true),
+		chromedp.Flag("disable-setuid-sandbox", true),
+		chromedp.UserAgent(ua),
+	)
+	if chromeBin != "" {
+		opts = append(opts, chromedp.ExecPath(chromeBin))
+	}
+	releaseCache := func() {}
+	if s.cfg.BrowserCacheDir != "" && s.cfg.BrowserCacheDir != "none" {
+		// Tabs share the browser's cache; a persistent cache dir also keeps
+		// it across browsers and runs.
+		var dir string
+		dir, releaseCache = acquireCacheDir(s.cfg.BrowserCacheDir)
+		opts = append(opts,
+			chromedp.Flag("disk-cache-dir", dir),
+			chromedp.Flag("disk-cache-size", strconv.Itoa(s.cfg.BrowserCacheMB<<20)),
+		)
+	}
+
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(s.parent(), opts...)
+	silentCtx, cancelSilent := chromedp.NewContext(allocCtx,
+		chromedp.WithLogf(func(string, ...interface{}) {}),
+		chromedp.WithErrorf(func(string, ...interface{}) {}),
+		chromedp.WithDebugf(func(string, ...interface{}) {}),
+	)
+	return silentCtx, func() {
+		cancelSilent()
+		cancelAlloc()
+		releaseCache()
+	}
+}
+
+// FetchCanaries scrapes the detail page of each canary URL, in order. A page
+// that cannot be loaded yields a listing carrying only its URL so the caller
+// sees it as failing its expectations.
+func (s *Scraper) FetchCanaries(urls []string) []*models.RawListing {
+	allocCtx, closeBrowser := s.newBrowser()
+	defer closeBrowser()
+
+	out := make([]*models.RawListing, 0, len(urls))
+	for _, u := range urls {
+		listing, _, err := s.scrapeDetailPage(allocCtx, u)
+		if err != nil {
+			s.logger.Warn("[airbnb] Canary %s failed to load: %v", u, err)
+			listing = &models.RawListing{URL: u, Platform: platform, SchemaVersion: models.RawSchemaVersion}
 		}
-		s.pool.Submit(func() {
-			enriched, err := s.scrapeDetailPage(allocCtx, l.URL)
-			if err != nil {
-				s.logger.Warn("[airbnb] Detail page failed for %s: %v", l.URL, err)
-				return
-			}
-			// Title — detail page has full title
-			if enriched.Title != "" && enriched.Title != "Property" {
-				l.Title = enriched.Title
-			}
-			// Location — only overwrite card's section location if detail page has a better one
-			if isGoodLocation(enriched.Location) && !isGoodLocation(l.Location) {
-				l.Location = enriched.Location
-			}
-			// Rating — if card didn't capture it, use detail page fallback
-			if l.Rating == "" && enriched.Rating != "" {
-				l.Rating = enriched.Rating
-			}
-			// Price — NEVER overwrite, already set from card
-			l.Description = enriched.Description
-		})
+		listing.ScrapedAt = time.Now()
+		out = append(out, listing)
 	}
-	s.pool.Wait()
+	return out
 }
 
-func (s *Scraper) scrapeDetailPage(allocCtx context.Context, url string) (*models.RawListing, error) {
-	listing := &models.RawListing{URL: url, Platform: platform}
+// ── Detail page enrichment (title, location, description only) ───────────────
 
-	err := s.retry.Do("detail-page", func() error {
-		ctx, cancel := chromedp.NewContext(allocCtx)
-		defer cancel()
-		ctx, cancelTimeout := context.WithTimeout(ctx, 60*time.Second)
-		defer cancelTimeout()
+// enrichListings visits each listing's detail page and returns the room URLs
+// found in its "Similar listings" block, for optional BFS expansion.
+func (s *Scraper) enrichListings(allocCtx context.Context, listings []*models.RawListing) []string {
+	return s.submitEnrichment(allocCtx, listings).wait()
+}
 
-		type pageData struct {
-			Title    string `json:"title"`
-			Location string `json:"location"`
-			Rating   string `json:"rating"`
-			Desc     string `json:"desc"`
+// unvisited returns the listings whose detail pages earlier runs have not
+// loaded. applyDetail marks a listing visited once its page succeeds, so a
+// failed, timed-out or budget-cut page is tried again next run.
+func (s *Scraper) unvisited(listings []*models.RawListing) []*models.RawListing {
+	if s.visitedURL == nil {
+		return listings
+	}
+	out := make([]*models.RawListing, 0, len(listings))
+	for _, l := range listings {
+		if !s.visitedURL.Contains(l.URL) {
+			out = append(out, l)
+		} else {
+			s.logger.Debug("[airbnb] Visited by an earlier run, card only: %s", l.URL)
 		}
-		var data pageData
+	}
+	return out
+}
 
-		err := chromedp.Run(ctx,
-			chromedp.Navigate(url),
-			chromedp.Sleep(4*time.Second),
-			chromedp.Evaluate(`window.scrollTo(0, 0)`, nil),
-			chromedp.Sleep(500*time.Millisecond),
+// enrichment is a batch of listings whose detail pages are queued on the
+// pool. Batches share the pool, so the next section's pages start as soon
+// as workers free up rather than after this batch is done.
+type enrichment struct {
+	done    chan struct{}
+	similar []string
+}
 
-			chromedp.Evaluate(`
-				(function() {
-					var result = { title: '', location: '', rating: '', desc: '' };
-
-					// ── Title ──────────────────────────────────────────────────────
-					// Real title is in h1[elementtiming="LCP-target"] above the photo grid.
-					// It may differ from the card title (e.g. long descriptive names).
-					var h1 = document.querySelector('h1[elementtiming="LCP-target"]') ||
-					          document.querySelector('h1');
-					if (h1) result.title = h1.innerText.trim();
-
-					// ── Rating ─────────────────────────────────────────────────────
-					// Strategy 1: the reviews anchor banner below the photo grid has
-					// data-testid="pdp-reviews-highlight-banner-host-rating" and inside it
-					// a span with aria-label="Rated X.X out of 5 stars."
-					var reviewBanner = document.querySelector('[data-testid="pdp-reviews-highlight-banner-host-rating"]');
-					if (reviewBanner) {
-						var ratedSpan = reviewBanner.querySelector('[aria-label*="out of 5"]') ||
-						                reviewBanner.querySelector('[aria-label*="Rated"]');
-						if (ratedSpan) {
-							var rl = ratedSpan.getAttribute('aria-label') || '';
-							var rm0 = rl.match(/([1-5]\.[0-9]{1,2})/);
-							if (rm0) result.rating = rm0[1];
-						}
-						// Also try the plain text number sibling div (aria-hidden="true">5.0</div>)
-						if (!result.rating) {
-							var numDiv = reviewBanner.querySelector('div[aria-hidden="true"]');
-							if (numDiv) {
-								var nd = numDiv.innerText.trim();
-								if (/^[1-5]\.[0-9]/.test(nd)) result.rating = nd;
-							}
-						}
-					}
+// wait blocks until every page of the batch has been applied and returns
+// the "Similar listings" links found.
+func (e *enrichment) wait() []string {
+	<-e.done
+	return e.similar
+}
 
-					// Strategy 2: any [aria-label*="Rated X out of 5"] anywhere on page
-					if (!result.rating) {
-						var rEl = document.querySelector('[aria-label*="out of 5"]') ||
-						          document.querySelector('[aria-label*="Rated"]');
-						if (rEl) {
-							var rt = rEl.getAttribute('aria-label') || rEl.innerText || '';
-							var rm2 = rt.match(/([1-5]\.[0-9]{1,2})/);
-							if (rm2) result.rating = rm2[1];
-						}
-					}
+// detailResult is what one detail-page job hands back to its batch.
+type detailResult struct {
+	listing *models.RawListing
+	similar []string
+}
 
-					// Strategy 3: scan body lines for "★ 4.8" or "4.8 · N reviews"
-					if (!result.rating) {
-						var bodyLines = document.body.innerText.split('\n');
-						for (var li = 0; li < bodyLines.length; li++) {
-							var line = bodyLines[li].trim();
-							var rm3 = line.match(/★\s*([1-5]\.[0-9]{1,2})/);
-							if (!rm3) rm3 = line.match(/^([1-5]\.[0-9]{1,2})\s*·/);
-							if (rm3) { result.rating = rm3[1]; break; }
-						}
-					}
+// submitEnrichment queues a detail-page job for each listing, at the
+// listing's priority, and returns once they are queued, without waiting for
+// them; a full pool queue holds it back. Results are merged into the
+// listings by a single goroutine as they arrive.
+func (s *Scraper) submitEnrichment(allocCtx context.Context, listings []*models.RawListing) *enrichment {
+	byURL := make(map[string]*models.RawListing, len(listings))
+	urls := make([]string, 0, len(listings))
+	for _, l := range listings {
+		if l.URL == "" {
+			continue
+		}
+		byURL[l.URL] = l // URLs are unique: seen dedups cards
+		urls = append(urls, l.URL)
+	}
+	results := s.details.Map(urls, func(url string) (detailResult, error) {
+		if s.degraded() || s.cancelled() {
+			return detailResult{}, nil
+		}
+		start := time.Now()
+		listing, similar, err := s.scrapeDetailPage(allocCtx, url)
+		s.tunePool(err, time.Since(start))
+		return detailResult{listing: listing, similar: similar}, err
+	})
+	e := &enrichment{done: make(chan struct{})}
+	go func() {
+		defer close(e.done)
+		for r := range results {
+			e.similar = append(e.similar, s.applyDetail(byURL[r.Input], r)...)
+		}
+	}()
+	return e
+}
 
-					// ── Location ───────────────────────────────────────────────────
-					var h2s = document.querySelectorAll('h2');
-					for (var i = 0; i < h2s.length; i++) {
-						var txt = h2s[i].innerText.trim();
-						var m = txt.match(/\bin\s+([A-Z][^,\n]{2,50}(?:,\s*[A-Z][^\n]{2,40})?)/);
-						if (m && m[1] && m[1].length < 80) {
-							result.location = m[1].trim();
-							break;
-						}
-					}
-					if (!result.location) {
-						var bt = document.body.innerText;
-						var nm = bt.match(/\d+\s*nights?\s+in\s+([^\n$\d]{3,60})/i);
-						if (nm) result.location = nm[1].trim();
-					}
+// applyDetail merges one detail-page result into its listing and returns
+// the "Similar listings" links found. A failed page leaves the listing as
+// its card provided it.
+func (s *Scraper) applyDetail(l *models.RawListing, r utils.Result[detailResult]) []string {
+	err := r.Err
+	switch {
+	case errors.Is(err, ErrPageBudget), errors.Is(err, utils.ErrJobSkipped):
+		return nil // logged once by pagesSpent
+	case err != nil && s.cancelled():
+		return nil // the browser was closed under it
+	case err == nil && r.Value.listing == nil:
+		return nil // skipped while degraded
+	case err != nil:
+		s.status.Add("detail_failed", 1)
+	default:
+		s.status.Add("detail_ok", 1)
+	}
+	var pe *utils.PanicError
+	switch {
+	case errors.Is(err, ErrListingRemoved):
+		s.recordFailure(l.URL, err)
+		l.Status = models.ListingStatusRemoved
+		s.logger.Info("[airbnb] Listing no longer available: %s", l.URL)
+		return nil
+	case errors.As(err, &pe):
+		s.recordFailure(l.URL, err)
+		s.logger.Error("[airbnb] Detail-page job panicked for %s: %v\n%s", l.URL, pe.Value, pe.Stack)
+		return nil
+	case err != nil:
+		s.recordFailure(l.URL, err)
+		s.logger.Warn("[airbnb] Detail page failed for %s (%s): %v", l.URL, errorClass(err), err)
+		return nil
+	}
 
-					// ── Description ────────────────────────────────────────────────
-					// Primary: [data-section-id="DESCRIPTION_DEFAULT"] — works for most listings.
-					var descEl = document.querySelector('[data-section-id="DESCRIPTION_DEFAULT"]');
-					if (descEl) {
-						var dt = descEl.innerText
-							.replace(/Some info has been automatically translated\.?\s*(Show original)?/gi, '')
-							.replace(/Show more/gi, '')
-							.trim();
-						if (dt.length > 30) result.desc = dt.substring(0, 1000);
-					}
+	enriched := r.Value.listing
+	if s.visitedURL != nil {
+		s.visitedURL.Add(l.URL)
+	}
+	// Title — detail page has full title
+	if enriched.Title != "" && enriched.Title != "Property" {
+		l.Title = enriched.Title
+		l.CopySource(enriched, "title")
+	}
+	// Location — only overwrite card's section location if detail page has a better one
+	if isGoodLocation(enriched.Location) && !isGoodLocation(l.Location) {
+		l.Location = enriched.Location
+		l.CopySource(enriched, "location")
+	}
+	// Rating — if card didn't capture it, use detail page fallback
+	if l.Rating == "" && enriched.Rating != "" {
+		l.Rating = enriched.Rating
+		l.CopySource(enriched, "rating")
+	}
+	if l.ReviewCount == "" && enriched.ReviewCount != "" {
+		l.ReviewCount = enriched.ReviewCount
+		l.CopySource(enriched, "reviews")
+	}
+	l.Latitude = enriched.Latitude
+	l.Longitude = enriched.Longitude
+	l.CopySource(enriched, "coordinates")
+	// Price — NEVER overwrite the card price; only fill it when discovery
+	// produced bare URLs (sitemap / allowlist)
+	if l.RawPrice == "" {
+		l.RawPrice = enriched.RawPrice
+		l.CopySource(enriched, "price")
+	}
+	l.Description = enriched.Description
+	l.CopySource(enriched, "description")
+	l.OriginalDescription = enriched.OriginalDescription
+	l.CopySource(enriched, "original_description")
+	l.Amenities = enriched.Amenities
+	l.CopySource(enriched, "amenities")
+	l.Host = enriched.Host
+	l.CopySource(enriched, "host")
+	l.Images = enriched.Images
+	l.CopySource(enriched, "images")
+	return r.Value.similar
+}
 
-					// Fallback 1: <main> paragraphs
-					if (!result.desc || result.desc.length < 30) {
-						var paras = document.querySelectorAll('main p');
-						var parts = [];
-						for (var j = 0; j < paras.length && parts.join(' ').length < 800; j++) {
-							var pt = paras[j].innerText.trim();
-							if (pt.length > 20) parts.push(pt);
-						}
-						if (parts.length) result.desc = parts.join(' ').substring(0, 1000);
-					}
+func (s *Scraper) scrapeDetailPage(allocCtx context.Context, url string) (*models.RawListing, []string, error) {
+	return s.scrapeDetail(allocCtx, url, nil)
+}
 
-					// Fallback 2: only when still empty — find "Show more" button via
-					// data-button-content="true" span, grab text BEFORE it in its container.
-					// This catches listings where description is not in the standard section.
-					if (!result.desc || result.desc.length < 30) {
-						var showMoreBtn = null;
-						var btns = document.querySelectorAll('button');
-						for (var bi = 0; bi < btns.length; bi++) {
-							var span = btns[bi].querySelector('[data-button-content="true"]');
-							if (span && span.innerText.trim().toLowerCase() === 'show more') {
-								showMoreBtn = btns[bi]; break;
-							}
-							if (!showMoreBtn && btns[bi].innerText.trim().toLowerCase() === 'show more') {
-								showMoreBtn = btns[bi];
-							}
-						}
-						if (showMoreBtn) {
-							var container = showMoreBtn.parentElement;
-							for (var up = 0; up < 6; up++) {
-								if (!container) break;
-								var cText = (container.innerText || '').trim();
-								if (cText.length > 80 && cText.replace(/show more/gi, '').trim().length > 40) break;
-								container = container.parentElement;
-							}
-							if (container) {
-								var descParts = [];
-								var walker = document.createTreeWalker(
-									container, NodeFilter.SHOW_TEXT, null, false
-								);
-								var node;
-								while ((node = walker.nextNode())) {
-									if (showMoreBtn.contains(node)) break;
-									var t = node.nodeValue.trim();
-									if (t.length > 0) descParts.push(t);
-								}
-								var raw = descParts.join(' ').trim();
-								raw = raw.replace(/Some info has been automatically translated\.?\s*(Show original)?/gi, '').trim();
-								if (raw.length > 30) result.desc = raw.substring(0, 1000);
-							}
-						}
-					}
+// scrapeDetail loads a detail page and extracts the listing; extra, when
+// set, reads more from the same tab before it closes.
+func (s *Scraper) scrapeDetail(allocCtx context.Context, url string, extra func(t *tab) error) (*models.RawListing, []string, error) {
+	listing := &models.RawListing{URL: url, Platform: platform, SchemaVersion: models.RawSchemaVersion}
+	var similar []string
 
-					if (!result.desc) result.desc = 'Description not available';
+	defer s.status.Begin(url)()
+	err := s.retry.Do("detail-page", func() error {
+		ctx, tab := s.openTab(allocCtx, "detail page", url, 60*time.Second)
+		defer tab.Close()
+		flush := s.startCapture(ctx)
 
-					return result;
-				})()
-			`, &data),
+		var data detailData
+
+		err := tab.Run(
+			s.blockRequests(),
+			chromedp.Navigate(s.stay.RoomURL(url)),
+			chromedp.Sleep(4*time.Second),
+			s.dismissPopups(),
+			chromedp.Evaluate(`window.scrollTo(0, 0)`, nil),
+			chromedp.Sleep(500*time.Millisecond),
+
+			chromedp.Evaluate(detailExtractorJS, &data),
 		)
 		if err != nil {
-			return fmt.Errorf("detail page: %w", err)
+			return navError("detail page", err)
+		}
+		flush() // before the translation toggle, so replays see the page as loaded
+		if data.State == "" {
+			s.axFallback(tab, &data)
+		}
+		if err := data.check(url); err != nil {
+			return err
+		}
+		if extra != nil {
+			if err := extra(tab); err != nil {
+				return err
+			}
+		}
+		if data.Translated {
+			data.Original = s.readOriginal(tab, data.Desc)
+		}
+		if data.AmenityCount > len(data.Amenities) {
+			if all := s.readAllAmenities(tab); len(all) > 0 {
+				data.Amenities = all
+				data.Src["amenities"] = "amenities-dialog"
+			}
 		}
 
-		listing.Title = data.Title
-		listing.Location = data.Location
-		listing.Rating = data.Rating
-		listing.Description = data.Desc
+		data.fill(listing, time.Now())
+		similar = data.Similar
 		return nil
 	})
 
-	return listing, err
+	return listing, similar, err
 }
 
 // ── Helpers ───────────────────────────────────────────────────────────────────
 
+// startURL is the homepage under AIRBNB_BASE_URL, with a trailing slash.
+func (s *Scraper) startURL() string {
+	base := strings.TrimRight(s.cfg.BaseURL, "/")
+	if base == "" {
+		base = defaultBaseURL
+	}
+	return base + "/"
+}
+
+// permitted applies the blocklist and allowlist to a listing URL.
+func (s *Scraper) permitted(url string) bool {
+	if s.blocklist != nil && s.blocklist.Contains(url) {
+		s.logger.Debug("[airbnb] Blocklisted listing skipped: %s", url)
+		return false
+	}
+	if s.allowlist != nil && s.allowlist.Size() > 0 && !s.allowlist.Contains(url) {
+		s.logger.Debug("[airbnb] Listing not in allowlist skipped: %s", url)
+		return false
+	}
+	return true
+}
+
+// allowlistSections turns the allowlist into sections of room URLs so a
+// monitoring run can skip discovery entirely.
+func (s *Scraper) allowlistSections() []section {
+	if s.allowlist == nil {
+		return nil
+	}
+	var out []section
+	ids := s.allowlist.IDs()
+	for i := 0; i < len(ids); i += listingsPerSection {
+		end := i + listingsPerSection
+		if end > len(ids) {
+			end = len(ids)
+		}
+		sec := section{Name: fmt.Sprintf("Allowlist [%d-%d]", i+1, end)}
+		for _, id := range ids[i:end] {
+			sec.Cards = append(sec.Cards, cardInfo{URL: s.startURL() + "rooms/" + id})
+		}
+		out = append(out, sec)
+	}
+	return out
+}
+
 var junkLocationPhrases = []string{
 	"where you'll be", "available next month", "add dates",
 	"check out homes", "things to do", "inspiration",
@@ -687,16 +1106,16 @@ func extractLocationFromSection(name string) string {
 	return name
 }
 
-func (s *Scraper) printSectionBanner(current, total int, name string, cardCount int) {
+func (s *Scraper) printSectionBanner(current, total int, name string, cardCount, scraping int) {
 	sep := strings.Repeat("─", 55)
-	fmt.Printf("\n\033[1;34m%s\033[0m\n", sep)
-	fmt.Printf("\033[1;34m  📍 Section [%d/%d]: %s\033[0m\n", current, total, name)
-	fmt.Printf("\033[1;34m     Found %d cards — scraping up to %d\033[0m\n", cardCount, listingsPerSection)
-	fmt.Printf("\033[1;34m%s\033[0m\n", sep)
+	utils.Printf("\n\033[1;34m%s\033[0m\n", sep)
+	utils.Printf("\033[1;34m  📍 Section [%d/%d]: %s\033[0m\n", current, total, name)
+	utils.Printf("\033[1;34m     Found %d cards — scraping up to %d\033[0m\n", cardCount, scraping)
+	utils.Printf("\033[1;34m%s\033[0m\n", sep)
 }
 
 func (s *Scraper) printSectionDone(name string) {
-	fmt.Printf("\n\033[1;32m  ✅ Section done: %q — moving to next\033[0m\n\n", name)
+	utils.Printf("\n\033[1;32m  ✅ Section done: %q — moving to next\033[0m\n\n", name)
 }
 
 func truncateStr(s string, max int) string {
@@ -734,4 +1153,4 @@ func findChromeBinary() string {
 		}
 	}
 	return ""
-}
\ No newline at end of file
+}
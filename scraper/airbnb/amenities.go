@@ -0,0 +1,85 @@
+package airbnb
+
+import (
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// amenitySectionJS defines readAmenitySection(), the amenities the "What
+// this place offers" section shows (Airbnb shows about ten) and the N of
+// its "Show all N amenities" button, 0 without one.
+const amenitySectionJS = `
+	function readAmenitySection() {
+		var out = {amenities: [], count: 0};
+		var am = document.querySelector('[data-section-id^="AMENITIES"]');
+		if (!am) return out;
+		var al = (am.innerText || '').split('\n').map(function(l) { return l.trim(); }).filter(Boolean);
+		for (var j = 0; j < al.length; j++) {
+			var cm = al[j].match(/^show all (\d+) amenities$/i);
+			if (cm) { out.count = parseInt(cm[1], 10); continue; }
+			if (/^what this place offers$/i.test(al[j]) || /^unavailable:/i.test(al[j])) continue;
+			out.amenities.push(al[j]);
+		}
+		return out;
+	}
+`
+
+// showAllAmenitiesJS clicks the "Show all N amenities" button and reports
+// whether there was one.
+const showAllAmenitiesJS = `
+	(function() {
+		var btns = document.querySelectorAll('button, [role="button"]');
+		for (var i = 0; i < btns.length; i++) {
+			if (/^show all \d+ amenities$/i.test((btns[i].innerText || '').trim())) { btns[i].click(); return true; }
+		}
+		return false;
+	})()
+`
+
+// amenityDialogJS reads the amenities dialog "Show all" opens: the first
+// line of each list item (the second, when present, describes it), in
+// page order. Items under the "Not included" heading or marked
+// "Unavailable:" are ones the place lacks and are skipped.
+const amenityDialogJS = `
+	(function() {
+		var out = [];
+		var dialogs = document.querySelectorAll('[role="dialog"], [aria-modal="true"]');
+		var dialog = null;
+		for (var i = 0; i < dialogs.length; i++) {
+			if (/what this place offers/i.test(dialogs[i].innerText || '')) { dialog = dialogs[i]; break; }
+		}
+		if (!dialog) return out;
+		var excluded = false;
+		dialog.querySelectorAll('h2, h3, li').forEach(function(el) {
+			var text = (el.innerText || '').trim();
+			if (el.tagName !== 'LI') { excluded = /^not included$/i.test(text); return; }
+			var name = text.split('\n')[0].trim();
+			if (excluded || !name || /^unavailable:/i.test(name)) return;
+			out.push(name);
+		});
+		return out;
+	})()
+`
+
+// readAllAmenities opens the amenities dialog and returns every amenity it
+// lists. It returns nil when the button is gone, the dialog shows nothing
+// or the tab failed; the section's amenities extracted before stay then.
+func (s *Scraper) readAllAmenities(t *tab) []string {
+	var clicked bool
+	var amenities []string
+	err := t.Run(
+		chromedp.Evaluate(showAllAmenitiesJS, &clicked),
+		chromedp.Sleep(1500*time.Millisecond),
+		chromedp.Evaluate(amenityDialogJS, &amenities),
+	)
+	switch {
+	case err != nil:
+		s.logger.Debug("[airbnb] Amenities dialog failed on %s: %v", t.url, err)
+		return nil
+	case !clicked || len(amenities) == 0:
+		s.logger.Debug("[airbnb] No amenities dialog on %s", t.url)
+		return nil
+	}
+	return amenities
+}
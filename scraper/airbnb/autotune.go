@@ -0,0 +1,22 @@
+package airbnb
+
+import (
+	"errors"
+	"time"
+)
+
+// tunePool feeds one detail-page outcome to the auto-tuner, when enabled.
+// Removed listings count as successes — the page loaded fine — and pages
+// never loaded for lack of budget are not counted at all.
+func (s *Scraper) tunePool(err error, latency time.Duration) {
+	if s.tuner == nil || errors.Is(err, ErrPageBudget) {
+		return
+	}
+	step, changed := s.tuner.Record(err == nil || errors.Is(err, ErrListingRemoved), latency)
+	if !changed {
+		return
+	}
+	s.logger.Info("[autotune] Concurrency %d → %d (%s: %.0f%% success, %.1fs per page)",
+		step.From, step.To, step.Reason, step.Success*100, step.Latency.Seconds())
+	s.status.Add("autotune_steps", 1)
+}
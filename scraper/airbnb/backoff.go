@@ -0,0 +1,36 @@
+package airbnb
+
+import (
+	"errors"
+
+	"airbnb-scraper/utils"
+)
+
+// SetCooldown shares a run-wide cool-down with this scraper, so a 429 seen
+// by any city's scraper pauses them all. New gives each scraper its own.
+func (s *Scraper) SetCooldown(c *utils.Cooldown) {
+	s.cooldown = c
+	s.pool.SetCooldown(c)
+}
+
+// noteThrottle is the retry hook for every failed page load: a 429 or
+// Airbnb's throttle page starts (or lengthens) the global cool-down, which
+// every worker then waits out before its next load.
+func (s *Scraper) noteThrottle(err error) {
+	if !rateLimited(err) {
+		return
+	}
+	if d := s.cooldown.Trigger(); d > 0 {
+		s.logger.Warn("[airbnb] Rate limited (%s) — pausing all workers for %v", errorClass(err), d)
+		s.status.Add("cooldowns", 1)
+	}
+}
+
+// rateLimited reports whether err says the site is throttling us.
+func rateLimited(err error) bool {
+	var ne *NetError
+	if errors.As(err, &ne) && ne.Kind == "http" && ne.Status == 429 {
+		return true
+	}
+	return errors.Is(err, ErrBotChallenge)
+}
@@ -0,0 +1,91 @@
+package airbnb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// analyticsURLPatterns are third-party tracking hosts nothing we extract
+// depends on.
+var analyticsURLPatterns = []string{
+	"*google-analytics.com/*",
+	"*googletagmanager.com/*",
+	"*doubleclick.net/*",
+	"*facebook.net/*",
+	"*hotjar.com/*",
+	"*segment.io/*",
+	"*sentry.io/*",
+}
+
+// blockClasses maps each BLOCK_RESOURCES name to the requests it blocks.
+var blockClasses = map[string][]*fetch.RequestPattern{
+	"image":      {{ResourceType: network.ResourceTypeImage}},
+	"font":       {{ResourceType: network.ResourceTypeFont}},
+	"media":      {{ResourceType: network.ResourceTypeMedia}},
+	"stylesheet": {{ResourceType: network.ResourceTypeStylesheet}},
+	"analytics":  urlPatterns(analyticsURLPatterns),
+}
+
+func urlPatterns(globs []string) []*fetch.RequestPattern {
+	out := make([]*fetch.RequestPattern, len(globs))
+	for i, g := range globs {
+		out[i] = &fetch.RequestPattern{URLPattern: g}
+	}
+	return out
+}
+
+// BlockPatterns resolves BLOCK_RESOURCES names to interception patterns;
+// "none" and empty names add nothing. Unknown names are an error listing
+// the valid ones.
+func BlockPatterns(names []string) ([]*fetch.RequestPattern, error) {
+	var out []*fetch.RequestPattern
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || name == "none" {
+			continue
+		}
+		patterns, ok := blockClasses[name]
+		if !ok {
+			valid := make([]string, 0, len(blockClasses))
+			for k := range blockClasses {
+				valid = append(valid, k)
+			}
+			sort.Strings(valid)
+			return nil, fmt.Errorf("unknown resource class %q (valid: %s)", name, strings.Join(valid, ", "))
+		}
+		out = append(out, patterns...)
+	}
+	return out, nil
+}
+
+// blockRequests is the first action of a detail-page load: it makes the tab
+// fail every request matching BLOCK_RESOURCES before it is sent, so images,
+// fonts, media and trackers never cross the (possibly metered) connection.
+// A no-op when nothing is blocked.
+func (s *Scraper) blockRequests() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		patterns, _ := BlockPatterns(s.cfg.BlockResources) // validated at startup
+		if len(patterns) == 0 {
+			return nil
+		}
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			paused, ok := ev.(*fetch.EventRequestPaused)
+			if !ok {
+				return
+			}
+			go func() {
+				c := chromedp.FromContext(ctx)
+				execCtx := cdp.WithExecutor(ctx, c.Target)
+				_ = fetch.FailRequest(paused.RequestID, network.ErrorReasonBlockedByClient).Do(execCtx)
+			}()
+		})
+		return fetch.Enable().WithPatterns(patterns).Do(ctx)
+	})
+}
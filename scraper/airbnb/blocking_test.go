@@ -0,0 +1,33 @@
+package airbnb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+func TestBlockPatterns(t *testing.T) {
+	patterns, err := BlockPatterns([]string{"image", " Font ", "analytics"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patterns) != 2+len(analyticsURLPatterns) {
+		t.Fatalf("got %d patterns", len(patterns))
+	}
+	if patterns[0].ResourceType != network.ResourceTypeImage || patterns[1].ResourceType != network.ResourceTypeFont {
+		t.Errorf("resource patterns = %+v, %+v", patterns[0], patterns[1])
+	}
+	if patterns[2].URLPattern == "" || patterns[2].ResourceType != "" {
+		t.Errorf("analytics pattern = %+v", patterns[2])
+	}
+
+	for _, names := range [][]string{nil, {"none"}, {""}} {
+		if p, err := BlockPatterns(names); err != nil || len(p) != 0 {
+			t.Errorf("BlockPatterns(%q) = %v, %v; want nothing blocked", names, p, err)
+		}
+	}
+	if _, err := BlockPatterns([]string{"video"}); err == nil || !strings.Contains(err.Error(), "valid: analytics, font") {
+		t.Errorf("unknown class error = %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package airbnb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/chromedp"
+)
+
+// BrowserBinary returns the Chrome/Chromium binary the scraper would launch:
+// CHROME_BIN if set, else the first one found on PATH or in the usual
+// install locations. Empty means none was found.
+func BrowserBinary() string {
+	return findChromeBinary()
+}
+
+// CheckBrowser launches the headless browser the same way a run does and
+// returns its product string (e.g. "HeadlessChrome/122.0.6261.94"), so a
+// missing or broken binary is reported before any scraping starts.
+func (s *Scraper) CheckBrowser() (string, error) {
+	allocCtx, closeBrowser := s.newBrowser()
+	defer closeBrowser()
+
+	ctx, cancel := context.WithTimeout(allocCtx, 30*time.Second)
+	defer cancel()
+
+	var product string
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		_, product, _, _, _, err = browser.GetVersion().Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return "", fmt.Errorf("launch browser: %w", err)
+	}
+	return product, nil
+}
@@ -0,0 +1,33 @@
+package airbnb
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+)
+
+// Chrome's disk cache must not be opened by two browsers at once, so each
+// concurrently running browser (parallel cities) takes its own numbered
+// slot under BROWSER_CACHE_DIR. Slots outlive the process: the next run's
+// first browser finds slot-0 already warm with Airbnb's JS bundles.
+var cacheSlots = struct {
+	mu   sync.Mutex
+	used map[int]bool
+}{used: map[int]bool{}}
+
+// acquireCacheDir returns the lowest free cache slot under root and a func
+// that frees it once the browser has exited.
+func acquireCacheDir(root string) (dir string, release func()) {
+	cacheSlots.mu.Lock()
+	defer cacheSlots.mu.Unlock()
+	slot := 0
+	for cacheSlots.used[slot] {
+		slot++
+	}
+	cacheSlots.used[slot] = true
+	return filepath.Join(root, fmt.Sprintf("slot-%d", slot)), func() {
+		cacheSlots.mu.Lock()
+		delete(cacheSlots.used, slot)
+		cacheSlots.mu.Unlock()
+	}
+}
@@ -0,0 +1,21 @@
+package airbnb
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestAcquireCacheDir(t *testing.T) {
+	a, releaseA := acquireCacheDir("cache")
+	b, releaseB := acquireCacheDir("cache")
+	if a != filepath.Join("cache", "slot-0") || b != filepath.Join("cache", "slot-1") {
+		t.Fatalf("slots = %q, %q", a, b)
+	}
+	releaseA()
+	c, releaseC := acquireCacheDir("cache")
+	if c != a {
+		t.Errorf("freed slot not reused: got %q, want %q", c, a)
+	}
+	releaseB()
+	releaseC()
+}
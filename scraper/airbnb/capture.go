@@ -0,0 +1,124 @@
+package airbnb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+
+	"airbnb-scraper/models"
+)
+
+// pageRecorder listens to CDP network events on a tab and keeps the
+// request/response pairs of top-level document loads for archiving.
+type pageRecorder struct {
+	mu       sync.Mutex
+	pages    map[network.RequestID]*models.PageCapture
+	order    []network.RequestID
+	finished map[network.RequestID]bool
+}
+
+func newPageRecorder(ctx context.Context) *pageRecorder {
+	r := &pageRecorder{
+		pages:    make(map[network.RequestID]*models.PageCapture),
+		finished: make(map[network.RequestID]bool),
+	}
+
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		switch e := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			if e.Type != network.ResourceTypeDocument || e.Request == nil {
+				return
+			}
+			if _, ok := r.pages[e.RequestID]; !ok {
+				r.order = append(r.order, e.RequestID)
+			}
+			// Redirects reuse the request ID; keep only the final hop.
+			r.pages[e.RequestID] = &models.PageCapture{
+				URL:            e.Request.URL,
+				Method:         e.Request.Method,
+				RequestHeaders: flattenHeaders(e.Request.Headers),
+				CapturedAt:     time.Now(),
+			}
+		case *network.EventResponseReceived:
+			p, ok := r.pages[e.RequestID]
+			if !ok || e.Response == nil {
+				return
+			}
+			p.URL = e.Response.URL
+			p.StatusCode = int(e.Response.Status)
+			p.StatusText = e.Response.StatusText
+			p.Protocol = e.Response.Protocol
+			p.ResponseHeaders = flattenHeaders(e.Response.Headers)
+			if len(e.Response.RequestHeaders) > 0 {
+				p.RequestHeaders = flattenHeaders(e.Response.RequestHeaders)
+			}
+		case *network.EventLoadingFinished:
+			if _, ok := r.pages[e.RequestID]; ok {
+				r.finished[e.RequestID] = true
+			}
+		}
+	})
+	return r
+}
+
+// collect fetches the bodies of all finished document loads. It must run
+// while the tab context is still alive.
+func (r *pageRecorder) collect(ctx context.Context) []*models.PageCapture {
+	r.mu.Lock()
+	var ids []network.RequestID
+	for _, id := range r.order {
+		if r.finished[id] && r.pages[id].StatusCode > 0 {
+			ids = append(ids, id)
+		}
+	}
+	r.mu.Unlock()
+
+	var out []*models.PageCapture
+	for _, id := range ids {
+		var body []byte
+		_ = chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			body, err = network.GetResponseBody(id).Do(ctx)
+			return err
+		}))
+
+		r.mu.Lock()
+		p := r.pages[id]
+		p.Body = body
+		r.mu.Unlock()
+		out = append(out, p)
+	}
+	return out
+}
+
+// startCapture begins recording document traffic on ctx when an archiver is
+// configured. The returned flush writes the recorded pages and must be called
+// before ctx is cancelled.
+func (s *Scraper) startCapture(ctx context.Context) func() {
+	if s.archiver == nil {
+		return func() {}
+	}
+	rec := newPageRecorder(ctx)
+	return func() {
+		for _, p := range rec.collect(ctx) {
+			if err := s.archiver.WritePage(p); err != nil {
+				s.logger.Warn("[airbnb] WARC write failed for %s: %v", p.URL, err)
+			}
+		}
+	}
+}
+
+func flattenHeaders(h network.Headers) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		out[k] = fmt.Sprint(v)
+	}
+	return out
+}
@@ -0,0 +1,160 @@
+package airbnb
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+
+	"airbnb-scraper/models"
+)
+
+// detailsJS reads the comparison facts the extractor skips: the overview
+// line ("4 guests · 2 bedrooms · 2 beds · 1 bath", one item per line when
+// the list wraps), the fee lines and total of the booking sidebar's price
+// breakdown, the amenities section and the days of the availability
+// calendar (calendar-day-MM/DD/YYYY cells). A fee's label and amount may
+// share a line or sit on consecutive ones, depending on how the breakdown
+// wraps.
+const detailsJS = `
+(function() {
+	` + amenitySectionJS + `
+	var out = {overview: '', fees: [], total: '', amenities: [], amenityCount: 0, calendar: []};
+	function lines(el) {
+		return (el.innerText || '').split('\n').map(function(l) { return l.trim(); }).filter(Boolean);
+	}
+	var amountRe = /^-?\$\s?[\d,]+(?:\.\d{2})?$/;
+
+	var ov = document.querySelector('[data-section-id^="OVERVIEW_DEFAULT"]') || document.querySelector('main') || document.body;
+	var ot = (ov.innerText || '').replace(/\s*\n\s*·/g, ' ·');
+	var om = ot.match(/\d+\+?\s+guests?[^\n]*/i);
+	if (om) out.overview = om[0].trim();
+
+	var bookIt = document.querySelector('[data-section-id="BOOK_IT_SIDEBAR"]') ||
+	             document.querySelector('[data-testid="book-it-default"]');
+	if (bookIt) {
+		var bl = lines(bookIt);
+		for (var i = 0; i < bl.length; i++) {
+			var label = bl[i], amount = '';
+			var same = label.match(/^(.*?)\s+(-?\$\s?[\d,]+(?:\.\d{2})?)$/);
+			if (same) { label = same[1]; amount = same[2]; }
+			else if (i + 1 < bl.length && amountRe.test(bl[i + 1])) { amount = bl[i + 1]; i++; }
+			if (!amount) continue;
+			if (/^total/i.test(label)) out.total = amount;
+			else if (/fee|tax|discount/i.test(label)) out.fees.push({label: label, amount: amount});
+		}
+	}
+
+	var am = readAmenitySection();
+	out.amenities = am.amenities;
+	out.amenityCount = am.count;
+
+	var seen = {};
+	document.querySelectorAll('[data-testid^="calendar-day-"]').forEach(function(el) {
+		var date = el.getAttribute('data-testid').substring('calendar-day-'.length);
+		if (seen[date]) return;
+		seen[date] = true;
+		out.calendar.push({date: date, blocked: el.getAttribute('data-is-day-blocked') === 'true'});
+	});
+	return out;
+})()
+`
+
+// detailsData is what detailsJS returns.
+type detailsData struct {
+	Overview     string         `json:"overview"`
+	Fees         []models.Fee   `json:"fees"`
+	Total        string         `json:"total"`
+	Amenities    []string       `json:"amenities"`
+	AmenityCount int            `json:"amenityCount"`
+	Calendar     []calendarCell `json:"calendar"`
+}
+
+// calendarCell is one day of the page's availability calendar.
+type calendarCell struct {
+	Date    string `json:"date"` // MM/DD/YYYY
+	Blocked bool   `json:"blocked"`
+}
+
+var (
+	guestsRe   = regexp.MustCompile(`(?i)(\d+)\+?\s+guests?`)
+	bedroomsRe = regexp.MustCompile(`(?i)(\d+)\s+bedrooms?`)
+	bedsRe     = regexp.MustCompile(`(?i)(\d+)\s+beds?\b`)
+	bathsRe    = regexp.MustCompile(`(?i)(\d+(?:\.\d)?)\s+(?:shared\s+|private\s+)?(?:half-)?baths?`)
+)
+
+// details converts the page data; counts missing from the overview stay 0
+// and calendar days with an unreadable date are dropped.
+func (d detailsData) details() *models.ListingDetails {
+	out := &models.ListingDetails{
+		Fees:         d.Fees,
+		Total:        d.Total,
+		Amenities:    d.Amenities,
+		AmenityCount: d.AmenityCount,
+	}
+	atoi := func(re *regexp.Regexp) int {
+		if m := re.FindStringSubmatch(d.Overview); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			return n
+		}
+		return 0
+	}
+	out.Guests = atoi(guestsRe)
+	out.Bedrooms = atoi(bedroomsRe)
+	out.Beds = atoi(bedsRe)
+	if m := bathsRe.FindStringSubmatch(d.Overview); m != nil {
+		out.Baths, _ = strconv.ParseFloat(m[1], 64)
+	} else if strings.Contains(strings.ToLower(d.Overview), "half-bath") {
+		out.Baths = 0.5
+	}
+	if out.AmenityCount == 0 {
+		out.AmenityCount = len(out.Amenities)
+	}
+	for _, c := range d.Calendar {
+		if day, err := time.Parse("01/02/2006", c.Date); err == nil {
+			out.Calendar = append(out.Calendar, models.CalendarDay{Date: day, Blocked: c.Blocked})
+		}
+	}
+	sort.Slice(out.Calendar, func(i, j int) bool { return out.Calendar[i].Date.Before(out.Calendar[j].Date) })
+	return out
+}
+
+// Comparison is one listing of FetchComparison: the detail-page listing
+// and its comparison facts, or the error that stopped them.
+type Comparison struct {
+	URL     string
+	Listing *models.RawListing
+	Details *models.ListingDetails
+	Err     error
+}
+
+// FetchComparison loads each URL's detail page, one after another, and
+// reads the listing plus its capacity, fees and amenities.
+func (s *Scraper) FetchComparison(urls []string) []Comparison {
+	allocCtx, closeBrowser := s.newBrowser()
+	defer closeBrowser()
+
+	out := make([]Comparison, 0, len(urls))
+	for _, u := range urls {
+		c := Comparison{URL: u}
+		c.Listing, _, c.Err = s.scrapeDetail(allocCtx, u, func(t *tab) error {
+			var d detailsData
+			if err := t.Run(chromedp.Evaluate(detailsJS, &d)); err != nil {
+				return navError("detail page", err)
+			}
+			c.Details = d.details()
+			return nil
+		})
+		if c.Listing != nil {
+			c.Listing.ScrapedAt = time.Now()
+		}
+		if c.Details != nil && len(c.Listing.Amenities) > len(c.Details.Amenities) {
+			c.Details.Amenities = c.Listing.Amenities // the full list from the amenities dialog
+		}
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,38 @@
+package airbnb
+
+import "testing"
+
+func TestDetailsDataDetails(t *testing.T) {
+	for _, tc := range []struct {
+		overview               string
+		guests, bedrooms, beds int
+		baths                  float64
+	}{
+		{"4 guests · 2 bedrooms · 2 beds · 1 bath", 4, 2, 2, 1},
+		{"16+ guests · 6 bedrooms · 9 beds · 4.5 baths", 16, 6, 9, 4.5},
+		{"2 guests · Studio · 1 bed · 1 shared bath", 2, 0, 1, 1},
+		{"1 guest · 1 bedroom · 1 bed · Half-bath", 1, 1, 1, 0.5},
+		{"", 0, 0, 0, 0},
+	} {
+		d := detailsData{Overview: tc.overview}.details()
+		if d.Guests != tc.guests || d.Bedrooms != tc.bedrooms || d.Beds != tc.beds || d.Baths != tc.baths {
+			t.Errorf("%q: got %d/%d/%d/%v", tc.overview, d.Guests, d.Bedrooms, d.Beds, d.Baths)
+		}
+	}
+
+	d := detailsData{Amenities: []string{"Wifi", "Kitchen"}}.details()
+	if d.AmenityCount != 2 {
+		t.Errorf("amenity count without the button = %d, want 2", d.AmenityCount)
+	}
+}
+
+func TestDetailsDataCalendar(t *testing.T) {
+	d := detailsData{Calendar: []calendarCell{{"05/02/2024", true}, {"05/01/2024", false}, {"not a date", true}}}
+	cal := d.details().Calendar
+	if len(cal) != 2 {
+		t.Fatalf("got %d days, want 2 (unreadable date dropped)", len(cal))
+	}
+	if cal[0].Date.Format("2006-01-02") != "2024-05-01" || cal[0].Blocked || !cal[1].Blocked {
+		t.Errorf("calendar = %+v, want 1 May open then 2 May blocked", cal)
+	}
+}
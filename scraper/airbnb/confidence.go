@@ -0,0 +1,54 @@
+package airbnb
+
+import (
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+// strategyConfidence rates each extraction strategy. Structured sources
+// (dedicated test ids, meta tags, the booking sidebar) are high; heuristics
+// scanning free body text are low because they can pick up numbers from
+// unrelated page blocks.
+var strategyConfidence = map[string]float64{
+	// Listing cards
+	"card:title-element":      models.ConfidenceHigh,
+	"card:bold-text":          models.ConfidenceLow,
+	"card:non-struck-amount":  models.ConfidenceHigh,
+	"card:aria-label":         models.ConfidenceHigh,
+	"card:text-line":          models.ConfidenceMedium,
+	"card:rating-parenthesis": models.ConfidenceMedium,
+	"card:new-badge":          models.ConfidenceMedium,
+	"section-heading":         models.ConfidenceMedium,
+
+	// Detail pages
+	"detail:lcp-h1":              models.ConfidenceHigh,
+	"detail:first-h1":            models.ConfidenceMedium,
+	"detail:reviews-banner":      models.ConfidenceHigh,
+	"detail:reviews-banner-text": models.ConfidenceHigh,
+	"detail:aria-label":          models.ConfidenceMedium,
+	"detail:body-text":           models.ConfidenceLow,
+	"detail:new-badge":           models.ConfidenceMedium,
+	"detail:h2-heading":          models.ConfidenceMedium,
+	"detail:nights-in-text":      models.ConfidenceLow,
+	"detail:book-it-sidebar":     models.ConfidenceHigh,
+	"detail:place-meta":          models.ConfidenceHigh,
+	"detail:embedded-json":       models.ConfidenceMedium,
+	"detail:description-section": models.ConfidenceHigh,
+	"detail:main-paragraphs":     models.ConfidenceMedium,
+	"detail:show-more-container": models.ConfidenceLow,
+	"detail:show-original":       models.ConfidenceHigh,
+	"detail:amenities-section":   models.ConfidenceHigh,
+	"detail:amenities-dialog":    models.ConfidenceHigh,
+	"detail:host-section":        models.ConfidenceHigh,
+	"detail:hosted-by":           models.ConfidenceMedium,
+	"detail:hero-gallery":        models.ConfidenceHigh,
+	"detail:page-images":         models.ConfidenceLow,
+	"detail:ax-tree":             models.ConfidenceMedium,
+}
+
+// recordSource stores the strategy that produced field together with its
+// confidence. Strategies missing from the table are recorded as unknown (0).
+func recordSource(l *models.RawListing, field, strategy string, at time.Time) {
+	l.SetSource(field, strategy, strategyConfidence[strategy], at)
+}
@@ -0,0 +1,38 @@
+package airbnb
+
+import (
+	"context"
+
+	"airbnb-scraper/models"
+)
+
+// ScrapeContext is Scrape bound to ctx. Once ctx is done the browser is
+// closed, no further sections, detail pages or similar-listing hops start,
+// and the listings collected so far are returned with ctx's error.
+func (s *Scraper) ScrapeContext(ctx context.Context) ([]*models.RawListing, error) {
+	s.SetContext(ctx)
+	listings, err := s.Scrape()
+	if err == nil {
+		err = ctx.Err()
+	}
+	return listings, err
+}
+
+// SetContext binds later Scrape calls to ctx as ScrapeContext does, for
+// callers that only see the scraper.Scraper interface.
+func (s *Scraper) SetContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// parent is the context the browser is started under.
+func (s *Scraper) parent() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
+// cancelled reports whether the scrape's context is done.
+func (s *Scraper) cancelled() bool {
+	return s.ctx != nil && s.ctx.Err() != nil
+}
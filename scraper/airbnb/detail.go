@@ -0,0 +1,290 @@
+package airbnb
+
+import (
+	"fmt"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+// detailData is what detailExtractorJS returns for a room page.
+type detailData struct {
+	Title    string            `json:"title"`
+	Location string            `json:"location"`
+	Rating   string            `json:"rating"`
+	Price    string            `json:"price"`
+	Desc     string            `json:"desc"`
+	Reviews  string            `json:"reviews"`
+	Lat      string            `json:"lat"`
+	Lng      string            `json:"lng"`
+	Similar  []string          `json:"similar"`
+	Src      map[string]string `json:"src"`
+	State    string            `json:"state"`
+
+	// Amenities are the ones the section shows; AmenityCount is the N of
+	// its "Show all N amenities" button, which opens the full list.
+	Amenities    []string `json:"amenities"`
+	AmenityCount int      `json:"amenityCount"`
+
+	Host   hostData  `json:"host"`
+	Images imageData `json:"images"`
+
+	// Translated is set when the page shows an auto-translated description
+	// with a "Show original" toggle; Original is filled by clicking it.
+	Translated bool   `json:"translated"`
+	Original   string `json:"-"`
+}
+
+// check turns a blocked, removed or unrecognisable page into a typed error.
+func (d *detailData) check(url string) error {
+	if err := stateError(d.State, url); err != nil {
+		return err
+	}
+	if d.Title == "" && len(d.Src) == 0 {
+		return fmt.Errorf("detail page %s: %w", url, ErrSelectorMissing)
+	}
+	return nil
+}
+
+// fill copies the extracted fields into listing and records the strategy
+// behind each one.
+func (d *detailData) fill(listing *models.RawListing, extractedAt time.Time) {
+	listing.Title = d.Title
+	listing.Location = d.Location
+	listing.Rating = d.Rating
+	listing.RawPrice = d.Price
+	listing.ReviewCount = d.Reviews
+	listing.Latitude = d.Lat
+	listing.Longitude = d.Lng
+	listing.Description = d.Desc
+	listing.OriginalDescription = d.Original
+	listing.Amenities = d.Amenities
+	listing.Host = d.Host.host()
+	listing.Images = d.Images.URLs
+
+	for field, strategy := range d.Src {
+		recordSource(listing, field, "detail:"+strategy, extractedAt)
+	}
+	if d.Original != "" {
+		recordSource(listing, "original_description", "detail:show-original", extractedAt)
+	}
+	if d.Host.Src != "" {
+		recordSource(listing, "host", "detail:"+d.Host.Src, extractedAt)
+	}
+	if d.Images.Src != "" {
+		recordSource(listing, "images", "detail:"+d.Images.Src, extractedAt)
+	}
+}
+
+// detailExtractorJS reads every field of a room detail page. It only looks
+// at the DOM, so it works the same on a live page and on a replayed capture.
+const detailExtractorJS = `
+(function() {
+	` + pageStateJS + descriptionSectionJS + showOriginalFinderJS + amenitySectionJS + hostJS + imagesJS + `
+	var result = { title: '', location: '', rating: '', price: '', reviews: '', lat: '', lng: '', desc: '', similar: [], src: {} };
+	result.state = pageState(true);
+	if (result.state) return result;
+
+	// ── Title ──────────────────────────────────────────────────────
+	// Real title is in h1[elementtiming="LCP-target"] above the photo grid.
+	// It may differ from the card title (e.g. long descriptive names).
+	var h1 = document.querySelector('h1[elementtiming="LCP-target"]');
+	result.src.title = 'lcp-h1';
+	if (!h1) { h1 = document.querySelector('h1'); result.src.title = 'first-h1'; }
+	if (h1) result.title = h1.innerText.trim();
+	else delete result.src.title;
+
+	// ── Rating ─────────────────────────────────────────────────────
+	// Strategy 1: the reviews anchor banner below the photo grid has
+	// data-testid="pdp-reviews-highlight-banner-host-rating" and inside it
+	// a span with aria-label="Rated X.X out of 5 stars."
+	var reviewBanner = document.querySelector('[data-testid="pdp-reviews-highlight-banner-host-rating"]');
+	if (reviewBanner) {
+		var ratedSpan = reviewBanner.querySelector('[aria-label*="out of 5"]') ||
+		                reviewBanner.querySelector('[aria-label*="Rated"]');
+		if (ratedSpan) {
+			var rl = ratedSpan.getAttribute('aria-label') || '';
+			var rm0 = rl.match(/([1-5]\.[0-9]{1,2})/);
+			if (rm0) { result.rating = rm0[1]; result.src.rating = 'reviews-banner'; }
+		}
+		// Also try the plain text number sibling div (aria-hidden="true">5.0</div>)
+		if (!result.rating) {
+			var numDiv = reviewBanner.querySelector('div[aria-hidden="true"]');
+			if (numDiv) {
+				var nd = numDiv.innerText.trim();
+				if (/^[1-5]\.[0-9]/.test(nd)) { result.rating = nd; result.src.rating = 'reviews-banner-text'; }
+			}
+		}
+	}
+
+	// Strategy 2: any [aria-label*="Rated X out of 5"] anywhere on page
+	if (!result.rating) {
+		var rEl = document.querySelector('[aria-label*="out of 5"]') ||
+		          document.querySelector('[aria-label*="Rated"]');
+		if (rEl) {
+			var rt = rEl.getAttribute('aria-label') || rEl.innerText || '';
+			var rm2 = rt.match(/([1-5]\.[0-9]{1,2})/);
+			if (rm2) { result.rating = rm2[1]; result.src.rating = 'aria-label'; }
+		}
+	}
+
+	// Strategy 3: scan body lines for "★ 4.8" or "4.8 · N reviews"
+	if (!result.rating) {
+		var bodyLines = document.body.innerText.split('\n');
+		for (var li = 0; li < bodyLines.length; li++) {
+			var line = bodyLines[li].trim();
+			var rm3 = line.match(/★\s*([1-5]\.[0-9]{1,2})/);
+			if (!rm3) rm3 = line.match(/^([1-5]\.[0-9]{1,2})\s*·/);
+			if (rm3) { result.rating = rm3[1]; result.src.rating = 'body-text'; break; }
+		}
+	}
+
+	// Strategy 4: no rating yet, "★ New" or "New · No reviews yet"
+	if (!result.rating) {
+		var newLines = document.body.innerText.split('\n');
+		for (var ni = 0; ni < newLines.length; ni++) {
+			if (/^(★\s*New|New\s*·\s*No reviews)/i.test(newLines[ni].trim())) {
+				result.rating = 'New'; result.src.rating = 'new-badge'; break;
+			}
+		}
+	}
+
+	// ── Location ───────────────────────────────────────────────────
+	var h2s = document.querySelectorAll('h2');
+	for (var i = 0; i < h2s.length; i++) {
+		var txt = h2s[i].innerText.trim();
+		var m = txt.match(/\bin\s+([A-Z][^,\n]{2,50}(?:,\s*[A-Z][^\n]{2,40})?)/);
+		if (m && m[1] && m[1].length < 80) {
+			result.location = m[1].trim();
+			result.src.location = 'h2-heading';
+			break;
+		}
+	}
+	if (!result.location) {
+		var bt = document.body.innerText;
+		var nm = bt.match(/\d+\s*nights?\s+in\s+([^\n$\d]{3,60})/i);
+		if (nm) { result.location = nm[1].trim(); result.src.location = 'nights-in-text'; }
+	}
+
+	// ── Price ──────────────────────────────────────────────────────
+	// Booking sidebar first; the first per-night amount in the body
+	// text is the fallback.
+	var priceRe = /\$\s?[\d,]+(?:\.\d{2})?\s*(?:for\s+\d+\s*nights?|\/?\s*night|per\s+night)/i;
+	var bookIt = document.querySelector('[data-section-id="BOOK_IT_SIDEBAR"]') ||
+	             document.querySelector('[data-testid="book-it-default"]');
+	var pm = bookIt ? (bookIt.innerText || '').match(priceRe) : null;
+	if (pm) result.src.price = 'book-it-sidebar';
+	if (!pm) {
+		pm = document.body.innerText.match(priceRe);
+		if (pm) result.src.price = 'body-text';
+	}
+	if (pm) result.price = pm[0].replace(/\s+/g, ' ').trim();
+
+	// ── Review count ───────────────────────────────────────────────
+	var rvm = document.body.innerText.match(/([\d,]+)\s+reviews?\b/i);
+	if (rvm) { result.reviews = rvm[1]; result.src.reviews = 'body-text'; }
+
+	// ── Coordinates ────────────────────────────────────────────────
+	// The map section is hydrated from embedded JSON carrying lat/lng.
+	var latMeta = document.querySelector('meta[property="place:location:latitude"]');
+	var lngMeta = document.querySelector('meta[property="place:location:longitude"]');
+	if (latMeta && lngMeta) {
+		result.lat = latMeta.getAttribute('content') || '';
+		result.lng = lngMeta.getAttribute('content') || '';
+		result.src.coordinates = 'place-meta';
+	}
+	if (!result.lat) {
+		var html = document.documentElement.innerHTML;
+		var cm = html.match(/"lat(?:itude)?"\s*:\s*(-?\d{1,3}\.\d+)\s*,\s*"(?:lng|longitude)"\s*:\s*(-?\d{1,3}\.\d+)/);
+		if (cm) { result.lat = cm[1]; result.lng = cm[2]; result.src.coordinates = 'embedded-json'; }
+	}
+
+	// ── Description ────────────────────────────────────────────────
+	// Primary: [data-section-id="DESCRIPTION_DEFAULT"] — works for most listings.
+	var dt = readDescriptionSection();
+	if (dt.length > 30) { result.desc = dt; result.src.description = 'description-section'; }
+	// Auto-translated listings carry a "Show original" toggle; the
+	// original is read after clicking it (see readOriginal).
+	result.translated = !!findShowOriginal();
+
+	// Fallback 1: <main> paragraphs
+	if (!result.desc || result.desc.length < 30) {
+		var paras = document.querySelectorAll('main p');
+		var parts = [];
+		for (var j = 0; j < paras.length && parts.join(' ').length < 800; j++) {
+			var pt = paras[j].innerText.trim();
+			if (pt.length > 20) parts.push(pt);
+		}
+		if (parts.length) { result.desc = parts.join(' ').substring(0, 1000); result.src.description = 'main-paragraphs'; }
+	}
+
+	// Fallback 2: only when still empty — find "Show more" button via
+	// data-button-content="true" span, grab text BEFORE it in its container.
+	// This catches listings where description is not in the standard section.
+	if (!result.desc || result.desc.length < 30) {
+		var showMoreBtn = null;
+		var btns = document.querySelectorAll('button');
+		for (var bi = 0; bi < btns.length; bi++) {
+			var span = btns[bi].querySelector('[data-button-content="true"]');
+			if (span && span.innerText.trim().toLowerCase() === 'show more') {
+				showMoreBtn = btns[bi]; break;
+			}
+			if (!showMoreBtn && btns[bi].innerText.trim().toLowerCase() === 'show more') {
+				showMoreBtn = btns[bi];
+			}
+		}
+		if (showMoreBtn) {
+			var container = showMoreBtn.parentElement;
+			for (var up = 0; up < 6; up++) {
+				if (!container) break;
+				var cText = (container.innerText || '').trim();
+				if (cText.length > 80 && cText.replace(/show more/gi, '').trim().length > 40) break;
+				container = container.parentElement;
+			}
+			if (container) {
+				var descParts = [];
+				var walker = document.createTreeWalker(
+					container, NodeFilter.SHOW_TEXT, null, false
+				);
+				var node;
+				while ((node = walker.nextNode())) {
+					if (showMoreBtn.contains(node)) break;
+					var t = node.nodeValue.trim();
+					if (t.length > 0) descParts.push(t);
+				}
+				var raw = descParts.join(' ').trim();
+				raw = raw.replace(/Some info has been automatically translated\.?\s*(Show original)?/gi, '').trim();
+				if (raw.length > 30) { result.desc = raw.substring(0, 1000); result.src.description = 'show-more-container'; }
+			}
+		}
+	}
+
+	if (!result.desc) result.desc = 'Description not available';
+
+	// ── Amenities ──────────────────────────────────────────────────
+	// The section shows about ten; the rest are in the dialog its
+	// "Show all N amenities" button opens (see readAllAmenities).
+	var am = readAmenitySection();
+	if (am.amenities.length) { result.amenities = am.amenities; result.src.amenities = 'amenities-section'; }
+	result.amenityCount = am.count;
+
+	// ── Host ───────────────────────────────────────────────────────
+	result.host = readHost();
+
+	// ── Photos ─────────────────────────────────────────────────────
+	result.images = readImages();
+
+	// ── Similar listings ───────────────────────────────────────────
+	// Room links other than this one — the "Similar listings" carousel.
+	var self = location.href.split('?')[0];
+	var seenSimilar = {};
+	document.querySelectorAll('a[href*="/rooms/"]').forEach(function(a) {
+		var u = a.href.split('?')[0];
+		if (!/\/rooms\/\d+$/.test(u) || u === self || seenSimilar[u]) return;
+		seenSimilar[u] = true;
+		result.similar.push(u);
+	});
+
+	return result;
+})()
+`
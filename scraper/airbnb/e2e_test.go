@@ -0,0 +1,260 @@
+//go:build e2e
+
+// End-to-end tests drive the real chromedp scraper against the mock site in
+// mocksite. They need Chrome/Chromium and take about a minute:
+//
+//	go test -tags e2e ./scraper/airbnb/
+
+package airbnb
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/scraper"
+	"airbnb-scraper/scraper/airbnb/mocksite"
+	"airbnb-scraper/scraper/scrapertest"
+	"airbnb-scraper/services"
+	"airbnb-scraper/utils"
+)
+
+func e2eScraper(t *testing.T, mode, query string) (*Scraper, string) {
+	t.Helper()
+	if BrowserBinary() == "" {
+		t.Skip("no Chrome/Chromium binary found (set CHROME_BIN)")
+	}
+	srv := httptest.NewServer(mocksite.Handler())
+	t.Cleanup(srv.Close)
+
+	cfg := &config.Config{
+		BaseURL:        srv.URL,
+		DiscoveryMode:  mode,
+		SearchQuery:    query,
+		PagesToScrape:  5,
+		MaxConcurrency: 2,
+		MaxRetries:     1,
+	}
+	return New(cfg, utils.NewLogger()), srv.URL
+}
+
+func TestE2EContract(t *testing.T) {
+	scrapertest.Contract(t, func(t *testing.T) scraper.Scraper {
+		sc, _ := e2eScraper(t, "homepage", "")
+		return sc
+	})
+}
+
+func TestE2EHomepage(t *testing.T) {
+	sc, base := e2eScraper(t, "homepage", "")
+	status := utils.NewStatusBoard()
+	sc.SetStatus(status)
+	listings, err := sc.Scrape()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every mock page carries a cookie banner; room pages add a translation
+	// dialog whose "in English" heading would otherwise read as a location.
+	if n := status.Snapshot().Counts["popups_dismissed"]; n == 0 {
+		t.Error("no popups dismissed")
+	}
+
+	byURL := make(map[string]*models.RawListing)
+	for _, l := range listings {
+		byURL[l.URL] = l
+	}
+	for _, room := range mocksite.Rooms() {
+		url := fmt.Sprintf("%s/rooms/%d", base, room.ID)
+		l, ok := byURL[url]
+		if !ok {
+			t.Errorf("%s (%s) not scraped — lazy sections need the scroll", url, room.Title)
+			continue
+		}
+		if room.Removed {
+			if l.Status != models.ListingStatusRemoved {
+				t.Errorf("%s: status %q, want removed", url, l.Status)
+			}
+			continue
+		}
+		if l.Title != room.Title {
+			t.Errorf("%s: title %q, want the detail page's %q", url, l.Title, room.Title)
+		}
+		if want := cardPrice(room); l.RawPrice != want {
+			t.Errorf("%s: price %q, want the non-struck card price %q", url, l.RawPrice, want)
+		}
+		reviews := strconv.Itoa(room.Reviews)
+		if room.Reviews == 0 {
+			reviews = "" // new listings show no count
+		}
+		if l.Rating != room.Rating || l.ReviewCount != reviews {
+			t.Errorf("%s: rating %q (%q reviews), want %s (%d)", url, l.Rating, l.ReviewCount, room.Rating, room.Reviews)
+		}
+		if l.Latitude != room.Lat || l.Longitude != room.Lng {
+			t.Errorf("%s: coordinates %s,%s, want %s,%s", url, l.Latitude, l.Longitude, room.Lat, room.Lng)
+		}
+		if l.Description != room.Description {
+			t.Errorf("%s: description %q", url, l.Description)
+		}
+		if l.OriginalDescription != room.OriginalDescription {
+			t.Errorf("%s: original description %q, want %q", url, l.OriginalDescription, room.OriginalDescription)
+		}
+		if strings.Join(l.Amenities, "|") != strings.Join(room.Amenities, "|") {
+			t.Errorf("%s: amenities %q, want all of %q from the dialog", url, l.Amenities, room.Amenities)
+		}
+		if h := room.Host; h != nil {
+			want := models.Host{ID: strconv.FormatInt(h.ID, 10), Name: h.Name, Superhost: h.Superhost,
+				YearsHosting: h.Years, ResponseRate: h.ResponseRate, ListingCount: h.Listings}
+			if l.Host == nil || *l.Host != want {
+				t.Errorf("%s: host %+v, want %+v", url, l.Host, want)
+			}
+		} else if l.Host != nil && l.Host.ID != "" {
+			t.Errorf("%s: host %+v on a page without one", url, l.Host)
+		}
+		var images []string
+		for _, p := range room.Images() {
+			images = append(images, base+strings.Split(p, "?")[0])
+		}
+		if strings.Join(l.Images, " ") != strings.Join(images, " ") {
+			t.Errorf("%s: images %q, want %q", url, l.Images, images)
+		}
+		if city := strings.Split(room.Location, ",")[0]; l.Location != city {
+			t.Errorf("%s: location %q, want the section's %q", url, l.Location, city)
+		}
+	}
+
+	// The cleaner must turn every card price back into the nightly rate.
+	cleaned := services.NewCleaner(utils.NewLogger()).Clean(listings)
+	for _, l := range cleaned {
+		id, _ := strconv.ParseInt(l.URL[strings.LastIndex(l.URL, "/")+1:], 10, 64)
+		room, _ := mocksite.Find(id)
+		if !room.Removed && l.Price != float64(room.Nightly) {
+			t.Errorf("%s: cleaned price %.2f, want %d", l.URL, l.Price, room.Nightly)
+		}
+		if l.IsNewListing != (room.Rating == "New") {
+			t.Errorf("%s: IsNewListing %v for rating %q", l.URL, l.IsNewListing, room.Rating)
+		}
+	}
+}
+
+func TestE2ESearch(t *testing.T) {
+	sc, base := e2eScraper(t, "search", "Bangkok")
+	listings, err := sc.Scrape()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := mocksite.Search("Bangkok")
+	if len(listings) != len(want) {
+		t.Fatalf("got %d listings, want %d", len(listings), len(want))
+	}
+	for i, room := range want {
+		if url := fmt.Sprintf("%s/rooms/%d", base, room.ID); listings[i].URL != url {
+			t.Errorf("listing %d: %s, want %s", i, listings[i].URL, url)
+		}
+		if listings[i].Location != "Bangkok" {
+			t.Errorf("listing %d: location %q, want the query", i, listings[i].Location)
+		}
+	}
+}
+
+func TestE2ESearchSeveralQueries(t *testing.T) {
+	sc, base := e2eScraper(t, "search", "Bangkok; Lisbon; bangkok")
+	listings, err := sc.Scrape()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	for _, query := range []string{"Bangkok", "Lisbon"} {
+		for _, room := range mocksite.Search(query) {
+			want = append(want, fmt.Sprintf("%s/rooms/%d %s", base, room.ID, query))
+		}
+	}
+	if len(listings) != len(want) {
+		t.Fatalf("got %d listings, want %d", len(listings), len(want))
+	}
+	for i, l := range listings {
+		if got := l.URL + " " + l.Location; got != want[i] {
+			t.Errorf("listing %d: %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestE2ESearchPagination(t *testing.T) {
+	all := mocksite.Search("Lisbon")
+	if len(all) <= mocksite.SearchPageSize {
+		t.Fatalf("Lisbon has %d results, want more than one page", len(all))
+	}
+	for _, tc := range []struct {
+		pages, perPage int
+		want           []mocksite.Room
+	}{
+		{pages: 1, want: all[:mocksite.SearchPageSize]},
+		{pages: 2, perPage: 1, want: []mocksite.Room{all[0], all[mocksite.SearchPageSize]}},
+		{pages: 5, want: all},
+	} {
+		sc, base := e2eScraper(t, "search", "Lisbon")
+		sc.cfg.PagesToScrape, sc.cfg.ListingsPerPage = tc.pages, tc.perPage
+		listings, err := sc.Scrape()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got, want []string
+		for _, l := range listings {
+			got = append(got, l.URL)
+		}
+		for _, room := range tc.want {
+			want = append(want, fmt.Sprintf("%s/rooms/%d", base, room.ID))
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%d pages × %d: got %v, want %v", tc.pages, tc.perPage, got, want)
+		}
+	}
+}
+
+func TestE2EFetchComparison(t *testing.T) {
+	sc, base := e2eScraper(t, "homepage", "")
+	got := sc.FetchComparison([]string{base + "/rooms/7100001", base + "/rooms/7100002", base + "/rooms/7100004"})
+	if len(got) != 3 {
+		t.Fatalf("got %d comparisons, want 3", len(got))
+	}
+
+	stay, loft := got[0], got[1]
+	if stay.Err != nil || loft.Err != nil {
+		t.Fatalf("errors: %v, %v", stay.Err, loft.Err)
+	}
+	if stay.Listing.Title != "Sunny Alfama apartment with river view" {
+		t.Errorf("title = %q", stay.Listing.Title)
+	}
+	d := stay.Details
+	if d.Guests != 4 || d.Bedrooms != 2 || d.Beds != 2 || d.Baths != 1 {
+		t.Errorf("capacity = %+v", d)
+	}
+	want := []models.Fee{{Label: "Cleaning fee", Amount: "$40"}, {Label: "Airbnb service fee", Amount: "$85"}}
+	if len(d.Fees) != len(want) || d.Fees[0] != want[0] || d.Fees[1] != want[1] {
+		t.Errorf("fees = %+v, want %+v", d.Fees, want)
+	}
+	if d.Total != "$725" || d.AmenityCount != 5 || len(d.Amenities) != 5 {
+		t.Errorf("total %q, amenities %d %v", d.Total, d.AmenityCount, d.Amenities)
+	}
+	if len(d.Calendar) != mocksite.CalendarDays || !d.Calendar[2].Blocked || d.Calendar[1].Blocked {
+		t.Errorf("calendar = %+v", d.Calendar)
+	}
+	if loft.Details.Baths != 1.5 || len(loft.Details.Fees) != 0 || loft.Details.Total != "" {
+		t.Errorf("per-night room details = %+v", loft.Details)
+	}
+	if got[2].Err == nil {
+		t.Error("removed room should fail")
+	}
+}
+
+// cardPrice is the price string extractCard builds from a mock card.
+func cardPrice(r mocksite.Room) string {
+	if r.Nights > 0 {
+		return fmt.Sprintf("$%d for %d nights", r.Total(), r.Nights)
+	}
+	return fmt.Sprintf("$%d", r.Total())
+}
@@ -0,0 +1,168 @@
+package airbnb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"airbnb-scraper/events"
+	"airbnb-scraper/utils"
+)
+
+// Typed extraction failures. Page loaders wrap the underlying error with one
+// of these so retry logic and reporting can branch with errors.Is instead of
+// matching message strings.
+var (
+	// ErrNavigationTimeout: the page did not load within its deadline.
+	// Usually transient, so it is retried.
+	ErrNavigationTimeout = errors.New("navigation timed out")
+	// ErrSelectorMissing: the page loaded but none of the elements we
+	// extract from were found — most likely a redesign. Not retried.
+	ErrSelectorMissing = errors.New("expected page elements missing")
+	// ErrBotChallenge: Airbnb served a captcha / "press and hold" page
+	// instead of content. Not retried; retrying only deepens the block.
+	ErrBotChallenge = errors.New("bot challenge served")
+	// ErrListingRemoved: the room redirected away or says it is no longer
+	// available. Not retried.
+	ErrListingRemoved = errors.New("listing removed")
+	// ErrWatchdog: the tab stopped responding and ran far past its timeout,
+	// so the watchdog closed it. Retried in a fresh tab.
+	ErrWatchdog = errors.New("tab hung, closed by watchdog")
+	// ErrPageBudget: the run's page-load cap (MAX_PAGES_PER_RUN /
+	// MAX_PAGES_PER_HOUR) is spent. Not retried.
+	ErrPageBudget = errors.New("page budget exhausted")
+	// ErrBlocked: the page answered 403 or 429 (see NetError). A 429 is
+	// retried after back-off; a 403 is not.
+	ErrBlocked = errors.New("blocked")
+	// ErrNetwork: the page failed below HTTP — DNS, TLS, proxy, connection
+	// (see NetError). Retried.
+	ErrNetwork = errors.New("network failure")
+)
+
+// pageStateJS defines pageState(expectRoom), which reports "challenge" for
+// bot-check interstitials, "removed" for delisted rooms (including a room URL
+// that redirected elsewhere) and "" otherwise. Callers concatenate it into
+// their own scripts, like cardExtractorJS.
+const pageStateJS = `
+	function pageState(expectRoom) {
+		var title = (document.title || '').toLowerCase();
+		var text = ((document.body && document.body.innerText) || '').slice(0, 4000).toLowerCase();
+		if (document.querySelector('#px-captcha, iframe[src*="captcha"], [data-testid="captcha"]') ||
+		    /access denied|are you a human|verify you are human|press (&|and) hold|unusual traffic/.test(title + ' ' + text)) {
+			return 'challenge';
+		}
+		if (expectRoom) {
+			if (location.pathname.indexOf('/rooms/') === -1) return 'removed';
+			if (/no longer available|listing (has been )?(removed|deactivated)/.test(text)) return 'removed';
+		}
+		return '';
+	}
+`
+
+// navError classifies a chromedp failure, keeping the original error in the
+// chain.
+func navError(op string, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%s: %w: %w", op, ErrNavigationTimeout, err)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// stateError maps a pageState() result to its typed error; "" is nil.
+func stateError(state, url string) error {
+	switch state {
+	case "challenge":
+		return fmt.Errorf("%s: %w", url, ErrBotChallenge)
+	case "removed":
+		return fmt.Errorf("%s: %w", url, ErrListingRemoved)
+	}
+	return nil
+}
+
+// retryable reports whether another attempt at the same page could succeed.
+func retryable(err error) bool {
+	var ne *NetError
+	if errors.As(err, &ne) && ne.Kind == "http" && ne.Status == 403 {
+		return false
+	}
+	return !errors.Is(err, context.Canceled) &&
+		!errors.Is(err, ErrSelectorMissing) &&
+		!errors.Is(err, ErrBotChallenge) &&
+		!errors.Is(err, ErrListingRemoved) &&
+		!errors.Is(err, ErrPageBudget)
+}
+
+// errorClass names the failure class of err for counting and logs.
+func errorClass(err error) string {
+	var ne *NetError
+	if errors.As(err, &ne) { // before timeout: a dead proxy often surfaces as one
+		return ne.class()
+	}
+	switch {
+	case errors.Is(err, ErrNavigationTimeout):
+		return "timeout"
+	case errors.Is(err, ErrSelectorMissing):
+		return "selector-missing"
+	case errors.Is(err, ErrBotChallenge):
+		return "bot-challenge"
+	case errors.Is(err, ErrListingRemoved):
+		return "removed"
+	case errors.Is(err, ErrWatchdog):
+		return "watchdog"
+	case errors.Is(err, ErrPageBudget):
+		return "page-budget"
+	case errors.Is(err, utils.ErrJobTimeout):
+		return "job-timeout"
+	}
+	var pe *utils.PanicError
+	if errors.As(err, &pe) {
+		return "panic"
+	}
+	return "other"
+}
+
+// jobFailed is the pool's error handler: a job outlived JOB_TIMEOUT.
+// Detail-page results carry the same error to applyDetail, which counts
+// and reports it, so this only notes it for debugging.
+func (s *Scraper) jobFailed(err error) {
+	s.logger.Debug("[airbnb] Pool job abandoned: %v", err)
+}
+
+// recordFailure counts a detail-page failure by class and publishes it.
+func (s *Scraper) recordFailure(url string, err error) {
+	class := errorClass(err)
+	s.mu.Lock()
+	if s.failures == nil {
+		s.failures = make(map[string]int)
+	}
+	s.failures[class]++
+	s.mu.Unlock()
+	s.events.Publish(events.ErrorOccurred{URL: url, Class: class, Err: err})
+}
+
+// Failures returns detail-page failure counts by class for this scraper.
+func (s *Scraper) Failures() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]int, len(s.failures))
+	for k, v := range s.failures {
+		out[k] = v
+	}
+	return out
+}
+
+// formatFailures renders failure counts as "bot-challenge=2, timeout=1".
+func formatFailures(f map[string]int) string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%d", k, f[k])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,48 @@
+package airbnb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorClassification(t *testing.T) {
+	timeout := navError("detail page", fmt.Errorf("chromedp: %w", context.DeadlineExceeded))
+	if !errors.Is(timeout, ErrNavigationTimeout) || !errors.Is(timeout, context.DeadlineExceeded) {
+		t.Errorf("timeout should wrap both the class and the cause: %v", timeout)
+	}
+
+	tests := []struct {
+		err       error
+		class     string
+		retryable bool
+	}{
+		{timeout, "timeout", true},
+		{stateError("challenge", "u"), "bot-challenge", false},
+		{stateError("removed", "u"), "removed", false},
+		{fmt.Errorf("p: %w", ErrSelectorMissing), "selector-missing", false},
+		{errors.New("net::ERR_CONNECTION_RESET"), "other", true},
+		{fmt.Errorf("detail page u: %w", &NetError{Kind: "http", Status: 429}), "blocked-429", true},
+		{fmt.Errorf("detail page u: %w", &NetError{Kind: "http", Status: 403}), "blocked-403", false},
+		{navError("detail page", fmt.Errorf("%w: %w", &NetError{Kind: "dns", Detail: "net::ERR_NAME_NOT_RESOLVED"}, context.DeadlineExceeded)), "net-dns", true},
+	}
+	for _, tt := range tests {
+		if got := errorClass(tt.err); got != tt.class {
+			t.Errorf("errorClass(%v) = %s, want %s", tt.err, got, tt.class)
+		}
+		if got := retryable(tt.err); got != tt.retryable {
+			t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.retryable)
+		}
+	}
+	if stateError("", "u") != nil {
+		t.Error("empty state should not be an error")
+	}
+}
+
+func TestFormatFailures(t *testing.T) {
+	got := formatFailures(map[string]int{"timeout": 1, "bot-challenge": 2})
+	if want := "bot-challenge=2, timeout=1"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
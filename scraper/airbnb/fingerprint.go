@@ -0,0 +1,70 @@
+package airbnb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/chromedp/chromedp"
+
+	"airbnb-scraper/models"
+)
+
+// fingerprintJS collects the structural markers the detail-page extractor
+// depends on: section ids, test ids and heading counts.
+const fingerprintJS = `
+	(function() {
+		var out = { sections: [], testIds: [], headings: {} };
+		var seen = {};
+		document.querySelectorAll('[data-section-id]').forEach(function(el) {
+			var id = el.getAttribute('data-section-id');
+			if (id && !seen['s:' + id]) { seen['s:' + id] = true; out.sections.push(id); }
+		});
+		document.querySelectorAll('[data-testid]').forEach(function(el) {
+			var id = el.getAttribute('data-testid');
+			if (id && !seen['t:' + id]) { seen['t:' + id] = true; out.testIds.push(id); }
+		});
+		['h1', 'h2', 'h3', 'h4'].forEach(function(tag) {
+			out.headings[tag] = document.querySelectorAll(tag).length;
+		});
+		return out;
+	})()
+`
+
+// FetchFingerprint loads a detail page and returns its structural fingerprint.
+func (s *Scraper) FetchFingerprint(url string) (*models.PageFingerprint, error) {
+	allocCtx, closeBrowser := s.newBrowser()
+	defer closeBrowser()
+
+	var data struct {
+		Sections []string       `json:"sections"`
+		TestIDs  []string       `json:"testIds"`
+		Headings map[string]int `json:"headings"`
+	}
+	err := s.retry.Do("fingerprint", func() error {
+		ctx, cancel := chromedp.NewContext(allocCtx)
+		defer cancel()
+		ctx, cancelTimeout := context.WithTimeout(ctx, 60*time.Second)
+		defer cancelTimeout()
+
+		return chromedp.Run(ctx,
+			chromedp.Navigate(url),
+			chromedp.Sleep(4*time.Second),
+			chromedp.Evaluate(fingerprintJS, &data),
+		)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fingerprint %s: %w", url, err)
+	}
+
+	sort.Strings(data.Sections)
+	sort.Strings(data.TestIDs)
+	return &models.PageFingerprint{
+		URL:        url,
+		CapturedAt: time.Now(),
+		SectionIDs: data.Sections,
+		TestIDs:    data.TestIDs,
+		Headings:   data.Headings,
+	}, nil
+}
@@ -0,0 +1,77 @@
+package airbnb
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"airbnb-scraper/models"
+)
+
+// hostJS defines readHost(), the host block of a room page: the user ID
+// from the profile link, the name from "Hosted by <name>" and the text of
+// the "Meet your host" section — or, without one, the "Hosted by" line and
+// the line after it ("Superhost · 6 years hosting") — for hostData.host to
+// parse. src is the strategy, empty when the page shows no host.
+const hostJS = `
+	function readHost() {
+		var out = {id: '', name: '', text: '', src: ''};
+		var hs = document.querySelector('[data-section-id^="MEET_YOUR_HOST"]') ||
+		         document.querySelector('[data-section-id^="HOST_PROFILE"]');
+		var link = (hs || document).querySelector('a[href*="/users/show/"], a[href*="/users/profile/"]');
+		if (link) {
+			var im = (link.getAttribute('href') || '').match(/\/users\/(?:show|profile)\/(\d+)/);
+			if (im) out.id = im[1];
+		}
+		var lines = (document.body.innerText || '').split('\n').map(function(l) { return l.trim(); });
+		for (var i = 0; i < lines.length; i++) {
+			var hb = lines[i].match(/^Hosted by (.{1,60})$/i);
+			if (!hb) continue;
+			out.name = hb[1].trim();
+			if (!hs) { out.text = lines[i] + '\n' + (lines[i + 1] || ''); out.src = 'hosted-by'; }
+			break;
+		}
+		if (hs) { out.text = hs.innerText || ''; out.src = 'host-section'; }
+		return out;
+	}
+`
+
+// hostData is what readHost returns.
+type hostData struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Text string `json:"text"`
+	Src  string `json:"src"`
+}
+
+var (
+	superhostRe    = regexp.MustCompile(`(?i)\bsuperhost\b`)
+	yearsHostingRe = regexp.MustCompile(`(?i)(\d+)\s+years?\s+(?:of\s+)?hosting|hosting\s+(?:for\s+)?(\d+)\s+years?`)
+	responseRateRe = regexp.MustCompile(`(?i)response\s+rate:?\s*(\d{1,3})\s*%`)
+	hostListingsRe = regexp.MustCompile(`(?i)(\d+)\s+listings?\b`)
+)
+
+// host parses the host block; it returns nil when the page showed none.
+func (d hostData) host() *models.Host {
+	if d.ID == "" && d.Name == "" && d.Text == "" {
+		return nil
+	}
+	// atoi reads the first group of re's first match that holds a number.
+	atoi := func(re *regexp.Regexp) int {
+		m := re.FindStringSubmatch(d.Text)
+		for i := 1; i < len(m); i++ {
+			if n, err := strconv.Atoi(m[i]); err == nil {
+				return n
+			}
+		}
+		return 0
+	}
+	return &models.Host{
+		ID:           d.ID,
+		Name:         strings.TrimSpace(d.Name),
+		Superhost:    superhostRe.MatchString(d.Text),
+		YearsHosting: atoi(yearsHostingRe),
+		ResponseRate: min(atoi(responseRateRe), 100),
+		ListingCount: atoi(hostListingsRe),
+	}
+}
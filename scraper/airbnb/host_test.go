@@ -0,0 +1,29 @@
+package airbnb
+
+import (
+	"testing"
+
+	"airbnb-scraper/models"
+)
+
+func TestHostDataHost(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		d    hostData
+		want *models.Host
+	}{
+		{"section", hostData{ID: "4242", Name: "Ana", Text: "Meet your host\nAna\nSuperhost\n312\nReviews\n4.93★\nRating\n6\nYears hosting\n" +
+			"Co-hosts\nResponse rate: 100%\nResponds within an hour\n3 listings"},
+			&models.Host{ID: "4242", Name: "Ana", Superhost: true, YearsHosting: 6, ResponseRate: 100, ListingCount: 3}},
+		{"hosted-by line", hostData{Name: "Ken ", Text: "Hosted by Ken\n2 years hosting"},
+			&models.Host{Name: "Ken", YearsHosting: 2}},
+		{"new host", hostData{ID: "7", Name: "Mia", Text: "Hosted by Mia\n5 months hosting"},
+			&models.Host{ID: "7", Name: "Mia"}},
+		{"no host", hostData{}, nil},
+	} {
+		got := tc.d.host()
+		if (got == nil) != (tc.want == nil) || got != nil && *got != *tc.want {
+			t.Errorf("%s: host() = %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package airbnb
+
+// imagesJS defines readImages(), the photo URLs of a room page's gallery in
+// page order, without their size parameters (muscache serves the original
+// then) and deduplicated. Host avatars are skipped, and so are the photos of
+// other rooms when the page-wide fallback has to look past the gallery.
+const imagesJS = `
+	function readImages() {
+		var out = {urls: [], src: ''};
+		var seen = {};
+		function collect(imgs, skipOtherRooms) {
+			imgs.forEach(function(img) {
+				var u = img.currentSrc || img.src || '';
+				if (!/\/im\/pictures\//.test(u) || /\/im\/pictures\/user\//.test(u)) return;
+				if (skipOtherRooms && img.closest('a[href*="/rooms/"]')) return;
+				u = u.split('#')[0].split('?')[0];
+				if (seen[u]) return;
+				seen[u] = true;
+				out.urls.push(u);
+			});
+		}
+		collect(document.querySelectorAll('[data-section-id^="HERO"] img, [data-testid="photo-viewer-section"] img'), false);
+		if (out.urls.length) { out.src = 'hero-gallery'; return out; }
+		collect(document.querySelectorAll('main img'), true);
+		if (out.urls.length) out.src = 'page-images';
+		return out;
+	}
+`
+
+// imageData is what readImages returns.
+type imageData struct {
+	URLs []string `json:"urls"`
+	Src  string   `json:"src"`
+}
@@ -0,0 +1,351 @@
+// Package mocksite serves a small static imitation of Airbnb — homepage,
+// search results and room pages — with the markup the extractors read, so
+// the chromedp scraper can be exercised end to end without the network.
+package mocksite
+
+import (
+	"bytes"
+	"embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Room is one listing the mock site serves.
+type Room struct {
+	ID          int64
+	Title       string
+	CardTitle   string // shorter title on cards; detail pages show Title
+	Kind        string // e.g. "Entire rental unit"
+	Location    string
+	Nightly     int // current price per night, in dollars
+	Original    int // struck-through price per night; 0 = no discount
+	Nights      int // stay length the card quotes; 0 = per night
+	Rating      string
+	Reviews     int
+	Lat, Lng    string
+	Description string
+	Removed     bool // room page says the listing is no longer available
+
+	// Capacity and amenities for the overview line and amenities section;
+	// zero counts are left out of the overview. The section shows the
+	// first ShownAmenities; its "Show all" dialog lists every one, then
+	// NotIncluded under "Not included".
+	Guests, Bedrooms, Beds int
+	Baths                  float64
+	Amenities              []string
+	NotIncluded            []string
+
+	// Fees appear in the price breakdown of rooms quoting a stay (Nights > 0).
+	CleaningFee, ServiceFee int
+
+	// Booked are the blocked days of the availability calendar, as offsets
+	// from today.
+	Booked []int
+
+	// OriginalDescription, when set, makes Description an auto-translation:
+	// the room page shows it with a "Show original" toggle.
+	OriginalDescription string
+
+	// Host, when set, adds "Hosted by" and the "Meet your host" section.
+	Host *Host
+
+	// Photos is the number of gallery photos; see Images.
+	Photos int
+}
+
+// Images are the paths of the room's gallery photos as the page shows
+// them, size parameter included.
+func (r Room) Images() []string {
+	out := make([]string, r.Photos)
+	for i := range out {
+		out[i] = fmt.Sprintf("/im/pictures/%d-%d.jpg?im_w=720", r.ID, i+1)
+	}
+	return out
+}
+
+// Host is the host of a mock room.
+type Host struct {
+	ID                  int64
+	Name                string
+	Superhost           bool
+	Years, ResponseRate int
+	Listings            int
+}
+
+// ana hosts two of the Lisbon rooms.
+var ana = &Host{ID: 4242, Name: "Ana", Superhost: true, Years: 6, ResponseRate: 100, Listings: 2}
+
+// Total is the price the card shows for the quoted stay.
+func (r Room) Total() int { return r.Nightly * max(r.Nights, 1) }
+
+// StayTotal is the breakdown total: the stay plus its fees.
+func (r Room) StayTotal() int { return r.Total() + r.CleaningFee + r.ServiceFee }
+
+// CalendarDays is the number of days the room page's calendar shows,
+// starting today.
+const CalendarDays = 14
+
+// Day is one cell of the availability calendar.
+type Day struct {
+	Date    time.Time
+	Blocked bool
+}
+
+// Calendar returns the CalendarDays days from today, with Booked blocked.
+func (r Room) Calendar() []Day {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days := make([]Day, CalendarDays)
+	for i := range days {
+		days[i].Date = today.AddDate(0, 0, i)
+	}
+	for _, b := range r.Booked {
+		if b >= 0 && b < CalendarDays {
+			days[b].Blocked = true
+		}
+	}
+	return days
+}
+
+// ShownAmenities is how many amenities the room page's section shows.
+const ShownAmenities = 3
+
+// SectionAmenities are the amenities the room page's section shows.
+func (r Room) SectionAmenities() []string { return r.Amenities[:min(len(r.Amenities), ShownAmenities)] }
+
+// OriginalTotal is the struck-through price for the quoted stay.
+func (r Room) OriginalTotal() int { return r.Original * max(r.Nights, 1) }
+
+// Section is a homepage carousel. Lazy sections are only rendered once the
+// page has been scrolled, like Airbnb's own below-the-fold rows.
+type Section struct {
+	ID      string
+	Heading string
+	Lazy    bool
+	Rooms   []Room
+}
+
+// Sections is the mock homepage, top to bottom. Every room appears in
+// exactly one section.
+var Sections = []Section{
+	{ID: "lisbon", Heading: "Popular homes in Lisbon", Rooms: []Room{
+		{ID: 7100001, Title: "Sunny Alfama apartment with river view", CardTitle: "Apartment in Alfama",
+			Kind: "Entire rental unit", Location: "Lisbon, Portugal", Nightly: 120, Original: 150, Nights: 5,
+			Rating: "4.92", Reviews: 128, Lat: "38.711720", Lng: "-9.130140",
+			Guests: 4, Bedrooms: 2, Beds: 2, Baths: 1, CleaningFee: 40, ServiceFee: 85, Booked: []int{2, 3, 4, 9},
+			Amenities:   []string{"River view", "Kitchen", "Wifi", "Washer", "Air conditioning"},
+			NotIncluded: []string{"Smoke alarm"},
+			Host:        ana,
+			Photos:      3,
+			Description: "Bright two-room flat on a quiet Alfama lane, a short walk from the river and the tram 28 stop."},
+		{ID: 7100002, Title: "Bairro Alto attic loft", CardTitle: "Loft in Bairro Alto",
+			Kind: "Entire loft", Location: "Lisbon, Portugal", Nightly: 95,
+			Rating: "4.81", Reviews: 64, Lat: "38.713400", Lng: "-9.145200",
+			Guests: 2, Bedrooms: 1, Beds: 1, Baths: 1.5,
+			Amenities:   []string{"Kitchen", "Wifi", "Dedicated workspace"},
+			Host:        ana,
+			Description: "Top-floor loft with skylights and a reading nook, right above the Bairro Alto cafés."},
+		{ID: 7100003, Title: "Belém family home", CardTitle: "Home in Belém",
+			Kind: "Entire home", Location: "Lisbon, Portugal", Nightly: 210, Nights: 7,
+			Rating: "4.97", Reviews: 22, Lat: "38.697700", Lng: "-9.206300",
+			Description: "Three bedrooms, a garden and parking, minutes from the monastery and the riverside path."},
+		{ID: 7100004, Title: "Retired Baixa studio", CardTitle: "Studio in Baixa",
+			Kind: "Entire rental unit", Location: "Lisbon, Portugal", Nightly: 70,
+			Rating: "4.60", Reviews: 12, Removed: true},
+	}},
+	{ID: "bangkok", Heading: "Stay in Bangkok", Lazy: true, Rooms: []Room{
+		{ID: 7200001, Title: "Riverside condo with rooftop pool", CardTitle: "Condo in Khlong San",
+			Kind: "Entire condo", Location: "Bangkok, Thailand", Nightly: 48, Original: 60, Nights: 5,
+			Rating: "4.88", Reviews: 342, Lat: "13.726500", Lng: "100.509800",
+			Description:         "Thirty-second floor condo with a rooftop infinity pool and a free shuttle boat to the BTS.",
+			OriginalDescription: "คอนโดชั้น 32 พร้อมสระว่ายน้ำอินฟินิตี้บนดาดฟ้า และเรือรับส่งฟรีไปยังรถไฟฟ้า BTS"},
+		{ID: 7200002, Title: "Sukhumvit studio near BTS", CardTitle: "Studio in Khlong Toei",
+			Kind: "Entire rental unit", Location: "Bangkok, Thailand", Nightly: 35,
+			Rating: "4.71", Reviews: 95, Lat: "13.737900", Lng: "100.560300",
+			Host:        &Host{ID: 5151, Name: "Somchai", Years: 1, ResponseRate: 90, Listings: 1},
+			Photos:      2,
+			Description: "Compact studio two minutes from Asok station, with a gym, co-working lounge and fast Wi-Fi."},
+		{ID: 7200003, Title: "Brand-new Ari garden studio", CardTitle: "Studio in Phaya Thai",
+			Kind: "Entire rental unit", Location: "Bangkok, Thailand", Nightly: 42,
+			Rating: "New", Lat: "13.779600", Lng: "100.544700",
+			Description: "Freshly built studio opening onto a shared garden, a short walk from Ari station's cafés."},
+	}},
+}
+
+//go:embed pages/*.html
+var pageFS embed.FS
+
+var pages = template.Must(template.ParseFS(pageFS, "pages/*.html"))
+
+// SearchPageSize is how many results a search page shows; the rest are
+// behind its Next link, as on Airbnb.
+const SearchPageSize = 2
+
+// searchCursor is the state Airbnb base64-encodes into a search page's
+// cursor parameter.
+type searchCursor struct {
+	SectionOffset int `json:"section_offset"`
+	ItemsOffset   int `json:"items_offset"`
+	Version       int `json:"version"`
+}
+
+// SearchCursor returns the cursor parameter of the search page starting at
+// result offset.
+func SearchCursor(offset int) string {
+	b, _ := json.Marshal(searchCursor{ItemsOffset: offset, Version: 1})
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// pageLink links the search page at offset, keeping u's other parameters
+// (dates and guests) as Airbnb's pagination does.
+func pageLink(u *url.URL, offset int) string {
+	q := u.Query()
+	q.Set("cursor", SearchCursor(offset))
+	return u.Path + "?" + q.Encode()
+}
+
+// parseCursor returns the result offset of a cursor; "" is the first page.
+func parseCursor(cursor string) (int, bool) {
+	if cursor == "" {
+		return 0, true
+	}
+	b, err := base64.StdEncoding.DecodeString(cursor)
+	var c searchCursor
+	if err != nil || json.Unmarshal(b, &c) != nil || c.ItemsOffset < 0 {
+		return 0, false
+	}
+	return c.ItemsOffset, true
+}
+
+// photo is the JPEG served for every gallery photo.
+var photo = func() []byte {
+	var b bytes.Buffer
+	img := image.NewGray(image.Rect(0, 0, 4, 3))
+	if err := jpeg.Encode(&b, img, nil); err != nil {
+		panic(err)
+	}
+	return b.Bytes()
+}()
+
+// Handler serves the mock site:
+//
+//	/                   homepage with Sections (lazy ones appear on scroll)
+//	/s/<query>/homes    search results: rooms whose location contains query,
+//	                    SearchPageSize per page; ?cursor= selects the page
+//	/rooms/<id>         room detail page; removed rooms say so
+//	/im/pictures/<name> a gallery photo (the same tiny JPEG for every name)
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		render(w, "home.html", Sections)
+	})
+	mux.HandleFunc("/s/", func(w http.ResponseWriter, r *http.Request) {
+		query, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/s/"), "/homes")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		query = strings.ReplaceAll(query, "-", " ")
+		offset, ok := parseCursor(r.URL.Query().Get("cursor"))
+		if !ok {
+			http.Error(w, "bad cursor", http.StatusBadRequest)
+			return
+		}
+		results := Search(query)
+		page := struct {
+			Query      string
+			Total      int
+			Rooms      []Room
+			Prev, Next string
+		}{Query: query, Total: len(results)}
+		if offset < len(results) {
+			page.Rooms = results[offset:min(offset+SearchPageSize, len(results))]
+		}
+		if offset > 0 {
+			page.Prev = pageLink(r.URL, max(offset-SearchPageSize, 0))
+		}
+		if offset+SearchPageSize < len(results) {
+			page.Next = pageLink(r.URL, offset+SearchPageSize)
+		}
+		render(w, "search.html", page)
+	})
+	mux.HandleFunc("/im/pictures/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(photo)
+	})
+	mux.HandleFunc("/rooms/", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/rooms/"), 10, 64)
+		room, ok := Find(id)
+		if err != nil || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		render(w, "room.html", struct {
+			Room
+			Similar []Room
+		}{room, similar(room)})
+	})
+	return mux
+}
+
+// Rooms returns every room on the homepage, in page order.
+func Rooms() []Room {
+	var out []Room
+	for _, s := range Sections {
+		out = append(out, s.Rooms...)
+	}
+	return out
+}
+
+// Find returns the room with the given id.
+func Find(id int64) (Room, bool) {
+	for _, r := range Rooms() {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return Room{}, false
+}
+
+// Search returns the live rooms whose location contains query, ignoring case.
+func Search(query string) []Room {
+	var out []Room
+	for _, r := range Rooms() {
+		if !r.Removed && strings.Contains(strings.ToLower(r.Location), strings.ToLower(query)) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// similar lists the other live rooms in the same location, for the
+// "Similar listings" carousel.
+func similar(room Room) []Room {
+	var out []Room
+	for _, r := range Search(room.Location) {
+		if r.ID != room.ID {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func render(w http.ResponseWriter, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pages.ExecuteTemplate(w, name, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,131 @@
+package mocksite
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func get(t *testing.T, srv *httptest.Server, path string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(srv.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(b)
+}
+
+func TestHandler(t *testing.T) {
+	srv := httptest.NewServer(Handler())
+	defer srv.Close()
+
+	code, body := get(t, srv, "/")
+	if code != http.StatusOK {
+		t.Fatalf("homepage: HTTP %d", code)
+	}
+	for _, want := range []string{
+		`data-section-id="lisbon"`, `href="/rooms/7100001"`, `<s>$750</s>`, `for 5 nights`,
+		`<template data-lazy="bangkok">`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("homepage lacks %s", want)
+		}
+	}
+
+	code, body = get(t, srv, "/s/Bangkok/homes")
+	if code != http.StatusOK || !strings.Contains(body, "/rooms/7200002") || strings.Contains(body, "/rooms/7100001") {
+		t.Errorf("search Bangkok: HTTP %d\n%s", code, body)
+	}
+
+	_, body = get(t, srv, "/s/Lisbon/homes")
+	next := `href="/s/Lisbon/homes?cursor=` + url.QueryEscape(SearchCursor(SearchPageSize)) + `"`
+	if !strings.Contains(body, "/rooms/7100001") || strings.Contains(body, "/rooms/7100003") || !strings.Contains(body, next) {
+		t.Errorf("search Lisbon page 1 should link page 2 and hold only its first rooms\n%s", body)
+	}
+	code, body = get(t, srv, "/s/Lisbon/homes?cursor="+url.QueryEscape(SearchCursor(SearchPageSize)))
+	if code != http.StatusOK || !strings.Contains(body, "/rooms/7100003") || strings.Contains(body, `aria-label="Next" href`) {
+		t.Errorf("search Lisbon page 2: HTTP %d\n%s", code, body)
+	}
+	if _, body = get(t, srv, "/s/Lisbon/homes?adults=2"); !strings.Contains(body, "adults=2&amp;cursor=") {
+		t.Errorf("search Lisbon Next link should keep the guests\n%s", body)
+	}
+	if code, _ = get(t, srv, "/s/Lisbon/homes?cursor=bogus"); code != http.StatusBadRequest {
+		t.Errorf("bad cursor: HTTP %d, want 400", code)
+	}
+
+	code, body = get(t, srv, "/rooms/7100002")
+	for _, want := range []string{
+		`<h1 elementtiming="LCP-target">Bairro Alto attic loft</h1>`, `Entire loft in Lisbon, Portugal`,
+		`$95 per night`, `content="38.713400"`, `href="/rooms/7100001"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("room page lacks %s", want)
+		}
+	}
+	if strings.Contains(body, "Cleaning fee") {
+		t.Error("per-night room should have no price breakdown")
+	}
+	if strings.Contains(body, `href="/rooms/7100004"`) {
+		t.Error("removed rooms should not be offered as similar listings")
+	}
+
+	_, body = get(t, srv, "/rooms/7100001")
+	for _, want := range []string{
+		`<li>4 guests</li>`, `<span>Cleaning fee</span> <span>$40</span>`,
+		`<span>Total before taxes</span> <span>$725</span>`, `Show all 5 amenities`,
+		`<li>Air conditioning</li>`, `<h3>Not included</h3>`,
+		`Hosted by Ana`, `href="/users/show/4242"`, `Response rate: 100%`,
+		`<img src="/im/pictures/7100001-3.jpg?im_w=720"`,
+		`data-testid="calendar-day-`, `data-is-day-blocked="true"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("room 7100001 lacks %s", want)
+		}
+	}
+
+	if _, body = get(t, srv, "/rooms/7200001"); !strings.Contains(body, "Show original") ||
+		!strings.Contains(body, `data-original="คอนโดชั้น 32`) {
+		t.Error("translated room should offer the original description")
+	}
+	if code, body = get(t, srv, "/im/pictures/7100001-1.jpg"); code != http.StatusOK || !strings.HasPrefix(body, "\xff\xd8") {
+		t.Errorf("gallery photo: HTTP %d, %d bytes", code, len(body))
+	}
+	if _, body = get(t, srv, "/rooms/7200003"); !strings.Contains(body, "★ New") ||
+		strings.Contains(body, "pdp-reviews-highlight-banner") {
+		t.Error("new room should show the New badge instead of a rating")
+	}
+	if _, body = get(t, srv, "/rooms/7100004"); !strings.Contains(body, "no longer available") {
+		t.Error("removed room should say it is no longer available")
+	}
+	if code, _ = get(t, srv, "/rooms/1"); code != http.StatusNotFound {
+		t.Errorf("unknown room: HTTP %d, want 404", code)
+	}
+}
+
+func TestRoomCalendar(t *testing.T) {
+	days := Room{Booked: []int{0, 2, CalendarDays}}.Calendar()
+	if len(days) != CalendarDays {
+		t.Fatalf("got %d days, want %d", len(days), CalendarDays)
+	}
+	if !days[0].Blocked || days[1].Blocked || !days[2].Blocked {
+		t.Errorf("blocked days = %v %v %v, want true false true", days[0].Blocked, days[1].Blocked, days[2].Blocked)
+	}
+	if !days[1].Date.Equal(days[0].Date.AddDate(0, 0, 1)) {
+		t.Errorf("days not consecutive: %v, %v", days[0].Date, days[1].Date)
+	}
+}
+
+func TestRoomTotals(t *testing.T) {
+	r := Room{Nightly: 120, Original: 150, Nights: 5}
+	if r.Total() != 600 || r.OriginalTotal() != 750 {
+		t.Errorf("totals = %d, %d", r.Total(), r.OriginalTotal())
+	}
+	if (Room{Nightly: 95}).Total() != 95 {
+		t.Error("per-night room total should be the nightly price")
+	}
+}
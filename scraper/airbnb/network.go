@@ -0,0 +1,109 @@
+package airbnb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// NetError is a page-load failure observed in the tab's CDP network events:
+// the document request failed at the network level (DNS, TLS, connection,
+// proxy, offline) or the server answered 403/429. It tells "blocked (429)"
+// apart from a dropped connection, which chromedp's own error does not.
+type NetError struct {
+	Kind   string // "http", "dns", "tls", "proxy", "offline" or "connection"
+	Status int    // HTTP status, for Kind "http"
+	Detail string // Chrome's net::ERR_* text, or the status text
+}
+
+func (e *NetError) Error() string {
+	if e.Kind == "http" {
+		return fmt.Sprintf("blocked (%d)", e.Status)
+	}
+	return fmt.Sprintf("network %s (%s)", e.Kind, e.Detail)
+}
+
+// Unwrap makes errors.Is match ErrBlocked for 403/429 and ErrNetwork for
+// network-level failures.
+func (e *NetError) Unwrap() error {
+	if e.Kind == "http" {
+		return ErrBlocked
+	}
+	return ErrNetwork
+}
+
+// class names the failure for counting: "blocked-429", "net-dns", …
+func (e *NetError) class() string {
+	if e.Kind == "http" {
+		return fmt.Sprintf("blocked-%d", e.Status)
+	}
+	return "net-" + e.Kind
+}
+
+// netErrorKind maps Chrome's net error text to a NetError kind; "" for
+// failures that are not network trouble (aborted navigations, requests we
+// blocked ourselves).
+func netErrorKind(text string) string {
+	switch {
+	case strings.Contains(text, "ERR_ABORTED"), strings.Contains(text, "ERR_BLOCKED_BY_CLIENT"):
+		return ""
+	case strings.Contains(text, "ERR_NAME_NOT_RESOLVED"), strings.Contains(text, "ERR_NAME_RESOLUTION_FAILED"):
+		return "dns"
+	case strings.Contains(text, "ERR_CERT_"), strings.Contains(text, "ERR_SSL_"):
+		return "tls"
+	case strings.Contains(text, "ERR_PROXY_"), strings.Contains(text, "ERR_TUNNEL_"):
+		return "proxy"
+	case strings.Contains(text, "ERR_INTERNET_DISCONNECTED"), strings.Contains(text, "ERR_NETWORK_CHANGED"):
+		return "offline"
+	}
+	return "connection"
+}
+
+// netWatcher keeps the first network failure of a tab's document loads.
+type netWatcher struct {
+	mu  sync.Mutex
+	err *NetError
+}
+
+// watchNetwork starts recording document-load failures on the tab ctx.
+func watchNetwork(ctx context.Context) *netWatcher {
+	w := &netWatcher{}
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch e := ev.(type) {
+		case *network.EventResponseReceived:
+			if e.Type != network.ResourceTypeDocument || e.Response == nil {
+				return
+			}
+			if s := int(e.Response.Status); s == 403 || s == 429 {
+				w.record(&NetError{Kind: "http", Status: s, Detail: e.Response.StatusText})
+			}
+		case *network.EventLoadingFailed:
+			if e.Type != network.ResourceTypeDocument || e.Canceled {
+				return
+			}
+			if kind := netErrorKind(e.ErrorText); kind != "" {
+				w.record(&NetError{Kind: kind, Detail: e.ErrorText})
+			}
+		}
+	})
+	return w
+}
+
+func (w *netWatcher) record(e *NetError) {
+	w.mu.Lock()
+	if w.err == nil {
+		w.err = e
+	}
+	w.mu.Unlock()
+}
+
+// failure returns the recorded failure, or nil.
+func (w *netWatcher) failure() *NetError {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
@@ -0,0 +1,54 @@
+package airbnb
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNetErrorKind(t *testing.T) {
+	tests := map[string]string{
+		"net::ERR_NAME_NOT_RESOLVED":       "dns",
+		"net::ERR_CERT_DATE_INVALID":       "tls",
+		"net::ERR_SSL_PROTOCOL_ERROR":      "tls",
+		"net::ERR_PROXY_CONNECTION_FAILED": "proxy",
+		"net::ERR_INTERNET_DISCONNECTED":   "offline",
+		"net::ERR_CONNECTION_RESET":        "connection",
+		"net::ERR_ABORTED":                 "",
+		"net::ERR_BLOCKED_BY_CLIENT":       "",
+	}
+	for text, want := range tests {
+		if got := netErrorKind(text); got != want {
+			t.Errorf("netErrorKind(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("detail page u: %w", &NetError{Kind: "http", Status: 429}), true},
+		{stateError("challenge", "u"), true},
+		{&NetError{Kind: "http", Status: 403}, false},
+		{&NetError{Kind: "dns"}, false},
+		{ErrSelectorMissing, false},
+	}
+	for _, tt := range tests {
+		if got := rateLimited(tt.err); got != tt.want {
+			t.Errorf("rateLimited(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestNetError(t *testing.T) {
+	blocked := &NetError{Kind: "http", Status: 429, Detail: "Too Many Requests"}
+	if blocked.Error() != "blocked (429)" || !errors.Is(blocked, ErrBlocked) || errors.Is(blocked, ErrNetwork) {
+		t.Errorf("429: %v", blocked)
+	}
+	dns := &NetError{Kind: "dns", Detail: "net::ERR_NAME_NOT_RESOLVED"}
+	if dns.Error() != "network dns (net::ERR_NAME_NOT_RESOLVED)" || !errors.Is(dns, ErrNetwork) {
+		t.Errorf("dns: %v", dns)
+	}
+}
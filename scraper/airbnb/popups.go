@@ -0,0 +1,90 @@
+package airbnb
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+// dismissPopupsJS closes the overlays Airbnb puts over a freshly loaded
+// page — cookie-consent banners, login nags, translation prompts and
+// app-download modals — and returns the kinds it dismissed. Their text
+// otherwise lands in document.body.innerText, where the body-text
+// fallbacks of the extractors pick it up (a "Translated in English" heading
+// reads as a location). A dialog is closed with its own button when it has
+// one and hidden either way, since the close may be animated; hidden
+// elements drop out of innerText without disturbing the page's own DOM
+// bookkeeping.
+const dismissPopupsJS = `
+	(function() {
+		var dismissed = [];
+		var clickButton = function(root, re) {
+			var buttons = root.querySelectorAll('button, [role="button"]');
+			for (var i = 0; i < buttons.length; i++) {
+				var label = (buttons[i].innerText || '').trim() || buttons[i].getAttribute('aria-label') || '';
+				if (re.test(label.trim())) { buttons[i].click(); return true; }
+			}
+			return false;
+		};
+
+		// Cookie consent: accept, which also keeps the banner from coming back.
+		var banners = document.querySelectorAll(
+			'[data-testid*="cookie" i], [aria-label*="cookie" i], [id*="cookie" i], [class*="cookie" i]');
+		for (var i = 0; i < banners.length; i++) {
+			if (clickButton(banners[i], /^(accept( all)?|agree|ok(ay)?|got it)$/i)) {
+				dismissed.push('cookie');
+				break;
+			}
+		}
+
+		// Modal dialogs, classified by their text.
+		var kinds = [
+			['login', /log in or sign up|continue with (email|google|apple|facebook)|welcome to airbnb/i],
+			['translation', /translat/i],
+			['app', /get the app|open (in|the) app|download the app|airbnb app/i]
+		];
+		var closeRe = /^(close|dismiss|not now|no thanks|maybe later|skip|continue in browser|×|✕)$/i;
+		document.querySelectorAll('[role="dialog"], [aria-modal="true"]').forEach(function(d) {
+			if (d.style.display === 'none') return;
+			var text = d.innerText || '';
+			for (var k = 0; k < kinds.length; k++) {
+				if (!kinds[k][1].test(text)) continue;
+				clickButton(d, closeRe);
+				d.style.display = 'none';
+				dismissed.push(kinds[k][0]);
+				return;
+			}
+		});
+
+		// Smart app banners sit outside any dialog.
+		document.querySelectorAll('[data-testid*="app-banner" i], [class*="app-banner" i]').forEach(function(b) {
+			if (b.style.display === 'none') return;
+			b.style.display = 'none';
+			dismissed.push('app');
+		});
+
+		// Modals lock page scrolling; lazy sections only load on scroll.
+		if (dismissed.length > 0) document.body.style.overflow = '';
+		return dismissed;
+	})()
+`
+
+// dismissPopups runs dismissPopupsJS in the tab. It is placed after the
+// first settle sleep of every navigation, before any scrolling or
+// extraction. A failed evaluation is ignored: the page is still usable,
+// only noisier.
+func (s *Scraper) dismissPopups() chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		var kinds []string
+		if err := chromedp.Evaluate(dismissPopupsJS, &kinds).Do(ctx); err != nil {
+			s.logger.Debug("[airbnb] Popup check failed: %v", err)
+			return nil
+		}
+		if len(kinds) > 0 {
+			s.logger.Debug("[airbnb] Dismissed popups: %s", strings.Join(kinds, ", "))
+			s.status.Add("popups_dismissed", len(kinds))
+		}
+		return nil
+	}
+}
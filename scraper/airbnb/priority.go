@@ -0,0 +1,29 @@
+package airbnb
+
+import "airbnb-scraper/utils"
+
+// Detail-page priorities. Queued pages start highest first, so when a run
+// is cut short — page budget, scrape window, interrupt — the monitoring
+// targets have been refreshed and only bulk discovery is left undone.
+const (
+	priorityBulk    = iota // discovery and the similar-listing crawl
+	priorityTracked        // PRIORITY_LIST_PATH
+	priorityCanary         // CANARY_URLS
+)
+
+// SetPriorityList marks tracked listings whose detail pages go ahead of
+// bulk discovery. Canary URLs always go first. l may be nil.
+func (s *Scraper) SetPriorityList(l *utils.IDList) {
+	s.tracked = l
+}
+
+// priority is the pool priority of a listing's detail page.
+func (s *Scraper) priority(url string) int {
+	switch {
+	case s.canaries.Contains(url):
+		return priorityCanary
+	case s.tracked != nil && s.tracked.Contains(url):
+		return priorityTracked
+	}
+	return priorityBulk
+}
@@ -0,0 +1,25 @@
+package airbnb
+
+import (
+	"testing"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/utils"
+)
+
+func TestPriority(t *testing.T) {
+	s := New(&config.Config{CanaryURLs: []string{"https://www.airbnb.com/rooms/1"}}, utils.NewLogger())
+	tracked, _ := utils.LoadIDList("")
+	tracked.Add("2")
+	s.SetPriorityList(tracked)
+
+	for url, want := range map[string]int{
+		"https://www.airbnb.com/rooms/1?check_in=2024-05-01": priorityCanary,
+		"https://www.airbnb.com/rooms/2":                     priorityTracked,
+		"https://www.airbnb.com/rooms/3":                     priorityBulk,
+	} {
+		if got := s.priority(url); got != want {
+			t.Errorf("priority(%s) = %d, want %d", url, got, want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package airbnb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/chromedp/chromedp"
+
+	"airbnb-scraper/models"
+)
+
+// replayCSP keeps a replayed capture inert: no scripts run and nothing is
+// fetched, so the DOM is exactly the archived server render. Evaluating the
+// extractor over CDP is not subject to the policy.
+const replayCSP = "default-src 'none'; style-src 'unsafe-inline'"
+
+// ReplayDetailPages runs the current detail-page extractor over archived
+// captures, so listings scraped before an extractor improvement can pick up
+// the fields it now finds. Only successful room pages are replayed; when a
+// room was archived more than once its latest capture wins. Each listing
+// carries its capture time as ScrapedAt.
+func (s *Scraper) ReplayDetailPages(pages []*models.PageCapture) ([]*models.RawListing, error) {
+	rooms := latestRoomCaptures(pages)
+	if len(rooms) == 0 {
+		return nil, nil
+	}
+
+	urls := make([]string, 0, len(rooms))
+	for room := range rooms {
+		urls = append(urls, room)
+	}
+	sort.Strings(urls)
+
+	// Serve the captures from loopback. The index keeps rooms from different
+	// Airbnb domains apart; the path still contains /rooms/ for pageState.
+	mux := http.NewServeMux()
+	paths := make([]string, len(urls))
+	for i, room := range urls {
+		u, _ := url.Parse(room)
+		paths[i] = fmt.Sprintf("/capture/%d%s", i, u.Path)
+		body := rooms[room].Body
+		mux.HandleFunc(paths[i], func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Header().Set("Content-Security-Policy", replayCSP)
+			_, _ = w.Write(body)
+		})
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("replay: listen: %w", err)
+	}
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer srv.Close()
+	base := "http://" + ln.Addr().String()
+
+	allocCtx, closeBrowser := s.newBrowser()
+	defer closeBrowser()
+
+	out := make([]*models.RawListing, 0, len(urls))
+	for i, room := range urls {
+		capture := rooms[room]
+		listing := &models.RawListing{
+			URL:           room,
+			Platform:      platform,
+			SchemaVersion: models.RawSchemaVersion,
+			ScrapedAt:     capture.CapturedAt,
+		}
+
+		var data detailData
+		ctx, cancel := chromedp.NewContext(allocCtx)
+		ctx, cancelTimeout := context.WithTimeout(ctx, 30*time.Second)
+		err := chromedp.Run(ctx,
+			chromedp.Navigate(base+paths[i]),
+			chromedp.Evaluate(detailExtractorJS, &data),
+		)
+		cancelTimeout()
+		cancel()
+		if err == nil {
+			err = data.check(room)
+		}
+		switch {
+		case errors.Is(err, ErrListingRemoved):
+			listing.Status = models.ListingStatusRemoved
+		case err != nil:
+			s.logger.Warn("[airbnb] Replay of %s failed (%s): %v", room, errorClass(err), err)
+			continue
+		default:
+			data.fill(listing, capture.CapturedAt)
+		}
+		out = append(out, listing)
+
+		if (i+1)%50 == 0 {
+			s.logger.Info("[airbnb] Replayed %d/%d captures", i+1, len(urls))
+		}
+	}
+	return out, nil
+}
+
+// latestRoomCaptures keeps the newest 200 response per canonical room URL.
+func latestRoomCaptures(pages []*models.PageCapture) map[string]*models.PageCapture {
+	rooms := make(map[string]*models.PageCapture)
+	for _, p := range pages {
+		room := roomURLRegexp.FindString(p.URL)
+		if room == "" || p.StatusCode != http.StatusOK {
+			continue
+		}
+		if prev, ok := rooms[room]; ok && prev.CapturedAt.After(p.CapturedAt) {
+			continue
+		}
+		rooms[room] = p
+	}
+	return rooms
+}
@@ -0,0 +1,28 @@
+package airbnb
+
+import (
+	"testing"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+func TestLatestRoomCaptures(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(24 * time.Hour)
+	pages := []*models.PageCapture{
+		{URL: "https://www.airbnb.com/rooms/1?adults=2", StatusCode: 200, Body: []byte("new"), CapturedAt: newer},
+		{URL: "https://www.airbnb.com/rooms/1", StatusCode: 200, Body: []byte("old"), CapturedAt: older},
+		{URL: "https://www.airbnb.com/rooms/2", StatusCode: 503, CapturedAt: newer},
+		{URL: "https://www.airbnb.com/s/Bali/homes", StatusCode: 200, CapturedAt: newer},
+	}
+
+	rooms := latestRoomCaptures(pages)
+	if len(rooms) != 1 {
+		t.Fatalf("got %d rooms, want 1: %v", len(rooms), rooms)
+	}
+	p, ok := rooms["https://www.airbnb.com/rooms/1"]
+	if !ok || string(p.Body) != "new" {
+		t.Errorf("rooms/1 = %+v, want the newer capture", p)
+	}
+}
@@ -0,0 +1,58 @@
+package airbnb
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/utils"
+)
+
+func TestPickCards(t *testing.T) {
+	var cards []cardInfo
+	for i := 0; i < 25; i++ {
+		cards = append(cards, cardInfo{URL: fmt.Sprintf("https://www.airbnb.com/rooms/%d", i)})
+	}
+
+	first := New(&config.Config{}, utils.NewLogger()).pickCards(cards)
+	if !reflect.DeepEqual(first, cards[:listingsPerSection]) {
+		t.Errorf("default should keep the first %d cards", listingsPerSection)
+	}
+
+	cfg := &config.Config{SampleCards: true, RandomSeed: 99}
+	a := New(cfg, utils.NewLogger()).pickCards(cards)
+	b := New(cfg, utils.NewLogger()).pickCards(cards)
+	if len(a) != listingsPerSection || !reflect.DeepEqual(a, b) {
+		t.Fatalf("seeded samples differ or have the wrong size:\n%v\n%v", a, b)
+	}
+	if reflect.DeepEqual(a, first) {
+		t.Error("sample happened to equal the first cards; pick another seed")
+	}
+
+	short := cards[:3]
+	if got := New(cfg, utils.NewLogger()).pickCards(short); !reflect.DeepEqual(got, short) {
+		t.Errorf("small sections should be kept whole, got %v", got)
+	}
+}
+
+func TestPickUserAgent(t *testing.T) {
+	if ua := New(&config.Config{}, utils.NewLogger()).pickUserAgent(); ua != userAgent {
+		t.Errorf("default user agent = %q", ua)
+	}
+	agents := []string{"UA-1", "UA-2", "UA-3"}
+	cfg := &config.Config{UserAgents: agents, RandomSeed: 5}
+	pick := func() []string {
+		s := New(cfg, utils.NewLogger())
+		return []string{s.pickUserAgent(), s.pickUserAgent(), s.pickUserAgent(), s.pickUserAgent()}
+	}
+	a := pick()
+	if !reflect.DeepEqual(a, pick()) {
+		t.Error("same seed should rotate user agents in the same order")
+	}
+	for _, ua := range a {
+		if ua != "UA-1" && ua != "UA-2" && ua != "UA-3" {
+			t.Errorf("picked %q, not one of USER_AGENTS", ua)
+		}
+	}
+}
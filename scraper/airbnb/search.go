@@ -0,0 +1,152 @@
+package airbnb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+const searchPathFormat = "s/%s/homes"
+
+// searchURL builds the Airbnb search-results URL for a free-text query,
+// for the configured stay.
+func (s *Scraper) searchURL(query string) string {
+	slug := strings.Join(strings.Fields(query), "-")
+	return s.stay.SearchURL(s.startURL() + fmt.Sprintf(searchPathFormat, url.PathEscape(slug)))
+}
+
+// SearchQuerySeparator separates the queries of SEARCH_QUERY; commas stay
+// part of a query, as in "Lisbon, Portugal".
+const SearchQuerySeparator = ";"
+
+// searchQueries splits SEARCH_QUERY into its queries, dropping blanks and
+// repeats.
+func searchQueries(raw string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, q := range strings.Split(raw, SearchQuerySeparator) {
+		q = strings.Join(strings.Fields(q), " ")
+		if key := strings.ToLower(q); q != "" && !seen[key] {
+			seen[key] = true
+			out = append(out, q)
+		}
+	}
+	return out
+}
+
+// discoverFromSearch loads the search results of every SEARCH_QUERY query
+// and returns one section per query, located at the query and holding
+// every card its result pages gave (see searchCards). A query that
+// fails is logged and skipped; the run fails only when all of them do.
+func (s *Scraper) discoverFromSearch(allocCtx context.Context) ([]section, error) {
+	queries := searchQueries(s.cfg.SearchQuery)
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("search discovery requires SEARCH_QUERY")
+	}
+
+	var sections []section
+	var firstErr error
+	for _, query := range queries {
+		cards, err := s.searchCards(allocCtx, query)
+		if err != nil {
+			s.logger.Warn("[airbnb] Search for %q failed: %v", query, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		s.logger.Info("[airbnb] Search %q: %d listings", query, len(cards))
+		sections = append(sections, section{
+			Name:     "Search: " + query,
+			Location: query,
+			Cards:    cards,
+			Paged:    true,
+		})
+	}
+	if len(sections) == 0 {
+		return nil, firstErr
+	}
+	return sections, nil
+}
+
+// searchCards collects query's search results: up to PAGES_TO_SCRAPE
+// pages, following each page's Next link (which carries Airbnb's cursor
+// parameter), and up to LISTINGS_PER_PAGE cards from each. Only a failed
+// first page is an error; a later one ends the query with what it has.
+func (s *Scraper) searchCards(allocCtx context.Context, query string) ([]cardInfo, error) {
+	var cards []cardInfo
+	pageURL := s.searchURL(query)
+	for page := 1; page <= max(s.cfg.PagesToScrape, 1) && pageURL != ""; page++ {
+		if page > 1 {
+			if s.cancelled() {
+				break
+			}
+			time.Sleep(s.rateLimit())
+		}
+		pageCards, next, err := s.searchPage(allocCtx, pageURL)
+		if err != nil {
+			if page == 1 {
+				return nil, err
+			}
+			s.logger.Warn("[airbnb] Search %q page %d failed: %v — keeping the first %d pages", query, page, err, page-1)
+			break
+		}
+		if n := s.cfg.ListingsPerPage; n > 0 && len(pageCards) > n {
+			pageCards = pageCards[:n]
+		}
+		s.logger.Debug("[airbnb] Search %q page %d: %d cards", query, page, len(pageCards))
+		cards = append(cards, pageCards...)
+		pageURL = next
+	}
+	return cards, nil
+}
+
+// searchPage loads one search-results page and returns its cards and the
+// URL of the next page, empty on the last one.
+func (s *Scraper) searchPage(allocCtx context.Context, pageURL string) ([]cardInfo, string, error) {
+	var page struct {
+		Cards []cardInfo `json:"cards"`
+		Next  string     `json:"next"`
+	}
+	defer s.status.Begin(pageURL)()
+	err := s.retry.Do("search-page", func() error {
+		ctx, tab := s.openTab(allocCtx, "search page", pageURL, 90*time.Second)
+		defer tab.Close()
+		flush := s.startCapture(ctx)
+
+		err := tab.Run(
+			chromedp.Navigate(pageURL),
+			chromedp.Sleep(6*time.Second),
+			s.dismissPopups(),
+			chromedp.Evaluate(`window.scrollTo(0, document.body.scrollHeight)`, nil),
+			chromedp.Sleep(2*time.Second),
+			chromedp.Evaluate(`
+				(function() {
+					`+cardExtractorJS+`
+					var cards = [];
+					document.querySelectorAll('a[href*="/rooms/"]').forEach(function(a) {
+						var c = extractCard(a);
+						if (c) cards.push(c);
+					});
+					var next = document.querySelector('a[aria-label="Next"][href]');
+					return {cards: cards, next: next ? next.href : ''};
+				})()
+			`, &page),
+		)
+		if err != nil {
+			return navError("chromedp search page", err)
+		}
+		flush()
+		if len(page.Cards) == 0 {
+			var state string
+			_ = tab.Run(chromedp.Evaluate(`(function() {`+pageStateJS+` return pageState(false); })()`, &state))
+			return stateError(state, pageURL)
+		}
+		return nil
+	})
+	return page.Cards, page.Next, err
+}
@@ -0,0 +1,16 @@
+package airbnb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearchQueries(t *testing.T) {
+	got := searchQueries(" Lisbon, Portugal ;Porto;; lisbon,  portugal ; Bangkok ")
+	if want := "Lisbon, Portugal|Porto|Bangkok"; strings.Join(got, "|") != want {
+		t.Errorf("searchQueries = %q, want %s", got, want)
+	}
+	if got := searchQueries(" ; "); len(got) != 0 {
+		t.Errorf("blank queries = %q, want none", got)
+	}
+}
@@ -0,0 +1,60 @@
+package airbnb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+// crawlSimilar expands coverage breadth-first from the "Similar listings"
+// links collected during section enrichment. Each level is enriched like a
+// section; expansion stops at SIMILAR_CRAWL_DEPTH levels or once
+// SIMILAR_CRAWL_LIMIT extra listings have been collected, or when the retry
+// or page budget runs out (these listings have no card data to fall back on).
+// For the same reason, listings visited by earlier runs are loaded again.
+func (s *Scraper) crawlSimilar(allocCtx context.Context, frontier []string) {
+	limit := s.cfg.SimilarCrawlLimit
+	collected := 0
+
+	for depth := 1; depth <= s.cfg.SimilarCrawlDepth && len(frontier) > 0; depth++ {
+		if s.degraded() || s.pagesSpent() || s.cancelled() {
+			break
+		}
+		var level []*models.RawListing
+		for _, u := range frontier {
+			if limit > 0 && collected+len(level) >= limit {
+				break
+			}
+			if !s.permitted(u) || !s.seen.Add(u) {
+				continue
+			}
+			level = append(level, &models.RawListing{
+				URL:           u,
+				ScrapedAt:     time.Now(),
+				Platform:      platform,
+				SchemaVersion: models.RawSchemaVersion,
+			})
+		}
+		if len(level) == 0 {
+			break
+		}
+
+		name := fmt.Sprintf("Similar listings (depth %d)", depth)
+		s.printSectionBanner(depth, s.cfg.SimilarCrawlDepth, name, len(level), len(level))
+		frontier = s.enrichListings(allocCtx, level)
+		collected += len(level)
+
+		total := s.emit(level)
+
+		s.printSectionDone(name)
+		s.logger.Info("[airbnb] Similar crawl depth %d: +%d listings (running total: %d)", depth, len(level), total)
+
+		if limit > 0 && collected >= limit {
+			s.logger.Info("[airbnb] Similar crawl limit of %d reached", limit)
+			break
+		}
+		time.Sleep(s.rateLimit())
+	}
+}
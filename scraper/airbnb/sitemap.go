@@ -0,0 +1,160 @@
+package airbnb
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// roomURLRegexp matches canonical listing URLs found in the sitemaps.
+var roomURLRegexp = regexp.MustCompile(`^https?://[^/]*airbnb\.[^/]+/rooms/\d+`)
+
+// sitemapDoc covers both <sitemapindex> and <urlset> documents.
+type sitemapDoc struct {
+	XMLName  xml.Name
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+	URLs []struct {
+		Loc string `xml:"loc"`
+	} `xml:"url"`
+}
+
+// discoverFromSitemaps walks the configured sitemap index and returns the
+// room URLs it lists, batched into sections of listingsPerSection cards.
+// Only child sitemaps whose location matches SITEMAP_FILTER are followed,
+// which is how a run is scoped to a geography.
+func (s *Scraper) discoverFromSitemaps() ([]section, error) {
+	var filter *regexp.Regexp
+	if s.cfg.SitemapFilter != "" {
+		re, err := regexp.Compile("(?i)" + s.cfg.SitemapFilter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SITEMAP_FILTER: %w", err)
+		}
+		filter = re
+	}
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	ua := s.pickUserAgent()
+	limit := s.cfg.SitemapMaxURLs
+
+	var sections []section
+	seen := make(map[string]struct{})
+	queue := []string{s.cfg.SitemapURL}
+	visited := make(map[string]struct{})
+
+	for len(queue) > 0 && (limit <= 0 || len(seen) < limit) {
+		loc := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[loc]; ok {
+			continue
+		}
+		visited[loc] = struct{}{}
+
+		var doc *sitemapDoc
+		err := s.retry.Do("sitemap-fetch", func() error {
+			var err error
+			doc, err = fetchSitemap(client, loc, ua)
+			return err
+		})
+		if err != nil {
+			s.logger.Warn("[airbnb] Sitemap %s failed: %v", loc, err)
+			continue
+		}
+
+		for _, sm := range doc.Sitemaps {
+			child := strings.TrimSpace(sm.Loc)
+			if child == "" || (filter != nil && !filter.MatchString(child)) {
+				continue
+			}
+			queue = append(queue, child)
+		}
+
+		var cards []cardInfo
+		for _, u := range doc.URLs {
+			room := roomURLRegexp.FindString(strings.TrimSpace(u.Loc))
+			if room == "" {
+				continue
+			}
+			if _, dup := seen[room]; dup {
+				continue
+			}
+			if limit > 0 && len(seen) >= limit {
+				break
+			}
+			seen[room] = struct{}{}
+			cards = append(cards, cardInfo{URL: room})
+		}
+		if len(cards) > 0 {
+			s.logger.Info("[airbnb] Sitemap %s: %d room URLs", path.Base(loc), len(cards))
+			sections = append(sections, batchSitemapCards(path.Base(loc), cards)...)
+		}
+	}
+
+	return sections, nil
+}
+
+// batchSitemapCards splits one sitemap's cards into section-sized batches so
+// progress reporting and enrichment stay bounded per section.
+func batchSitemapCards(name string, cards []cardInfo) []section {
+	var out []section
+	for i := 0; i < len(cards); i += listingsPerSection {
+		end := i + listingsPerSection
+		if end > len(cards) {
+			end = len(cards)
+		}
+		out = append(out, section{
+			Name:  fmt.Sprintf("Sitemap %s [%d-%d]", name, i+1, end),
+			Cards: cards[i:end],
+		})
+	}
+	return out
+}
+
+func fetchSitemap(client *http.Client, loc, ua string) (*sitemapDoc, error) {
+	req, err := http.NewRequest(http.MethodGet, loc, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", ua)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sitemap %s: HTTP %d", loc, resp.StatusCode)
+	}
+	return parseSitemap(resp.Body)
+}
+
+// parseSitemap decodes a sitemap or sitemap index, transparently handling
+// gzip-compressed files (Airbnb publishes *.xml.gz).
+func parseSitemap(r io.Reader) (*sitemapDoc, error) {
+	br := bufio.NewReader(r)
+	var body io.Reader = br
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("sitemap gzip: %w", err)
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	doc := &sitemapDoc{}
+	if err := xml.NewDecoder(body).Decode(doc); err != nil {
+		return nil, fmt.Errorf("sitemap xml: %w", err)
+	}
+	return doc, nil
+}
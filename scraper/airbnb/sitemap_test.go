@@ -0,0 +1,52 @@
+package airbnb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+)
+
+func TestParseSitemapIndexAndGzipURLSet(t *testing.T) {
+	index := `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://www.airbnb.com/sitemap-homes-urls-1.xml.gz</loc></sitemap>
+</sitemapindex>`
+	doc, err := parseSitemap(strings.NewReader(index))
+	if err != nil {
+		t.Fatalf("parse index: %v", err)
+	}
+	if len(doc.Sitemaps) != 1 || len(doc.URLs) != 0 {
+		t.Fatalf("index: got %d sitemaps / %d urls", len(doc.Sitemaps), len(doc.URLs))
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://www.airbnb.com/rooms/123</loc></url>
+  <url><loc>https://www.airbnb.com/rooms/456?locale=en</loc></url>
+</urlset>`))
+	gz.Close()
+
+	doc, err = parseSitemap(&buf)
+	if err != nil {
+		t.Fatalf("parse gzip urlset: %v", err)
+	}
+	if len(doc.URLs) != 2 {
+		t.Fatalf("urlset: got %d urls, want 2", len(doc.URLs))
+	}
+	if got := roomURLRegexp.FindString(doc.URLs[1].Loc); got != "https://www.airbnb.com/rooms/456" {
+		t.Errorf("room URL: got %q", got)
+	}
+}
+
+func TestBatchSitemapCards(t *testing.T) {
+	cards := make([]cardInfo, listingsPerSection+3)
+	secs := batchSitemapCards("homes.xml.gz", cards)
+	if len(secs) != 2 {
+		t.Fatalf("got %d sections, want 2", len(secs))
+	}
+	if len(secs[1].Cards) != 3 || secs[1].Location != "" {
+		t.Errorf("second batch: %d cards, location %q", len(secs[1].Cards), secs[1].Location)
+	}
+}
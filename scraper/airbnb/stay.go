@@ -0,0 +1,89 @@
+package airbnb
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"airbnb-scraper/config"
+)
+
+// stayDateLayout is the date format of CHECK_IN / CHECK_OUT and of
+// Airbnb's own date parameters.
+const stayDateLayout = config.StayDateLayout
+
+// Stay is the trip that search results and detail pages are priced for.
+// The zero Stay sends no dates or party size, leaving them to Airbnb.
+type Stay struct {
+	CheckIn  time.Time
+	CheckOut time.Time
+	Guests   int // adults; 0 = Airbnb's default
+}
+
+// ParseStay reads CHECK_IN, CHECK_OUT (YYYY-MM-DD, both or neither) and
+// GUESTS; see config.ParseStayDates.
+func ParseStay(checkIn, checkOut string, guests int) (Stay, error) {
+	in, out, err := config.ParseStayDates(checkIn, checkOut, guests)
+	if err != nil {
+		return Stay{}, err
+	}
+	return Stay{CheckIn: in, CheckOut: out, Guests: guests}, nil
+}
+
+// Nights is the length of the stay; 0 without dates.
+func (st Stay) Nights() int {
+	if st.CheckIn.IsZero() {
+		return 0
+	}
+	return int(st.CheckOut.Sub(st.CheckIn).Hours() / 24)
+}
+
+// String describes the stay for logs.
+func (st Stay) String() string {
+	var parts []string
+	if !st.CheckIn.IsZero() {
+		parts = append(parts, fmt.Sprintf("%s → %s (%d nights)",
+			st.CheckIn.Format(stayDateLayout), st.CheckOut.Format(stayDateLayout), st.Nights()))
+	}
+	if st.Guests > 0 {
+		parts = append(parts, fmt.Sprintf("%d guests", st.Guests))
+	}
+	if len(parts) == 0 {
+		return "Airbnb default dates and guests"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// apply adds the stay to u using the given date parameter names: search
+// pages take checkin/checkout, room pages check_in/check_out.
+func (st Stay) apply(u, checkInParam, checkOutParam string) string {
+	q := url.Values{}
+	if !st.CheckIn.IsZero() {
+		q.Set(checkInParam, st.CheckIn.Format(stayDateLayout))
+		q.Set(checkOutParam, st.CheckOut.Format(stayDateLayout))
+	}
+	if st.Guests > 0 {
+		q.Set("adults", strconv.Itoa(st.Guests))
+	}
+	if len(q) == 0 {
+		return u
+	}
+	sep := "?"
+	if strings.Contains(u, "?") {
+		sep = "&"
+	}
+	return u + sep + q.Encode()
+}
+
+// SearchURL adds the stay to a search-results URL.
+func (st Stay) SearchURL(u string) string {
+	return st.apply(u, "checkin", "checkout")
+}
+
+// RoomURL adds the stay to a room page URL. Listings keep their plain URL;
+// only the navigation carries the stay.
+func (st Stay) RoomURL(u string) string {
+	return st.apply(u, "check_in", "check_out")
+}
@@ -0,0 +1,43 @@
+package airbnb
+
+import (
+	"testing"
+)
+
+func TestParseStay(t *testing.T) {
+	st, err := ParseStay("2026-12-20", "2026-12-27", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Nights() != 7 || st.Guests != 3 {
+		t.Errorf("Nights = %d, Guests = %d; want 7, 3", st.Nights(), st.Guests)
+	}
+	if got, want := st.RoomURL("https://www.airbnb.com/rooms/42"), "https://www.airbnb.com/rooms/42?adults=3&check_in=2026-12-20&check_out=2026-12-27"; got != want {
+		t.Errorf("RoomURL = %s, want %s", got, want)
+	}
+	if got, want := st.SearchURL("https://www.airbnb.com/s/Lisbon/homes?currency=EUR"), "https://www.airbnb.com/s/Lisbon/homes?currency=EUR&adults=3&checkin=2026-12-20&checkout=2026-12-27"; got != want {
+		t.Errorf("SearchURL = %s, want %s", got, want)
+	}
+
+	zero, err := ParseStay("", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u := "https://www.airbnb.com/rooms/42"; zero.RoomURL(u) != u {
+		t.Errorf("zero Stay changed the URL: %s", zero.RoomURL(u))
+	}
+
+	for _, bad := range [][2]string{
+		{"2026-12-20", ""},
+		{"20/12/2026", "2026-12-27"},
+		{"2026-12-27", "2026-12-27"},
+		{"2026-12-27", "2026-12-20"},
+	} {
+		if _, err := ParseStay(bad[0], bad[1], 2); err == nil {
+			t.Errorf("ParseStay(%q, %q) accepted", bad[0], bad[1])
+		}
+	}
+	if _, err := ParseStay("", "", -1); err == nil {
+		t.Error("negative guests accepted")
+	}
+}
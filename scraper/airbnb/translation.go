@@ -0,0 +1,68 @@
+package airbnb
+
+import (
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// showOriginalFinderJS defines findShowOriginal(), which returns the
+// "Show original" toggle Airbnb puts on auto-translated listings, or null.
+const showOriginalFinderJS = `
+	function findShowOriginal() {
+		var btns = document.querySelectorAll('button, [role="button"]');
+		for (var i = 0; i < btns.length; i++) {
+			if (/^show original( language)?$/i.test((btns[i].innerText || '').trim())) return btns[i];
+		}
+		return null;
+	}
+`
+
+// descriptionSectionJS defines readDescriptionSection(), the text of the
+// description section without the translation notice and its toggles.
+const descriptionSectionJS = `
+	function readDescriptionSection() {
+		var el = document.querySelector('[data-section-id="DESCRIPTION_DEFAULT"]');
+		if (!el) return '';
+		return el.innerText
+			.replace(/Some info has been automatically translated\.?\s*(Show (original|translation))?/gi, '')
+			.replace(/Show more/gi, '')
+			.trim()
+			.substring(0, 1000);
+	}
+`
+
+// showOriginalJS clicks the "Show original" toggle and reports whether
+// there was one.
+const showOriginalJS = `
+	(function() {
+		` + showOriginalFinderJS + `
+		var btn = findShowOriginal();
+		if (!btn) return false;
+		btn.click();
+		return true;
+	})()
+`
+
+// readOriginal switches an auto-translated listing back to its original
+// language and returns that description. It returns "" when the toggle is
+// gone, the section did not change or the tab failed; the translated
+// description extracted before stays either way.
+func (s *Scraper) readOriginal(t *tab, translated string) string {
+	var clicked bool
+	var original string
+	err := t.Run(
+		chromedp.Evaluate(showOriginalJS, &clicked),
+		chromedp.Sleep(1500*time.Millisecond),
+		chromedp.Evaluate(`(function() {`+descriptionSectionJS+` return readDescriptionSection(); })()`, &original),
+	)
+	switch {
+	case err != nil:
+		s.logger.Debug("[airbnb] Show original failed on %s: %v", t.url, err)
+		return ""
+	case !clicked || original == "" || original == translated:
+		s.logger.Debug("[airbnb] No original description on %s", t.url)
+		return ""
+	}
+	return original
+}
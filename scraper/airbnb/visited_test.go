@@ -0,0 +1,51 @@
+package airbnb
+
+import (
+	"errors"
+	"testing"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+func TestUnvisitedKeepsVisitedCards(t *testing.T) {
+	s := New(&config.Config{}, utils.NewLogger())
+	listings := []*models.RawListing{
+		{URL: "https://www.airbnb.com/rooms/1"},
+		{URL: "https://www.airbnb.com/rooms/2"},
+	}
+	if got := s.unvisited(listings); len(got) != 2 {
+		t.Fatalf("without a visited set: %d listings to enrich, want 2", len(got))
+	}
+
+	visited := utils.NewURLSet()
+	visited.Add("https://www.airbnb.com/rooms/1")
+	s.SetVisited(visited)
+	got := s.unvisited(listings)
+	if len(got) != 1 || got[0].URL != "https://www.airbnb.com/rooms/2" {
+		t.Errorf("listings to enrich = %v, want only rooms/2", got)
+	}
+	if visited.Contains("https://www.airbnb.com/rooms/2") {
+		t.Error("rooms/2 marked visited before its detail page loaded")
+	}
+}
+
+func TestApplyDetailMarksVisitedOnSuccess(t *testing.T) {
+	s := New(&config.Config{}, utils.NewLogger())
+	visited := utils.NewURLSet()
+	s.SetVisited(visited)
+
+	failed := &models.RawListing{URL: "https://www.airbnb.com/rooms/1"}
+	s.applyDetail(failed, utils.Result[detailResult]{Input: failed.URL, Err: ErrPageBudget})
+	s.applyDetail(failed, utils.Result[detailResult]{Input: failed.URL, Err: errors.New("timeout")})
+	if visited.Contains(failed.URL) {
+		t.Error("a failed detail page was marked visited")
+	}
+
+	ok := &models.RawListing{URL: "https://www.airbnb.com/rooms/2"}
+	s.applyDetail(ok, utils.Result[detailResult]{Input: ok.URL, Value: detailResult{listing: &models.RawListing{URL: ok.URL}}})
+	if !visited.Contains(ok.URL) {
+		t.Error("a loaded detail page was not marked visited")
+	}
+}
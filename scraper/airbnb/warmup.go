@@ -0,0 +1,61 @@
+package airbnb
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// warmUp browses WARMUP_PATHS before the real scrape so the session has
+// cookies, a consent choice and a currency/locale like a visitor's, rather
+// than opening straight onto room pages. Tabs share the browser's cookie
+// jar, so the state carries over. Failures are logged and ignored.
+func (s *Scraper) warmUp(allocCtx context.Context) {
+	urls := warmupURLs(s.startURL(), s.cfg.WarmupPaths, s.cfg.WarmupCurrency, s.cfg.WarmupLocale)
+	s.logger.Info("[airbnb] Warming up the session on %d pages…", len(urls))
+	for _, u := range urls {
+		_, tab := s.openTab(allocCtx, "warm-up", u, 45*time.Second)
+		err := tab.Run(
+			chromedp.Navigate(u),
+			chromedp.Sleep(3*time.Second),
+			s.dismissPopups(),
+			chromedp.Evaluate(`window.scrollTo(0, document.body.scrollHeight * 0.5)`, nil),
+			chromedp.Sleep(s.rand.Jitter(2*time.Second, 0.5)),
+		)
+		tab.Close()
+		if err != nil {
+			s.logger.Warn("[airbnb] Warm-up page %s failed: %v", u, err)
+		} else {
+			s.logger.Debug("[airbnb] Warm-up: visited %s", u)
+		}
+		time.Sleep(s.rateLimit())
+	}
+}
+
+// warmupURLs resolves paths against the site root. The first page carries
+// the currency and locale parameters, which Airbnb remembers for the
+// session.
+func warmupURLs(start string, paths []string, currency, locale string) []string {
+	base := strings.TrimRight(start, "/")
+	out := make([]string, 0, len(paths))
+	for i, p := range paths {
+		u := base + "/" + strings.TrimLeft(p, "/")
+		if i == 0 {
+			q := url.Values{}
+			if currency != "" {
+				q.Set("currency", currency)
+			}
+			if locale != "" {
+				q.Set("locale", locale)
+			}
+			if len(q) > 0 {
+				u += "?" + q.Encode()
+			}
+		}
+		out = append(out, u)
+	}
+	return out
+}
@@ -0,0 +1,21 @@
+package airbnb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWarmupURLs(t *testing.T) {
+	got := warmupURLs("https://www.airbnb.com/", []string{"/", "help", "/s/experiences"}, "USD", "en")
+	want := []string{
+		"https://www.airbnb.com/?currency=USD&locale=en",
+		"https://www.airbnb.com/help",
+		"https://www.airbnb.com/s/experiences",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("warmupURLs = %q, want %q", got, want)
+	}
+	if got := warmupURLs("http://localhost:8089/", []string{"/"}, "", ""); got[0] != "http://localhost:8089/" {
+		t.Errorf("no currency/locale: %q", got)
+	}
+}
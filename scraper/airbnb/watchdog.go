@@ -0,0 +1,89 @@
+package airbnb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+
+	"airbnb-scraper/utils"
+)
+
+// tab is a browser tab opened for one page load. Its actions run under a
+// watchdog: a wedged tab can ignore its context deadline and block
+// chromedp.Run indefinitely, so once WATCHDOG_FACTOR × the timeout passes
+// the tab is force-closed in the background, the incident is logged and
+// counted, and the worker moves on with ErrWatchdog.
+type tab struct {
+	s       *Scraper
+	ctx     context.Context
+	cancel  func()
+	op, url string
+	ceiling time.Duration
+	hung    bool
+	denied  bool // the page budget refused this load
+	net     *netWatcher
+}
+
+// openTab waits out any rate-limit cool-down, then opens a tab for op on
+// url with the given timeout, charging one load to the page budget; when
+// the budget is spent, Run fails with ErrPageBudget without loading
+// anything. The returned context is the tab's; Close it when done.
+func (s *Scraper) openTab(allocCtx context.Context, op, url string, timeout time.Duration) (context.Context, *tab) {
+	s.cooldown.Wait() // every load, including retries and discovery
+	ctx, cancelTab := chromedp.NewContext(allocCtx)
+	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
+	t := &tab{
+		s:      s,
+		ctx:    ctx,
+		cancel: func() { cancelTimeout(); cancelTab() },
+		op:     op,
+		url:    url,
+		net:    watchNetwork(ctx),
+	}
+	if s.pages != nil && !s.pages.Take() {
+		t.denied = true
+	}
+	if f := s.cfg.WatchdogFactor; f > 0 {
+		t.ceiling = time.Duration(max(f, 1) * float64(timeout))
+	}
+	return ctx, t
+}
+
+// Run is chromedp.Run on the tab, bounded by the watchdog ceiling. Once
+// the tab has hung, further runs fail immediately. A network failure or
+// 403/429 seen on the document load is returned as a *NetError — wrapping
+// chromedp's error when there is one, and even when the page "loaded".
+func (t *tab) Run(actions ...chromedp.Action) error {
+	if t.denied {
+		return fmt.Errorf("%s %s: %w", t.op, t.url, ErrPageBudget)
+	}
+	if t.hung {
+		return fmt.Errorf("%s %s: %w", t.op, t.url, ErrWatchdog)
+	}
+	err := utils.RunWithin(t.ceiling, func() error { return chromedp.Run(t.ctx, actions...) })
+	if !errors.Is(err, utils.ErrHung) {
+		if ne := t.net.failure(); ne != nil {
+			if err != nil {
+				return fmt.Errorf("%s %s: %w: %w", t.op, t.url, ne, err)
+			}
+			return fmt.Errorf("%s %s: %w", t.op, t.url, ne)
+		}
+		return err
+	}
+	t.hung = true
+	t.s.logger.Warn("[watchdog] %s %s still running after %v — force-closing its tab", t.op, t.url, t.ceiling)
+	t.s.status.Add("watchdog", 1)
+	go t.cancel() // closing a wedged tab can block too
+	return fmt.Errorf("%s %s: %w", t.op, t.url, ErrWatchdog)
+}
+
+// Close closes the tab; a tab the watchdog already abandoned is left to its
+// background close.
+func (t *tab) Close() {
+	if !t.hung {
+		t.cancel()
+	}
+}
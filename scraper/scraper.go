@@ -0,0 +1,25 @@
+// Package scraper defines what the pipeline needs from a listing source, so
+// the stages after it — CSV, cleaning, storage, insights — can run against
+// scrapertest.Mock instead of a browser. airbnb.Scraper is the real one.
+package scraper
+
+import (
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+)
+
+// Scraper collects raw listings. One Scraper serves one scrape.
+type Scraper interface {
+	// SetOutput streams each finished batch (a section, a search page) to
+	// ch instead of accumulating listings for Scrape's return value. Sends
+	// block while the consumer falls behind; the caller owns and closes ch.
+	SetOutput(ch chan<- []*models.RawListing)
+	// Scrape collects listings until the source is exhausted or fails. With
+	// an output set it returns no listings, having sent them all to the
+	// output; on error, what was collected so far is still delivered.
+	Scrape() ([]*models.RawListing, error)
+}
+
+// Factory returns a ready-to-run Scraper for cfg, which may be a per-city
+// copy of the run's config.
+type Factory func(cfg *config.Config) Scraper
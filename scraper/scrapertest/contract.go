@@ -0,0 +1,87 @@
+package scrapertest
+
+import (
+	"testing"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/scraper"
+)
+
+// Contract checks the behaviour the pipeline relies on from a Scraper.
+// newScraper must return a fresh Scraper over a source that yields at
+// least one listing. It checks that:
+//
+//   - without an output, Scrape returns the listings, each with a URL and
+//     a scrape time;
+//   - with an output, Scrape sends the same listings in non-empty batches,
+//     returns none itself, and leaves the channel open for the caller.
+func Contract(t *testing.T, newScraper func(t *testing.T) scraper.Scraper) {
+	t.Helper()
+	var returned []*models.RawListing
+
+	t.Run("return", func(t *testing.T) {
+		listings, err := newScraper(t).Scrape()
+		if err != nil {
+			t.Fatalf("Scrape: %v", err)
+		}
+		if len(listings) == 0 {
+			t.Fatal("Scrape returned no listings")
+		}
+		for i, l := range listings {
+			if l == nil {
+				t.Fatalf("listing %d is nil", i)
+			}
+			if l.URL == "" {
+				t.Errorf("listing %d has no URL", i)
+			}
+			if l.ScrapedAt.IsZero() {
+				t.Errorf("listing %s has no scrape time", l.URL)
+			}
+		}
+		returned = listings
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		sc := newScraper(t)
+		ch := make(chan []*models.RawListing)
+		sc.SetOutput(ch)
+		type result struct {
+			listings []*models.RawListing
+			err      error
+		}
+		done := make(chan result, 1)
+		go func() {
+			listings, err := sc.Scrape()
+			done <- result{listings, err}
+		}()
+
+		var streamed []*models.RawListing
+		var r result
+	collect:
+		for {
+			select {
+			case batch := <-ch:
+				if len(batch) == 0 {
+					t.Error("empty batch sent to the output")
+				}
+				streamed = append(streamed, batch...)
+			case r = <-done:
+				break collect
+			}
+		}
+		close(ch) // panics if Scrape closed the caller's channel
+
+		if r.err != nil {
+			t.Fatalf("Scrape: %v", r.err)
+		}
+		if len(r.listings) != 0 {
+			t.Errorf("Scrape returned %d listings with an output set, want 0", len(r.listings))
+		}
+		if len(streamed) == 0 {
+			t.Fatal("no listings streamed")
+		}
+		if returned != nil && len(streamed) != len(returned) {
+			t.Errorf("streamed %d listings, returned %d without an output", len(streamed), len(returned))
+		}
+	})
+}
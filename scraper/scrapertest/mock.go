@@ -0,0 +1,56 @@
+// Package scrapertest provides a scraper.Scraper that replays fixed
+// batches, and the contract every Scraper implementation must satisfy.
+package scrapertest
+
+import (
+	"sync/atomic"
+
+	"airbnb-scraper/models"
+)
+
+// Mock replays its batches as one scrape would produce them. Err, when set,
+// is returned after the batches are delivered, like a scrape that failed
+// part-way. Mock is safe to share between the goroutines of a scrape but
+// replays the same listings on every call; use one Mock per scrape when
+// the consumer modifies them.
+type Mock struct {
+	Batches [][]*models.RawListing
+	Err     error
+
+	out   chan<- []*models.RawListing
+	calls atomic.Int32
+}
+
+// NewMock returns a Mock delivering the given batches in order.
+func NewMock(batches ...[]*models.RawListing) *Mock {
+	return &Mock{Batches: batches}
+}
+
+// SetOutput streams batches to ch, as airbnb.Scraper does.
+func (m *Mock) SetOutput(ch chan<- []*models.RawListing) {
+	m.out = ch
+}
+
+// Scrape delivers the batches to the output, or returns them all when no
+// output is set, then returns Err.
+func (m *Mock) Scrape() ([]*models.RawListing, error) {
+	m.calls.Add(1)
+	if m.out != nil {
+		for _, b := range m.Batches {
+			if len(b) > 0 {
+				m.out <- b
+			}
+		}
+		return nil, m.Err
+	}
+	var all []*models.RawListing
+	for _, b := range m.Batches {
+		all = append(all, b...)
+	}
+	return all, m.Err
+}
+
+// Calls is how many times Scrape ran.
+func (m *Mock) Calls() int {
+	return int(m.calls.Load())
+}
@@ -0,0 +1,48 @@
+package scrapertest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"airbnb-scraper/demo"
+	"airbnb-scraper/models"
+	"airbnb-scraper/scraper"
+)
+
+func demoBatches(t *testing.T) [][]*models.RawListing {
+	t.Helper()
+	raw, err := demo.Listings(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return [][]*models.RawListing{raw[:len(raw)/2], raw[len(raw)/2:]}
+}
+
+func TestMockContract(t *testing.T) {
+	Contract(t, func(t *testing.T) scraper.Scraper {
+		return NewMock(demoBatches(t)...)
+	})
+}
+
+func TestMockErr(t *testing.T) {
+	batches := demoBatches(t)
+	want := errors.New("blocked")
+	m := &Mock{Batches: batches, Err: want}
+	ch := make(chan []*models.RawListing, len(batches))
+	m.SetOutput(ch)
+	if _, err := m.Scrape(); err != want {
+		t.Errorf("Scrape err = %v, want %v", err, want)
+	}
+	close(ch)
+	n := 0
+	for b := range ch {
+		n += len(b)
+	}
+	if want := len(batches[0]) + len(batches[1]); n != want {
+		t.Errorf("streamed %d listings before the error, want %d", n, want)
+	}
+	if m.Calls() != 1 {
+		t.Errorf("Calls = %d, want 1", m.Calls())
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// AnomalyThresholds decide when a run looks like parsing breakage rather
+// than market movement.
+type AnomalyThresholds struct {
+	MaxPriceChange      float64 // e.g. 0.4 flags a >40% move in a location's average price
+	MaxCountDrop        float64 // e.g. 0.5 flags a >50% fall in listings (overall or per location)
+	MinLocationListings int     // locations smaller than this in either run are ignored
+}
+
+type AnomalyDetector struct {
+	thresholds AnomalyThresholds
+	logger     *utils.Logger
+}
+
+func NewAnomalyDetector(thresholds AnomalyThresholds, logger *utils.Logger) *AnomalyDetector {
+	return &AnomalyDetector{thresholds: thresholds, logger: logger}
+}
+
+// Compare returns human-readable anomalies found between the previous and
+// current run. An empty result means the run looks healthy.
+func (d *AnomalyDetector) Compare(prev, curr *models.RunSnapshot) []string {
+	if prev == nil || curr == nil {
+		return nil
+	}
+	t := d.thresholds
+	var out []string
+
+	if prev.ListingCount > 0 && t.MaxCountDrop > 0 {
+		drop := 1 - float64(curr.ListingCount)/float64(prev.ListingCount)
+		if drop > t.MaxCountDrop {
+			out = append(out, fmt.Sprintf("listing count collapsed %d → %d (-%.0f%%)",
+				prev.ListingCount, curr.ListingCount, drop*100))
+		}
+	}
+
+	locs := make([]string, 0, len(prev.Locations))
+	for loc := range prev.Locations {
+		locs = append(locs, loc)
+	}
+	sort.Strings(locs)
+
+	for _, loc := range locs {
+		p := prev.Locations[loc]
+		if p.Count < t.MinLocationListings {
+			continue
+		}
+		c, ok := curr.Locations[loc]
+		if t.MaxCountDrop > 0 {
+			drop := 1 - float64(c.Count)/float64(p.Count)
+			if drop > t.MaxCountDrop {
+				out = append(out, fmt.Sprintf("%s: listings fell %d → %d", loc, p.Count, c.Count))
+				continue
+			}
+		}
+		if !ok || c.Count < t.MinLocationListings || p.AveragePrice <= 0 || t.MaxPriceChange <= 0 {
+			continue
+		}
+		change := (c.AveragePrice - p.AveragePrice) / p.AveragePrice
+		if math.Abs(change) > t.MaxPriceChange {
+			out = append(out, fmt.Sprintf("%s: average price $%.2f → $%.2f (%+.0f%%)",
+				loc, p.AveragePrice, c.AveragePrice, change*100))
+		}
+	}
+
+	for _, a := range out {
+		d.logger.Warn("[anomaly] Run #%d vs #%d: %s", curr.RunID, prev.RunID, a)
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+func TestAnomalyDetectorFlagsPriceJumpAndCollapse(t *testing.T) {
+	d := NewAnomalyDetector(AnomalyThresholds{
+		MaxPriceChange: 0.4, MaxCountDrop: 0.5, MinLocationListings: 3,
+	}, utils.NewLogger())
+
+	prev := &models.RunSnapshot{RunID: 1, ListingCount: 100, Locations: map[string]models.LocationSnapshot{
+		"Bangkok": {Count: 20, AveragePrice: 100},
+		"Tokyo":   {Count: 10, AveragePrice: 150},
+		"Bali":    {Count: 8, AveragePrice: 90},
+		"Tiny":    {Count: 1, AveragePrice: 50},
+	}}
+	curr := &models.RunSnapshot{RunID: 2, ListingCount: 95, Locations: map[string]models.LocationSnapshot{
+		"Bangkok": {Count: 19, AveragePrice: 150}, // +50%
+		"Tokyo":   {Count: 10, AveragePrice: 160}, // +7%, fine
+		"Bali":    {Count: 2, AveragePrice: 90},   // collapsed
+		"Tiny":    {Count: 1, AveragePrice: 500},  // ignored, too small
+	}}
+
+	got := d.Compare(prev, curr)
+	if len(got) != 2 {
+		t.Fatalf("anomalies: got %d (%v), want 2", len(got), got)
+	}
+	if got[0] != "Bali: listings fell 8 → 2" {
+		t.Errorf("first anomaly: %q", got[0])
+	}
+	if got[1] != "Bangkok: average price $100.00 → $150.00 (+50%)" {
+		t.Errorf("second anomaly: %q", got[1])
+	}
+}
+
+func TestAnomalyDetectorOverallCollapse(t *testing.T) {
+	d := NewAnomalyDetector(AnomalyThresholds{MaxPriceChange: 0.4, MaxCountDrop: 0.5}, utils.NewLogger())
+	got := d.Compare(
+		&models.RunSnapshot{RunID: 1, ListingCount: 100},
+		&models.RunSnapshot{RunID: 2, ListingCount: 10},
+	)
+	if len(got) != 1 {
+		t.Errorf("expected overall collapse anomaly, got %v", got)
+	}
+	if d.Compare(nil, &models.RunSnapshot{}) != nil {
+		t.Error("first run should never be anomalous")
+	}
+}
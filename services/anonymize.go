@@ -0,0 +1,114 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"regexp"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// anonymizeCellMetres is the side of the grid coordinates are snapped to.
+const anonymizeCellMetres = 500
+
+// hostPhraseRegexp matches the phrases titles and descriptions use to name
+// the host, e.g. "Hosted by Maria" or "Your host, Ken Tanaka". The phrase
+// is case-insensitive; the name must be capitalised.
+var hostPhraseRegexp = regexp.MustCompile(`\b((?i:hosted by|your hosts?,?|co-?hosts?,?|i['’]?m|my name is))\s+(\p{Lu}[\p{L}'’-]*(?:\s+(?:&|and)\s+\p{Lu}[\p{L}'’-]*|\s+\p{Lu}[\p{L}'’-]*)*)`)
+
+// Anonymizer prepares listings for public datasets: host names are
+// redacted from text and dropped from the host record, whose user ID is
+// hashed like listing references, coordinates are snapped to a ~500 m grid, URLs and
+// photo URLs are dropped, analyst notes are cleared and listing references are replaced
+// by salted hashes. The same salt yields the same hashes, so separate
+// releases can be joined; a random salt makes each release unlinkable.
+type Anonymizer struct {
+	salt []byte
+}
+
+// NewAnonymizer uses salt for ID hashing; an empty salt draws a random one.
+func NewAnonymizer(salt string) *Anonymizer {
+	if salt == "" {
+		b := make([]byte, 32)
+		_, _ = rand.Read(b)
+		return &Anonymizer{salt: b}
+	}
+	return &Anonymizer{salt: []byte(salt)}
+}
+
+// Apply returns anonymized copies of listings; the originals are untouched.
+func (a *Anonymizer) Apply(listings []*models.Listing) []*models.Listing {
+	out := make([]*models.Listing, len(listings))
+	for i, l := range listings {
+		c := *l
+		c.ID = 0
+		c.ShortID = a.hashID(l)
+		c.URL = ""
+		c.Images = nil
+		c.Title = redactHosts(l.Title)
+		c.Description = redactHosts(l.Description)
+		c.OriginalDescription = redactHosts(l.OriginalDescription)
+		c.Note = ""
+		if l.Host != nil {
+			h := *l.Host
+			h.Name = ""
+			if h.ID != "" {
+				h.ID = a.hash("host:" + h.ID)
+			}
+			c.Host = &h
+		}
+		c.Latitude, c.Longitude = snapToGrid(l.Latitude, l.Longitude, anonymizeCellMetres)
+		out[i] = &c
+	}
+	return out
+}
+
+// hashID derives a 16-hex-digit reference from the platform and room ID.
+// Short IDs are unsalted hashes of enumerable room IDs, so they would not
+// hide the listing.
+func (a *Anonymizer) hashID(l *models.Listing) string {
+	ref := utils.ListingID(l.URL)
+	if ref == "" {
+		ref = l.URL
+	}
+	return a.hash(l.Platform + ":" + ref)
+}
+
+// hash is the salted 16-hex-digit hash of s.
+func (a *Anonymizer) hash(s string) string {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(s))
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// redactHosts replaces host names introduced by a host phrase.
+func redactHosts(s string) string {
+	return hostPhraseRegexp.ReplaceAllStringFunc(s, func(m string) string {
+		parts := hostPhraseRegexp.FindStringSubmatch(m)
+		return parts[1] + " [host]"
+	})
+}
+
+// snapToGrid moves a coordinate to the centre of its cell on a grid of
+// cellMetres squares. Longitude cells widen towards the poles so they stay
+// roughly square. Unknown (0, 0) coordinates are kept as they are.
+func snapToGrid(lat, lng, cellMetres float64) (float64, float64) {
+	if lat == 0 && lng == 0 {
+		return 0, 0
+	}
+	const metresPerDegree = 111_320.0
+	latStep := cellMetres / metresPerDegree
+	snappedLat := (math.Floor(lat/latStep) + 0.5) * latStep
+
+	lngStep := latStep / math.Max(math.Cos(snappedLat*math.Pi/180), 0.01)
+	snappedLng := (math.Floor(lng/lngStep) + 0.5) * lngStep
+	return round6(snappedLat), round6(snappedLng)
+}
+
+func round6(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"airbnb-scraper/models"
+)
+
+func TestAnonymizerApply(t *testing.T) {
+	orig := &models.Listing{
+		ID: 7, ShortID: "7K2M-Q9XD", Platform: "airbnb",
+		URL:         "https://www.airbnb.com/rooms/4242",
+		Title:       "Loft hosted by Maria Lopez",
+		Description: "Hi, I'm Ken and my co-host Aiko will greet you. I'm happy to help.",
+		Latitude:    13.756331, Longitude: 100.501762, Price: 80,
+
+		OriginalDescription: "Hola, I'm Ken.",
+		Tags:                []string{"shortlisted"},
+		Note:                "Called Maria, she offers 10% off",
+		Host:                &models.Host{ID: "4242", Name: "Maria", Superhost: true, YearsHosting: 6},
+		Images:              []string{"https://a0.muscache.com/im/pictures/4242-1.jpg"},
+	}
+	a := NewAnonymizer("secret")
+	got := a.Apply([]*models.Listing{orig})[0]
+
+	if got.URL != "" || got.ID != 0 || got.Note != "" || got.Images != nil {
+		t.Errorf("URL/ID/note/images kept: %q %d %q %q", got.URL, got.ID, got.Note, got.Images)
+	}
+	if got.ShortID == orig.ShortID || len(got.ShortID) != 16 {
+		t.Errorf("ShortID = %q, want a 16-digit hash", got.ShortID)
+	}
+	if again := NewAnonymizer("secret").Apply([]*models.Listing{orig})[0]; again.ShortID != got.ShortID {
+		t.Error("same salt gave different IDs")
+	}
+	if other := NewAnonymizer("other").Apply([]*models.Listing{orig})[0]; other.ShortID == got.ShortID {
+		t.Error("different salts gave the same ID")
+	}
+
+	for _, name := range []string{"Maria", "Lopez", "Ken", "Aiko"} {
+		if strings.Contains(got.Title+got.Description+got.OriginalDescription, name) {
+			t.Errorf("host name %q not redacted: %q / %q", name, got.Title, got.Description)
+		}
+	}
+	if !strings.Contains(got.Description, "I'm happy to help") {
+		t.Errorf("ordinary text redacted: %q", got.Description)
+	}
+
+	// Moved by less than one grid cell, but not left in place.
+	dLat := math.Abs(got.Latitude-orig.Latitude) * 111_320
+	dLng := math.Abs(got.Longitude-orig.Longitude) * 111_320 * math.Cos(orig.Latitude*math.Pi/180)
+	if dLat > 500 || dLng > 500 || (dLat == 0 && dLng == 0) {
+		t.Errorf("coordinates moved %.0f m / %.0f m", dLat, dLng)
+	}
+
+	if orig.URL == "" || orig.Title != "Loft hosted by Maria Lopez" {
+		t.Error("original listing was modified")
+	}
+	if h := got.Host; h == nil || h.Name != "" || h.ID == "4242" || len(h.ID) != 16 || !h.Superhost || h.YearsHosting != 6 {
+		t.Errorf("Host = %+v, want the name dropped, the ID hashed and the stats kept", got.Host)
+	}
+	if orig.Host.Name != "Maria" {
+		t.Error("original host was modified")
+	}
+	if got.Price != 80 {
+		t.Errorf("Price = %v, want untouched", got.Price)
+	}
+}
+
+func TestSnapToGridSharesCells(t *testing.T) {
+	// Two points ~50 m apart in the middle of a cell land on the same centre.
+	lat1, lng1 := snapToGrid(13.7521, 100.5021, 500)
+	lat2, lng2 := snapToGrid(13.7525, 100.5024, 500)
+	if lat1 != lat2 || lng1 != lng2 {
+		t.Errorf("(%v,%v) != (%v,%v)", lat1, lng1, lat2, lng2)
+	}
+	if lat, lng := snapToGrid(0, 0, 500); lat != 0 || lng != 0 {
+		t.Errorf("unknown coordinates moved to %v,%v", lat, lng)
+	}
+}
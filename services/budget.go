@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// ValueScore rates how much quality a listing offers for its price, as
+// rating points per $100 per night. Listings without a price or rating
+// score 0.
+func ValueScore(l *models.Listing) float64 {
+	if l.Price <= 0 || l.Rating <= 0 {
+		return 0
+	}
+	return round2(l.Rating / l.Price * 100)
+}
+
+// Shortlist returns listings priced at or under maxPrice per night and rated
+// at least minRating, best value first. Listings with unknown price or
+// rating never match.
+func (s *InsightService) Shortlist(listings []*models.Listing, maxPrice, minRating float64) []*models.ShortlistEntry {
+	var out []*models.ShortlistEntry
+	for _, l := range listings {
+		if l.Price <= 0 || l.Price > maxPrice {
+			continue
+		}
+		if l.Rating <= 0 || l.Rating < minRating {
+			continue
+		}
+		out = append(out, &models.ShortlistEntry{Listing: l, ValueScore: ValueScore(l)})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].ValueScore != out[j].ValueScore {
+			return out[i].ValueScore > out[j].ValueScore
+		}
+		return out[i].Listing.Price < out[j].Listing.Price
+	})
+	for i, e := range out {
+		e.Rank = i + 1
+	}
+
+	s.logger.Info("[insights] Budget $%.2f, rating ≥ %.2f → %d of %d listings match",
+		maxPrice, minRating, len(out), len(listings))
+	return out
+}
+
+// PrintShortlist renders the budget-finder result as a terminal table.
+func (s *InsightService) PrintShortlist(entries []*models.ShortlistEntry) {
+	thin := strings.Repeat("─", 78)
+
+	utils.Printf("\n\033[1;33m  Budget Shortlist (best value first)\033[0m\n")
+	utils.Printf("  %s\n", thin)
+	if len(entries) == 0 {
+		utils.Printf("  No listings match the budget and rating\n\n")
+		return
+	}
+	utils.Printf("  %-3s %-9s %-26s %-16s %8s %6s %6s\n", "#", "ID", "Title", "Location", "Price", "Rating", "Value")
+	for i, e := range entries {
+		l := e.Listing
+		utils.Printf("  %-3d %-9s %-26s %-16s %8s %6.2f %6.2f\n",
+			i+1, l.ShortID, truncate(l.Title, 26), truncate(l.Location, 16),
+			s.money.Format(l.Price), l.Rating, e.ValueScore)
+	}
+	fmt.Println()
+}
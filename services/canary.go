@@ -0,0 +1,55 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// CanaryChecker validates known-good listings scraped at the start of a run.
+// Canaries that come back without a title or with an implausible price mean
+// extraction is broken, and the run should stop before it stores garbage.
+type CanaryChecker struct {
+	minPrice float64
+	maxPrice float64
+	cleaner  *Cleaner
+	logger   *utils.Logger
+}
+
+func NewCanaryChecker(minPrice, maxPrice float64, logger *utils.Logger) *CanaryChecker {
+	return &CanaryChecker{
+		minPrice: minPrice,
+		maxPrice: maxPrice,
+		cleaner:  NewCleaner(logger),
+		logger:   logger,
+	}
+}
+
+// Check returns one failure message per canary that missed its expectations.
+func (c *CanaryChecker) Check(canaries []*models.RawListing) []string {
+	var failures []string
+	for _, r := range canaries {
+		var problems []string
+		if t := normaliseText(r.Title); t == "" || t == "Property" {
+			problems = append(problems, "title missing")
+		}
+		price := c.cleaner.parsePrice(r.RawPrice)
+		switch {
+		case price <= 0:
+			problems = append(problems, "price missing")
+		case price < c.minPrice || (c.maxPrice > 0 && price > c.maxPrice):
+			problems = append(problems, fmt.Sprintf("price $%.2f outside $%.0f–$%.0f", price, c.minPrice, c.maxPrice))
+		}
+
+		if len(problems) == 0 {
+			c.logger.Info("[canary] ✓ %s", r.URL)
+			continue
+		}
+		msg := fmt.Sprintf("%s: %s", r.URL, strings.Join(problems, ", "))
+		c.logger.Warn("[canary] ✗ %s", msg)
+		failures = append(failures, msg)
+	}
+	return failures
+}
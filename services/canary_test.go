@@ -0,0 +1,29 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"airbnb-scraper/models"
+)
+
+func TestCanaryCheckerFlagsBrokenFields(t *testing.T) {
+	c := NewCanaryChecker(10, 2000, newTestLogger())
+
+	failures := c.Check([]*models.RawListing{
+		{URL: "ok", Title: "Loft in Lisbon", RawPrice: "$120 night"},
+		{URL: "no-title", Title: "", RawPrice: "$120 night"},
+		{URL: "no-price", Title: "Loft", RawPrice: ""},
+		{URL: "too-cheap", Title: "Loft", RawPrice: "$3 night"},
+		{URL: "too-dear", Title: "Loft", RawPrice: "$5,000 night"},
+	})
+
+	if len(failures) != 4 {
+		t.Fatalf("failures: got %d (%v), want 4", len(failures), failures)
+	}
+	for i, prefix := range []string{"no-title:", "no-price:", "too-cheap:", "too-dear:"} {
+		if !strings.HasPrefix(failures[i], prefix) {
+			t.Errorf("failure %d = %q, want prefix %q", i, failures[i], prefix)
+		}
+	}
+}
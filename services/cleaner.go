@@ -3,8 +3,10 @@ package services
 import (
 	"math"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
@@ -12,33 +14,134 @@ import (
 	"airbnb-scraper/utils"
 )
 
+// A price is an amount with a currency symbol or ISO code before it
+// ("$122", "฿3,500", "USD 99", "NZ$80") or after it ("1.234,56 €",
+// "3 500 ₽", "99 EUR"). The amount may group thousands with commas, dots
+// or (no-break) spaces and end in a one- or two-digit decimal part.
+const (
+	currencyCodes  = `USD|EUR|GBP|THB|JPY|INR|AUD|CAD|CHF|CNY|HKD|SGD|NZD|MXN|BRL|KRW|IDR|MYR|PHP|VND|ZAR|SEK|NOK|DKK|PLN|CZK|HUF|TRY|AED|ILS|RUB|TWD|ARS|CLP|COP|PEN`
+	currencyBefore = `(?:[A-Z]{1,2}\$|[$€£¥₹฿₩₫₱₪₺₽]|\b(?:` + currencyCodes + `)\b)`
+	currencyAfter  = `(?:[€£¥₹฿₩₫₱₪₺₽]|zł|Kč|\b(?:kr|` + currencyCodes + `)\b)`
+	amountPattern  = `(\d{1,3}(?:[,.\x{00a0}\x{202f} ]\d{3})+(?:[.,]\d{1,2})?|\d+(?:[.,]\d{1,2})?)`
+	pricePattern   = `(?:` + currencyBefore + `\s*` + amountPattern + `|` + amountPattern + `\s*` + currencyAfter + `)`
+)
+
 var (
-	// Matches "$122", "$1,200", "$122.50"
-	priceRegexp = regexp.MustCompile(`\$\s*(\d+(?:,\d{3})*(?:\.\d{2})?)`)
+	// Matches "$122", "$1,200", "$122.50", "฿3,500", "1.234,56 €"
+	priceRegexp = regexp.MustCompile(pricePattern)
 
 	// Matches "X night" or "X nights" for multi-night total price
 	nightsRegexp = regexp.MustCompile(`(\d+)\s*nights?`)
 
 	// Per-night price patterns: "$122 / night", "$122/night", "$122 per night", "$122 night"
-	perNightRegexp = regexp.MustCompile(`\$\s*(\d+(?:,\d{3})*(?:\.\d{2})?)\s*(?:/\s*night|per\s+night|\bnight\b)`)
+	perNightRegexp = regexp.MustCompile(pricePattern + `\s*(?:/\s*night|per\s+night|\bnight\b)`)
 
 	// "X nights in Location" total pricing block — e.g. "$244 for 2 nights"
-	totalForNightsRegexp = regexp.MustCompile(`\$\s*(\d+(?:,\d{3})*(?:\.\d{2})?)\s+for\s+(\d+)\s*nights?`)
+	totalForNightsRegexp = regexp.MustCompile(pricePattern + `\s+for\s+(\d+)\s*nights?`)
 
 	ratingRegexp = regexp.MustCompile(`\b([0-5](?:\.\d{1,2})?)\b`)
+	// "New", "★ New" or "New · No reviews yet": listed too recently to rate.
+	newRatingRegexp = regexp.MustCompile(`(?i)^[^\pL\d]*new\b`)
+
+	// "2 nights in Lisbon" — location fallback in raw page text
+	nightsInRegexp = regexp.MustCompile(`\d+\s*nights?\s+in\s+([^\n$\d]{3,60})`)
+)
+
+// cleanChunkSize is how many rows one worker transforms per job.
+const cleanChunkSize = 500
+
+// Reasons strict mode drops a listing, the keys of Cleaner.Rejected.
+const (
+	RejectNoPrice    = "no_price"
+	RejectNoLocation = "no_location"
 )
 
 type Cleaner struct {
-	logger *utils.Logger
+	logger  *utils.Logger
+	workers int
+	strict  bool
+
+	mu       sync.Mutex
+	rejected map[string]int
 }
 
 func NewCleaner(logger *utils.Logger) *Cleaner {
-	return &Cleaner{logger: logger}
+	return &Cleaner{logger: logger, workers: 1, rejected: make(map[string]int)}
+}
+
+// SetStrict makes the cleaner drop listings whose price or location did
+// not parse instead of passing them on with 0 or "", which drag the
+// minimum and the averages down. Removed listings are kept either way:
+// they are stored bare on purpose.
+func (c *Cleaner) SetStrict(on bool) {
+	c.strict = on
+}
+
+// Rejected returns how many listings strict mode has dropped so far, by
+// reason (RejectNoPrice, RejectNoLocation).
+func (c *Cleaner) Rejected() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]int, len(c.rejected))
+	for reason, n := range c.rejected {
+		out[reason] = n
+	}
+	return out
 }
 
+// SetWorkers sets how many goroutines transform rows in parallel. Values
+// below 1 use one worker per CPU.
+func (c *Cleaner) SetWorkers(n int) {
+	if n < 1 {
+		n = runtime.NumCPU()
+	}
+	c.workers = n
+}
+
+// Clean deduplicates raw listings by URL and transforms the survivors into
+// cleaned listings. Dedup runs sequentially so the first occurrence always
+// wins; the per-row transformations then run across the worker pool and the
+// result keeps the input order regardless of worker count.
 func (c *Cleaner) Clean(raw []*models.RawListing) []*models.Listing {
+	result := c.cleanBatch(raw, make(map[string]struct{}))
+	c.logger.Info("[cleaner] Cleaned %d → %d listings (dropped %d)",
+		len(raw), len(result), len(raw)-len(result))
+	c.logRejected()
+	return result
+}
+
+// CleanStream cleans batches from in and sends the results to out until in is
+// closed, then closes out. Dedup spans the whole stream, so a URL seen in an
+// earlier batch is dropped from later ones.
+func (c *Cleaner) CleanStream(in <-chan []*models.RawListing, out chan<- []*models.Listing) {
+	defer close(out)
 	seen := make(map[string]struct{})
-	result := make([]*models.Listing, 0, len(raw))
+	var rawTotal, cleanTotal int
+	for batch := range in {
+		result := c.cleanBatch(batch, seen)
+		rawTotal += len(batch)
+		cleanTotal += len(result)
+		if len(result) > 0 {
+			out <- result
+		}
+	}
+	c.logger.Info("[cleaner] Cleaned %d → %d listings (dropped %d)",
+		rawTotal, cleanTotal, rawTotal-cleanTotal)
+	c.logRejected()
+}
+
+// logRejected reports strict mode's drops, if any.
+func (c *Cleaner) logRejected() {
+	if r := c.Rejected(); len(r) > 0 {
+		c.logger.Info("[cleaner] Strict mode dropped %d listings without a price and %d without a location",
+			r[RejectNoPrice], r[RejectNoLocation])
+	}
+}
+
+// cleanBatch dedups raw against seen (updating it) and transforms the rest.
+func (c *Cleaner) cleanBatch(raw []*models.RawListing, seen map[string]struct{}) []*models.Listing {
+	kept := make([]*models.RawListing, 0, len(raw))
+	urls := make([]string, 0, len(raw))
 
 	for _, r := range raw {
 		url := strings.TrimSpace(r.URL)
@@ -52,34 +155,132 @@ func (c *Cleaner) Clean(raw []*models.RawListing) []*models.Listing {
 			continue
 		}
 		seen[url] = struct{}{}
+		kept = append(kept, r)
+		urls = append(urls, url)
+	}
 
-		listing := &models.Listing{
-			Platform:    normalisePlatform(r.Platform),
-			Title:       normaliseText(r.Title),
-			Price:       c.parsePrice(r.RawPrice),
-			Location:    c.parseLocation(r.Location, r.RawPrice),
-			Rating:      c.parseRating(r.Rating),
-			URL:         url,
-			Description: normaliseText(r.Description),
-			CreatedAt:   time.Now(),
+	result := make([]*models.Listing, len(kept))
+	if c.workers <= 1 || len(kept) <= cleanChunkSize {
+		for i, r := range kept {
+			result[i] = c.cleanOne(r, urls[i])
+		}
+	} else {
+		pool := utils.NewWorkerPool(c.workers, 0)
+		for start := 0; start < len(kept); start += cleanChunkSize {
+			start, end := start, start+cleanChunkSize
+			if end > len(kept) {
+				end = len(kept)
+			}
+			pool.Submit(func() {
+				for i := start; i < end; i++ {
+					result[i] = c.cleanOne(kept[i], urls[i])
+				}
+			})
 		}
+		pool.Wait()
+	}
+	if c.strict {
+		result = c.dropUnparsed(result)
+	}
+	return result
+}
 
-		result = append(result, listing)
+// dropUnparsed removes the listings strict mode rejects and counts them.
+func (c *Cleaner) dropUnparsed(listings []*models.Listing) []*models.Listing {
+	kept := listings[:0]
+	for _, l := range listings {
+		var reason string
+		switch {
+		case l.Status == models.ListingStatusRemoved:
+		case !l.HasPrice():
+			reason = RejectNoPrice
+		case l.Location == "" || strings.EqualFold(l.Location, "Unknown"):
+			reason = RejectNoLocation
+		}
+		if reason == "" {
+			kept = append(kept, l)
+			continue
+		}
+		c.logger.Debug("[cleaner] Strict mode: dropping %s (%s)", l.URL, reason)
+		c.mu.Lock()
+		c.rejected[reason]++
+		c.mu.Unlock()
 	}
+	return kept
+}
 
-	c.logger.Info("[cleaner] Cleaned %d → %d listings (dropped %d)",
-		len(raw), len(result), len(raw)-len(result))
-	return result
+// cleanOne transforms a single deduplicated raw listing. It must not touch
+// shared state so it can run concurrently.
+func (c *Cleaner) cleanOne(r *models.RawListing, url string) *models.Listing {
+	if r.Status == models.ListingStatusRemoved {
+		return removedListing(r, url)
+	}
+	listing := &models.Listing{
+		Platform:    normalisePlatform(r.Platform),
+		Title:       normaliseText(r.Title),
+		Location:    c.parseLocation(r.Location, r.RawPrice),
+		ReviewCount: parseCount(r.ReviewCount),
+		URL:         url,
+		Description: normaliseText(r.Description),
+		TargetCity:  normaliseText(r.TargetCity),
+		Status:      models.ListingStatusActive,
+		CreatedAt:   time.Now(),
+	}
+
+	listing.ShortID = utils.ShortID(listing.Platform, url)
+	listing.Rating, listing.IsNewListing = c.parseRatingNew(r.Rating)
+	listing.OriginalDescription = normaliseText(r.OriginalDescription)
+	listing.Amenities = normaliseAmenities(r.Amenities)
+	listing.Images = normaliseImages(r.Images)
+	if r.Host != nil {
+		host := *r.Host
+		host.Name = normaliseText(host.Name)
+		listing.Host = &host
+	}
+	listing.Latitude, listing.Longitude = parseCoordinates(r.Latitude, r.Longitude)
+	listing.Price, listing.PriceKnown = c.parsePriceKnown(r.RawPrice)
+	listing.PriceConfidence = r.Provenance.Confidence("price")
+	listing.RatingConfidence = r.Provenance.Confidence("rating")
+	listing.LocationConfidence = r.Provenance.Confidence("location")
+	return listing
+}
+
+// removedListing keeps only the identity of a delisted room: whatever the
+// card showed is stale, and storing it would pollute prices and ratings.
+func removedListing(r *models.RawListing, url string) *models.Listing {
+	platform := normalisePlatform(r.Platform)
+	return &models.Listing{
+		ShortID:    utils.ShortID(platform, url),
+		Platform:   platform,
+		Title:      normaliseText(r.Title),
+		URL:        url,
+		TargetCity: normaliseText(r.TargetCity),
+		Status:     models.ListingStatusRemoved,
+		CreatedAt:  time.Now(),
+	}
 }
 
 // parsePrice handles the structured price strings produced by the scraper:
 //   "$66 for 2 nights"  → 66/2 = $33/night
 //   "$73 per night"     → $73/night
 //   "$45 for 1 night"   → $45/night
-// Falls back to regex extraction for any other format.
+// Falls back to regex extraction for any other format. 0 means unknown
+// or free; parsePriceKnown tells them apart.
 func (c *Cleaner) parsePrice(raw string) float64 {
+	price, _ := c.parsePriceKnown(raw)
+	return price
+}
+
+// parsePriceKnown is parsePrice reporting whether raw held a price at all:
+// "$0 per night", "$0 for 3 nights" and "Free" are known prices of 0, "N/A"
+// is unknown. A zero elsewhere in the text, or an amount that does not
+// parse, never counts as a known 0.
+func (c *Cleaner) parsePriceKnown(raw string) (float64, bool) {
 	if raw == "" || raw == "N/A" {
-		return 0
+		return 0, false
+	}
+	if strings.EqualFold(strings.TrimSpace(raw), "free") {
+		return 0, true
 	}
 
 	preview := raw
@@ -89,38 +290,38 @@ func (c *Cleaner) parsePrice(raw string) float64 {
 	c.logger.Debug("[cleaner] parsePrice input: %q", preview)
 
 	// Strategy 1: "$X for N nights" — divide total by nights
-	if m := totalForNightsRegexp.FindStringSubmatch(raw); len(m) > 2 {
-		total := parseDollarAmount(m[1])
-		nights, _ := strconv.Atoi(m[2])
-		if total > 0 && nights > 0 {
+	if m := totalForNightsRegexp.FindStringSubmatch(raw); len(m) > 3 {
+		total := parseAmount(matchedAmount(m))
+		nights, _ := strconv.Atoi(m[3])
+		if (total > 0 || isZeroAmount(matchedAmount(m))) && nights > 0 {
 			perNight := math.Round((total/float64(nights))*100) / 100
 			c.logger.Debug("[cleaner] $%.2f / %d nights = $%.2f/night", total, nights, perNight)
-			return perNight
+			return perNight, true
 		}
 	}
 
 	// Strategy 2: explicit per-night label
-	if m := perNightRegexp.FindStringSubmatch(raw); len(m) > 1 {
-		val := parseDollarAmount(m[1])
-		if val > 0 {
+	if m := perNightRegexp.FindStringSubmatch(raw); len(m) > 2 {
+		val := parseAmount(matchedAmount(m))
+		if val > 0 || isZeroAmount(matchedAmount(m)) {
 			c.logger.Debug("[cleaner] Per-night: $%.2f", val)
-			return val
+			return val, true
 		}
 	}
 
-	// Strategy 3: first dollar amount on the line (last resort)
+	// Strategy 3: first price on the line under its currency's cap (last resort)
 	matches := priceRegexp.FindAllStringSubmatch(raw, -1)
 	for _, m := range matches {
-		if len(m) > 1 {
-			val := parseDollarAmount(m[1])
-			if val > 0 && val < 10000 {
+		if len(m) > 2 {
+			val := parseAmount(matchedAmount(m))
+			if val > 0 && val < fallbackCap(m[0]) {
 				c.logger.Debug("[cleaner] Fallback price: $%.2f", val)
-				return val
+				return val, true
 			}
 		}
 	}
 
-	return 0
+	return 0, false
 }
 
 // parseLocation uses the pre-set section location if it's meaningful,
@@ -171,8 +372,7 @@ func (c *Cleaner) parseLocation(location, rawPageText string) string {
 
 	// Fallback: extract from page body
 	if rawPageText != "" {
-		re := regexp.MustCompile(`\d+\s*nights?\s+in\s+([^\n$\d]{3,60})`)
-		if m := re.FindStringSubmatch(rawPageText); len(m) > 1 {
+		if m := nightsInRegexp.FindStringSubmatch(rawPageText); len(m) > 1 {
 			extracted := strings.TrimSpace(m[1])
 			if extracted != "" && !isJunk(extracted) {
 				return normaliseText(extracted)
@@ -184,31 +384,134 @@ func (c *Cleaner) parseLocation(location, rawPageText string) string {
 }
 
 func (c *Cleaner) parseRating(raw string) float64 {
+	rating, _ := c.parseRatingNew(raw)
+	return rating
+}
+
+// parseRatingNew is parseRating that also reports a "New" badge in place of
+// a rating; the rating is 0 then.
+func (c *Cleaner) parseRatingNew(raw string) (float64, bool) {
+	if newRatingRegexp.MatchString(raw) {
+		return 0, true
+	}
 	match := ratingRegexp.FindStringSubmatch(raw)
 	if len(match) < 2 {
-		return 0
+		return 0, false
 	}
 	val, err := strconv.ParseFloat(match[1], 64)
 	if err != nil {
-		return 0
+		return 0, false
 	}
 	if val < 0 || val > 5 {
-		return 0
+		return 0, false
 	}
-	return val
+	return val, false
 }
 
 // ── Helpers ──────────────────────────────────────────────────────────────────
 
-func parseDollarAmount(s string) float64 {
-	s = strings.ReplaceAll(s, ",", "")
-	val, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+// fallbackCapUSD bounds the last-resort price match in dollars: a larger
+// amount is more likely a stay total or a stray number than a nightly rate.
+const fallbackCapUSD = 10000
+
+// currencyUnitsPerUSD scales the fallback cap for currencies whose amounts
+// run far larger than dollars. The rates are rough; only the order of
+// magnitude matters.
+var currencyUnitsPerUSD = []struct {
+	token string
+	units float64
+}{
+	{"JPY", 150}, {"¥", 150}, {"KRW", 1400}, {"₩", 1400}, {"IDR", 16000},
+	{"VND", 25000}, {"₫", 25000}, {"COP", 4000}, {"CLP", 950}, {"ARS", 900},
+	{"HUF", 360}, {"RUB", 90}, {"₽", 90}, {"INR", 83}, {"₹", 83},
+	{"PHP", 56}, {"₱", 56}, {"THB", 36}, {"฿", 36}, {"TWD", 32},
+	{"TRY", 32}, {"₺", 32}, {"CZK", 23}, {"Kč", 23}, {"MXN", 17},
+	{"ZAR", 18}, {"SEK", 10}, {"NOK", 10}, {"kr", 10}, {"DKK", 7},
+	{"CNY", 7},
+}
+
+// fallbackCap is the largest amount the last-resort strategy accepts for a
+// price match, in the match's currency.
+func fallbackCap(match string) float64 {
+	for _, c := range currencyUnitsPerUSD {
+		if strings.Contains(match, c.token) {
+			return fallbackCapUSD * c.units
+		}
+	}
+	return fallbackCapUSD
+}
+
+// matchedAmount is the amount of a pricePattern match: its first group
+// when the currency came first, else its second.
+func matchedAmount(m []string) string {
+	if m[1] != "" {
+		return m[1]
+	}
+	return m[2]
+}
+
+// parseAmount reads an amount such as "1,200.50", "1.200,50" or "3 500".
+// A final comma or dot followed by one or two digits is the decimal point;
+// every other separator groups thousands.
+func parseAmount(s string) float64 {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '\u202f' {
+			return -1
+		}
+		return r
+	}, s)
+	ungroup := strings.NewReplacer(",", "", ".", "")
+	if i := strings.LastIndexAny(s, ",."); i >= 0 && len(s)-i-1 <= 2 {
+		s = ungroup.Replace(s[:i]) + "." + s[i+1:]
+	} else {
+		s = ungroup.Replace(s)
+	}
+	val, err := strconv.ParseFloat(s, 64)
 	if err != nil {
 		return 0
 	}
 	return val
 }
 
+// isZeroAmount reports whether s is an amount written as zero, such as "0"
+// or "0.00", as opposed to one parseAmount could not read.
+func isZeroAmount(s string) bool {
+	s = strings.TrimSpace(s)
+	return s != "" && strings.Trim(s, "0.,") == "" && strings.Contains(s, "0")
+}
+
+// parseCount reads an integer such as "3,215" or "(42)"; junk yields 0.
+func parseCount(s string) int {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		if r == ',' || r == '(' || r == ')' || unicode.IsSpace(r) {
+			return -1
+		}
+		return 'x'
+	}, s)
+	n, err := strconv.Atoi(digits)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// parseCoordinates validates a latitude/longitude pair. Anything out of
+// range or unparseable yields (0, 0), meaning unknown.
+func parseCoordinates(latRaw, lngRaw string) (float64, float64) {
+	lat, err1 := strconv.ParseFloat(strings.TrimSpace(latRaw), 64)
+	lng, err2 := strconv.ParseFloat(strings.TrimSpace(lngRaw), 64)
+	if err1 != nil || err2 != nil {
+		return 0, 0
+	}
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 || (lat == 0 && lng == 0) {
+		return 0, 0
+	}
+	return lat, lng
+}
+
 func normaliseText(s string) string {
 	s = strings.TrimSpace(s)
 	if s == "" || s == "N/A" {
@@ -220,6 +523,35 @@ func normaliseText(s string) string {
 	return strings.Join(fields, " ")
 }
 
+// normaliseAmenities normalises each amenity's whitespace and drops empty
+// and repeated ones (ignoring case), keeping page order.
+func normaliseAmenities(raw []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(raw))
+	for _, a := range raw {
+		a = normaliseText(a)
+		if key := strings.ToLower(a); a != "" && !seen[key] {
+			seen[key] = true
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// normaliseImages trims each photo URL and drops empty and repeated ones,
+// keeping page order.
+func normaliseImages(raw []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(raw))
+	for _, u := range raw {
+		if u = strings.TrimSpace(u); u != "" && !seen[u] {
+			seen[u] = true
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
 func normalisePlatform(s string) string {
 	return strings.ToLower(strings.TrimSpace(s))
 }
\ No newline at end of file
@@ -1,6 +1,11 @@
 package services
 
 import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,6 +28,12 @@ func TestCleanerParsePrice(t *testing.T) {
 		{"free", 0},
 		{"$1,200.50", 1200.50},
 		{"USD 99", 99},
+		// The fallback cap scales with the currency.
+		{"$15,000", 0},
+		{"¥15,000", 15000},
+		{"₩120,000", 120000},
+		{"Rated 4.9 · IDR 1.250.000", 1250000},
+		{"3 500 000 ₫", 3500000},
 	}
 
 	for _, tt := range tests {
@@ -33,6 +44,97 @@ func TestCleanerParsePrice(t *testing.T) {
 	}
 }
 
+// priceCase is a price string and the per-night price it stands for.
+type priceCase struct {
+	raw  string
+	want float64
+}
+
+// genPrice builds a random price string the way listing cards and detail
+// pages phrase them — currency before or after, en/eu/space thousands
+// separators, per-night or multi-night totals, surrounding noise — with
+// the per-night price it stands for.
+func genPrice(rng *rand.Rand) priceCase {
+	cents := 100 + rng.Intn(999900) // $1.00 – $9,999.99, under the fallback cap
+	units, frac := cents/100, cents%100
+
+	seps := [][2]string{{",", "."}, {".", ","}, {" ", ","}, {"\u00a0", ","}, {"\u202f", "."}, {"", "."}}
+	sep := seps[rng.Intn(len(seps))]
+	digits := strconv.Itoa(units)
+	var grouped string
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped += sep[0]
+		}
+		grouped += string(d)
+	}
+	amount := float64(units)
+	switch {
+	case frac == 0 && rng.Intn(2) == 0:
+	case frac%10 == 0 && rng.Intn(2) == 0:
+		grouped += sep[1] + strconv.Itoa(frac/10)
+		amount += float64(frac) / 100
+	default:
+		grouped += fmt.Sprintf("%s%02d", sep[1], frac)
+		amount += float64(frac) / 100
+	}
+
+	before := []string{"$", "US$", "NZ$", "€", "£", "฿", "₹", "USD ", "EUR ", "THB "}
+	after := []string{" €", "€", " EUR", " kr", " ₽", " zł"}
+	price := before[rng.Intn(len(before))] + grouped
+	if rng.Intn(3) == 0 {
+		price = grouped + after[rng.Intn(len(after))]
+	}
+
+	want := amount
+	switch nights := rng.Intn(8); {
+	case nights == 0:
+	case nights == 1:
+		price += []string{" / night", "/night", " per night", " night"}[rng.Intn(4)]
+	default:
+		price += fmt.Sprintf(" for %d nights", nights)
+		want = math.Round(amount/float64(nights)*100) / 100
+	}
+
+	prefixes := []string{"", "Price: ", "Superhost · ", "Rated 4.92 · ", "Guest favourite\n"}
+	suffixes := []string{"", " total", " · Free cancellation", " (before taxes)", "\n4.85 (120)"}
+	return priceCase{prefixes[rng.Intn(len(prefixes))] + price + suffixes[rng.Intn(len(suffixes))], want}
+}
+
+func TestParsePriceProperties(t *testing.T) {
+	c := NewCleaner(newTestLogger())
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		tc := genPrice(rng)
+		if got := c.parsePrice(tc.raw); math.Abs(got-tc.want) > 0.005 {
+			t.Errorf("parsePrice(%q) = %.2f; want %.2f", tc.raw, got, tc.want)
+		}
+	}
+}
+
+// FuzzParsePrice checks what must hold for any input: the price is finite
+// and never negative, and surrounding whitespace does not change it.
+//
+//	go test ./services -fuzz FuzzParsePrice -fuzztime 30s
+func FuzzParsePrice(f *testing.F) {
+	for _, seed := range []string{
+		"$66 for 2 nights", "$73 per night", "฿3,500 /night", "USD 99", "1.234,56 € for 3 nights",
+		"3\u00a0500 ₽ night", "N/A", "", "Rated 4.92 · €120", "$0 for 0 nights", "99999999999999999999 EUR",
+	} {
+		f.Add(seed)
+	}
+	c := NewCleaner(newTestLogger())
+	f.Fuzz(func(t *testing.T, raw string) {
+		got := c.parsePrice(raw)
+		if math.IsNaN(got) || math.IsInf(got, 0) || got < 0 {
+			t.Fatalf("parsePrice(%q) = %v", raw, got)
+		}
+		if padded := c.parsePrice(" " + raw + "\n"); padded != got {
+			t.Fatalf("parsePrice(%q) = %v, but %v with surrounding whitespace", raw, got, padded)
+		}
+	})
+}
+
 func TestCleanerParseRating(t *testing.T) {
 	c := NewCleaner(newTestLogger())
 
@@ -81,3 +183,161 @@ func TestCleanerDeduplicatesURL(t *testing.T) {
 		t.Errorf("expected 1 listing after deduplication, got %d", len(cleaned))
 	}
 }
+
+func TestCleanerNormalisesAmenities(t *testing.T) {
+	c := NewCleaner(newTestLogger())
+	raw := []*models.RawListing{{
+		Title: "Loft", URL: "https://airbnb.com/rooms/3", Platform: "airbnb", ScrapedAt: time.Now(),
+		Amenities: []string{" Wifi ", "Pool", "", "wifi", "Air  conditioning"},
+	}}
+
+	got := c.Clean(raw)[0].Amenities
+	want := []string{"Wifi", "Pool", "Air conditioning"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("amenities = %q, want %q", got, want)
+	}
+}
+
+func TestCleanerPriceKnown(t *testing.T) {
+	c := NewCleaner(newTestLogger())
+	for _, tc := range []struct {
+		raw   string
+		price float64
+		known bool
+	}{
+		{"$73 per night", 73, true},
+		{"$0 per night", 0, true},
+		{"$0 for 3 nights", 0, true},
+		{"Free", 0, true},
+		{"$0 cleaning fee, $85 total", 85, true},
+		{"Was $0 off", 0, false},
+		{"$,, per night", 0, false},
+		{"N/A", 0, false},
+		{"", 0, false},
+		{"Price on request", 0, false},
+	} {
+		if price, known := c.parsePriceKnown(tc.raw); price != tc.price || known != tc.known {
+			t.Errorf("parsePriceKnown(%q) = %.2f, %v; want %.2f, %v", tc.raw, price, known, tc.price, tc.known)
+		}
+	}
+}
+
+func TestCleanerRatingNew(t *testing.T) {
+	c := NewCleaner(newTestLogger())
+	for _, tc := range []struct {
+		raw    string
+		rating float64
+		isNew  bool
+	}{
+		{"4.88", 4.88, false},
+		{"New", 0, true},
+		{"★ New", 0, true},
+		{"New · No reviews yet", 0, true},
+		{"", 0, false},
+		{"Newly renovated", 0, false},
+	} {
+		if rating, isNew := c.parseRatingNew(tc.raw); rating != tc.rating || isNew != tc.isNew {
+			t.Errorf("parseRatingNew(%q) = %.2f, %v; want %.2f, %v", tc.raw, rating, isNew, tc.rating, tc.isNew)
+		}
+	}
+}
+
+func TestCleanerStrictDropsUnparsed(t *testing.T) {
+	raw := []*models.RawListing{
+		{Title: "Priced", RawPrice: "$90 night", Location: "Lisbon", URL: "https://airbnb.com/rooms/1", Platform: "airbnb"},
+		{Title: "No price", RawPrice: "N/A", Location: "Lisbon", URL: "https://airbnb.com/rooms/2", Platform: "airbnb"},
+		{Title: "No location", RawPrice: "$80 night", URL: "https://airbnb.com/rooms/3", Platform: "airbnb"},
+		{Title: "Gone", URL: "https://airbnb.com/rooms/4", Platform: "airbnb", Status: models.ListingStatusRemoved},
+	}
+
+	if got := len(NewCleaner(newTestLogger()).Clean(raw)); got != 4 {
+		t.Fatalf("lenient: %d listings, want 4", got)
+	}
+
+	c := NewCleaner(newTestLogger())
+	c.SetStrict(true)
+	cleaned := c.Clean(raw)
+	if len(cleaned) != 2 || cleaned[0].Title != "Priced" || cleaned[1].Status != models.ListingStatusRemoved {
+		t.Fatalf("strict kept %d listings: %+v", len(cleaned), cleaned)
+	}
+	if r := c.Rejected(); r[RejectNoPrice] != 1 || r[RejectNoLocation] != 1 {
+		t.Errorf("rejected = %v, want one of each", r)
+	}
+}
+
+func TestCleanerKeepsRemovedListingsBare(t *testing.T) {
+	c := NewCleaner(newTestLogger())
+	raw := []*models.RawListing{
+		{Title: "Gone", RawPrice: "$90 night", Rating: "4.9", URL: "https://airbnb.com/rooms/7",
+			Platform: "airbnb", Status: models.ListingStatusRemoved, ScrapedAt: time.Now()},
+		{Title: "Live", RawPrice: "$90 night", URL: "https://airbnb.com/rooms/8", Platform: "airbnb", ScrapedAt: time.Now()},
+	}
+
+	cleaned := c.Clean(raw)
+	if len(cleaned) != 2 {
+		t.Fatalf("expected 2 listings, got %d", len(cleaned))
+	}
+	gone, live := cleaned[0], cleaned[1]
+	if gone.Status != models.ListingStatusRemoved || gone.Price != 0 || gone.Rating != 0 || gone.ShortID == "" {
+		t.Errorf("removed listing should keep identity only: %+v", gone)
+	}
+	if live.Status != models.ListingStatusActive || live.Price != 90 {
+		t.Errorf("active listing: status %q price %.2f", live.Status, live.Price)
+	}
+}
+
+func TestCleanerParallelMatchesSequential(t *testing.T) {
+	var raw []*models.RawListing
+	for i := 0; i < 3*cleanChunkSize; i++ {
+		raw = append(raw, &models.RawListing{
+			URL:      fmt.Sprintf("https://airbnb.com/rooms/%d", i%(2*cleanChunkSize)), // second half repeats
+			Title:    fmt.Sprintf("Listing %d", i),
+			RawPrice: fmt.Sprintf("$%d night", 50+i%200),
+			Platform: "airbnb",
+		})
+	}
+
+	seq := NewCleaner(newTestLogger()).Clean(raw)
+	par := NewCleaner(newTestLogger())
+	par.SetWorkers(8)
+	got := par.Clean(raw)
+
+	if len(got) != 2*cleanChunkSize || len(got) != len(seq) {
+		t.Fatalf("len: parallel %d, sequential %d, want %d", len(got), len(seq), 2*cleanChunkSize)
+	}
+	for i := range seq {
+		if got[i].URL != seq[i].URL || got[i].Title != seq[i].Title || got[i].Price != seq[i].Price {
+			t.Fatalf("row %d differs: parallel %+v, sequential %+v", i, got[i], seq[i])
+		}
+	}
+	// First occurrence wins the dedup.
+	if got[0].Title != "Listing 0" {
+		t.Errorf("dedup kept %q, want first occurrence", got[0].Title)
+	}
+}
+
+func TestCleanerStreamDedupsAcrossBatches(t *testing.T) {
+	in := make(chan []*models.RawListing, 2)
+	out := make(chan []*models.Listing, 2)
+	in <- []*models.RawListing{
+		{URL: "https://airbnb.com/rooms/1", Title: "A", Platform: "airbnb"},
+		{URL: "https://airbnb.com/rooms/2", Title: "B", Platform: "airbnb"},
+	}
+	in <- []*models.RawListing{
+		{URL: "https://airbnb.com/rooms/2", Title: "B again", Platform: "airbnb"},
+		{URL: "https://airbnb.com/rooms/3", Title: "C", Platform: "airbnb"},
+	}
+	close(in)
+
+	NewCleaner(newTestLogger()).CleanStream(in, out)
+
+	var titles []string
+	for batch := range out {
+		for _, l := range batch {
+			titles = append(titles, l.Title)
+		}
+	}
+	if strings.Join(titles, ",") != "A,B,C" {
+		t.Errorf("streamed titles = %v, want [A B C]", titles)
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"sort"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// SetClustering enables DBSCAN neighbourhood clustering in Generate.
+// Listings within epsKm of each other are neighbours; a cluster needs at
+// least minPoints listings. epsKm <= 0 disables clustering.
+func (s *InsightService) SetClustering(epsKm float64, minPoints int) {
+	s.clusterEpsKm = epsKm
+	s.clusterMinPoints = minPoints
+}
+
+// clusterListings runs DBSCAN over listings with coordinates and returns
+// per-cluster statistics, largest cluster first. Noise points are dropped.
+func clusterListings(listings []*models.Listing, epsKm float64, minPoints int) []*models.ClusterStats {
+	if epsKm <= 0 {
+		return nil
+	}
+	if minPoints < 1 {
+		minPoints = 1
+	}
+
+	var points []*models.Listing
+	for _, l := range listings {
+		if l.Latitude != 0 || l.Longitude != 0 {
+			points = append(points, l)
+		}
+	}
+
+	const (
+		unvisited = 0
+		noise     = -1
+	)
+	labels := make([]int, len(points))
+	neighbours := func(i int) []int {
+		var out []int
+		for j, p := range points {
+			if utils.HaversineKm(points[i].Latitude, points[i].Longitude, p.Latitude, p.Longitude) <= epsKm {
+				out = append(out, j)
+			}
+		}
+		return out
+	}
+
+	cluster := 0
+	for i := range points {
+		if labels[i] != unvisited {
+			continue
+		}
+		seeds := neighbours(i)
+		if len(seeds) < minPoints {
+			labels[i] = noise
+			continue
+		}
+		cluster++
+		labels[i] = cluster
+		for k := 0; k < len(seeds); k++ {
+			j := seeds[k]
+			if labels[j] == noise {
+				labels[j] = cluster // border point
+			}
+			if labels[j] != unvisited {
+				continue
+			}
+			labels[j] = cluster
+			if more := neighbours(j); len(more) >= minPoints {
+				seeds = append(seeds, more...)
+			}
+		}
+	}
+
+	members := make(map[int][]*models.Listing)
+	for i, label := range labels {
+		if label > 0 {
+			members[label] = append(members[label], points[i])
+		}
+	}
+
+	out := make([]*models.ClusterStats, 0, len(members))
+	for _, group := range members {
+		out = append(out, clusterStats(group))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Size != out[j].Size {
+			return out[i].Size > out[j].Size
+		}
+		return out[i].Label < out[j].Label
+	})
+	return out
+}
+
+func clusterStats(group []*models.Listing) *models.ClusterStats {
+	stats := &models.ClusterStats{Size: len(group)}
+
+	names := make(map[string]int)
+	var prices []float64
+	for _, l := range group {
+		stats.CenterLat += l.Latitude
+		stats.CenterLng += l.Longitude
+		if l.Location != "" {
+			names[l.Location]++
+		}
+		if l.HasPrice() {
+			prices = append(prices, l.Price)
+		}
+	}
+	stats.CenterLat /= float64(len(group))
+	stats.CenterLng /= float64(len(group))
+
+	// Label by the most common location string; ties break alphabetically.
+	best := 0
+	for name, n := range names {
+		if n > best || (n == best && name < stats.Label) {
+			stats.Label, best = name, n
+		}
+	}
+	if stats.Label == "" {
+		stats.Label = "Unnamed area"
+	}
+
+	if len(prices) > 0 {
+		sort.Float64s(prices)
+		var sum float64
+		for _, p := range prices {
+			sum += p
+		}
+		stats.AveragePrice = round2(sum / float64(len(prices)))
+		stats.MedianPrice = round2(percentile(prices, 0.5))
+		stats.MinPrice = prices[0]
+		stats.MaxPrice = prices[len(prices)-1]
+	}
+	return stats
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+
+	"airbnb-scraper/models"
+)
+
+func TestClusterListingsDBSCAN(t *testing.T) {
+	listings := []*models.Listing{
+		// Silom cluster (~100-300 m apart)
+		{Location: "Silom", Price: 60, Latitude: 13.7280, Longitude: 100.5340},
+		{Location: "Silom", Price: 80, Latitude: 13.7290, Longitude: 100.5350},
+		{Location: "Bang Rak", Price: 100, Latitude: 13.7300, Longitude: 100.5330},
+		// Sukhumvit cluster (~5 km away)
+		{Location: "Sukhumvit", Price: 120, Latitude: 13.7370, Longitude: 100.5600},
+		{Location: "Sukhumvit", Price: 140, Latitude: 13.7380, Longitude: 100.5610},
+		// Isolated point — noise
+		{Location: "Nonthaburi", Price: 40, Latitude: 13.8600, Longitude: 100.5140},
+		// No coordinates — ignored
+		{Location: "Unknown", Price: 10},
+	}
+
+	clusters := clusterListings(listings, 0.5, 2)
+	if len(clusters) != 2 {
+		t.Fatalf("clusters: got %d, want 2", len(clusters))
+	}
+	silom := clusters[0]
+	if silom.Label != "Silom" || silom.Size != 3 {
+		t.Errorf("first cluster: got %s/%d, want Silom/3", silom.Label, silom.Size)
+	}
+	if silom.AveragePrice != 80 || silom.MinPrice != 60 || silom.MaxPrice != 100 {
+		t.Errorf("Silom prices: avg %.2f min %.2f max %.2f", silom.AveragePrice, silom.MinPrice, silom.MaxPrice)
+	}
+	if clusters[1].Label != "Sukhumvit" || clusters[1].MedianPrice != 130 {
+		t.Errorf("second cluster: got %s median %.2f", clusters[1].Label, clusters[1].MedianPrice)
+	}
+
+	if got := clusterListings(listings, 0, 2); got != nil {
+		t.Error("eps 0 should disable clustering")
+	}
+}
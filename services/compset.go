@@ -0,0 +1,124 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+// BuildCompSetReport compares own with the competitors over the last weeks
+// calendar weeks up to now. history holds each listing's watch states,
+// oldest first, and calendars its calendar days; listings missing from
+// both are reported with unknown figures. A day's price is the one in
+// effect at its end, counted only while the listing was available.
+func BuildCompSetReport(own string, competitors []string, history map[string][]models.WatchState,
+	calendars map[string][]models.CalendarDay, weeks int, now time.Time) *models.CompSetReport {
+	r := &models.CompSetReport{Listing: own, Competitors: competitors, GeneratedAt: now}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	for i := weeks - 1; i >= 0; i-- {
+		start := monday.AddDate(0, 0, -7*i)
+		w := models.CompSetWeek{Start: start, Own: weekStats(history[own], calendars[own], start, now)}
+
+		var price, occupancy, rating []float64
+		for _, c := range competitors {
+			s := weekStats(history[c], calendars[c], start, now)
+			if s.Price > 0 {
+				price = append(price, s.Price)
+			}
+			if s.CalendarDays > 0 {
+				occupancy = append(occupancy, s.Occupancy)
+				w.CompSet.CalendarDays += s.CalendarDays
+			}
+			if s.Rating > 0 {
+				rating = append(rating, s.Rating)
+			}
+			if s.Price > 0 || s.CalendarDays > 0 || s.Rating > 0 {
+				w.CompSet.Listings++
+			}
+		}
+		w.CompSet.Price, w.CompSet.Occupancy, w.CompSet.Rating = mean(price), mean(occupancy), mean(rating)
+		if w.Own.Price > 0 && w.CompSet.Price > 0 {
+			w.PriceIndex = w.Own.Price / w.CompSet.Price * 100
+		}
+		r.Weeks = append(r.Weeks, w)
+	}
+
+	for _, u := range append([]string{own}, competitors...) {
+		m := models.CompSetMember{WatchState: models.WatchState{URL: u}}
+		if h := history[u]; len(h) > 0 {
+			m.WatchState = h[len(h)-1]
+		}
+		m.Occupancy, m.CalendarDays = occupancy(calendars[u], today, time.Time{})
+		r.Latest = append(r.Latest, m)
+	}
+	return r
+}
+
+// weekStats computes one listing's figures for the week starting at start,
+// ignoring days after now.
+func weekStats(history []models.WatchState, calendar []models.CalendarDay, start, now time.Time) models.CompSetStats {
+	var s models.CompSetStats
+	end := start.AddDate(0, 0, 7)
+	var prices []float64
+	for day := start; day.Before(end) && day.Before(now); day = day.AddDate(0, 0, 1) {
+		st := stateAt(history, day.AddDate(0, 0, 1))
+		if st != nil && st.Status == models.WatchAvailable && st.Price > 0 {
+			prices = append(prices, st.Price)
+		}
+	}
+	s.Price = mean(prices)
+	if st := stateAt(history, minTime(end, now)); st != nil {
+		s.Rating = st.Rating
+	}
+	s.Occupancy, s.CalendarDays = occupancy(calendar, start, end)
+	return s
+}
+
+// stateAt returns the last state checked before t, or nil.
+func stateAt(history []models.WatchState, t time.Time) *models.WatchState {
+	i := sort.Search(len(history), func(i int) bool { return !history[i].CheckedAt.Before(t) })
+	if i == 0 {
+		return nil
+	}
+	return &history[i-1]
+}
+
+// occupancy is the blocked share of the calendar days in [from, to) and
+// how many days it is based on; a zero to means no upper bound.
+func occupancy(calendar []models.CalendarDay, from, to time.Time) (float64, int) {
+	blocked, days := 0, 0
+	for _, d := range calendar {
+		if d.Date.Before(from) || (!to.IsZero() && !d.Date.Before(to)) {
+			continue
+		}
+		days++
+		if d.Blocked {
+			blocked++
+		}
+	}
+	if days == 0 {
+		return 0, 0
+	}
+	return float64(blocked) / float64(days), days
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
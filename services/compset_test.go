@@ -0,0 +1,69 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+func TestBuildCompSetReport(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) } // 6 May is a Monday
+	state := func(url string, checked time.Time, price float64, status string, rating float64) models.WatchState {
+		return models.WatchState{URL: url, Price: price, Status: status, Rating: rating, CheckedAt: checked}
+	}
+	now := day(15).Add(12 * time.Hour) // Wednesday of the second week
+
+	history := map[string][]models.WatchState{
+		"own": {
+			state("own", day(1), 100, models.WatchAvailable, 4.8),
+			state("own", day(9).Add(time.Hour), 120, models.WatchAvailable, 4.8), // from Thursday the 9th
+		},
+		"a": {
+			state("a", day(1), 80, models.WatchAvailable, 4.6),
+			state("a", day(14), 0, models.WatchUnavailable, 4.5),
+		},
+		"b": {state("b", day(1), 100, models.WatchAvailable, 4.9)},
+	}
+	calendars := map[string][]models.CalendarDay{
+		"own": {{Date: day(6), Blocked: true}, {Date: day(7)}, {Date: day(16), Blocked: true}, {Date: day(17), Blocked: true}},
+		"a":   {{Date: day(6), Blocked: true}, {Date: day(7), Blocked: true}},
+	}
+
+	r := BuildCompSetReport("own", []string{"a", "b", "c"}, history, calendars, 2, now)
+	if len(r.Weeks) != 2 || !r.Weeks[0].Start.Equal(day(6)) || !r.Weeks[1].Start.Equal(day(13)) {
+		t.Fatalf("weeks = %+v", r.Weeks)
+	}
+
+	w := r.Weeks[0]
+	// 6–8 May at $100, 9–12 May at $120.
+	if want := (3*100 + 4*120) / 7.0; math.Abs(w.Own.Price-want) > 1e-9 {
+		t.Errorf("own price = %.2f, want %.2f", w.Own.Price, want)
+	}
+	if w.Own.Occupancy != 0.5 || w.Own.CalendarDays != 2 {
+		t.Errorf("own occupancy = %.2f over %d days", w.Own.Occupancy, w.Own.CalendarDays)
+	}
+	if w.CompSet.Price != 90 || w.CompSet.Listings != 2 || w.CompSet.Occupancy != 1 {
+		t.Errorf("comp set = %+v, want $90 over 2 listings, occupancy 1", w.CompSet)
+	}
+	if math.Abs(w.PriceIndex-w.Own.Price/90*100) > 1e-9 {
+		t.Errorf("price index = %.2f", w.PriceIndex)
+	}
+
+	w = r.Weeks[1]
+	// a is unavailable from the 14th: only the 13th counts; b all three days.
+	if w.CompSet.Price != 90 || w.CompSet.Rating != (4.5+4.9)/2 {
+		t.Errorf("current week comp set = %+v", w.CompSet)
+	}
+	if w.Own.Rating != 4.8 || w.Own.CalendarDays != 2 || w.Own.Occupancy != 1 {
+		t.Errorf("current week own = %+v", w.Own)
+	}
+
+	if len(r.Latest) != 4 || r.Latest[0].URL != "own" || r.Latest[0].Price != 120 || r.Latest[3].URL != "c" || r.Latest[3].Status != "" {
+		t.Errorf("latest = %+v", r.Latest)
+	}
+	if r.Latest[0].Occupancy != 1 {
+		t.Errorf("own upcoming occupancy = %.2f, want 1 (16th and 17th blocked)", r.Latest[0].Occupancy)
+	}
+}
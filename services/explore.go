@@ -0,0 +1,409 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// Explorer is the state behind the `explore` shell: the full dataset, the
+// current filtered and sorted view, and the filters that produced it.
+type Explorer struct {
+	all     []*models.Listing
+	view    []*models.Listing
+	filters []string
+	sortKey string
+	out     io.Writer
+	open    func(url string) error
+	money   *utils.Money // nil = en-US dollars
+}
+
+// NewExplorer starts with every listing in view, ordered by score. open
+// launches a URL in a browser.
+func NewExplorer(listings []*models.Listing, out io.Writer, open func(string) error) *Explorer {
+	e := &Explorer{all: listings, out: out, open: open}
+	e.reset()
+	return e
+}
+
+// SetMoney sets the locale and display currency prices are printed in.
+func (e *Explorer) SetMoney(m *utils.Money) {
+	e.money = m
+}
+
+const exploreHelp = `Commands:
+  filter EXPR...     narrow the view; EXPR is field OP value, e.g. price<80 rating>=4.5 location~bang
+                     numeric fields: price rating reviews score    text fields: title location city platform
+                     operators: = != < <= > >= and ~ (contains); quote values with spaces: location="Chiang Mai"
+  sort [-]FIELD      order the view (price and text ascending, rating/reviews/score descending; - reverses)
+  group FIELD        count, average price and rating per location, city or platform
+  list [N]           show the first N listings of the view (default 20)
+  open N|ID...       open listings by list number or short ID in the browser
+  reset              drop all filters
+  help, quit
+`
+
+// Run reads commands from in until EOF or quit, printing a prompt before each.
+func (e *Explorer) Run(in io.Reader) error {
+	fmt.Fprintf(e.out, "%d listings loaded. Type help for commands.\n", len(e.all))
+	sc := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(e.out, "explore> ")
+		if !sc.Scan() {
+			fmt.Fprintln(e.out)
+			return sc.Err()
+		}
+		quit, err := e.Exec(sc.Text())
+		if err != nil {
+			fmt.Fprintf(e.out, "error: %v\n", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// Exec runs one command line. quit reports a quit/exit command.
+func (e *Explorer) Exec(line string) (quit bool, err error) {
+	args, err := splitArgs(line)
+	if err != nil || len(args) == 0 {
+		return false, err
+	}
+	cmd, args := strings.ToLower(args[0]), args[1:]
+	switch cmd {
+	case "help", "?":
+		fmt.Fprint(e.out, exploreHelp)
+	case "quit", "exit", "q":
+		return true, nil
+	case "reset":
+		e.reset()
+		fmt.Fprintf(e.out, "%d listings\n", len(e.view))
+	case "filter", "where":
+		err = e.filter(args)
+	case "sort":
+		if len(args) != 1 {
+			return false, fmt.Errorf("usage: sort [-]FIELD")
+		}
+		err = e.sort(args[0])
+	case "group":
+		if len(args) != 1 {
+			return false, fmt.Errorf("usage: group location|city|platform")
+		}
+		err = e.group(args[0])
+	case "list", "ls", "show":
+		n := 20
+		if len(args) > 0 {
+			if n, err = strconv.Atoi(args[0]); err != nil || n <= 0 {
+				return false, fmt.Errorf("list: %q is not a positive number", args[0])
+			}
+		}
+		e.list(n)
+	case "open":
+		err = e.openListings(args)
+	default:
+		err = fmt.Errorf("unknown command %q (try help)", cmd)
+	}
+	return false, err
+}
+
+func (e *Explorer) reset() {
+	e.view = append([]*models.Listing(nil), e.all...)
+	e.filters = nil
+	e.sortKey = "score"
+	_ = e.sortView("score")
+}
+
+// ── Filtering ────────────────────────────────────────────────────────────────
+
+var filterExprRegexp = regexp.MustCompile(`^([a-z_]+)\s*(<=|>=|!=|=|<|>|~)\s*(.*)$`)
+
+var numericFields = map[string]func(*models.Listing) float64{
+	"price":   func(l *models.Listing) float64 { return l.Price },
+	"rating":  func(l *models.Listing) float64 { return l.Rating },
+	"reviews": func(l *models.Listing) float64 { return float64(l.ReviewCount) },
+	"score":   func(l *models.Listing) float64 { return l.Score },
+}
+
+var textFields = map[string]func(*models.Listing) string{
+	"title":    func(l *models.Listing) string { return l.Title },
+	"location": func(l *models.Listing) string { return l.Location },
+	"city":     func(l *models.Listing) string { return l.TargetCity },
+	"platform": func(l *models.Listing) string { return l.Platform },
+}
+
+func (e *Explorer) filter(exprs []string) error {
+	if len(exprs) == 0 {
+		if len(e.filters) == 0 {
+			fmt.Fprintln(e.out, "no filters")
+		}
+		for _, f := range e.filters {
+			fmt.Fprintln(e.out, f)
+		}
+		return nil
+	}
+	preds := make([]func(*models.Listing) bool, 0, len(exprs))
+	for _, expr := range exprs {
+		p, err := parseFilter(expr)
+		if err != nil {
+			return err
+		}
+		preds = append(preds, p)
+	}
+
+	kept := e.view[:0:0]
+	for _, l := range e.view {
+		ok := true
+		for _, p := range preds {
+			if !p(l) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			kept = append(kept, l)
+		}
+	}
+	e.view = kept
+	e.filters = append(e.filters, exprs...)
+	fmt.Fprintf(e.out, "%d listings\n", len(e.view))
+	return nil
+}
+
+// parseFilter turns "price<80" or "location~bang" into a predicate. Numeric
+// comparisons never match unknown (zero) values.
+func parseFilter(expr string) (func(*models.Listing) bool, error) {
+	m := filterExprRegexp.FindStringSubmatch(strings.TrimSpace(expr))
+	if m == nil {
+		return nil, fmt.Errorf("bad filter %q (want field OP value, e.g. price<80)", expr)
+	}
+	field, op, value := strings.ToLower(m[1]), m[2], strings.TrimSpace(m[3])
+
+	if get, ok := numericFields[field]; ok {
+		want, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("filter %s: %q is not a number", field, value)
+		}
+		var cmp func(a float64) bool
+		switch op {
+		case "=":
+			cmp = func(a float64) bool { return a == want }
+		case "!=":
+			cmp = func(a float64) bool { return a != want }
+		case "<":
+			cmp = func(a float64) bool { return a < want }
+		case "<=":
+			cmp = func(a float64) bool { return a <= want }
+		case ">":
+			cmp = func(a float64) bool { return a > want }
+		case ">=":
+			cmp = func(a float64) bool { return a >= want }
+		default:
+			return nil, fmt.Errorf("filter %s: operator %s needs a text field", field, op)
+		}
+		return func(l *models.Listing) bool {
+			v := get(l)
+			return v != 0 && cmp(v)
+		}, nil
+	}
+
+	if get, ok := textFields[field]; ok {
+		want := strings.ToLower(value)
+		switch op {
+		case "=":
+			return func(l *models.Listing) bool { return strings.ToLower(get(l)) == want }, nil
+		case "!=":
+			return func(l *models.Listing) bool { return strings.ToLower(get(l)) != want }, nil
+		case "~":
+			return func(l *models.Listing) bool { return strings.Contains(strings.ToLower(get(l)), want) }, nil
+		}
+		return nil, fmt.Errorf("filter %s: operator %s needs a numeric field", field, op)
+	}
+	return nil, fmt.Errorf("unknown field %q", field)
+}
+
+// ── Sorting and grouping ─────────────────────────────────────────────────────
+
+func (e *Explorer) sort(key string) error {
+	if err := e.sortView(key); err != nil {
+		return err
+	}
+	e.sortKey = key
+	e.list(20)
+	return nil
+}
+
+// sortView orders the view by key: price and text fields ascending, other
+// numbers descending, unknown (zero) prices last; "-" reverses.
+func (e *Explorer) sortView(key string) error {
+	field := strings.TrimPrefix(strings.ToLower(key), "-")
+	desc := strings.HasPrefix(key, "-")
+
+	var less func(a, b *models.Listing) bool
+	if get, ok := numericFields[field]; ok {
+		if field != "price" {
+			desc = !desc
+		}
+		less = func(a, b *models.Listing) bool {
+			x, y := get(a), get(b)
+			if (x == 0) != (y == 0) {
+				return y == 0 // unknown values last either way
+			}
+			if desc {
+				return x > y
+			}
+			return x < y
+		}
+	} else if get, ok := textFields[field]; ok {
+		less = func(a, b *models.Listing) bool {
+			x, y := strings.ToLower(get(a)), strings.ToLower(get(b))
+			if desc {
+				return x > y
+			}
+			return x < y
+		}
+	} else {
+		return fmt.Errorf("unknown sort field %q", field)
+	}
+	sort.SliceStable(e.view, func(i, j int) bool { return less(e.view[i], e.view[j]) })
+	return nil
+}
+
+func (e *Explorer) group(field string) error {
+	get, ok := textFields[strings.ToLower(field)]
+	if !ok || field == "title" {
+		return fmt.Errorf("group by location, city or platform")
+	}
+
+	type bucket struct {
+		name                  string
+		count                 int
+		priceSum, ratingSum   float64
+		priceSeen, ratingSeen int
+	}
+	byName := make(map[string]*bucket)
+	var buckets []*bucket
+	for _, l := range e.view {
+		name := get(l)
+		if name == "" {
+			name = "(none)"
+		}
+		b, ok := byName[name]
+		if !ok {
+			b = &bucket{name: name}
+			byName[name] = b
+			buckets = append(buckets, b)
+		}
+		b.count++
+		if l.HasPrice() {
+			b.priceSum += l.Price
+			b.priceSeen++
+		}
+		if l.Rating > 0 {
+			b.ratingSum += l.Rating
+			b.ratingSeen++
+		}
+	}
+	sort.SliceStable(buckets, func(i, j int) bool { return buckets[i].count > buckets[j].count })
+
+	fmt.Fprintf(e.out, "%-28s %6s %10s %7s\n", strings.ToUpper(field), "COUNT", "AVG PRICE", "RATING")
+	for _, b := range buckets {
+		avgPrice, avgRating := "-", "-"
+		if b.priceSeen > 0 {
+			avgPrice = e.money.Format(b.priceSum / float64(b.priceSeen))
+		}
+		if b.ratingSeen > 0 {
+			avgRating = fmt.Sprintf("%.2f", b.ratingSum/float64(b.ratingSeen))
+		}
+		fmt.Fprintf(e.out, "%-28s %6d %10s %7s\n", truncate(b.name, 28), b.count, avgPrice, avgRating)
+	}
+	return nil
+}
+
+// ── Listing and opening ──────────────────────────────────────────────────────
+
+func (e *Explorer) list(n int) {
+	if n > len(e.view) {
+		n = len(e.view)
+	}
+	fmt.Fprintf(e.out, "%-4s %-9s %-30s %-18s %8s %6s %7s\n", "#", "ID", "TITLE", "LOCATION", "PRICE", "RATING", "REVIEWS")
+	for i, l := range e.view[:n] {
+		fmt.Fprintf(e.out, "%-4d %-9s %-30s %-18s %8s %6.2f %7d\n",
+			i+1, l.ShortID, truncate(l.Title, 30), truncate(l.Location, 18),
+			e.money.Format(l.Price), l.Rating, l.ReviewCount)
+	}
+	fmt.Fprintf(e.out, "%d of %d listings (sorted by %s)\n", n, len(e.view), e.sortKey)
+}
+
+func (e *Explorer) openListings(refs []string) error {
+	if len(refs) == 0 {
+		return fmt.Errorf("usage: open N|ID...")
+	}
+	for _, ref := range refs {
+		l := e.lookup(ref)
+		if l == nil {
+			return fmt.Errorf("no listing %q in the current view", ref)
+		}
+		if err := e.open(l.URL); err != nil {
+			return err
+		}
+		fmt.Fprintf(e.out, "opened %s\n", l.URL)
+	}
+	return nil
+}
+
+// lookup resolves a 1-based list number or a short ID within the view.
+func (e *Explorer) lookup(ref string) *models.Listing {
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n >= 1 && n <= len(e.view) {
+			return e.view[n-1]
+		}
+		return nil
+	}
+	for _, l := range e.view {
+		if strings.EqualFold(l.ShortID, ref) {
+			return l
+		}
+	}
+	return nil
+}
+
+// splitArgs splits a command line on spaces, keeping double-quoted runs
+// together (the quotes themselves are dropped).
+func splitArgs(line string) ([]string, error) {
+	var (
+		args    []string
+		cur     strings.Builder
+		inQuote bool
+		started bool
+	)
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			started = true
+		case (r == ' ' || r == '\t') && !inQuote:
+			if started {
+				args = append(args, cur.String())
+				cur.Reset()
+				started = false
+			}
+		default:
+			cur.WriteRune(r)
+			started = true
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if started {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"airbnb-scraper/models"
+)
+
+func exploreListings() []*models.Listing {
+	return []*models.Listing{
+		{ShortID: "AAAA-0001", Title: "Loft", Location: "Bangkok", Price: 60, Rating: 4.8, Score: 70, URL: "https://www.airbnb.com/rooms/1"},
+		{ShortID: "AAAA-0002", Title: "Villa", Location: "Chiang Mai", Price: 150, Rating: 4.9, Score: 90, URL: "https://www.airbnb.com/rooms/2"},
+		{ShortID: "AAAA-0003", Title: "Room", Location: "Bangkok", Price: 30, Rating: 4.2, Score: 50, URL: "https://www.airbnb.com/rooms/3"},
+		{ShortID: "AAAA-0004", Title: "Hut", Location: "Chiang Mai", Score: 10, URL: "https://www.airbnb.com/rooms/4"},
+	}
+}
+
+func viewIDs(e *Explorer) []string {
+	var ids []string
+	for _, l := range e.view {
+		ids = append(ids, l.ShortID)
+	}
+	return ids
+}
+
+func TestExplorerFilterSortReset(t *testing.T) {
+	var out bytes.Buffer
+	e := NewExplorer(exploreListings(), &out, nil)
+
+	if got := viewIDs(e); !reflect.DeepEqual(got, []string{"AAAA-0002", "AAAA-0001", "AAAA-0003", "AAAA-0004"}) {
+		t.Errorf("initial view (by score) = %v", got)
+	}
+
+	if _, err := e.Exec(`filter location~bang price<=60`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Exec("sort price"); err != nil {
+		t.Fatal(err)
+	}
+	if got := viewIDs(e); !reflect.DeepEqual(got, []string{"AAAA-0003", "AAAA-0001"}) {
+		t.Errorf("filtered view = %v", got)
+	}
+
+	e.Exec("reset")
+	e.Exec(`filter location="chiang mai"`)
+	e.Exec("sort price")
+	if got := viewIDs(e); !reflect.DeepEqual(got, []string{"AAAA-0002", "AAAA-0004"}) {
+		t.Errorf("unknown price should sort last: %v", got)
+	}
+	e.Exec("sort -price")
+	if got := viewIDs(e); !reflect.DeepEqual(got, []string{"AAAA-0002", "AAAA-0004"}) {
+		t.Errorf("unknown price should sort last when reversed too: %v", got)
+	}
+}
+
+func TestExplorerErrorsAndQuit(t *testing.T) {
+	e := NewExplorer(exploreListings(), &bytes.Buffer{}, nil)
+	for _, line := range []string{"filter price~3", "filter bogus=1", "filter rating>high", "sort colour", "frobnicate", `filter title="open`} {
+		if _, err := e.Exec(line); err == nil {
+			t.Errorf("%q: expected an error", line)
+		}
+	}
+	if quit, _ := e.Exec("quit"); !quit {
+		t.Error("quit did not quit")
+	}
+}
+
+func TestExplorerGroupAndOpen(t *testing.T) {
+	var out bytes.Buffer
+	var opened []string
+	e := NewExplorer(exploreListings(), &out, func(u string) error {
+		opened = append(opened, u)
+		return nil
+	})
+
+	e.Exec("group location")
+	text := out.String()
+	if !strings.Contains(text, "Bangkok") || !strings.Contains(text, "$45.00") {
+		t.Errorf("group output missing Bangkok average:\n%s", text)
+	}
+
+	if _, err := e.Exec("open 1 aaaa-0003"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"https://www.airbnb.com/rooms/2", "https://www.airbnb.com/rooms/3"}
+	if !reflect.DeepEqual(opened, want) {
+		t.Errorf("opened %v, want %v", opened, want)
+	}
+	if _, err := e.Exec("open 9"); err == nil {
+		t.Error("out-of-range number accepted")
+	}
+}
+
+func TestExplorerRun(t *testing.T) {
+	var out bytes.Buffer
+	e := NewExplorer(exploreListings(), &out, nil)
+	if err := e.Run(strings.NewReader("filter rating>=4.5\nlist 1\nquit\nlist\n")); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "2 listings") || !strings.Contains(out.String(), "1 of 2 listings") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"airbnb-scraper/models"
+)
+
+// DiffFingerprints lists the structural changes between two detail-page
+// fingerprints: section ids and test ids that disappeared or appeared, and
+// heading counts that changed. Missing markers come first since those are
+// the ones extraction depends on.
+func DiffFingerprints(prev, curr *models.PageFingerprint) []string {
+	if prev == nil || curr == nil {
+		return nil
+	}
+	var out []string
+
+	removed, added := diffSets(prev.SectionIDs, curr.SectionIDs)
+	for _, id := range removed {
+		out = append(out, fmt.Sprintf("section removed: %s", id))
+	}
+	removedT, addedT := diffSets(prev.TestIDs, curr.TestIDs)
+	for _, id := range removedT {
+		out = append(out, fmt.Sprintf("test id removed: %s", id))
+	}
+	for _, id := range added {
+		out = append(out, fmt.Sprintf("section added: %s", id))
+	}
+	for _, id := range addedT {
+		out = append(out, fmt.Sprintf("test id added: %s", id))
+	}
+
+	tags := make(map[string]struct{})
+	for t := range prev.Headings {
+		tags[t] = struct{}{}
+	}
+	for t := range curr.Headings {
+		tags[t] = struct{}{}
+	}
+	sorted := make([]string, 0, len(tags))
+	for t := range tags {
+		sorted = append(sorted, t)
+	}
+	sort.Strings(sorted)
+	for _, t := range sorted {
+		if p, c := prev.Headings[t], curr.Headings[t]; p != c {
+			out = append(out, fmt.Sprintf("%s count %d → %d", t, p, c))
+		}
+	}
+	return out
+}
+
+// diffSets returns the elements only in a and only in b, sorted.
+func diffSets(a, b []string) (onlyA, onlyB []string) {
+	inA := make(map[string]struct{}, len(a))
+	for _, v := range a {
+		inA[v] = struct{}{}
+	}
+	inB := make(map[string]struct{}, len(b))
+	for _, v := range b {
+		inB[v] = struct{}{}
+		if _, ok := inA[v]; !ok {
+			onlyB = append(onlyB, v)
+		}
+	}
+	for _, v := range a {
+		if _, ok := inB[v]; !ok {
+			onlyA = append(onlyA, v)
+		}
+	}
+	sort.Strings(onlyA)
+	sort.Strings(onlyB)
+	return onlyA, onlyB
+}
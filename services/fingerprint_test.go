@@ -0,0 +1,39 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"airbnb-scraper/models"
+)
+
+func TestDiffFingerprints(t *testing.T) {
+	prev := &models.PageFingerprint{
+		SectionIDs: []string{"AMENITIES_DEFAULT", "DESCRIPTION_DEFAULT", "TITLE_DEFAULT"},
+		TestIDs:    []string{"book-it-default"},
+		Headings:   map[string]int{"h1": 1, "h2": 8},
+	}
+	curr := &models.PageFingerprint{
+		SectionIDs: []string{"AMENITIES_DEFAULT", "DESCRIPTION_V2", "TITLE_DEFAULT"},
+		TestIDs:    []string{"book-it-default"},
+		Headings:   map[string]int{"h1": 1, "h2": 5, "h3": 2},
+	}
+
+	got := DiffFingerprints(prev, curr)
+	want := []string{
+		"section removed: DESCRIPTION_DEFAULT",
+		"section added: DESCRIPTION_V2",
+		"h2 count 8 → 5",
+		"h3 count 0 → 2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffFingerprints:\n got  %q\n want %q", got, want)
+	}
+
+	if d := DiffFingerprints(prev, prev); len(d) != 0 {
+		t.Errorf("identical fingerprints should not differ: %q", d)
+	}
+	if d := DiffFingerprints(nil, curr); d != nil {
+		t.Errorf("first fingerprint should yield no diff: %q", d)
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"sort"
+
+	"airbnb-scraper/models"
+)
+
+// Holt's linear smoothing parameters: level reacts quickly, trend slowly.
+const (
+	holtAlpha = 0.5
+	holtBeta  = 0.3
+)
+
+// Forecast projects each location's weekly average price `weeks` ahead.
+// Locations with at least minPoints weeks use Holt's linear trend method;
+// those with fewer (but at least 2) fall back to a moving average. Results
+// are ordered by history length, then location.
+func (s *InsightService) Forecast(series map[string][]models.PricePoint, weeks, minPoints int) []*models.PriceForecast {
+	if weeks <= 0 {
+		return nil
+	}
+	var out []*models.PriceForecast
+	for loc, points := range series {
+		if len(points) < 2 {
+			continue
+		}
+		values := make([]float64, len(points))
+		for i, p := range points {
+			values[i] = p.AveragePrice
+		}
+
+		f := &models.PriceForecast{
+			Location:  loc,
+			History:   len(values),
+			LastPrice: round2(values[len(values)-1]),
+		}
+		if len(values) >= minPoints {
+			f.Method = "holt"
+			f.Weeks = holtForecast(values, weeks)
+		} else {
+			f.Method = "moving-average"
+			f.Weeks = movingAverageForecast(values, weeks, 3)
+		}
+		out = append(out, f)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].History != out[j].History {
+			return out[i].History > out[j].History
+		}
+		return out[i].Location < out[j].Location
+	})
+	s.logger.Info("[insights] Forecast %d weeks for %d locations", weeks, len(out))
+	return out
+}
+
+// holtForecast applies double exponential smoothing and extrapolates the
+// final level and trend. Negative projections are clamped to 0.
+func holtForecast(values []float64, horizon int) []float64 {
+	level := values[0]
+	trend := values[1] - values[0]
+	for _, v := range values[1:] {
+		prevLevel := level
+		level = holtAlpha*v + (1-holtAlpha)*(level+trend)
+		trend = holtBeta*(level-prevLevel) + (1-holtBeta)*trend
+	}
+
+	out := make([]float64, horizon)
+	for h := 1; h <= horizon; h++ {
+		f := level + float64(h)*trend
+		if f < 0 {
+			f = 0
+		}
+		out[h-1] = round2(f)
+	}
+	return out
+}
+
+// movingAverageForecast projects the mean of the last `window` values flat.
+func movingAverageForecast(values []float64, horizon, window int) []float64 {
+	if window > len(values) {
+		window = len(values)
+	}
+	var sum float64
+	for _, v := range values[len(values)-window:] {
+		sum += v
+	}
+	avg := round2(sum / float64(window))
+
+	out := make([]float64, horizon)
+	for i := range out {
+		out[i] = avg
+	}
+	return out
+}
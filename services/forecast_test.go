@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+func weekly(prices ...float64) []models.PricePoint {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	out := make([]models.PricePoint, len(prices))
+	for i, p := range prices {
+		out[i] = models.PricePoint{Week: start.AddDate(0, 0, 7*i), AveragePrice: p}
+	}
+	return out
+}
+
+func TestForecastHoltFollowsLinearTrend(t *testing.T) {
+	svc := NewInsightService(utils.NewLogger())
+	got := svc.Forecast(map[string][]models.PricePoint{
+		"Bangkok": weekly(100, 110, 120, 130),
+	}, 4, 3)
+
+	if len(got) != 1 || got[0].Method != "holt" {
+		t.Fatalf("expected one holt forecast, got %+v", got)
+	}
+	want := []float64{140, 150, 160, 170}
+	for i, w := range want {
+		if got[0].Weeks[i] != w {
+			t.Errorf("week %d: got %.2f, want %.2f", i+1, got[0].Weeks[i], w)
+		}
+	}
+}
+
+func TestForecastFallsBackToMovingAverage(t *testing.T) {
+	svc := NewInsightService(utils.NewLogger())
+	got := svc.Forecast(map[string][]models.PricePoint{
+		"Tokyo": weekly(80, 100),
+		"Bali":  weekly(50), // too short, skipped
+	}, 2, 3)
+
+	if len(got) != 1 || got[0].Location != "Tokyo" || got[0].Method != "moving-average" {
+		t.Fatalf("unexpected forecasts: %+v", got)
+	}
+	if got[0].Weeks[0] != 90 || got[0].Weeks[1] != 90 {
+		t.Errorf("moving average: got %v, want [90 90]", got[0].Weeks)
+	}
+}
@@ -2,21 +2,65 @@ package services
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"sort"
 	"strings"
 
+	"airbnb-scraper/config"
 	"airbnb-scraper/models"
 	"airbnb-scraper/utils"
 )
 
 type InsightService struct {
 	logger *utils.Logger
+
+	landmarks        []config.Landmark
+	landmarkRadiusKm float64
+
+	clusterEpsKm     float64
+	clusterMinPoints int
+
+	minConfidence float64
+
+	money *utils.Money // report currency and number format; nil = en-US dollars
+	out   io.Writer    // where Print writes; nil = stdout
 }
 
 func NewInsightService(logger *utils.Logger) *InsightService {
 	return &InsightService{logger: logger}
 }
 
+// SetMinConfidence excludes prices and ratings whose extractor confidence is
+// below min from the report statistics. Values with unknown confidence (0)
+// are always kept.
+func (s *InsightService) SetMinConfidence(min float64) {
+	s.minConfidence = min
+}
+
+// SetMoney sets the locale and display currency prices are printed in.
+func (s *InsightService) SetMoney(m *utils.Money) {
+	s.money = m
+}
+
+// SetOutput makes Print write to w instead of stdout.
+func (s *InsightService) SetOutput(w io.Writer) {
+	s.out = w
+}
+
+// printf writes styled report output (see utils.Style).
+func (s *InsightService) printf(format string, args ...any) {
+	w := s.out
+	if w == nil {
+		w = os.Stdout
+	}
+	utils.Fprintf(w, format, args...)
+}
+
+func (s *InsightService) lowConfidence(c float64) bool {
+	return c > 0 && c < s.minConfidence
+}
+
 func (s *InsightService) Generate(listings []*models.Listing) *models.InsightReport {
 	report := &models.InsightReport{
 		ListingsByLocation: make(map[string]int),
@@ -35,21 +79,34 @@ func (s *InsightService) Generate(listings []*models.Listing) *models.InsightRep
 		if l.Platform == "airbnb" {
 			report.AirbnbListings++
 		}
-		if l.Price > 0 {
-			priceListings = append(priceListings, l)
+		if l.HasPrice() {
+			if s.lowConfidence(l.PriceConfidence) {
+				report.LowConfidence++
+			} else {
+				priceListings = append(priceListings, l)
+			}
+		} else if l.Status != models.ListingStatusRemoved {
+			report.UnknownPrice++
 		}
 		if l.Rating > 0 {
-			ratedListings = append(ratedListings, l)
+			if s.lowConfidence(l.RatingConfidence) {
+				report.LowConfidence++
+			} else {
+				ratedListings = append(ratedListings, l)
+			}
+		} else if l.IsNewListing && l.Status != models.ListingStatusRemoved {
+			report.NewListings++
 		}
 		if l.Location != "" {
 			report.ListingsByLocation[l.Location]++
 		}
 	}
 
-	// Price stats (only listings with price > 0)
+	// Price stats (only listings with a known price)
 	if len(priceListings) > 0 {
 		report.MinPrice = priceListings[0].Price
 		report.MaxPrice = priceListings[0].Price
+		report.MostExpensive = priceListings[0]
 		var total float64
 		for _, l := range priceListings {
 			total += l.Price
@@ -66,6 +123,18 @@ func (s *InsightService) Generate(listings []*models.Listing) *models.InsightRep
 		report.MaxPrice = round2(report.MaxPrice)
 	}
 
+	report.CityComparison = compareCities(listings)
+	report.Landmarks = landmarkStats(listings, s.landmarks, s.landmarkRadiusKm)
+	report.Clusters = clusterListings(listings, s.clusterEpsKm, s.clusterMinPoints)
+
+	// Top 5 by composite score (only when scoring has run)
+	for _, l := range Rank(listings) {
+		if l.Score <= 0 || len(report.TopScored) == 5 {
+			break
+		}
+		report.TopScored = append(report.TopScored, l)
+	}
+
 	// Top 5 by rating
 	sort.Slice(ratedListings, func(i, j int) bool {
 		return ratedListings[i].Rating > ratedListings[j].Rating
@@ -79,62 +148,237 @@ func (s *InsightService) Generate(listings []*models.Listing) *models.InsightRep
 	return report
 }
 
+// GeneratePerCity builds one report per target city, in the given order,
+// from listings tagged by the multi-city orchestrator.
+func (s *InsightService) GeneratePerCity(listings []*models.Listing, cities []string) []*models.InsightReport {
+	byCity := make(map[string][]*models.Listing)
+	for _, l := range listings {
+		if l.TargetCity != "" {
+			byCity[l.TargetCity] = append(byCity[l.TargetCity], l)
+		}
+	}
+
+	reports := make([]*models.InsightReport, 0, len(cities))
+	for _, city := range cities {
+		r := s.Generate(byCity[city])
+		r.Scope = city
+		reports = append(reports, r)
+	}
+	return reports
+}
+
+// Print renders the report for the terminal, to the output set by
+// SetOutput.
 func (s *InsightService) Print(r *models.InsightReport) {
 	sep := strings.Repeat("═", 54)
 	thin := strings.Repeat("─", 54)
 
-	fmt.Printf("\n\033[1;35m%s\033[0m\n", sep)
-	fmt.Printf("\033[1;35m  📊 AIRBNB SCRAPE INSIGHTS\033[0m\n")
-	fmt.Printf("\033[1;35m%s\033[0m\n\n", sep)
+	s.printf("\n\033[1;35m%s\033[0m\n", sep)
+	if r.Scope != "" {
+		s.printf("\033[1;35m  📊 AIRBNB SCRAPE INSIGHTS — %s\033[0m\n", r.Scope)
+	} else {
+		s.printf("\033[1;35m  📊 AIRBNB SCRAPE INSIGHTS\033[0m\n")
+	}
+	s.printf("\033[1;35m%s\033[0m\n\n", sep)
+
+	// Run health
+	if len(r.Anomalies) > 0 {
+		s.printf("\033[1;31m  ⚠  Run flagged as SUSPECT — possible parsing breakage\033[0m\n")
+		s.printf("  %s\n", thin)
+		for _, a := range r.Anomalies {
+			s.printf("  • %s\n", a)
+		}
+		s.printf("\n")
+	}
 
 	// Overview
-	fmt.Printf("\033[1;33m  Overview\033[0m\n")
-	fmt.Printf("  %s\n", thin)
-	fmt.Printf("  Total listings scraped : \033[1m%d\033[0m\n", r.TotalListings)
-	fmt.Printf("  Airbnb listings        : \033[1m%d\033[0m\n", r.AirbnbListings)
-	fmt.Println()
+	s.printf("\033[1;33m  Overview\033[0m\n")
+	s.printf("  %s\n", thin)
+	s.printf("  Total listings scraped : \033[1m%d\033[0m\n", r.TotalListings)
+	s.printf("  Airbnb listings        : \033[1m%d\033[0m\n", r.AirbnbListings)
+	if r.Removed > 0 {
+		s.printf("  Removed listings       : \033[1m%d\033[0m (no longer available, excluded)\n", r.Removed)
+	}
+	if r.UnknownPrice > 0 {
+		s.printf("  Unknown price          : \033[1m%d\033[0m (excluded from price stats)\n", r.UnknownPrice)
+	}
+	if r.NewListings > 0 {
+		s.printf("  New listings           : \033[1m%d\033[0m (not rated yet, excluded from ratings)\n", r.NewListings)
+	}
+	if r.LowConfidence > 0 {
+		s.printf("  Low-confidence values  : \033[1m%d\033[0m (excluded from stats)\n", r.LowConfidence)
+	}
+	if len(r.StatusCounts) > 0 {
+		parts := make([]string, 0, len(models.ListingStatuses))
+		for _, st := range models.ListingStatuses {
+			parts = append(parts, fmt.Sprintf("%s %d", st, r.StatusCounts[st]))
+		}
+		s.printf("  Listing lifecycle      : %s\n", strings.Join(parts, " | "))
+	}
+	s.printf("\n")
 
 	// Price Stats
-	fmt.Printf("\033[1;33m  Price Statistics (per night)\033[0m\n")
-	fmt.Printf("  %s\n", thin)
+	s.printf("\033[1;33m  Price Statistics (per night)\033[0m\n")
+	s.printf("  %s\n", thin)
 	if r.AveragePrice > 0 {
-		fmt.Printf("  Average price : \033[1;32m$%.2f\033[0m\n", r.AveragePrice)
-		fmt.Printf("  Minimum price : \033[1;32m$%.2f\033[0m\n", r.MinPrice)
-		fmt.Printf("  Maximum price : \033[1;32m$%.2f\033[0m\n", r.MaxPrice)
+		s.printf("  Average price : \033[1;32m%s\033[0m\n", s.money.Format(r.AveragePrice))
+		s.printf("  Minimum price : \033[1;32m%s\033[0m\n", s.money.Format(r.MinPrice))
+		s.printf("  Maximum price : \033[1;32m%s\033[0m\n", s.money.Format(r.MaxPrice))
 	} else {
-		fmt.Printf("  No price data available\n")
+		s.printf("  No price data available\n")
 	}
-	fmt.Println()
+	s.printf("\n")
 
 	// Most Expensive
 	if r.MostExpensive != nil {
-		fmt.Printf("\033[1;33m  Most Expensive Listing\033[0m\n")
-		fmt.Printf("  %s\n", thin)
-		fmt.Printf("  %s\n", truncate(r.MostExpensive.Title, 50))
-		fmt.Printf("  Location : %s\n", r.MostExpensive.Location)
-		fmt.Printf("  Price    : \033[1;31m$%.2f/night\033[0m\n", r.MostExpensive.Price)
-		fmt.Println()
+		s.printf("\033[1;33m  Most Expensive Listing\033[0m\n")
+		s.printf("  %s\n", thin)
+		s.printf("  %s\n", truncate(r.MostExpensive.Title, 50))
+		s.printf("  Location : %s\n", r.MostExpensive.Location)
+		s.printf("  Price    : \033[1;31m%s/night\033[0m\n", s.money.Format(r.MostExpensive.Price))
+		s.printf("\n")
 	}
 
 	// ── TOP 5 HIGHEST RATED ──────────────────────────────────────────────
-	fmt.Printf("\033[1;33m  Top 5 Highest Rated Properties\033[0m\n")
-	fmt.Printf("  %s\n", thin)
+	s.printf("\033[1;33m  Top 5 Highest Rated Properties\033[0m\n")
+	s.printf("  %s\n", thin)
 	if len(r.TopRated) == 0 {
-		fmt.Printf("  No rated listings found\n")
+		s.printf("  No rated listings found\n")
 	} else {
 		for i, l := range r.TopRated {
 			title := truncate(l.Title, 38)
-			fmt.Printf("  \033[1m%d.\033[0m %-40s \033[1;32m%.2f ★\033[0m\n",
+			s.printf("  \033[1m%d.\033[0m %-40s \033[1;32m%.2f ★\033[0m\n",
 				i+1, title, l.Rating)
 		}
 	}
-	fmt.Println()
+	s.printf("\n")
+
+	// Top by composite score
+	if len(r.TopScored) > 0 {
+		s.printf("\033[1;33m  Top 5 by Composite Score\033[0m\n")
+		s.printf("  %s\n", thin)
+		for i, l := range r.TopScored {
+			s.printf("  \033[1m%d.\033[0m %-40s \033[1;36m%6.2f\033[0m\n",
+				i+1, truncate(l.Title, 38), l.Score)
+		}
+		s.printf("\n")
+	}
+
+	// Near landmarks
+	for _, lm := range r.Landmarks {
+		s.printf("\033[1;33m  Within %.1f km of %s\033[0m\n", lm.RadiusKm, lm.Name)
+		s.printf("  %s\n", thin)
+		if lm.Count == 0 {
+			s.printf("  No listings with coordinates in range\n\n")
+			continue
+		}
+		s.printf("  Listings : \033[1m%d\033[0m | Average price : %s\n", lm.Count, s.formatPrice(lm.AveragePrice))
+		for _, n := range lm.Nearest {
+			s.printf("  %-40s %5.2f km  %s\n", truncate(n.Listing.Title, 40), n.DistanceKm, s.formatPrice(n.Listing.Price))
+		}
+		s.printf("\n")
+	}
+
+	// Neighbourhood clusters
+	if len(r.Clusters) > 0 {
+		s.printf("\033[1;33m  Neighbourhood Clusters\033[0m\n")
+		s.printf("  %s\n", thin)
+		s.printf("  %-24s %5s %9s %9s %19s\n", "Area", "Count", "Average", "Median", "Range")
+		for _, c := range r.Clusters {
+			s.printf("  %-24s %5d %9s %9s %19s\n",
+				truncate(c.Label, 24), c.Size,
+				s.formatPrice(c.AveragePrice), s.formatPrice(c.MedianPrice),
+				s.formatPrice(c.MinPrice)+" – "+s.formatPrice(c.MaxPrice))
+		}
+		s.printf("\n")
+	}
+
+	// Price forecasts
+	if len(r.Forecasts) > 0 {
+		s.printf("\033[1;33m  Average Price Forecast (next %d weeks)\033[0m\n", len(r.Forecasts[0].Weeks))
+		s.printf("  %s\n", thin)
+		for _, f := range r.Forecasts {
+			cells := make([]string, len(f.Weeks))
+			for i, w := range f.Weeks {
+				cells[i] = s.formatPrice(w)
+			}
+			s.printf("  %-20s now %s → %s  (%s, %dw)\n",
+				truncate(f.Location, 20), s.formatPrice(f.LastPrice),
+				strings.Join(cells, " "), f.Method, f.History)
+		}
+		s.printf("\n")
+	}
+
+	// Inventory churn
+	if len(r.Churn) > 0 {
+		s.printf("\033[1;33m  Inventory Churn (by week)\033[0m\n")
+		s.printf("  %s\n", thin)
+		s.printf("  %-10s  %-24s %5s %11s\n", "Week", "Location", "New", "Disappeared")
+		for _, c := range r.Churn {
+			s.printf("  %-10s  %-24s %5d %11d\n",
+				c.Week.Format("2006-01-02"), truncate(c.Location, 24), c.New, c.Disappeared)
+		}
+		s.printf("\n")
+	}
+
+	// Price recommendations
+	if len(r.Recommendations) > 0 {
+		s.printf("\033[1;33m  Price Recommendations\033[0m\n")
+		s.printf("  %s\n", thin)
+		s.printf("  %-28s %9s %9s %19s %5s  %s\n", "Listing", "Current", "Suggest", "Range", "Comps", "Basis")
+		for _, rec := range r.Recommendations {
+			name := rec.Title
+			if name == "" {
+				name = rec.URL
+			}
+			s.printf("  %-28s %9s %9s %19s %5d  %s\n",
+				truncate(name, 28), s.formatPrice(rec.Current), s.formatPrice(rec.Suggested),
+				s.formatPrice(rec.Low)+" – "+s.formatPrice(rec.High), rec.Comparables, rec.Basis)
+		}
+		s.printf("\n")
+	}
+
+	// Estimated occupancy
+	if len(r.LocationOccupancy) > 0 {
+		calendar := 0
+		for _, e := range r.Occupancy {
+			if e.Method == "calendar" {
+				calendar++
+			}
+		}
+		s.printf("\033[1;33m  Estimated Occupancy (%d listings, %d from calendars)\033[0m\n", len(r.Occupancy), calendar)
+		s.printf("  %s\n", thin)
+		s.printf("  %-24s %8s %11s %9s\n", "Location", "Listings", "Reviews/mo", "Occupancy")
+		for _, lo := range r.LocationOccupancy {
+			velocity := "—"
+			if lo.ReviewsPerMonth > 0 {
+				velocity = fmt.Sprintf("%.1f", lo.ReviewsPerMonth)
+			}
+			s.printf("  %-24s %8d %11s %8.0f%%\n",
+				truncate(lo.Location, 24), lo.Listings, velocity, lo.Occupancy*100)
+		}
+		s.printf("\n")
+	}
+
+	// City Comparison
+	if len(r.CityComparison) > 1 {
+		s.printf("\033[1;33m  City Comparison\033[0m\n")
+		s.printf("  %s\n", thin)
+		s.printf("  %-18s %6s %9s %9s %7s\n", "City", "Count", "Median", "Top 25%", "Rating")
+		for _, c := range r.CityComparison {
+			s.printf("  %-18s %6d %9s %9s %7s\n",
+				truncate(c.City, 18), c.Inventory,
+				s.formatPrice(c.MedianPrice), s.formatPrice(c.UpperQuartilePrice),
+				formatRating(c.AverageRating))
+		}
+		s.printf("\n")
+	}
 
 	// Listings by Location
-	fmt.Printf("\033[1;33m  Listings by Location\033[0m\n")
-	fmt.Printf("  %s\n", thin)
+	s.printf("\033[1;33m  Listings by Location\033[0m\n")
+	s.printf("  %s\n", thin)
 	if len(r.ListingsByLocation) == 0 {
-		fmt.Printf("  No location data\n")
+		s.printf("  No location data\n")
 	} else {
 		// Sort locations by count descending
 		type locCount struct {
@@ -148,15 +392,114 @@ func (s *InsightService) Print(r *models.InsightReport) {
 			}
 		}
 		sort.Slice(locs, func(i, j int) bool {
-			return locs[i].count > locs[j].count
+			if locs[i].count != locs[j].count {
+				return locs[i].count > locs[j].count
+			}
+			return locs[i].loc < locs[j].loc
 		})
 		for _, lc := range locs {
 			bar := strings.Repeat("█", lc.count)
-			fmt.Printf("  %-30s %s (%d)\n", truncate(lc.loc, 28), bar, lc.count)
+			s.printf("  %-30s %s (%d)\n", truncate(lc.loc, 28), bar, lc.count)
+		}
+	}
+
+	s.printf("\n\033[1;35m%s\033[0m\n\n", sep)
+}
+
+// compareCities groups listings by target city (or by location when no city
+// tags are present) and computes per-group price and rating statistics.
+// Groups are ordered by inventory, largest first.
+func compareCities(listings []*models.Listing) []*models.CityStats {
+	byTarget := false
+	for _, l := range listings {
+		if l.TargetCity != "" {
+			byTarget = true
+			break
 		}
 	}
 
-	fmt.Printf("\n\033[1;35m%s\033[0m\n\n", sep)
+	type group struct {
+		prices      []float64
+		ratingSum   float64
+		ratingCount int
+		inventory   int
+	}
+	groups := make(map[string]*group)
+	for _, l := range listings {
+		key := l.Location
+		if byTarget {
+			key = l.TargetCity
+		}
+		if key == "" {
+			continue
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &group{}
+			groups[key] = g
+		}
+		g.inventory++
+		if l.HasPrice() {
+			g.prices = append(g.prices, l.Price)
+		}
+		if l.Rating > 0 {
+			g.ratingSum += l.Rating
+			g.ratingCount++
+		}
+	}
+
+	out := make([]*models.CityStats, 0, len(groups))
+	for city, g := range groups {
+		sort.Float64s(g.prices)
+		stats := &models.CityStats{
+			City:               city,
+			Inventory:          g.inventory,
+			MedianPrice:        round2(percentile(g.prices, 0.5)),
+			UpperQuartilePrice: round2(percentile(g.prices, 0.75)),
+		}
+		if g.ratingCount > 0 {
+			stats.AverageRating = round2(g.ratingSum / float64(g.ratingCount))
+		}
+		out = append(out, stats)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Inventory != out[j].Inventory {
+			return out[i].Inventory > out[j].Inventory
+		}
+		return out[i].City < out[j].City
+	})
+	return out
+}
+
+// percentile returns the p-th percentile (0..1) of sorted values using
+// linear interpolation between closest ranks. Empty input yields 0.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p * float64(len(sorted)-1)
+	lo := int(rank)
+	if lo >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	frac := rank - float64(lo)
+	return sorted[lo] + frac*(sorted[lo+1]-sorted[lo])
+}
+
+// formatPrice writes a nightly price in the report currency (see
+// SetMoney); unknown prices show as "—".
+func (s *InsightService) formatPrice(p float64) string {
+	if p <= 0 {
+		return "—"
+	}
+	return s.money.Format(p)
+}
+
+func formatRating(r float64) string {
+	if r <= 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%.2f", r)
 }
 
 func round2(f float64) float64 {
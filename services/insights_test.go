@@ -3,6 +3,7 @@ package services
 import (
 	"testing"
 
+	"airbnb-scraper/config"
 	"airbnb-scraper/models"
 	"airbnb-scraper/utils"
 )
@@ -43,6 +44,56 @@ func TestInsightPrices(t *testing.T) {
 	}
 }
 
+func TestInsightSeparatesFreeFromUnknownPrices(t *testing.T) {
+	listings := sampleListings() // Flat E has no price
+	listings = append(listings, &models.Listing{Platform: "airbnb", Title: "Free G", PriceKnown: true,
+		Location: "Tokyo", URL: "https://airbnb.com/rooms/7"})
+
+	r := NewInsightService(utils.NewLogger()).Generate(listings)
+	if r.UnknownPrice != 1 {
+		t.Errorf("UnknownPrice: got %d, want 1", r.UnknownPrice)
+	}
+	if r.MinPrice != 0 || r.AveragePrice != 134 {
+		t.Errorf("MinPrice %.2f, AveragePrice %.2f: want the free stay counted (0, 134)", r.MinPrice, r.AveragePrice)
+	}
+}
+
+func TestInsightCountsNewListingsApart(t *testing.T) {
+	listings := append(sampleListings(), &models.Listing{Platform: "airbnb", Title: "New H", Price: 90,
+		IsNewListing: true, Location: "Tokyo", URL: "https://airbnb.com/rooms/8"})
+
+	r := NewInsightService(utils.NewLogger()).Generate(listings)
+	if r.NewListings != 1 {
+		t.Errorf("NewListings: got %d, want 1", r.NewListings)
+	}
+	for _, l := range r.TopRated {
+		if l.IsNewListing {
+			t.Errorf("TopRated includes unrated %s", l.Title)
+		}
+	}
+}
+
+func TestInsightExcludesLowConfidenceValues(t *testing.T) {
+	listings := sampleListings()
+	listings[3].PriceConfidence = models.ConfidenceLow  // Cabin D, $300
+	listings[0].RatingConfidence = models.ConfidenceLow // Villa A, 4.9 ★
+	listings[1].PriceConfidence = models.ConfidenceHigh
+
+	svc := NewInsightService(utils.NewLogger())
+	svc.SetMinConfidence(0.5)
+	r := svc.Generate(listings)
+
+	if r.LowConfidence != 2 {
+		t.Errorf("LowConfidence: got %d, want 2", r.LowConfidence)
+	}
+	if r.MaxPrice != 200 {
+		t.Errorf("MaxPrice: got %.2f, want 200 (low-confidence $300 excluded)", r.MaxPrice)
+	}
+	if r.TopRated[0].Title != "Loft C" {
+		t.Errorf("TopRated[0]: got %q, want %q", r.TopRated[0].Title, "Loft C")
+	}
+}
+
 func TestInsightMostExpensive(t *testing.T) {
 	svc := NewInsightService(utils.NewLogger())
 	r := svc.Generate(sampleListings())
@@ -54,6 +105,16 @@ func TestInsightMostExpensive(t *testing.T) {
 	}
 }
 
+func TestInsightMostExpensiveFirst(t *testing.T) {
+	svc := NewInsightService(utils.NewLogger())
+	listings := sampleListings()
+	listings[0], listings[3] = listings[3], listings[0] // Cabin D, the priciest, first
+	r := svc.Generate(listings)
+	if r.MostExpensive == nil || r.MostExpensive.Title != "Cabin D" {
+		t.Errorf("MostExpensive = %v, want Cabin D", r.MostExpensive)
+	}
+}
+
 func TestInsightTopRated(t *testing.T) {
 	svc := NewInsightService(utils.NewLogger())
 	r := svc.Generate(sampleListings())
@@ -83,3 +144,101 @@ func TestInsightEmptyInput(t *testing.T) {
 		t.Errorf("expected 0 total listings for empty input")
 	}
 }
+
+func TestInsightPerCityReports(t *testing.T) {
+	listings := sampleListings()
+	for i, l := range listings {
+		if i < 3 {
+			l.TargetCity = "Bangkok"
+		} else {
+			l.TargetCity = "Tokyo"
+		}
+	}
+
+	svc := NewInsightService(utils.NewLogger())
+	reports := svc.GeneratePerCity(listings, []string{"Tokyo", "Bangkok", "Paris"})
+	if len(reports) != 3 {
+		t.Fatalf("reports: got %d, want 3", len(reports))
+	}
+	if reports[0].Scope != "Tokyo" || reports[0].TotalListings != 2 {
+		t.Errorf("Tokyo report: scope %q, total %d", reports[0].Scope, reports[0].TotalListings)
+	}
+	if reports[1].TotalListings != 3 {
+		t.Errorf("Bangkok total: got %d, want 3", reports[1].TotalListings)
+	}
+	if reports[2].TotalListings != 0 {
+		t.Errorf("Paris total: got %d, want 0", reports[2].TotalListings)
+	}
+}
+
+func TestInsightCityComparison(t *testing.T) {
+	svc := NewInsightService(utils.NewLogger())
+	r := svc.Generate(sampleListings())
+
+	if len(r.CityComparison) != 3 {
+		t.Fatalf("CityComparison len: got %d, want 3", len(r.CityComparison))
+	}
+	bkk := r.CityComparison[0]
+	if bkk.City != "Bangkok" || bkk.Inventory != 2 {
+		t.Errorf("first row: got %s/%d, want Bangkok/2", bkk.City, bkk.Inventory)
+	}
+	if bkk.MedianPrice != 125 || bkk.UpperQuartilePrice != 162.5 {
+		t.Errorf("Bangkok prices: median %.2f, p75 %.2f", bkk.MedianPrice, bkk.UpperQuartilePrice)
+	}
+	if bkk.AverageRating != 4.7 {
+		t.Errorf("Bangkok avg rating: got %.2f, want 4.7", bkk.AverageRating)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	vals := []float64{10, 20, 30, 40}
+	if got := percentile(vals, 0.5); got != 25 {
+		t.Errorf("median: got %.2f, want 25", got)
+	}
+	if got := percentile(vals, 0.75); got != 32.5 {
+		t.Errorf("p75: got %.2f, want 32.5", got)
+	}
+	if got := percentile(nil, 0.5); got != 0 {
+		t.Errorf("empty: got %.2f, want 0", got)
+	}
+}
+
+func TestShortlistFiltersAndRanksByValue(t *testing.T) {
+	svc := NewInsightService(utils.NewLogger())
+	got := svc.Shortlist(sampleListings(), 150, 4.6)
+
+	// Studio B (50, 4.5) fails rating; Villa A/Cabin D exceed budget; Flat E has no price.
+	if len(got) != 1 || got[0].Listing.Title != "Loft C" {
+		t.Fatalf("shortlist: got %d entries", len(got))
+	}
+	if got[0].ValueScore != 4 {
+		t.Errorf("Loft C value score: got %.2f, want 4", got[0].ValueScore)
+	}
+
+	all := svc.Shortlist(sampleListings(), 1000, 0)
+	if len(all) != 3 || all[0].Listing.Title != "Studio B" {
+		t.Errorf("expected Studio B to be best value among %d", len(all))
+	}
+}
+
+func TestInsightLandmarks(t *testing.T) {
+	listings := sampleListings()
+	listings[0].Latitude, listings[0].Longitude = 13.7500, 100.4920 // ~0.1 km
+	listings[1].Latitude, listings[1].Longitude = 13.7600, 100.5000 // ~1.4 km
+	listings[2].Latitude, listings[2].Longitude = 35.6762, 139.6503 // Tokyo
+
+	svc := NewInsightService(utils.NewLogger())
+	svc.SetLandmarks([]config.Landmark{{Name: "Grand Palace", Lat: 13.7500, Lng: 100.4913}}, 2)
+	r := svc.Generate(listings)
+
+	if len(r.Landmarks) != 1 {
+		t.Fatalf("Landmarks len: got %d, want 1", len(r.Landmarks))
+	}
+	lm := r.Landmarks[0]
+	if lm.Count != 2 || lm.AveragePrice != 125 {
+		t.Errorf("Grand Palace: count %d, avg %.2f", lm.Count, lm.AveragePrice)
+	}
+	if lm.Nearest[0].Listing.Title != "Villa A" {
+		t.Errorf("nearest: got %q, want Villa A", lm.Nearest[0].Listing.Title)
+	}
+}
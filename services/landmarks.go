@@ -0,0 +1,54 @@
+package services
+
+import (
+	"sort"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// SetLandmarks configures the points of interest reported by Generate.
+func (s *InsightService) SetLandmarks(landmarks []config.Landmark, radiusKm float64) {
+	s.landmarks = landmarks
+	s.landmarkRadiusKm = radiusKm
+}
+
+// landmarkStats computes, for each landmark, the listings with coordinates
+// that lie within radiusKm of it.
+func landmarkStats(listings []*models.Listing, landmarks []config.Landmark, radiusKm float64) []*models.LandmarkStats {
+	out := make([]*models.LandmarkStats, 0, len(landmarks))
+	for _, lm := range landmarks {
+		stats := &models.LandmarkStats{Name: lm.Name, RadiusKm: radiusKm}
+		var within []*models.LandmarkDistance
+		var priceSum float64
+		var priced int
+
+		for _, l := range listings {
+			if l.Latitude == 0 && l.Longitude == 0 {
+				continue
+			}
+			d := utils.HaversineKm(lm.Lat, lm.Lng, l.Latitude, l.Longitude)
+			if d > radiusKm {
+				continue
+			}
+			within = append(within, &models.LandmarkDistance{Listing: l, DistanceKm: round2(d)})
+			if l.HasPrice() {
+				priceSum += l.Price
+				priced++
+			}
+		}
+
+		sort.Slice(within, func(i, j int) bool { return within[i].DistanceKm < within[j].DistanceKm })
+		stats.Count = len(within)
+		if priced > 0 {
+			stats.AveragePrice = round2(priceSum / float64(priced))
+		}
+		if len(within) > 5 {
+			within = within[:5]
+		}
+		stats.Nearest = within
+		out = append(out, stats)
+	}
+	return out
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"airbnb-scraper/models"
+)
+
+// minLocationSimilarity is the lowest edit-distance similarity (1 = equal)
+// at which a location still matches a misspelt query.
+const minLocationSimilarity = 0.8
+
+// accentFolds maps accented Latin letters to their base letter, so "Phú
+// Quốc" and "Phu Quoc" share a key.
+var accentFolds = map[rune]rune{}
+
+func init() {
+	for base, accented := range map[rune]string{
+		'a': "àáâãäåāăą", 'c': "çćč", 'd': "ďđ", 'e': "èéêëēėęěẹếềểễệ", 'g': "ğ",
+		'i': "ìíîïīįı", 'l': "łľ", 'n': "ñńň", 'o': "òóôõöøōőơọốồổỗộớờởỡợ", 'r': "řŕ",
+		's': "śšş", 't': "ťţ", 'u': "ùúûüūůűųưụứừửữự", 'y': "ýÿỳỷỹ", 'z': "źżž",
+	} {
+		for _, r := range accented {
+			accentFolds[r] = base
+		}
+	}
+}
+
+// LocationKey folds a location string for matching: lowercase, accents
+// removed and everything but letters and digits dropped, so "Bang Rak",
+// "bang-rak" and "BANGRAK" are the same location.
+func LocationKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if f, ok := accentFolds[r]; ok {
+			r = f
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// KnownLocations groups the listings' locations by LocationKey, most
+// listings first. Each is named by its most common spelling.
+func KnownLocations(listings []*models.Listing) []*models.LocationSuggestion {
+	spellings := make(map[string]map[string]int)
+	for _, l := range listings {
+		name := normaliseText(l.Location)
+		key := LocationKey(name)
+		if key == "" {
+			continue
+		}
+		if spellings[key] == nil {
+			spellings[key] = make(map[string]int)
+		}
+		spellings[key][name]++
+	}
+
+	out := make([]*models.LocationSuggestion, 0, len(spellings))
+	for _, names := range spellings {
+		variants := make([]string, 0, len(names))
+		total := 0
+		for name, n := range names {
+			variants = append(variants, name)
+			total += n
+		}
+		sort.Slice(variants, func(i, j int) bool {
+			if names[variants[i]] != names[variants[j]] {
+				return names[variants[i]] > names[variants[j]]
+			}
+			return variants[i] < variants[j]
+		})
+		s := &models.LocationSuggestion{Name: variants[0], Count: total}
+		if len(variants) > 1 {
+			s.Variants = variants[1:]
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// MatchLocations returns the known locations matching query, best first,
+// at most limit of them (0 = all). A location matches when its key, or the
+// key of one of its comma-separated parts, equals the query's (score 1),
+// starts with it (0.9) or contains it (0.8), or else is within edit
+// distance of it (similarity scaled to at most 0.75), so "bangrak" finds
+// "Bang Rak" and "bankok" finds "Sukhumvit, Bangkok".
+func MatchLocations(known []*models.LocationSuggestion, query string, limit int) []*models.LocationSuggestion {
+	q := LocationKey(query)
+	if q == "" {
+		return nil
+	}
+	var out []*models.LocationSuggestion
+	for _, k := range known {
+		score := 0.0
+		keys := []string{LocationKey(k.Name)}
+		for _, part := range strings.Split(k.Name, ",") {
+			keys = append(keys, LocationKey(part))
+		}
+		for _, key := range keys {
+			score = max(score, locationScore(key, q))
+		}
+		if score > 0 {
+			m := *k
+			m.Score = round2(score)
+			out = append(out, &m)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Count > out[j].Count
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// locationScore rates how well the location key matches the query key.
+func locationScore(key, q string) float64 {
+	switch {
+	case key == "":
+		return 0
+	case key == q:
+		return 1
+	case strings.HasPrefix(key, q):
+		return 0.9
+	case strings.Contains(key, q):
+		return 0.8
+	}
+	a, b := []rune(key), []rune(q)
+	sim := 1 - float64(editDistance(a, b))/float64(max(len(a), len(b)))
+	if sim < minLocationSimilarity {
+		return 0
+	}
+	return sim * 0.75
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+
+	"airbnb-scraper/models"
+)
+
+func TestKnownLocationsGroupsSpellings(t *testing.T) {
+	var listings []*models.Listing
+	for _, loc := range []string{"Bang Rak", "Bang Rak", "bang-rak", "BANGRAK", "Sukhumvit, Bangkok", "", "Phú Quốc"} {
+		listings = append(listings, &models.Listing{Location: loc})
+	}
+	got := KnownLocations(listings)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 locations, got %+v", got)
+	}
+	if got[0].Name != "Bang Rak" || got[0].Count != 4 || len(got[0].Variants) != 2 {
+		t.Errorf("Bang Rak: got %+v", got[0])
+	}
+	if LocationKey("Phu Quoc") != LocationKey("Phú Quốc") {
+		t.Errorf("accents not folded: %q", LocationKey("Phú Quốc"))
+	}
+}
+
+func TestMatchLocations(t *testing.T) {
+	known := []*models.LocationSuggestion{
+		{Name: "Sukhumvit, Bangkok", Count: 9},
+		{Name: "Bang Rak", Count: 4},
+		{Name: "Bangkok", Count: 2},
+		{Name: "Chiang Mai", Count: 7},
+	}
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"bangrak", []string{"Bang Rak"}},
+		{"Bangkok", []string{"Sukhumvit, Bangkok", "Bangkok"}}, // exact part, then by count
+		{"bankok", []string{"Sukhumvit, Bangkok", "Bangkok"}},
+		{"chiang", []string{"Chiang Mai"}},
+		{"", nil},
+	}
+	for _, c := range cases {
+		got := MatchLocations(known, c.query, 0)
+		var names []string
+		for _, m := range got {
+			names = append(names, m.Name)
+		}
+		if len(names) != len(c.want) {
+			t.Errorf("%q: got %v, want %v", c.query, names, c.want)
+			continue
+		}
+		for i := range names {
+			if names[i] != c.want[i] {
+				t.Errorf("%q: got %v, want %v", c.query, names, c.want)
+				break
+			}
+		}
+	}
+	if got := MatchLocations(known, "bang", 1); len(got) != 1 || got[0].Score != 0.9 {
+		t.Errorf("limit 1: got %+v", got)
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+// minReviewSpan is the shortest stretch between a listing's first and last
+// recorded review count that yields a review-velocity estimate; shorter
+// ones are mostly noise.
+const minReviewSpan = 7 * 24 * time.Hour
+
+// OccupancyModel holds the review-velocity assumptions: each booking of
+// AverageStay nights leaves a review with probability ReviewRate, and the
+// resulting occupancy is capped at Cap, since few listings are booked
+// every night.
+type OccupancyModel struct {
+	ReviewRate  float64
+	AverageStay float64
+	Cap         float64
+}
+
+// EstimateOccupancy estimates each stored listing's booked share of nights.
+// A listing `watch` holds a calendar for uses its blocked share from now
+// on; any other uses review velocity — reviews per month between its first
+// and last recorded count, ÷ ReviewRate × AverageStay over a 30-night month.
+// Listings with neither are skipped. Estimates are ordered by location,
+// then occupancy; the per-location figures are their means.
+func (s *InsightService) EstimateOccupancy(reviews map[string][]models.ReviewPoint, calendars map[string][]models.CalendarDay,
+	listings []*models.Listing, m OccupancyModel, now time.Time) ([]*models.OccupancyEstimate, []*models.LocationOccupancy) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var out []*models.OccupancyEstimate
+	for _, l := range listings {
+		if l.Status == models.ListingStatusRemoved {
+			continue
+		}
+		e := &models.OccupancyEstimate{URL: l.URL, Title: l.Title, Location: l.Location}
+		if share, days := occupancy(calendars[l.URL], today, time.Time{}); days > 0 {
+			e.Method, e.Occupancy, e.Days = "calendar", share, days
+		} else if perMonth, days, ok := reviewVelocity(reviews[l.URL]); ok && m.ReviewRate > 0 {
+			e.Method, e.ReviewsPerMonth, e.Days = "reviews", round2(perMonth), days
+			e.Occupancy = perMonth / m.ReviewRate * m.AverageStay / 30
+			if m.Cap > 0 {
+				e.Occupancy = min(e.Occupancy, m.Cap)
+			}
+		} else {
+			continue
+		}
+		e.Occupancy = round2(e.Occupancy)
+		e.NightsPerMonth = round2(e.Occupancy * 30)
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Location != out[j].Location {
+			return out[i].Location < out[j].Location
+		}
+		if out[i].Occupancy != out[j].Occupancy {
+			return out[i].Occupancy > out[j].Occupancy
+		}
+		return out[i].URL < out[j].URL
+	})
+
+	var locations []*models.LocationOccupancy
+	byKey := make(map[string]*models.LocationOccupancy)
+	shares := make(map[string][]float64)
+	velocities := make(map[string][]float64)
+	for _, e := range out {
+		key := strings.ToLower(strings.TrimSpace(e.Location))
+		if key == "" {
+			continue
+		}
+		lo := byKey[key]
+		if lo == nil {
+			lo = &models.LocationOccupancy{Location: e.Location}
+			byKey[key] = lo
+			locations = append(locations, lo)
+		}
+		lo.Listings++
+		shares[key] = append(shares[key], e.Occupancy)
+		if e.Method == "reviews" {
+			velocities[key] = append(velocities[key], e.ReviewsPerMonth)
+		}
+	}
+	for key, lo := range byKey {
+		lo.Occupancy = round2(mean(shares[key]))
+		lo.ReviewsPerMonth = round2(mean(velocities[key]))
+	}
+	sort.Slice(locations, func(i, j int) bool {
+		if locations[i].Listings != locations[j].Listings {
+			return locations[i].Listings > locations[j].Listings
+		}
+		return locations[i].Location < locations[j].Location
+	})
+	s.logger.Info("[insights] Occupancy estimated for %d listings in %d locations", len(out), len(locations))
+	return out, locations
+}
+
+// reviewVelocity is the review count's growth per 30 days between the first
+// and last point, and the days between them. ok is false for fewer than two
+// points, a span under minReviewSpan or a falling count (reviews removed).
+func reviewVelocity(points []models.ReviewPoint) (perMonth float64, days int, ok bool) {
+	if len(points) < 2 {
+		return 0, 0, false
+	}
+	first, last := points[0], points[len(points)-1]
+	span := last.At.Sub(first.At)
+	if span < minReviewSpan || last.Reviews < first.Reviews {
+		return 0, 0, false
+	}
+	perMonth = float64(last.Reviews-first.Reviews) / span.Hours() * 24 * 30
+	return perMonth, int(span.Hours() / 24), true
+}
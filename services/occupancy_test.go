@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+func TestEstimateOccupancy(t *testing.T) {
+	svc := NewInsightService(utils.NewLogger())
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	start := now.AddDate(0, 0, -30)
+	listings := []*models.Listing{
+		{URL: "a", Location: "Bangkok"},
+		{URL: "b", Location: "Bangkok"},
+		{URL: "c", Location: "Bangkok"}, // one recent count only
+		{URL: "d", Location: "Bangkok", Status: models.ListingStatusRemoved},
+		{URL: "e", Location: "Tokyo"},
+	}
+	reviews := map[string][]models.ReviewPoint{
+		"a": {{At: start, Reviews: 10}, {At: now.AddDate(0, 0, -15), Reviews: 11}, {At: now, Reviews: 12}},
+		"b": {{At: start, Reviews: 10}, {At: now, Reviews: 30}},
+		"c": {{At: now, Reviews: 50}},
+		"d": {{At: start, Reviews: 10}, {At: now, Reviews: 20}},
+		"e": {{At: start, Reviews: 40}, {At: now, Reviews: 45}}, // calendar wins
+	}
+	day := func(d int, blocked bool) models.CalendarDay {
+		return models.CalendarDay{Date: time.Date(2024, 3, 30+d, 0, 0, 0, 0, time.UTC), Blocked: blocked}
+	}
+	calendars := map[string][]models.CalendarDay{
+		// The past day is ignored: 1 of the 4 days from today is blocked.
+		"e": {day(0, true), day(1, true), day(2, false), day(3, false), day(4, false)},
+	}
+
+	got, locations := svc.EstimateOccupancy(reviews, calendars, listings, OccupancyModel{ReviewRate: 0.5, AverageStay: 3, Cap: 0.7}, now)
+	if len(got) != 3 {
+		t.Fatalf("expected estimates for a, b and e, got %+v", got)
+	}
+	// b: 20 reviews/month → 40 stays → 120 nights, capped at 0.7.
+	if got[0].URL != "b" || got[0].Occupancy != 0.7 || got[0].ReviewsPerMonth != 20 {
+		t.Errorf("b: got %+v", got[0])
+	}
+	// a: 2 reviews/month → 4 stays → 12 nights of 30.
+	if got[1].URL != "a" || got[1].Occupancy != 0.4 || got[1].NightsPerMonth != 12 || got[1].Days != 30 {
+		t.Errorf("a: got %+v", got[1])
+	}
+	if got[2].URL != "e" || got[2].Method != "calendar" || got[2].Occupancy != 0.25 || got[2].Days != 4 {
+		t.Errorf("e: got %+v", got[2])
+	}
+
+	if len(locations) != 2 || locations[0].Location != "Bangkok" || locations[0].Listings != 2 {
+		t.Fatalf("got locations %+v", locations)
+	}
+	if locations[0].Occupancy != 0.55 || locations[0].ReviewsPerMonth != 11 {
+		t.Errorf("Bangkok: got %+v", locations[0])
+	}
+	if locations[1].ReviewsPerMonth != 0 || locations[1].Occupancy != 0.25 {
+		t.Errorf("Tokyo: got %+v", locations[1])
+	}
+}
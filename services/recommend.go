@@ -0,0 +1,79 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// minComparables is the fewest comparable prices a recommendation needs;
+// below it the percentile band says little.
+const minComparables = 3
+
+// Recommend suggests a nightly price band for each watched listing: the
+// 25th–75th percentile and median of its comparables' prices. Comparables
+// are the stored listings in the same location (other than the listing
+// itself, and without low-confidence prices) plus compSets[url], the
+// current prices of that listing's comp set. Removed listings and listings
+// with fewer than minComparables prices get no recommendation.
+func (s *InsightService) Recommend(watched []*models.WatchState, listings []*models.Listing, compSets map[string][]float64) []*models.PriceRecommendation {
+	byLocation := make(map[string][]*models.Listing)
+	for _, l := range listings {
+		if !l.HasPrice() || l.Status == models.ListingStatusRemoved || s.lowConfidence(l.PriceConfidence) {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(l.Location))
+		byLocation[key] = append(byLocation[key], l)
+	}
+
+	var out []*models.PriceRecommendation
+	for _, w := range watched {
+		if w.Status == models.WatchRemoved {
+			continue
+		}
+		var prices []float64
+		var bases []string
+		for _, p := range compSets[w.URL] {
+			if p > 0 {
+				prices = append(prices, p)
+			}
+		}
+		if len(prices) > 0 {
+			bases = append(bases, "comp set")
+		}
+		local := 0
+		if key := strings.ToLower(strings.TrimSpace(w.Location)); key != "" {
+			id := utils.ListingID(w.URL)
+			for _, l := range byLocation[key] {
+				if id != "" && utils.ListingID(l.URL) == id {
+					continue
+				}
+				prices = append(prices, l.Price)
+				local++
+			}
+		}
+		if local > 0 {
+			bases = append(bases, "location")
+		}
+		if len(prices) < minComparables {
+			continue
+		}
+
+		sort.Float64s(prices)
+		out = append(out, &models.PriceRecommendation{
+			URL:         w.URL,
+			Title:       w.Title,
+			Location:    w.Location,
+			Current:     w.Price,
+			Low:         round2(percentile(prices, 0.25)),
+			Suggested:   round2(percentile(prices, 0.5)),
+			High:        round2(percentile(prices, 0.75)),
+			Comparables: len(prices),
+			Basis:       strings.Join(bases, " + "),
+		})
+	}
+	s.logger.Info("[insights] Price recommendations for %d of %d watched listings", len(out), len(watched))
+	return out
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"testing"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+func TestRecommendUsesLocationAndCompSet(t *testing.T) {
+	svc := NewInsightService(utils.NewLogger())
+	svc.SetMinConfidence(0.5)
+	listings := []*models.Listing{
+		{URL: "https://www.airbnb.com/rooms/1", Location: "Bangkok", Price: 500}, // the watched room itself
+		{URL: "https://www.airbnb.com/rooms/2", Location: "bangkok", Price: 100},
+		{URL: "https://www.airbnb.com/rooms/3", Location: "Bangkok", Price: 200},
+		{URL: "https://www.airbnb.com/rooms/4", Location: "Bangkok", Price: 999, PriceConfidence: 0.2},
+		{URL: "https://www.airbnb.com/rooms/5", Location: "Bangkok", Price: 999, Status: models.ListingStatusRemoved},
+		{URL: "https://www.airbnb.com/rooms/6", Location: "Tokyo", Price: 50},
+	}
+	watched := []*models.WatchState{
+		{URL: "https://www.airbnb.com/rooms/1", Location: "Bangkok", Price: 180, Status: models.WatchAvailable},
+		{URL: "https://www.airbnb.com/rooms/7", Location: "Tokyo", Price: 60, Status: models.WatchAvailable},
+		{URL: "https://www.airbnb.com/rooms/8", Location: "Bangkok", Status: models.WatchRemoved},
+	}
+	compSets := map[string][]float64{"https://www.airbnb.com/rooms/1": {300, 0, 400}}
+
+	got := svc.Recommend(watched, listings, compSets)
+	if len(got) != 1 {
+		t.Fatalf("expected one recommendation (Tokyo has too few comparables), got %+v", got)
+	}
+	r := got[0]
+	// Comparables: 100, 200 (location) and 300, 400 (comp set).
+	if r.Comparables != 4 || r.Basis != "comp set + location" {
+		t.Errorf("got %d comparables, basis %q", r.Comparables, r.Basis)
+	}
+	if r.Low != 175 || r.Suggested != 250 || r.High != 325 || r.Current != 180 {
+		t.Errorf("got band %.2f / %.2f / %.2f (current %.2f)", r.Low, r.Suggested, r.High, r.Current)
+	}
+}
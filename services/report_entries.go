@@ -0,0 +1,30 @@
+package services
+
+import (
+	"sort"
+
+	"airbnb-scraper/models"
+)
+
+// ReportEntries maps the names of the report's listing sections, as used by
+// the `open` command, to the listings they show.
+var ReportEntries = map[string]func(r *models.InsightReport) []*models.Listing{
+	"top-rated":  func(r *models.InsightReport) []*models.Listing { return r.TopRated },
+	"top-scored": func(r *models.InsightReport) []*models.Listing { return r.TopScored },
+	"most-expensive": func(r *models.InsightReport) []*models.Listing {
+		if r.MostExpensive == nil {
+			return nil
+		}
+		return []*models.Listing{r.MostExpensive}
+	},
+}
+
+// ReportEntryNames lists the ReportEntries keys in sorted order.
+func ReportEntryNames() []string {
+	names := make([]string, 0, len(ReportEntries))
+	for name := range ReportEntries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
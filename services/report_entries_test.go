@@ -0,0 +1,26 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"airbnb-scraper/models"
+)
+
+func TestReportEntries(t *testing.T) {
+	a, b := &models.Listing{Title: "A"}, &models.Listing{Title: "B"}
+	r := &models.InsightReport{TopRated: []*models.Listing{a, b}, TopScored: []*models.Listing{b}, MostExpensive: a}
+
+	if got := ReportEntries["top-rated"](r); !reflect.DeepEqual(got, []*models.Listing{a, b}) {
+		t.Errorf("top-rated = %v", got)
+	}
+	if got := ReportEntries["most-expensive"](r); len(got) != 1 || got[0] != a {
+		t.Errorf("most-expensive = %v", got)
+	}
+	if got := ReportEntries["most-expensive"](&models.InsightReport{}); got != nil {
+		t.Errorf("empty report most-expensive = %v, want nil", got)
+	}
+	if names := ReportEntryNames(); !reflect.DeepEqual(names, []string{"most-expensive", "top-rated", "top-scored"}) {
+		t.Errorf("names = %v", names)
+	}
+}
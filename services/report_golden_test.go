@@ -0,0 +1,126 @@
+package services
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata from the current output")
+
+// checkGolden compares got with testdata/name, or rewrites the file under
+// -update so the change shows up as a diff to review.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test ./services -run %s -update to create it)", err, t.Name())
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s; run go test ./services -run %s -update and review the diff\n--- got ---\n%s",
+			path, t.Name(), got)
+	}
+}
+
+// goldenReport fills every section Print can show.
+func goldenReport() *models.InsightReport {
+	villa := &models.Listing{Title: "Riverside Villa with Private Pool and Rooftop Terrace", Location: "Bang Rak", Price: 420, Rating: 4.97, Score: 91.5}
+	loft := &models.Listing{Title: "Sukhumvit Loft", Location: "Khlong Toei", Price: 85, Rating: 4.88, Score: 84.25}
+	studio := &models.Listing{Title: "Old Town Studio", Location: "Phra Nakhon", Price: 42.5, Rating: 4.71, Score: 77}
+	return &models.InsightReport{
+		TotalListings:  42,
+		AirbnbListings: 40,
+		AveragePrice:   1234.5,
+		MinPrice:       42.5,
+		MaxPrice:       420,
+		MostExpensive:  villa,
+		TopRated:       []*models.Listing{villa, loft, studio},
+		TopScored:      []*models.Listing{villa, loft, studio},
+		ListingsByLocation: map[string]int{
+			"Bang Rak": 3, "Khlong Toei": 2, "Phra Nakhon": 2, "Sathon": 1, "": 4,
+		},
+		CityComparison: []*models.CityStats{
+			{City: "Bangkok", Inventory: 30, MedianPrice: 65, UpperQuartilePrice: 110, AverageRating: 4.81},
+			{City: "Chiang Mai", Inventory: 12, MedianPrice: 38, UpperQuartilePrice: 52.5},
+		},
+		Landmarks: []*models.LandmarkStats{
+			{Name: "Grand Palace", RadiusKm: 1.5, Count: 2, AveragePrice: 63.75, Nearest: []*models.LandmarkDistance{
+				{Listing: studio, DistanceKm: 0.42}, {Listing: loft, DistanceKm: 1.38},
+			}},
+			{Name: "Airport", RadiusKm: 2},
+		},
+		Clusters: []*models.ClusterStats{
+			{Label: "Bang Rak", Size: 3, AveragePrice: 180, MedianPrice: 95, MinPrice: 60, MaxPrice: 420},
+		},
+		Forecasts: []*models.PriceForecast{
+			{Location: "Bang Rak", Method: "holt", History: 8, LastPrice: 95, Weeks: []float64{96, 97.5, 99}},
+		},
+		Churn: []*models.LocationChurn{
+			{Location: "Bang Rak", Week: time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC), New: 3, Disappeared: 1},
+			{Location: "Khlong Toei", Week: time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC), Disappeared: 2},
+			{Location: "Bang Rak", Week: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), New: 1},
+		},
+		Recommendations: []*models.PriceRecommendation{
+			{URL: "https://www.airbnb.com/rooms/1", Title: "Sukhumvit Loft", Current: 85, Low: 70, Suggested: 80, High: 92, Comparables: 5, Basis: "comp set"},
+			{URL: "https://www.airbnb.com/rooms/2", Low: 40, Suggested: 45, High: 52, Comparables: 3, Basis: "location"},
+		},
+		Occupancy: []*models.OccupancyEstimate{
+			{Location: "Bang Rak", Method: "calendar", Occupancy: 0.72},
+			{Location: "Khlong Toei", Method: "reviews", ReviewsPerMonth: 2.5, Occupancy: 0.5},
+		},
+		LocationOccupancy: []*models.LocationOccupancy{
+			{Location: "Bang Rak", Listings: 1, Occupancy: 0.72},
+			{Location: "Khlong Toei", Listings: 1, ReviewsPerMonth: 2.5, Occupancy: 0.5},
+		},
+		Anomalies:     []string{"listing count dropped 60% from the previous run"},
+		LowConfidence: 3,
+		Removed:       2,
+		StatusCounts:  map[string]int{"active": 38, "stale": 2, "removed": 2},
+	}
+}
+
+func TestPrintGolden(t *testing.T) {
+	defer utils.SetStyle(true, true)
+	cases := []struct {
+		name     string
+		color    bool
+		emoji    bool
+		locale   string
+		currency string
+		rate     float64
+		scope    string
+	}{
+		{name: "report.golden", color: true, emoji: true, locale: "en-US", currency: "USD", rate: 1},
+		{name: "report_plain_eur.golden", locale: "de-DE", currency: "EUR", rate: 0.9, scope: "Bangkok"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			utils.SetStyle(c.color, c.emoji)
+			money, err := utils.NewMoney(c.locale, c.currency, c.rate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			svc := NewInsightService(utils.NewLogger())
+			svc.SetMoney(money)
+			svc.SetOutput(&buf)
+			r := goldenReport()
+			r.Scope = c.scope
+			svc.Print(r)
+			checkGolden(t, c.name, buf.Bytes())
+		})
+	}
+}
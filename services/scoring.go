@@ -0,0 +1,113 @@
+package services
+
+import (
+	"math"
+	"sort"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// Scorer computes a composite 0-100 score per listing from weighted,
+// dataset-normalised components: cheaper price, higher rating, more reviews
+// and shorter distance to the configured reference point.
+type Scorer struct {
+	weights config.ScoringWeights
+	logger  *utils.Logger
+}
+
+func NewScorer(weights config.ScoringWeights, logger *utils.Logger) *Scorer {
+	return &Scorer{weights: weights, logger: logger}
+}
+
+// Apply sets Score on every listing. Normalisation is relative to the batch,
+// so scores are comparable within one dataset.
+func (s *Scorer) Apply(listings []*models.Listing) {
+	w := s.weights
+	priceLo, priceHi := bounds(listings, func(l *models.Listing) (float64, bool) {
+		return l.Price, l.HasPrice()
+	})
+	_, reviewsHi := bounds(listings, func(l *models.Listing) (float64, bool) {
+		return math.Log1p(float64(l.ReviewCount)), l.ReviewCount > 0
+	})
+	distLo, distHi := bounds(listings, func(l *models.Listing) (float64, bool) {
+		return s.distanceKm(l)
+	})
+
+	for _, l := range listings {
+		var total, weightSum float64
+		add := func(weight, component float64) {
+			total += weight * component
+			weightSum += weight
+		}
+
+		if w.Price > 0 {
+			add(w.Price, invNormalise(l.Price, priceLo, priceHi, l.HasPrice()))
+		}
+		if w.Rating > 0 && !l.IsNewListing { // no rating yet: score on the rest
+			add(w.Rating, l.Rating/5)
+		}
+		if w.Reviews > 0 {
+			c := 0.0
+			if reviewsHi > 0 && l.ReviewCount > 0 {
+				c = math.Log1p(float64(l.ReviewCount)) / reviewsHi
+			}
+			add(w.Reviews, c)
+		}
+		if w.Distance > 0 && w.HasPoint {
+			d, ok := s.distanceKm(l)
+			add(w.Distance, invNormalise(d, distLo, distHi, ok))
+		}
+
+		if weightSum > 0 {
+			l.Score = round2(total / weightSum * 100)
+		}
+	}
+	s.logger.Info("[scoring] Scored %d listings (weights price=%.2f rating=%.2f reviews=%.2f distance=%.2f)",
+		len(listings), w.Price, w.Rating, w.Reviews, w.Distance)
+}
+
+// Rank returns listings ordered by score, highest first, without mutating input.
+func Rank(listings []*models.Listing) []*models.Listing {
+	ranked := make([]*models.Listing, len(listings))
+	copy(ranked, listings)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	return ranked
+}
+
+func (s *Scorer) distanceKm(l *models.Listing) (float64, bool) {
+	if !s.weights.HasPoint || (l.Latitude == 0 && l.Longitude == 0) {
+		return 0, false
+	}
+	return utils.HaversineKm(s.weights.PointLat, s.weights.PointLng, l.Latitude, l.Longitude), true
+}
+
+// bounds returns the min and max of the values for which ok is true.
+func bounds(listings []*models.Listing, value func(*models.Listing) (float64, bool)) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, l := range listings {
+		if v, ok := value(l); ok {
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+	}
+	if math.IsInf(lo, 1) {
+		return 0, 0
+	}
+	return lo, hi
+}
+
+// invNormalise maps v in [lo, hi] to [1, 0] — smaller is better. Unknown
+// values score 0; a degenerate range scores 1.
+func invNormalise(v, lo, hi float64, known bool) float64 {
+	if !known {
+		return 0
+	}
+	if hi <= lo {
+		return 1
+	}
+	return 1 - (v-lo)/(hi-lo)
+}
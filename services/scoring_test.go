@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+func TestScorerPriceAndRating(t *testing.T) {
+	listings := []*models.Listing{
+		{Title: "cheap-good", Price: 50, Rating: 5},
+		{Title: "pricey-good", Price: 150, Rating: 5},
+		{Title: "unknown", Price: 0, Rating: 0},
+		{Title: "cheap-new", Price: 50, IsNewListing: true},
+	}
+	NewScorer(config.ScoringWeights{Price: 1, Rating: 1}, utils.NewLogger()).Apply(listings)
+
+	if listings[0].Score != 100 {
+		t.Errorf("cheap-good: got %.2f, want 100", listings[0].Score)
+	}
+	if listings[1].Score != 50 {
+		t.Errorf("pricey-good: got %.2f, want 50", listings[1].Score)
+	}
+	if listings[2].Score != 0 {
+		t.Errorf("unknown: got %.2f, want 0", listings[2].Score)
+	}
+	if listings[3].Score != 100 {
+		t.Errorf("cheap-new: got %.2f, want 100 (no rating yet is not a bad one)", listings[3].Score)
+	}
+	if Rank(listings)[0].Title != "cheap-good" {
+		t.Error("Rank should put the highest score first")
+	}
+}
+
+func TestScorerDistance(t *testing.T) {
+	w := config.ScoringWeights{Distance: 1, PointLat: 13.75, PointLng: 100.50, HasPoint: true}
+	listings := []*models.Listing{
+		{Title: "near", Latitude: 13.75, Longitude: 100.50},
+		{Title: "far", Latitude: 13.85, Longitude: 100.60},
+		{Title: "no-coords"},
+	}
+	NewScorer(w, utils.NewLogger()).Apply(listings)
+
+	if listings[0].Score != 100 || listings[1].Score != 0 || listings[2].Score != 0 {
+		t.Errorf("distance scores: %.2f / %.2f / %.2f", listings[0].Score, listings[1].Score, listings[2].Score)
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// syntheticCities are the centres synthetic listings are scattered around.
+var syntheticCities = []struct {
+	name     string
+	lat, lng float64
+}{
+	{"Bangkok, Thailand", 13.7563, 100.5018},
+	{"Lisbon, Portugal", 38.7223, -9.1393},
+	{"Mexico City, Mexico", 19.4326, -99.1332},
+	{"Bali, Indonesia", -8.4095, 115.1889},
+	{"Tokyo, Japan", 35.6762, 139.6503},
+	{"Cape Town, South Africa", -33.9249, 18.4241},
+	{"New York, United States", 40.7128, -74.0060},
+	{"Barcelona, Spain", 41.3874, 2.1686},
+}
+
+// SyntheticListings generates n raw listings shaped like a real scrape:
+// the card price formats the Cleaner parses, ratings (some "New"), review
+// counts, coordinates scattered within ~5 km of a few cities and about 2%
+// duplicate URLs. It feeds benchmarks and the bench command; the same seed
+// yields the same rows.
+func SyntheticListings(n int, seed int64) []*models.RawListing {
+	rng := utils.NewRandom(seed)
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	out := make([]*models.RawListing, 0, n)
+	for i := 0; i < n; i++ {
+		if i > 0 && rng.Intn(50) == 0 {
+			dup := *out[rng.Intn(len(out))]
+			out = append(out, &dup)
+			continue
+		}
+		city := syntheticCities[rng.Intn(len(syntheticCities))]
+		nightly := 20 + rng.Intn(480)
+		var price string
+		switch rng.Intn(4) {
+		case 0:
+			nights := 2 + rng.Intn(6)
+			price = fmt.Sprintf("$%d for %d nights", nightly*nights, nights)
+		case 1:
+			price = fmt.Sprintf("$%d night", nightly)
+		default:
+			price = fmt.Sprintf("$%d per night", nightly)
+		}
+		rating := fmt.Sprintf("%d.%02d", 4, rng.Intn(100))
+		if rng.Intn(20) == 0 {
+			rating = "New"
+		}
+		offset := func() float64 { return float64(rng.Intn(9000)-4500) / 100000 }
+		id := 10_000_000 + int64(i)
+		out = append(out, &models.RawListing{
+			URL:           fmt.Sprintf("https://www.airbnb.com/rooms/%d", id),
+			Title:         fmt.Sprintf("Synthetic stay #%d", i+1),
+			RawPrice:      price,
+			Rating:        rating,
+			ReviewCount:   fmt.Sprintf("%d", rng.Intn(900)),
+			Location:      city.name,
+			Latitude:      fmt.Sprintf("%.6f", city.lat+offset()),
+			Longitude:     fmt.Sprintf("%.6f", city.lng+offset()),
+			Description:   "Synthetic listing generated for benchmarking the cleaning and insight stages.",
+			ScrapedAt:     at,
+			Platform:      "airbnb",
+			SchemaVersion: models.RawSchemaVersion,
+		})
+	}
+	return out
+}
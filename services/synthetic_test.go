@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"slices"
+	"testing"
+
+	"airbnb-scraper/utils"
+)
+
+// benchSizes are the dataset sizes the Clean and Generate benchmarks run at.
+var benchSizes = []int{10_000, 100_000}
+
+func TestSyntheticListings(t *testing.T) {
+	a, b := SyntheticListings(500, 3), SyntheticListings(500, 3)
+	if len(a) != 500 || !reflect.DeepEqual(a, b) {
+		t.Fatal("same seed should generate the same 500 rows")
+	}
+
+	cleaned := NewCleaner(utils.NewLoggerTo(io.Discard)).Clean(a)
+	if len(cleaned) >= len(a) || len(cleaned) < len(a)*9/10 {
+		t.Errorf("cleaned %d of %d rows, want only the ~2%% duplicates dropped", len(cleaned), len(a))
+	}
+	for _, l := range cleaned {
+		if l.Price < 20 || l.Price >= 500 {
+			t.Fatalf("%s: price %.2f outside the generated 20–499 range", l.URL, l.Price)
+		}
+	}
+}
+
+func BenchmarkClean(b *testing.B) {
+	for _, n := range benchSizes {
+		raw := SyntheticListings(n, 1)
+		for _, workers := range slices.Compact([]int{1, runtime.NumCPU()}) {
+			b.Run(fmt.Sprintf("n=%d/workers=%d", n, workers), func(b *testing.B) {
+				c := NewCleaner(utils.NewLoggerTo(io.Discard))
+				c.SetWorkers(workers)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					c.Clean(raw)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	logger := utils.NewLoggerTo(io.Discard)
+	for _, n := range benchSizes {
+		listings := NewCleaner(logger).Clean(SyntheticListings(n, 1))
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			svc := NewInsightService(logger)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				svc.Generate(listings)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// Watcher turns re-scraped room pages of watched listings into states,
+// finds the differences from the previous check and decides which are
+// worth an alert.
+type Watcher struct {
+	minChange float64 // percent
+	cleaner   *Cleaner
+}
+
+// NewWatcher returns a Watcher reporting price moves of at least minChange
+// percent; 0 reports every move. Availability changes are always reported.
+func NewWatcher(minChange float64, logger *utils.Logger) *Watcher {
+	return &Watcher{minChange: minChange, cleaner: NewCleaner(logger)}
+}
+
+// State reads a listing's state from its room page; removed marks a room
+// that redirected away or said it is no longer available.
+func (w *Watcher) State(r *models.RawListing, removed bool) models.WatchState {
+	s := models.WatchState{
+		URL:       r.URL,
+		Title:     normaliseText(r.Title),
+		Status:    models.WatchRemoved,
+		CheckedAt: r.ScrapedAt,
+	}
+	if removed {
+		return s
+	}
+	s.Location = w.cleaner.parseLocation(r.Location, r.RawPrice)
+	s.Price = w.cleaner.parsePrice(r.RawPrice)
+	s.Rating = w.cleaner.parseRating(r.Rating)
+	s.Status = models.WatchUnavailable
+	if s.Price > 0 {
+		s.Status = models.WatchAvailable
+	}
+	return s
+}
+
+// Diff compares curr with the previous state (nil before the first check)
+// and returns nil when price, availability and rating are all unchanged.
+// A removed room keeps its last known title, location and rating.
+func (w *Watcher) Diff(prev *models.WatchState, curr models.WatchState) *models.WatchChange {
+	if prev != nil && curr.Status == models.WatchRemoved {
+		curr.Title, curr.Location, curr.Rating = prev.Title, prev.Location, prev.Rating
+	}
+	if curr.Title == "" && prev != nil {
+		curr.Title = prev.Title
+	}
+	if prev != nil && prev.Status == curr.Status && prev.Price == curr.Price && prev.Rating == curr.Rating {
+		return nil
+	}
+	return &models.WatchChange{Previous: prev, Current: curr}
+}
+
+// Alert reports whether a change is worth an alert: availability changed
+// or the price moved by at least the minimum change. First checks and
+// rating-only changes are stored silently.
+func (w *Watcher) Alert(c *models.WatchChange) bool {
+	prev := c.Previous
+	switch {
+	case prev == nil:
+		return false
+	case prev.Status != c.Current.Status:
+		return true
+	case prev.Price == c.Current.Price:
+		return false
+	case prev.Price <= 0:
+		return true
+	}
+	return math.Abs(c.Current.Price-prev.Price)/prev.Price*100 >= w.minChange
+}
+
+// DescribeWatchChange summarises a change for logs and alerts, e.g.
+// "$120.00 → $95.00 (-20.8%)" or "available → removed".
+func DescribeWatchChange(c models.WatchChange) string {
+	if c.Previous == nil {
+		if c.Current.Status == models.WatchAvailable {
+			return fmt.Sprintf("first check: $%.2f", c.Current.Price)
+		}
+		return "first check: " + c.Current.Status
+	}
+	var parts []string
+	if c.Previous.Status != c.Current.Status {
+		parts = append(parts, c.Previous.Status+" → "+c.Current.Status)
+	}
+	if d := c.PriceDelta(); d != 0 {
+		parts = append(parts, fmt.Sprintf("$%.2f → $%.2f (%+.1f%%)", c.Previous.Price, c.Current.Price, d/c.Previous.Price*100))
+	}
+	if c.Previous.Rating != c.Current.Rating {
+		parts = append(parts, fmt.Sprintf("rating %.2f → %.2f", c.Previous.Rating, c.Current.Rating))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+
+	"airbnb-scraper/models"
+)
+
+func TestWatcherState(t *testing.T) {
+	w := NewWatcher(0, newTestLogger())
+	for _, tc := range []struct {
+		raw     models.RawListing
+		removed bool
+		status  string
+		price   float64
+	}{
+		{models.RawListing{URL: "a", Title: "Loft", RawPrice: "$600 for 5 nights"}, false, models.WatchAvailable, 120},
+		{models.RawListing{URL: "b", Title: "Loft"}, false, models.WatchUnavailable, 0},
+		{models.RawListing{URL: "c", RawPrice: "$95 per night"}, true, models.WatchRemoved, 0},
+	} {
+		s := w.State(&tc.raw, tc.removed)
+		if s.URL != tc.raw.URL || s.Status != tc.status || s.Price != tc.price {
+			t.Errorf("%s: got %s $%.2f, want %s $%.2f", tc.raw.URL, s.Status, s.Price, tc.status, tc.price)
+		}
+	}
+}
+
+func TestWatcherDiff(t *testing.T) {
+	w := NewWatcher(5, newTestLogger())
+	prev := &models.WatchState{URL: "a", Title: "Loft", Price: 100, Status: models.WatchAvailable, Rating: 4.8}
+	for _, tc := range []struct {
+		name          string
+		prev          *models.WatchState
+		curr          models.WatchState
+		change, alert bool
+	}{
+		{"first check", nil, models.WatchState{Price: 100, Status: models.WatchAvailable}, true, false},
+		{"unchanged", prev, models.WatchState{Price: 100, Status: models.WatchAvailable, Rating: 4.8}, false, false},
+		{"below threshold", prev, models.WatchState{Price: 104, Status: models.WatchAvailable, Rating: 4.8}, true, false},
+		{"rating only", prev, models.WatchState{Price: 100, Status: models.WatchAvailable, Rating: 4.7}, true, false},
+		{"price drop", prev, models.WatchState{Price: 90, Status: models.WatchAvailable, Rating: 4.8}, true, true},
+		{"booked out", prev, models.WatchState{Status: models.WatchUnavailable, Rating: 4.8}, true, true},
+		{"back with a price", &models.WatchState{Status: models.WatchUnavailable}, models.WatchState{Price: 100, Status: models.WatchAvailable}, true, true},
+	} {
+		got := w.Diff(tc.prev, tc.curr)
+		if (got != nil) != tc.change {
+			t.Errorf("%s: change = %v, want %v", tc.name, got != nil, tc.change)
+			continue
+		}
+		if got != nil && w.Alert(got) != tc.alert {
+			t.Errorf("%s: alert = %v, want %v", tc.name, !tc.alert, tc.alert)
+		}
+	}
+
+	removed := w.Diff(prev, models.WatchState{Status: models.WatchRemoved})
+	if removed.Current.Title != "Loft" || removed.Current.Rating != 4.8 {
+		t.Errorf("removed room lost its title or rating: %+v", removed.Current)
+	}
+}
+
+func TestDescribeWatchChange(t *testing.T) {
+	prev := &models.WatchState{Price: 120, Status: models.WatchAvailable}
+	for _, tc := range []struct {
+		change models.WatchChange
+		want   string
+	}{
+		{models.WatchChange{Current: models.WatchState{Price: 120, Status: models.WatchAvailable}}, "first check: $120.00"},
+		{models.WatchChange{Previous: prev, Current: models.WatchState{Price: 90, Status: models.WatchAvailable}}, "$120.00 → $90.00 (-25.0%)"},
+		{models.WatchChange{Previous: prev, Current: models.WatchState{Status: models.WatchRemoved}}, "available → removed"},
+	} {
+		if got := DescribeWatchChange(tc.change); got != tc.want {
+			t.Errorf("got %q, want %q", got, tc.want)
+		}
+	}
+}
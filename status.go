@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"airbnb-scraper/utils"
+)
+
+// logStatus writes a status snapshot to the log: stage, counters, queue
+// depths and every URL in flight with how long it has been loading.
+func logStatus(logger *utils.Logger, s utils.StatusSnapshot) {
+	logger.Info("[status] Stage %q, running %s — %d active workers", s.Stage, s.Elapsed, s.ActiveWorkers)
+	logger.Info("[status] Counts: %s", formatCounts(s.Counts))
+	logger.Info("[status] Queues: %s", formatCounts(s.Queues))
+	for _, f := range s.InFlight {
+		logger.Info("[status]   in flight %s: %s", time.Since(f.Since).Round(time.Second), f.URL)
+	}
+}
+
+// formatCounts renders counts as "a=1, b=2", sorted by name.
+func formatCounts(m map[string]int) string {
+	if len(m) == 0 {
+		return "none"
+	}
+	parts := make([]string, 0, len(m))
+	for k, v := range m {
+		parts = append(parts, fmt.Sprintf("%s=%d", k, v))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
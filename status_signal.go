@@ -0,0 +1,21 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"airbnb-scraper/utils"
+)
+
+// dumpStatusOnSignal logs a status snapshot on every SIGUSR1
+// (kill -USR1 <pid>) for the life of the process.
+func dumpStatusOnSignal(logger *utils.Logger, status *utils.StatusBoard) {
+	usr1 := make(chan os.Signal, 1)
+	signal.Notify(usr1, syscall.SIGUSR1)
+	for range usr1 {
+		logStatus(logger, status.Snapshot())
+	}
+}
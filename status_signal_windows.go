@@ -0,0 +1,7 @@
+package main
+
+import "airbnb-scraper/utils"
+
+// dumpStatusOnSignal is a no-op: Windows has no SIGUSR1. Use the admin
+// endpoint's GET /admin/status instead.
+func dumpStatusOnSignal(logger *utils.Logger, status *utils.StatusBoard) {}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"fmt"
+
+	"airbnb-scraper/models"
+)
+
+// descriptionPlaceholder is what the detail extractor stores when it finds
+// no description; backfill treats it as missing.
+const descriptionPlaceholder = "Description not available"
+
+// backfillTable keeps the values recovered by Backfill, one row per URL.
+// The listings table is recreated on every run, so the recovered values
+// live here and ApplyBackfill merges them into each run's fresh rows.
+const backfillTable = `
+		CREATE TABLE IF NOT EXISTS listing_backfill (
+			url         TEXT PRIMARY KEY,
+			latitude    DOUBLE PRECISION NOT NULL DEFAULT 0,
+			longitude   DOUBLE PRECISION NOT NULL DEFAULT 0,
+			description TEXT        NOT NULL DEFAULT '',
+			location    TEXT        NOT NULL DEFAULT '',
+			updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+`
+
+// Backfill fills extractor-derived columns of stored listings, matched by
+// URL, from re-extracted listings. Only values the re-extraction actually
+// found are written, and unless overwrite is set only into columns that are
+// still empty. Prices and ratings are left alone: they change over time, and
+// an archived capture is older than the stored row. The found values are
+// also kept in listing_backfill, so later runs get them back through
+// ApplyBackfill. It returns the number of stored listings matched.
+func (pw *PostgresWriter) Backfill(listings []*models.Listing, overwrite bool) (int64, error) {
+	tx, err := pw.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("postgres: backfill: %w", err)
+	}
+	defer tx.Rollback()
+
+	keep, err := tx.Prepare(`
+		INSERT INTO listing_backfill (url, latitude, longitude, description, location, updated_at)
+		VALUES ($1, $2, $3, CASE WHEN $4::text = $6::text THEN '' ELSE $4::text END, $5, NOW())
+		ON CONFLICT (url) DO UPDATE SET
+			latitude    = CASE WHEN EXCLUDED.latitude <> 0 THEN EXCLUDED.latitude ELSE listing_backfill.latitude END,
+			longitude   = CASE WHEN EXCLUDED.latitude <> 0 THEN EXCLUDED.longitude ELSE listing_backfill.longitude END,
+			description = CASE WHEN EXCLUDED.description <> '' THEN EXCLUDED.description ELSE listing_backfill.description END,
+			location    = CASE WHEN EXCLUDED.location <> '' THEN EXCLUDED.location ELSE listing_backfill.location END,
+			updated_at  = NOW()
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("postgres: backfill: prepare: %w", err)
+	}
+	defer keep.Close()
+
+	stmt, err := tx.Prepare(`
+		UPDATE listings SET
+			latitude    = CASE WHEN $2::float8 <> 0 AND ($6::bool OR latitude = 0) THEN $2::float8 ELSE latitude END,
+			longitude   = CASE WHEN $2::float8 <> 0 AND ($6::bool OR latitude = 0) THEN $3::float8 ELSE longitude END,
+			description = CASE WHEN $4::text NOT IN ('', $7::text) AND ($6::bool OR description IN ('', $7::text))
+			                   THEN $4::text ELSE description END,
+			location    = CASE WHEN $5::text <> '' AND ($6::bool OR location = '') THEN $5::text ELSE location END
+		WHERE url = $1
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("postgres: backfill: prepare: %w", err)
+	}
+	defer stmt.Close()
+
+	var matched int64
+	for _, l := range listings {
+		if _, err := keep.Exec(l.URL, l.Latitude, l.Longitude, l.Description, l.Location, descriptionPlaceholder); err != nil {
+			return 0, fmt.Errorf("postgres: backfill %s: %w", l.URL, err)
+		}
+		res, err := stmt.Exec(l.URL, l.Latitude, l.Longitude, l.Description, l.Location, overwrite, descriptionPlaceholder)
+		if err != nil {
+			return 0, fmt.Errorf("postgres: backfill %s: %w", l.URL, err)
+		}
+		n, _ := res.RowsAffected()
+		matched += n
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("postgres: backfill: commit: %w", err)
+	}
+	return matched, nil
+}
+
+// ApplyBackfill fills the freshly stored listings from listing_backfill,
+// matched by URL. Only columns the run left empty are filled: a value the
+// run extracted itself is newer than any archived capture. It returns the
+// number of listings matched.
+func (pw *PostgresWriter) ApplyBackfill() (int64, error) {
+	res, err := pw.db.Exec(`
+		UPDATE listings l SET
+			latitude    = CASE WHEN l.latitude = 0 AND b.latitude <> 0 THEN b.latitude ELSE l.latitude END,
+			longitude   = CASE WHEN l.latitude = 0 AND b.latitude <> 0 THEN b.longitude ELSE l.longitude END,
+			description = CASE WHEN l.description IN ('', $1::text) AND b.description <> '' THEN b.description ELSE l.description END,
+			location    = CASE WHEN l.location = '' AND b.location <> '' THEN b.location ELSE l.location END
+		FROM listing_backfill b
+		WHERE l.url = b.url
+	`, descriptionPlaceholder)
+	if err != nil {
+		return 0, fmt.Errorf("postgres: apply backfill: %w", err)
+	}
+	return res.RowsAffected()
+}
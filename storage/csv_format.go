@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// CSVFormat controls how CSV exports are encoded so they open cleanly in
+// spreadsheet apps across locales (e.g. semicolon + BOM for European Excel).
+type CSVFormat struct {
+	Delimiter rune // field separator: ',', ';' or '\t'
+	BOM       bool // prefix a UTF-8 byte-order mark so Excel detects the encoding
+	QuoteAll  bool // quote every field instead of only those that need it
+	Sanitize  bool // neutralise formulas and strip control characters (SanitizeCSVField)
+}
+
+// DefaultCSVFormat is plain RFC 4180 CSV with sanitization on.
+func DefaultCSVFormat() CSVFormat {
+	return CSVFormat{Delimiter: ',', Sanitize: true}
+}
+
+// ParseCSVDelimiter accepts "comma", "semicolon", "tab" or a single character.
+func ParseCSVDelimiter(s string) (rune, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "comma", ",":
+		return ',', nil
+	case "semicolon", ";":
+		return ';', nil
+	case "tab", "\\t", "\t":
+		return '\t', nil
+	}
+	if r, size := utf8.DecodeRuneInString(s); size == len(s) && r != '"' && r != '\n' && r != '\r' {
+		return r, nil
+	}
+	return 0, fmt.Errorf("csv: invalid delimiter %q (want comma, semicolon, tab or one character)", s)
+}
+
+// ParseCSVQuoting accepts "minimal" (quote only when needed) or "all".
+func ParseCSVQuoting(s string) (quoteAll bool, err error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "minimal":
+		return false, nil
+	case "all":
+		return true, nil
+	}
+	return false, fmt.Errorf("csv: invalid quoting %q (want minimal or all)", s)
+}
+
+// csvRowWriter writes rows in a CSVFormat. encoding/csv handles minimal
+// quoting; quote-all rows are encoded here since csv.Writer cannot force it.
+type csvRowWriter struct {
+	buf    *bufio.Writer
+	csv    *csv.Writer
+	format CSVFormat
+}
+
+func newCSVRowWriter(w io.Writer, format CSVFormat) (*csvRowWriter, error) {
+	if format.Delimiter == 0 {
+		format.Delimiter = ','
+	}
+	buf := bufio.NewWriter(w)
+	if format.BOM {
+		if _, err := buf.WriteString("\ufeff"); err != nil {
+			return nil, err
+		}
+	}
+	cw := csv.NewWriter(buf)
+	cw.Comma = format.Delimiter
+	return &csvRowWriter{buf: buf, csv: cw, format: format}, nil
+}
+
+// Write encodes one row. Sanitization applies to data rows only; pass
+// header=true for the header.
+func (w *csvRowWriter) Write(row []string, header bool) error {
+	if !header {
+		row = sanitizeRow(row, w.format.Sanitize)
+	}
+	if !w.format.QuoteAll {
+		return w.csv.Write(row)
+	}
+	for i, field := range row {
+		if i > 0 {
+			w.buf.WriteRune(w.format.Delimiter)
+		}
+		w.buf.WriteByte('"')
+		w.buf.WriteString(strings.ReplaceAll(field, `"`, `""`))
+		w.buf.WriteByte('"')
+	}
+	return w.buf.WriteByte('\n')
+}
+
+// Flush pushes buffered rows to the underlying writer.
+func (w *csvRowWriter) Flush() error {
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	return w.buf.Flush()
+}
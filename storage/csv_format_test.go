@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCSVRowWriterFormats(t *testing.T) {
+	row := []string{"Loft; central", `say "hi"`, "=1+1"}
+	tests := []struct {
+		name   string
+		format CSVFormat
+		want   string
+	}{
+		{"default", DefaultCSVFormat(), "h1,h2,h3\nLoft; central,\"say \"\"hi\"\"\",'=1+1\n"},
+		{"semicolon+bom", CSVFormat{Delimiter: ';', BOM: true}, "\ufeffh1;h2;h3\n\"Loft; central\";\"say \"\"hi\"\"\";=1+1\n"},
+		{"tab quote-all", CSVFormat{Delimiter: '\t', QuoteAll: true}, "\"h1\"\t\"h2\"\t\"h3\"\n\"Loft; central\"\t\"say \"\"hi\"\"\"\t\"=1+1\"\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		w, err := newCSVRowWriter(&buf, tt.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Write([]string{"h1", "h2", "h3"}, true); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Write(append([]string(nil), row...), false); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s:\n got  %q\n want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseCSVDelimiter(t *testing.T) {
+	for in, want := range map[string]rune{"comma": ',', "semicolon": ';', "tab": '\t', "|": '|', "": ','} {
+		got, err := ParseCSVDelimiter(in)
+		if err != nil || got != want {
+			t.Errorf("ParseCSVDelimiter(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseCSVDelimiter("pipe"); err == nil {
+		t.Error("expected error for unknown delimiter name")
+	}
+}
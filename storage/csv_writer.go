@@ -1,12 +1,11 @@
 package storage
 
 import (
-	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
-	"time"
 
 	"airbnb-scraper/models"
 )
@@ -16,12 +15,20 @@ import (
 type CSVWriter struct {
 	mu     sync.Mutex
 	file   *os.File
-	writer *csv.Writer
+	writer *csvRowWriter
+	fields []Field[*models.RawListing]
 }
 
 // NewCSVWriter creates (or truncates) the CSV file at the given path and
-// writes the header row. Intermediate directories are created automatically.
-func NewCSVWriter(path string) (*CSVWriter, error) {
+// writes the header row in the given format. columns selects and orders the
+// RawFields to write; empty means all. Intermediate directories are created
+// automatically.
+func NewCSVWriter(path string, format CSVFormat, columns []string) (*CSVWriter, error) {
+	fields, err := SelectFields(RawFields, columns)
+	if err != nil {
+		return nil, fmt.Errorf("csv: raw columns: %w", err)
+	}
+
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return nil, fmt.Errorf("csv: create output dir: %w", err)
 	}
@@ -31,17 +38,22 @@ func NewCSVWriter(path string) (*CSVWriter, error) {
 		return nil, fmt.Errorf("csv: create file %q: %w", path, err)
 	}
 
-	w := csv.NewWriter(f)
+	w, err := newCSVRowWriter(f, format)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("csv: write BOM: %w", err)
+	}
 
-	if err := w.Write([]string{
-		"platform", "title", "raw_price", "location", "rating", "url", "description", "scraped_at",
-	}); err != nil {
+	if err := w.Write(fieldNames(fields), true); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("csv: write header: %w", err)
+	}
+	if err := w.Flush(); err != nil {
 		_ = f.Close()
 		return nil, fmt.Errorf("csv: write header: %w", err)
 	}
-	w.Flush()
 
-	return &CSVWriter{file: f, writer: w}, nil
+	return &CSVWriter{file: f, writer: w, fields: fields}, nil
 }
 
 // WriteRaw writes ALL raw listings to the CSV file — no cap.
@@ -50,27 +62,29 @@ func (c *CSVWriter) WriteRaw(listings []*models.RawListing) error {
 	defer c.mu.Unlock()
 
 	for _, l := range listings {
-		row := []string{
-			l.Platform,
-			l.Title,
-			l.RawPrice,
-			l.Location,
-			l.Rating,
-			l.URL,
-			l.Description,
-			l.ScrapedAt.Format(time.RFC3339),
-		}
-		if err := c.writer.Write(row); err != nil {
+		if err := c.writer.Write(csvRow(c.fields, l), false); err != nil {
 			return fmt.Errorf("csv: write row: %w", err)
 		}
 	}
 
-	c.writer.Flush()
-	return c.writer.Error()
+	return c.writer.Flush()
 }
 
 // Close flushes and closes the underlying file.
 func (c *CSVWriter) Close() error {
-	c.writer.Flush()
+	_ = c.writer.Flush()
 	return c.file.Close()
-}
\ No newline at end of file
+}
+
+// provenanceJSON encodes per-field provenance as a compact JSON object, or ""
+// when nothing was recorded.
+func provenanceJSON(p models.Provenance) string {
+	if len(p) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
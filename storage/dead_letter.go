@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+// deadLetterRecord is one NDJSON line in the dead-letter file.
+type deadLetterRecord struct {
+	FailedAt time.Time       `json:"failed_at"`
+	Error    string          `json:"error"`
+	Listing  *models.Listing `json:"listing"`
+}
+
+// DeadLetterWriter appends listings that could not be stored to an NDJSON
+// file, one JSON object per line with the error that rejected it, so they can
+// be inspected and replayed. It is safe for concurrent use.
+type DeadLetterWriter struct {
+	mu    sync.Mutex
+	file  *os.File
+	enc   *json.Encoder
+	count int
+}
+
+// NewDeadLetterWriter creates (or truncates) the dead-letter file at path.
+func NewDeadLetterWriter(path string) (*DeadLetterWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("dead-letter: create output dir: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("dead-letter: create file %q: %w", path, err)
+	}
+	return &DeadLetterWriter{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Write records each listing together with the error that rejected it.
+func (d *DeadLetterWriter) Write(listings []*models.Listing, cause error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	for _, l := range listings {
+		if err := d.enc.Encode(deadLetterRecord{FailedAt: now, Error: cause.Error(), Listing: l}); err != nil {
+			return fmt.Errorf("dead-letter: write: %w", err)
+		}
+		d.count++
+	}
+	return nil
+}
+
+// Count returns how many listings have been dead-lettered.
+func (d *DeadLetterWriter) Count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.count
+}
+
+// Close closes the underlying file.
+func (d *DeadLetterWriter) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.file.Close()
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"airbnb-scraper/models"
+)
+
+func TestDeadLetterWriterNDJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dl", "dead.ndjson")
+	d, err := NewDeadLetterWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := []*models.Listing{{URL: "https://airbnb.com/rooms/1"}, {URL: "https://airbnb.com/rooms/2"}}
+	if err := d.Write(rows, errors.New("value too long")); err != nil {
+		t.Fatal(err)
+	}
+	if d.Count() != 2 {
+		t.Errorf("Count = %d, want 2", d.Count())
+	}
+	d.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var lines int
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var rec struct {
+			Error   string
+			Listing struct{ URL string }
+		}
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			t.Fatalf("line %d is not JSON: %v", lines+1, err)
+		}
+		if rec.Error != "value too long" || rec.Listing.URL != rows[lines].URL {
+			t.Errorf("line %d = %+v", lines+1, rec)
+		}
+		lines++
+	}
+	if lines != 2 {
+		t.Errorf("lines = %d, want 2", lines)
+	}
+}
+
+func TestIsolateFailuresKeepsGoodRows(t *testing.T) {
+	batch := []*models.Listing{{URL: "a"}, {URL: "bad"}, {URL: "c"}}
+	var inserted []string
+	failed := isolateFailures(batch, func(rows []*models.Listing) error {
+		if rows[0].URL == "bad" {
+			return errors.New("invalid input")
+		}
+		inserted = append(inserted, rows[0].URL)
+		return nil
+	})
+	if len(failed) != 1 || failed[0].listing.URL != "bad" {
+		t.Errorf("failed = %+v, want only the bad row", failed)
+	}
+	if len(inserted) != 2 {
+		t.Errorf("inserted = %v, want the two good rows", inserted)
+	}
+}
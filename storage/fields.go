@@ -0,0 +1,269 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// Field is one named column of an export. CSV renders it as text; JSON keeps
+// its native type.
+type Field[T any] struct {
+	Name string
+	CSV  func(T) string
+	JSON func(T) any
+}
+
+func textField[T any](name string, get func(T) string) Field[T] {
+	return Field[T]{Name: name, CSV: get, JSON: func(v T) any { return get(v) }}
+}
+
+func floatField[T any](name string, prec int, get func(T) float64) Field[T] {
+	return Field[T]{
+		Name: name,
+		CSV:  func(v T) string { return strconv.FormatFloat(get(v), 'f', prec, 64) },
+		JSON: func(v T) any { return get(v) },
+	}
+}
+
+func intField[T any](name string, get func(T) int) Field[T] {
+	return Field[T]{
+		Name: name,
+		CSV:  func(v T) string { return strconv.Itoa(get(v)) },
+		JSON: func(v T) any { return get(v) },
+	}
+}
+
+func timeField[T any](name string, get func(T) time.Time) Field[T] {
+	return Field[T]{
+		Name: name,
+		CSV:  func(v T) string { return get(v).Format(time.RFC3339) },
+		JSON: func(v T) any { return get(v) },
+	}
+}
+
+// listField renders a list as a JSON array in CSV, as Inside Airbnb writes
+// amenities, since items may contain commas; an empty list is "".
+func listField[T any](name string, get func(T) []string) Field[T] {
+	return Field[T]{Name: name, CSV: func(v T) string { return jsonList(get(v)) }, JSON: func(v T) any { return get(v) }}
+}
+
+func jsonList(s []string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// hostField renders the host as a JSON object in CSV, "" without one.
+func hostField[T any](get func(T) *models.Host) Field[T] {
+	return Field[T]{
+		Name: "host",
+		CSV: func(v T) string {
+			h := get(v)
+			if h == nil {
+				return ""
+			}
+			b, _ := json.Marshal(h)
+			return string(b)
+		},
+		JSON: func(v T) any { return get(v) },
+	}
+}
+
+// RawFields are the raw CSV columns, in default order.
+var RawFields = []Field[*models.RawListing]{
+	textField("platform", func(l *models.RawListing) string { return l.Platform }),
+	textField("title", func(l *models.RawListing) string { return l.Title }),
+	textField("raw_price", func(l *models.RawListing) string { return l.RawPrice }),
+	textField("location", func(l *models.RawListing) string { return l.Location }),
+	textField("rating", func(l *models.RawListing) string { return l.Rating }),
+	textField("url", func(l *models.RawListing) string { return l.URL }),
+	textField("description", func(l *models.RawListing) string { return l.Description }),
+	timeField("scraped_at", func(l *models.RawListing) time.Time { return l.ScrapedAt }),
+	textField("target_city", func(l *models.RawListing) string { return l.TargetCity }),
+	textField("review_count", func(l *models.RawListing) string { return l.ReviewCount }),
+	textField("latitude", func(l *models.RawListing) string { return l.Latitude }),
+	textField("longitude", func(l *models.RawListing) string { return l.Longitude }),
+	intField("schema_version", func(l *models.RawListing) int { return l.SchemaVersion }),
+	{
+		Name: "provenance",
+		CSV:  func(l *models.RawListing) string { return provenanceJSON(l.Provenance) },
+		JSON: func(l *models.RawListing) any { return l.Provenance },
+	},
+	textField("short_id", func(l *models.RawListing) string { return utils.ShortID(l.Platform, l.URL) }),
+	textField("status", func(l *models.RawListing) string { return l.Status }),
+	textField("original_description", func(l *models.RawListing) string { return l.OriginalDescription }),
+	listField("amenities", func(l *models.RawListing) []string { return l.Amenities }),
+	hostField(func(l *models.RawListing) *models.Host { return l.Host }),
+	listField("images", func(l *models.RawListing) []string { return l.Images }),
+}
+
+// ListingFields are the exportable columns of a cleaned listing.
+var ListingFields = []Field[*models.Listing]{
+	{
+		Name: "id",
+		CSV:  func(l *models.Listing) string { return strconv.FormatInt(l.ID, 10) },
+		JSON: func(l *models.Listing) any { return l.ID },
+	},
+	textField("short_id", func(l *models.Listing) string { return l.ShortID }),
+	textField("platform", func(l *models.Listing) string { return l.Platform }),
+	textField("title", func(l *models.Listing) string { return l.Title }),
+	{
+		Name: "price", // blank / null when unknown
+		CSV:  func(l *models.Listing) string { return knownPrice(l, strconv.FormatFloat(l.Price, 'f', 2, 64)) },
+		JSON: func(l *models.Listing) any {
+			if !l.HasPrice() {
+				return nil
+			}
+			return l.Price
+		},
+	},
+	textField("location", func(l *models.Listing) string { return l.Location }),
+	{
+		Name: "rating", // blank / null when unrated, see is_new_listing
+		CSV:  func(l *models.Listing) string { return optionalFloat(l.Rating, 2) },
+		JSON: func(l *models.Listing) any {
+			if l.Rating == 0 {
+				return nil
+			}
+			return l.Rating
+		},
+	},
+	intField("review_count", func(l *models.Listing) int { return l.ReviewCount }),
+	{
+		Name: "is_new_listing",
+		CSV:  func(l *models.Listing) string { return strconv.FormatBool(l.IsNewListing) },
+		JSON: func(l *models.Listing) any { return l.IsNewListing },
+	},
+	floatField("latitude", -1, func(l *models.Listing) float64 { return l.Latitude }),
+	floatField("longitude", -1, func(l *models.Listing) float64 { return l.Longitude }),
+	floatField("score", 2, func(l *models.Listing) float64 { return l.Score }),
+	textField("url", func(l *models.Listing) string { return l.URL }),
+	textField("description", func(l *models.Listing) string { return l.Description }),
+	textField("target_city", func(l *models.Listing) string { return l.TargetCity }),
+	textField("status", func(l *models.Listing) string { return l.Status }),
+	timeField("created_at", func(l *models.Listing) time.Time { return l.CreatedAt }),
+	floatField("price_confidence", 2, func(l *models.Listing) float64 { return l.PriceConfidence }),
+	floatField("rating_confidence", 2, func(l *models.Listing) float64 { return l.RatingConfidence }),
+	floatField("location_confidence", 2, func(l *models.Listing) float64 { return l.LocationConfidence }),
+	textField("original_description", func(l *models.Listing) string { return l.OriginalDescription }),
+	listField("amenities", func(l *models.Listing) []string { return l.Amenities }),
+	hostField(func(l *models.Listing) *models.Host { return l.Host }),
+	listField("images", func(l *models.Listing) []string { return l.Images }),
+	{
+		Name: "tags",
+		CSV:  func(l *models.Listing) string { return strings.Join(l.Tags, ",") },
+		JSON: func(l *models.Listing) any { return l.Tags },
+	},
+	textField("note", func(l *models.Listing) string { return l.Note }),
+}
+
+// ShortlistFields are the budget-shortlist columns: rank and value score
+// followed by every listing field.
+var ShortlistFields = append([]Field[*models.ShortlistEntry]{
+	intField("rank", func(e *models.ShortlistEntry) int { return e.Rank }),
+	floatField("value_score", 2, func(e *models.ShortlistEntry) float64 { return e.ValueScore }),
+}, liftFields(ListingFields, func(e *models.ShortlistEntry) *models.Listing { return e.Listing })...)
+
+// DefaultShortlistColumns is the shortlist layout used when none is configured.
+var DefaultShortlistColumns = []string{"rank", "short_id", "value_score", "title", "price", "rating", "location", "url"}
+
+// liftFields adapts fields of an inner type to an outer one.
+func liftFields[Outer, Inner any](fields []Field[Inner], inner func(Outer) Inner) []Field[Outer] {
+	out := make([]Field[Outer], len(fields))
+	for i, f := range fields {
+		f := f
+		out[i] = Field[Outer]{
+			Name: f.Name,
+			CSV:  func(o Outer) string { return f.CSV(inner(o)) },
+			JSON: func(o Outer) any { return f.JSON(inner(o)) },
+		}
+	}
+	return out
+}
+
+// SelectFields returns the fields named in spec, in spec order. An empty
+// spec selects every field in its default order.
+func SelectFields[T any](all []Field[T], spec []string) ([]Field[T], error) {
+	if len(spec) == 0 {
+		return all, nil
+	}
+	byName := make(map[string]Field[T], len(all))
+	names := make([]string, 0, len(all))
+	for _, f := range all {
+		byName[f.Name] = f
+		names = append(names, f.Name)
+	}
+	out := make([]Field[T], 0, len(spec))
+	for _, name := range spec {
+		f, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q (available: %s)", name, strings.Join(names, ", "))
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// fieldNames returns the header row for fields.
+func fieldNames[T any](fields []Field[T]) []string {
+	out := make([]string, len(fields))
+	for i, f := range fields {
+		out[i] = f.Name
+	}
+	return out
+}
+
+// csvRow renders v as one CSV row of fields.
+func csvRow[T any](fields []Field[T], v T) []string {
+	out := make([]string, len(fields))
+	for i, f := range fields {
+		out[i] = f.CSV(v)
+	}
+	return out
+}
+
+// Record is a JSON object whose keys keep the order of the selected fields.
+type Record struct {
+	keys   []string
+	values []any
+}
+
+// NewRecord builds the JSON record of v restricted to fields.
+func NewRecord[T any](fields []Field[T], v T) Record {
+	r := Record{keys: make([]string, len(fields)), values: make([]any, len(fields))}
+	for i, f := range fields {
+		r.keys[i] = f.Name
+		r.values[i] = f.JSON(v)
+	}
+	return r
+}
+
+// MarshalJSON encodes the record with keys in field order.
+func (r Record) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		buf.Write(key)
+		buf.WriteByte(':')
+		val, err := json.Marshal(r.values[i])
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", k, err)
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
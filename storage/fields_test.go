@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"airbnb-scraper/models"
+)
+
+func TestSelectFields(t *testing.T) {
+	all, err := SelectFields(RawFields, nil)
+	if err != nil || len(all) != len(RawFields) {
+		t.Fatalf("empty spec: got %d fields, err %v", len(all), err)
+	}
+
+	got, err := SelectFields(ListingFields, []string{"url", " Price ", "title"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := strings.Join(fieldNames(got), ","); names != "url,price,title" {
+		t.Errorf("order = %s, want url,price,title", names)
+	}
+
+	if _, err := SelectFields(ListingFields, []string{"title", "bogus"}); err == nil ||
+		!strings.Contains(err.Error(), "bogus") {
+		t.Errorf("unknown field: err = %v", err)
+	}
+}
+
+func TestRecordKeepsFieldOrder(t *testing.T) {
+	fields, _ := SelectFields(ListingFields, []string{"title", "price", "id"})
+	l := &models.Listing{ID: 7, Title: "Loft", Price: 120.5}
+	b, err := json.Marshal(NewRecord(fields, l))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"title":"Loft","price":120.5,"id":7}`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+}
+
+func TestUnknownPriceExportsBlank(t *testing.T) {
+	fields, _ := SelectFields(ListingFields, []string{"price"})
+	for _, tc := range []struct {
+		l        *models.Listing
+		csv, rec string
+	}{
+		{&models.Listing{}, "", `{"price":null}`},
+		{&models.Listing{PriceKnown: true}, "0.00", `{"price":0}`},
+		{&models.Listing{Price: 80}, "80.00", `{"price":80}`},
+	} {
+		b, _ := json.Marshal(NewRecord(fields, tc.l))
+		if got := csvRow(fields, tc.l)[0]; got != tc.csv || string(b) != tc.rec {
+			t.Errorf("%+v: CSV %q, JSON %s; want %q, %s", tc.l, got, b, tc.csv, tc.rec)
+		}
+	}
+}
+
+func TestUnratedExportsBlank(t *testing.T) {
+	fields, _ := SelectFields(ListingFields, []string{"rating", "is_new_listing"})
+	for _, tc := range []struct {
+		l        *models.Listing
+		csv, rec string
+	}{
+		{&models.Listing{}, ",false", `{"rating":null,"is_new_listing":false}`},
+		{&models.Listing{IsNewListing: true}, ",true", `{"rating":null,"is_new_listing":true}`},
+		{&models.Listing{Rating: 4.8}, "4.80,false", `{"rating":4.8,"is_new_listing":false}`},
+	} {
+		b, _ := json.Marshal(NewRecord(fields, tc.l))
+		if got := strings.Join(csvRow(fields, tc.l), ","); got != tc.csv || string(b) != tc.rec {
+			t.Errorf("%+v: CSV %q, JSON %s; want %q, %s", tc.l, got, b, tc.csv, tc.rec)
+		}
+	}
+}
+
+func TestWriteShortlistCSVColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shortlist.csv")
+	entries := []*models.ShortlistEntry{
+		{Rank: 1, ValueScore: 4.2, Listing: &models.Listing{Title: "Loft", Price: 99, Description: "long text"}},
+	}
+	if err := WriteShortlistCSV(path, entries, DefaultCSVFormat(), []string{"title", "rank", "price"}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "title,rank,price\nLoft,1,99.00\n"; string(b) != want {
+		t.Errorf("got %q, want %q", b, want)
+	}
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"airbnb-scraper/models"
+)
+
+// LoadFingerprint reads the fingerprint saved by the previous run. A missing
+// file yields (nil, nil).
+func LoadFingerprint(path string) (*models.PageFingerprint, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fingerprint: read %q: %w", path, err)
+	}
+	fp := &models.PageFingerprint{}
+	if err := json.Unmarshal(b, fp); err != nil {
+		return nil, fmt.Errorf("fingerprint: decode %q: %w", path, err)
+	}
+	return fp, nil
+}
+
+// SaveFingerprint overwrites path with fp, creating directories as needed.
+func SaveFingerprint(path string, fp *models.PageFingerprint) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("fingerprint: create output dir: %w", err)
+	}
+	b, err := json.MarshalIndent(fp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fingerprint: encode: %w", err)
+	}
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		return fmt.Errorf("fingerprint: write %q: %w", path, err)
+	}
+	return nil
+}
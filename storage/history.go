@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+// RecordRun stores a run row, stamped with the scraper version, plus one
+// price_history row (price and review count) per priced listing, in a
+// single transaction, and returns the new run ID.
+func (pw *PostgresWriter) RecordRun(listings []*models.Listing) (int64, error) {
+	tx, err := pw.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("postgres: begin run: %w", err)
+	}
+	defer tx.Rollback()
+
+	var runID int64
+	if err := tx.QueryRow(
+		`INSERT INTO runs (listing_count, scraper_version) VALUES ($1, $2) RETURNING id`,
+		len(listings), pw.version,
+	).Scan(&runID); err != nil {
+		return 0, fmt.Errorf("postgres: insert run: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO price_history (run_id, url, location, price, recorded_at, review_count)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("postgres: prepare price history: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, l := range listings {
+		if !l.HasPrice() {
+			continue
+		}
+		if _, err := stmt.Exec(runID, l.URL, l.Location, l.Price, now, l.ReviewCount); err != nil {
+			return 0, fmt.Errorf("postgres: insert price history: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("postgres: commit run: %w", err)
+	}
+	return runID, nil
+}
+
+// FetchWeeklyPrices returns the average recorded nightly price per location
+// per calendar week, oldest first — the input to price forecasting. Weeks
+// rolled up by retention are merged back in with their original weights.
+func (pw *PostgresWriter) FetchWeeklyPrices() (map[string][]models.PricePoint, error) {
+	rows, err := pw.db.Query(`
+		SELECT location, week, (SUM(price_sum) / SUM(samples))::float8
+		FROM (
+			SELECT location, date_trunc('week', recorded_at) AS week, SUM(price) AS price_sum, COUNT(*) AS samples
+			FROM price_history
+			WHERE location <> ''
+			GROUP BY location, week
+			UNION ALL
+			SELECT location, week, price_sum, samples
+			FROM price_history_weekly
+			WHERE location <> ''
+		) weekly
+		GROUP BY location, week
+		ORDER BY location, week
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: fetch weekly prices: %w", err)
+	}
+	defer rows.Close()
+
+	series := make(map[string][]models.PricePoint)
+	for rows.Next() {
+		var loc string
+		var p models.PricePoint
+		if err := rows.Scan(&loc, &p.Week, &p.AveragePrice); err != nil {
+			return nil, fmt.Errorf("postgres: scan weekly price: %w", err)
+		}
+		series[loc] = append(series[loc], p)
+	}
+	return series, rows.Err()
+}
+
+// FetchReviewHistory returns each listing's recorded review counts, oldest
+// first — the input to review-velocity occupancy estimates. Runs that saw
+// no reviews are left out.
+func (pw *PostgresWriter) FetchReviewHistory() (map[string][]models.ReviewPoint, error) {
+	rows, err := pw.db.Query(`
+		SELECT url, recorded_at, review_count
+		FROM price_history
+		WHERE review_count > 0
+		ORDER BY url, recorded_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: fetch review history: %w", err)
+	}
+	defer rows.Close()
+
+	history := make(map[string][]models.ReviewPoint)
+	for rows.Next() {
+		var url string
+		var p models.ReviewPoint
+		if err := rows.Scan(&url, &p.At, &p.Reviews); err != nil {
+			return nil, fmt.Errorf("postgres: scan review count: %w", err)
+		}
+		history[url] = append(history[url], p)
+	}
+	return history, rows.Err()
+}
+
+// FetchRunSnapshot summarises a stored run: its listing count and the
+// per-location priced-listing count and average price.
+func (pw *PostgresWriter) FetchRunSnapshot(runID int64) (*models.RunSnapshot, error) {
+	snap := &models.RunSnapshot{RunID: runID, Locations: make(map[string]models.LocationSnapshot)}
+	if err := pw.db.QueryRow(
+		`SELECT listing_count FROM runs WHERE id = $1`, runID,
+	).Scan(&snap.ListingCount); err != nil {
+		return nil, fmt.Errorf("postgres: fetch run %d: %w", runID, err)
+	}
+
+	rows, err := pw.db.Query(`
+		SELECT location, COUNT(*), AVG(price)::float8
+		FROM price_history
+		WHERE run_id = $1 AND location <> ''
+		GROUP BY location
+	`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: fetch run %d locations: %w", runID, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var loc string
+		var ls models.LocationSnapshot
+		if err := rows.Scan(&loc, &ls.Count, &ls.AveragePrice); err != nil {
+			return nil, fmt.Errorf("postgres: scan run location: %w", err)
+		}
+		snap.Locations[loc] = ls
+	}
+	return snap, rows.Err()
+}
+
+// PreviousRunID returns the most recent run before runID that was not marked
+// suspect, or 0 if there is none.
+func (pw *PostgresWriter) PreviousRunID(runID int64) (int64, error) {
+	var prev int64
+	err := pw.db.QueryRow(`
+		SELECT COALESCE(MAX(id), 0) FROM runs WHERE id < $1 AND status <> 'suspect'
+	`, runID).Scan(&prev)
+	if err != nil {
+		return 0, fmt.Errorf("postgres: previous run: %w", err)
+	}
+	return prev, nil
+}
+
+// MarkRun sets a run's status and the anomalies that justified it.
+func (pw *PostgresWriter) MarkRun(runID int64, status string, anomalies []string) error {
+	_, err := pw.db.Exec(
+		`UPDATE runs SET status = $2, anomalies = $3 WHERE id = $1`,
+		runID, status, strings.Join(anomalies, "\n"),
+	)
+	if err != nil {
+		return fmt.Errorf("postgres: mark run %d: %w", runID, err)
+	}
+	return nil
+}
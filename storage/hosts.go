@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"airbnb-scraper/models"
+)
+
+// hostsTable holds one row per host, kept across runs and refreshed
+// whenever one of their listings is stored; listings.host_id references it.
+const hostsTable = `
+		CREATE TABLE IF NOT EXISTS hosts (
+			id            TEXT PRIMARY KEY,
+			name          TEXT        NOT NULL DEFAULT '',
+			superhost     BOOLEAN     NOT NULL DEFAULT FALSE,
+			years_hosting INT         NOT NULL DEFAULT 0,
+			response_rate INT         NOT NULL DEFAULT 0,
+			listing_count INT         NOT NULL DEFAULT 0,
+			updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+`
+
+// hostID is the listing's host_id: its host's user ID, NULL without one.
+func hostID(l *models.Listing) sql.NullString {
+	if l.Host == nil || l.Host.ID == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: l.Host.ID, Valid: true}
+}
+
+// upsertHosts stores the hosts of listings that carry a user ID, so the
+// listings' host_id references resolve. A host listed twice is written
+// once, with the last listing's figures.
+func (pw *PostgresWriter) upsertHosts(listings []*models.Listing) error {
+	byID := make(map[string]*models.Host)
+	var ids []string
+	for _, l := range listings {
+		if !hostID(l).Valid {
+			continue
+		}
+		if _, seen := byID[l.Host.ID]; !seen {
+			ids = append(ids, l.Host.ID)
+		}
+		byID[l.Host.ID] = l.Host
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(ids))
+	args := make([]interface{}, 0, len(ids)*6)
+	for i, id := range ids {
+		h := byID[id]
+		n := i * 6
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6))
+		args = append(args, h.ID, h.Name, h.Superhost, h.YearsHosting, h.ResponseRate, h.ListingCount)
+	}
+	_, err := pw.db.Exec(`
+		INSERT INTO hosts (id, name, superhost, years_hosting, response_rate, listing_count)
+		VALUES `+strings.Join(values, ",")+`
+		ON CONFLICT (id) DO UPDATE SET
+			name          = COALESCE(NULLIF(EXCLUDED.name, ''), hosts.name),
+			superhost     = EXCLUDED.superhost,
+			years_hosting = EXCLUDED.years_hosting,
+			response_rate = EXCLUDED.response_rate,
+			listing_count = EXCLUDED.listing_count,
+			updated_at    = NOW()
+	`, args...)
+	if err != nil {
+		return fmt.Errorf("postgres: upsert hosts: %w", err)
+	}
+	return nil
+}
+
+// attachHosts fills in the Host of listings read with a host_id, which
+// scanListings leaves holding the ID only.
+func (pw *PostgresWriter) attachHosts(listings []*models.Listing) error {
+	byID := make(map[string][]*models.Listing)
+	var ids []string
+	for _, l := range listings {
+		if l.Host == nil {
+			continue
+		}
+		if _, seen := byID[l.Host.ID]; !seen {
+			ids = append(ids, l.Host.ID)
+		}
+		byID[l.Host.ID] = append(byID[l.Host.ID], l)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := pw.db.Query(`
+		SELECT id, name, superhost, years_hosting, response_rate, listing_count
+		FROM hosts WHERE id = ANY($1)
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("postgres: fetch hosts: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		h := &models.Host{}
+		if err := rows.Scan(&h.ID, &h.Name, &h.Superhost, &h.YearsHosting, &h.ResponseRate, &h.ListingCount); err != nil {
+			return fmt.Errorf("postgres: scan host: %w", err)
+		}
+		for _, l := range byID[h.ID] {
+			l.Host = h
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// WriteICal writes a listing's availability calendar to a fresh .ics file.
+func WriteICal(path, title, url string, days []models.CalendarDay, stamp time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("ical: create output dir: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("ical: create %q: %w", path, err)
+	}
+	if err := EncodeICal(f, title, url, days, stamp); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("ical: close %q: %w", path, err)
+	}
+	return nil
+}
+
+// EncodeICal writes days as an iCalendar (RFC 5545) calendar: one all-day
+// event per run of consecutive blocked or available days, e.g. "Blocked:
+// Sunny loft" from the 3rd to the 6th. Events are transparent, so they do
+// not mark the subscriber as busy, and their UIDs derive from the room ID
+// and start date, so re-imports update rather than duplicate them. days
+// must be sorted; a missing day ends a run.
+func EncodeICal(w io.Writer, title, url string, days []models.CalendarDay, stamp time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(s string) { bw.WriteString(foldICal(s) + "\r\n") }
+
+	id := utils.ListingID(url)
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//airbnb-scraper//listing availability//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:" + escapeICal(title))
+	for start := 0; start < len(days); {
+		end := start + 1
+		for end < len(days) && days[end].Blocked == days[start].Blocked &&
+			days[end].Date.Equal(days[end-1].Date.AddDate(0, 0, 1)) {
+			end++
+		}
+		state := "Available"
+		if days[start].Blocked {
+			state = "Blocked"
+		}
+		first, last := days[start].Date, days[end-1].Date
+		line("BEGIN:VEVENT")
+		line(fmt.Sprintf("UID:%s-%s@airbnb-scraper", id, first.Format("20060102")))
+		line("DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"))
+		line("DTSTART;VALUE=DATE:" + first.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + last.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + escapeICal(state+": "+title))
+		line("URL:" + url)
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+		start = end
+	}
+	line("END:VCALENDAR")
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("ical: write: %w", err)
+	}
+	return nil
+}
+
+// escapeICal escapes a TEXT value.
+var escapeICal = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace
+
+// foldICal splits a content line longer than 75 octets into continuation
+// lines starting with a space, without cutting a UTF-8 sequence.
+func foldICal(s string) string {
+	var b strings.Builder
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = 74 // the leading space counts
+	}
+	b.WriteString(s)
+	return b.String()
+}
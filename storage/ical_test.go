@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+func calendarDays(start string, blocked ...bool) []models.CalendarDay {
+	day, _ := time.Parse("2006-01-02", start)
+	out := make([]models.CalendarDay, len(blocked))
+	for i, b := range blocked {
+		out[i] = models.CalendarDay{Date: day.AddDate(0, 0, i), Blocked: b}
+	}
+	return out
+}
+
+func TestEncodeICal(t *testing.T) {
+	days := calendarDays("2024-05-01", false, false, true, true, true, false)
+	days = append(days, calendarDays("2024-05-09", false)...) // gap after the 6th
+	var b strings.Builder
+	stamp := time.Date(2024, 4, 30, 12, 0, 0, 0, time.UTC)
+	if err := EncodeICal(&b, "Loft; river, view", "https://www.airbnb.com/rooms/42", days, stamp); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	if !strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Errorf("not a calendar:\n%s", out)
+	}
+	if n := strings.Count(out, "BEGIN:VEVENT"); n != 4 {
+		t.Errorf("got %d events, want 4 (available, blocked, available, available after the gap)", n)
+	}
+	for _, want := range []string{
+		`X-WR-CALNAME:Loft\; river\, view` + "\r\n",
+		"UID:42-20240503@airbnb-scraper\r\n",
+		"DTSTART;VALUE=DATE:20240503\r\nDTEND;VALUE=DATE:20240506\r\n" + `SUMMARY:Blocked: Loft\; river\, view` + "\r\n",
+		"DTSTART;VALUE=DATE:20240509\r\nDTEND;VALUE=DATE:20240510\r\n",
+		"DTSTAMP:20240430T120000Z\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFoldICal(t *testing.T) {
+	long := "SUMMARY:" + strings.Repeat("é", 60)
+	folded := foldICal(long)
+	for i, l := range strings.Split(folded, "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("line %d is %d octets", i, len(l))
+		}
+		if i > 0 && !strings.HasPrefix(l, " ") {
+			t.Errorf("continuation line %d does not start with a space", i)
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != long {
+		t.Error("unfolding does not restore the line")
+	}
+	if foldICal("SHORT") != "SHORT" {
+		t.Error("short lines must not be folded")
+	}
+}
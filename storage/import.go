@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+// importColumns maps each raw listing field to the column names it is read
+// from, in order of preference. The first names follow the Inside Airbnb
+// listings.csv layout (detailed and summary files); the rest are the
+// exporter's own names, so a RAW_CSV or API dump can be imported back.
+var importColumns = map[string][]string{
+	"url":         {"listing_url", "url"},
+	"id":          {"id", "room_id"},
+	"title":       {"name", "title"},
+	"price":       {"price", "raw_price"},
+	"location":    {"neighbourhood_cleansed", "neighbourhood", "location"},
+	"rating":      {"review_scores_rating", "rating"},
+	"reviews":     {"number_of_reviews", "review_count"},
+	"latitude":    {"latitude", "lat"},
+	"longitude":   {"longitude", "lng"},
+	"description": {"description"},
+	"original":    {"original_description"},
+	"scraped_at":  {"last_scraped", "scraped_at"},
+}
+
+// ReadExternalListings reads a third-party listing dataset into raw listings
+// the Cleaner understands. CSV (optionally .gz, as Inside Airbnb publishes
+// it), a JSON array and NDJSON are accepted, chosen by extension. Rows
+// without a URL or room id are skipped.
+func ReadExternalListings(path string) ([]*models.RawListing, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("import: open %q: %w", path, err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	name := strings.ToLower(path)
+	if trimmed, ok := strings.CutSuffix(name, ".gz"); ok {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("import: gzip %q: %w", path, err)
+		}
+		defer gz.Close()
+		r, name = gz, trimmed
+	}
+
+	var rows []map[string]string
+	switch {
+	case strings.HasSuffix(name, ".csv"):
+		rows, err = readCSVRows(r)
+	case strings.HasSuffix(name, ".json"):
+		rows, err = readJSONRows(r, false)
+	case strings.HasSuffix(name, ".ndjson"), strings.HasSuffix(name, ".jsonl"):
+		rows, err = readJSONRows(r, true)
+	default:
+		return nil, fmt.Errorf("import: %s: unsupported format (want .csv, .json, .ndjson or .jsonl, optionally .gz)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("import: %s: %w", path, err)
+	}
+
+	out := make([]*models.RawListing, 0, len(rows))
+	for _, row := range rows {
+		if l := externalListing(row); l != nil {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func readCSVRows(r io.Reader) ([]map[string]string, error) {
+	cr := csv.NewReader(r)
+	cr.LazyQuotes = true
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+
+	var rows []map[string]string
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]string, len(header))
+		for i, v := range rec {
+			if i < len(header) {
+				row[header[i]] = v
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+func readJSONRows(r io.Reader, lines bool) ([]map[string]string, error) {
+	var objects []map[string]any
+	if lines {
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for n := 1; sc.Scan(); n++ {
+			line := bytes.TrimSpace(sc.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var obj map[string]any
+			if err := json.Unmarshal(line, &obj); err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			objects = append(objects, obj)
+		}
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+	} else if err := json.NewDecoder(r).Decode(&objects); err != nil {
+		return nil, err
+	}
+
+	rows := make([]map[string]string, len(objects))
+	for i, obj := range objects {
+		row := make(map[string]string, len(obj))
+		for k, v := range obj {
+			switch v := v.(type) {
+			case nil:
+			case string:
+				row[strings.ToLower(k)] = v
+			default:
+				row[strings.ToLower(k)] = fmt.Sprint(v)
+			}
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// externalListing maps one dataset row onto a raw listing, or returns nil
+// when the row identifies no room.
+func externalListing(row map[string]string) *models.RawListing {
+	get := func(field string) (string, string) {
+		for _, col := range importColumns[field] {
+			if v := strings.TrimSpace(row[col]); v != "" {
+				return v, col
+			}
+		}
+		return "", ""
+	}
+
+	url, _ := get("url")
+	if url == "" {
+		id, _ := get("id")
+		if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+			return nil
+		}
+		url = "https://www.airbnb.com/rooms/" + id
+	}
+
+	l := &models.RawListing{
+		URL:           strings.Split(url, "?")[0],
+		Platform:      "airbnb",
+		SchemaVersion: models.RawSchemaVersion,
+		ScrapedAt:     time.Now(),
+	}
+	if v, _ := get("scraped_at"); v != "" {
+		for _, layout := range []string{"2006-01-02", time.RFC3339} {
+			if t, err := time.Parse(layout, v); err == nil {
+				l.ScrapedAt = t
+				break
+			}
+		}
+	}
+
+	var col string
+	l.Title, _ = get("title")
+	l.Description, _ = get("description")
+	l.OriginalDescription, _ = get("original")
+	l.Latitude, _ = get("latitude")
+	l.Longitude, _ = get("longitude")
+	l.ReviewCount, _ = get("reviews")
+	if l.Location, col = get("location"); col != "" {
+		l.SetSource("location", "import:"+col, models.ConfidenceHigh, l.ScrapedAt)
+	}
+	if l.RawPrice, col = get("price"); col != "" {
+		l.RawPrice = importPrice(l.RawPrice)
+		l.SetSource("price", "import:"+col, models.ConfidenceHigh, l.ScrapedAt)
+	}
+	if l.Rating, col = get("rating"); col != "" {
+		l.Rating = importRating(l.Rating)
+		l.SetSource("rating", "import:"+col, models.ConfidenceHigh, l.ScrapedAt)
+	}
+	return l
+}
+
+// importPrice turns a dataset's nightly price ("$1,250.00" or "85") into the
+// per-night form the Cleaner parses. Already labelled prices pass through.
+func importPrice(v string) string {
+	if strings.Contains(strings.ToLower(v), "night") {
+		return v
+	}
+	if !strings.HasPrefix(v, "$") {
+		v = "$" + v
+	}
+	return v + " per night"
+}
+
+// importRating rescales ratings published on a 0–100 scale (older Inside
+// Airbnb files) to 0–5.
+func importRating(v string) string {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f <= 5 {
+		return v
+	}
+	if f > 100 {
+		return ""
+	}
+	return strconv.FormatFloat(f/20, 'f', 2, 64)
+}
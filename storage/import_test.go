@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadExternalListingsInsideAirbnbCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "listings.csv")
+	data := "id,listing_url,name,neighbourhood_cleansed,latitude,longitude,price,number_of_reviews,review_scores_rating,last_scraped\n" +
+		"101,https://www.airbnb.com/rooms/101,Sunny loft,Sukhumvit,13.73,100.56,\"$1,250.00\",12,4.87,2024-03-19\n" +
+		"102,,Old scale,Silom,,,85,3,94,2019-01-02\n" +
+		"abc,,No id,Silom,,,10,,,\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadExternalListings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d listings, want 2 (row without a room id skipped)", len(got))
+	}
+
+	a := got[0]
+	if a.URL != "https://www.airbnb.com/rooms/101" || a.Title != "Sunny loft" || a.Location != "Sukhumvit" {
+		t.Errorf("identity = %q %q %q", a.URL, a.Title, a.Location)
+	}
+	if a.RawPrice != "$1,250.00 per night" || a.Rating != "4.87" || a.ReviewCount != "12" {
+		t.Errorf("values = %q %q %q", a.RawPrice, a.Rating, a.ReviewCount)
+	}
+	if a.Latitude != "13.73" || a.Longitude != "100.56" {
+		t.Errorf("coordinates = %q, %q", a.Latitude, a.Longitude)
+	}
+	if want := time.Date(2024, 3, 19, 0, 0, 0, 0, time.UTC); !a.ScrapedAt.Equal(want) {
+		t.Errorf("ScrapedAt = %v, want %v", a.ScrapedAt, want)
+	}
+	if src := a.Provenance["price"]; src.Strategy != "import:price" {
+		t.Errorf("price provenance = %+v", src)
+	}
+
+	b := got[1]
+	if b.URL != "https://www.airbnb.com/rooms/102" {
+		t.Errorf("URL from id = %q", b.URL)
+	}
+	if b.Rating != "4.70" || b.RawPrice != "$85 per night" {
+		t.Errorf("rescaled = %q %q", b.Rating, b.RawPrice)
+	}
+}
+
+func TestReadExternalListingsJSON(t *testing.T) {
+	dir := t.TempDir()
+	arr := filepath.Join(dir, "listings.json")
+	os.WriteFile(arr, []byte(`[{"url":"https://www.airbnb.com/rooms/7?x=1","title":"A","lat":1.5,"lng":2.5,"rating":4.5}]`), 0644)
+	nd := filepath.Join(dir, "listings.ndjson")
+	os.WriteFile(nd, []byte("{\"id\":8,\"name\":\"B\"}\n\n{\"id\":9,\"name\":\"C\"}\n"), 0644)
+
+	got, err := ReadExternalListings(arr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].URL != "https://www.airbnb.com/rooms/7" || got[0].Latitude != "1.5" || got[0].Rating != "4.5" {
+		t.Fatalf("json = %+v", got)
+	}
+
+	got, err = ReadExternalListings(nd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[1].URL != "https://www.airbnb.com/rooms/9" || got[1].Title != "C" {
+		t.Fatalf("ndjson = %+v", got)
+	}
+
+	xlsx := filepath.Join(dir, "listings.xlsx")
+	os.WriteFile(xlsx, nil, 0644)
+	if _, err := ReadExternalListings(xlsx); err == nil {
+		t.Error("unsupported extension accepted")
+	}
+}
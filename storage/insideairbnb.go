@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"strconv"
+	"strings"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// Inside Airbnb (insideairbnb.com) publishes two listings layouts per city:
+// the summary visualisations/listings.csv and the detailed
+// data/listings.csv.gz. The profiles below reproduce their column names and
+// order exactly; columns this scraper does not collect are left empty, the
+// same way Inside Airbnb leaves unknown values blank.
+var (
+	insideAirbnbSummaryColumns = []string{
+		"id", "name", "host_id", "host_name", "neighbourhood_group", "neighbourhood",
+		"latitude", "longitude", "room_type", "price", "minimum_nights", "number_of_reviews",
+		"last_review", "reviews_per_month", "calculated_host_listings_count", "availability_365",
+		"number_of_reviews_ltm", "license",
+	}
+	insideAirbnbDetailedColumns = []string{
+		"id", "listing_url", "scrape_id", "last_scraped", "source", "name", "description",
+		"neighborhood_overview", "picture_url", "host_id", "host_url", "host_name", "host_since",
+		"host_location", "host_about", "host_response_time", "host_response_rate",
+		"host_acceptance_rate", "host_is_superhost", "host_thumbnail_url", "host_picture_url",
+		"host_neighbourhood", "host_listings_count", "host_total_listings_count",
+		"host_verifications", "host_has_profile_pic", "host_identity_verified", "neighbourhood",
+		"neighbourhood_cleansed", "neighbourhood_group_cleansed", "latitude", "longitude",
+		"property_type", "room_type", "accommodates", "bathrooms", "bathrooms_text", "bedrooms",
+		"beds", "amenities", "price", "minimum_nights", "maximum_nights", "minimum_minimum_nights",
+		"maximum_minimum_nights", "minimum_maximum_nights", "maximum_maximum_nights",
+		"minimum_nights_avg_ntm", "maximum_nights_avg_ntm", "calendar_updated", "has_availability",
+		"availability_30", "availability_60", "availability_90", "availability_365",
+		"calendar_last_scraped", "number_of_reviews", "number_of_reviews_ltm",
+		"number_of_reviews_l30d", "first_review", "last_review", "review_scores_rating",
+		"review_scores_accuracy", "review_scores_cleanliness", "review_scores_checkin",
+		"review_scores_communication", "review_scores_location", "review_scores_value", "license",
+		"instant_bookable", "calculated_host_listings_count",
+		"calculated_host_listings_count_entire_homes", "calculated_host_listings_count_private_rooms",
+		"calculated_host_listings_count_shared_rooms", "reviews_per_month",
+	}
+)
+
+// insideAirbnbValues are the columns this scraper can fill, shared by both
+// layouts. Summary prices are plain numbers; the detailed file formats them
+// as "$1,250.00", which is overridden below.
+var insideAirbnbValues = map[string]func(*models.Listing) string{
+	"id":                     listingRef,
+	"listing_url":            func(l *models.Listing) string { return l.URL },
+	"last_scraped":           func(l *models.Listing) string { return l.CreatedAt.Format("2006-01-02") },
+	"source":                 func(*models.Listing) string { return "airbnb-scraper" },
+	"name":                   func(l *models.Listing) string { return l.Title },
+	"description":            func(l *models.Listing) string { return l.Description },
+	"neighbourhood":          func(l *models.Listing) string { return l.Location },
+	"neighbourhood_cleansed": func(l *models.Listing) string { return l.Location },
+	"latitude":               func(l *models.Listing) string { return optionalFloat(l.Latitude, -1) },
+	"longitude":              func(l *models.Listing) string { return optionalFloat(l.Longitude, -1) },
+	"price":                  func(l *models.Listing) string { return knownPrice(l, strconv.FormatFloat(l.Price, 'f', -1, 64)) },
+	"number_of_reviews":      func(l *models.Listing) string { return strconv.Itoa(l.ReviewCount) },
+	"review_scores_rating":   func(l *models.Listing) string { return optionalFloat(l.Rating, 2) },
+	"amenities":              func(l *models.Listing) string { return jsonList(l.Amenities) },
+	"picture_url":            func(l *models.Listing) string { return firstImage(l) },
+}
+
+// InsideAirbnbSummaryFields follow visualisations/listings.csv.
+var InsideAirbnbSummaryFields = insideAirbnbLayout(insideAirbnbSummaryColumns, nil)
+
+// InsideAirbnbDetailedFields follow data/listings.csv.gz.
+var InsideAirbnbDetailedFields = insideAirbnbLayout(insideAirbnbDetailedColumns, map[string]func(*models.Listing) string{
+	"price": func(l *models.Listing) string { return knownPrice(l, dollarAmount(l.Price)) },
+})
+
+func insideAirbnbLayout(columns []string, overrides map[string]func(*models.Listing) string) []Field[*models.Listing] {
+	out := make([]Field[*models.Listing], len(columns))
+	for i, name := range columns {
+		get, ok := overrides[name]
+		if !ok {
+			get, ok = insideAirbnbValues[name]
+		}
+		if !ok {
+			get = func(*models.Listing) string { return "" }
+		}
+		out[i] = textField(name, get)
+	}
+	return out
+}
+
+// listingRef is the Airbnb room ID, or the short ID when the URL is gone
+// (anonymized exports).
+func listingRef(l *models.Listing) string {
+	if id := utils.ListingID(l.URL); id != "" {
+		return id
+	}
+	return l.ShortID
+}
+
+// knownPrice is formatted, or blank when the listing's price is unknown;
+// free stays are "0".
+func knownPrice(l *models.Listing, formatted string) string {
+	if !l.HasPrice() {
+		return ""
+	}
+	return formatted
+}
+
+// optionalFloat leaves zero (unknown) values blank.
+// firstImage is the listing's cover photo, "" without one.
+func firstImage(l *models.Listing) string {
+	if len(l.Images) == 0 {
+		return ""
+	}
+	return l.Images[0]
+}
+
+func optionalFloat(v float64, prec int) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', prec, 64)
+}
+
+// dollarAmount formats v the way the detailed file does, e.g. "$1,250.00".
+func dollarAmount(v float64) string {
+	whole, cents, _ := strings.Cut(strconv.FormatFloat(v, 'f', 2, 64), ".")
+	var b strings.Builder
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return "$" + b.String() + "." + cents
+}
+
+// ListingProfiles are named, fixed listing export layouts.
+var ListingProfiles = map[string][]Field[*models.Listing]{
+	"insideairbnb":          InsideAirbnbSummaryFields,
+	"insideairbnb-detailed": InsideAirbnbDetailedFields,
+}
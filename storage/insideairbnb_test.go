@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+func TestInsideAirbnbProfiles(t *testing.T) {
+	if n := len(InsideAirbnbSummaryFields); n != 18 {
+		t.Errorf("summary layout has %d columns, want 18", n)
+	}
+	if n := len(InsideAirbnbDetailedFields); n != 75 {
+		t.Errorf("detailed layout has %d columns, want 75", n)
+	}
+
+	l := &models.Listing{
+		URL: "https://www.airbnb.com/rooms/4242", Title: "Loft", Location: "Silom",
+		Latitude: 13.7, Longitude: 100.5, Price: 1250, ReviewCount: 12, Rating: 4.8,
+		CreatedAt: time.Date(2024, 3, 19, 8, 0, 0, 0, time.UTC),
+	}
+	path := filepath.Join(t.TempDir(), "listings.csv")
+	if err := WriteListingsCSV(path, []*models.Listing{l, {URL: "https://www.airbnb.com/rooms/1"}}, CSVFormat{}, InsideAirbnbDetailedFields); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(row int, col string) string {
+		for i, name := range rows[0] {
+			if name == col {
+				return rows[row][i]
+			}
+		}
+		t.Fatalf("no column %q", col)
+		return ""
+	}
+	want := map[string]string{
+		"id": "4242", "listing_url": l.URL, "name": "Loft", "neighbourhood_cleansed": "Silom",
+		"latitude": "13.7", "price": "$1,250.00", "number_of_reviews": "12",
+		"review_scores_rating": "4.80", "last_scraped": "2024-03-19", "host_name": "",
+	}
+	for col, v := range want {
+		if got := get(1, col); got != v {
+			t.Errorf("%s = %q, want %q", col, got, v)
+		}
+	}
+	if got := get(2, "price"); got != "" {
+		t.Errorf("unknown price = %q, want blank", got)
+	}
+
+	// The importer reads the export back.
+	back, err := ReadExternalListings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(back) != 2 || back[0].URL != l.URL || back[0].RawPrice != "$1,250.00 per night" {
+		t.Errorf("round trip = %+v", back[0])
+	}
+}
+
+func TestDollarAmount(t *testing.T) {
+	for v, want := range map[float64]string{5: "$5.00", 999.5: "$999.50", 1250: "$1,250.00", 1234567.891: "$1,234,567.89"} {
+		if got := dollarAmount(v); got != want {
+			t.Errorf("dollarAmount(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
@@ -13,3 +13,9 @@ type RawListingWriter interface {
 	WriteRaw(listings []*models.RawListing) error
 	Close() error
 }
+
+// PageArchiver is the interface for archiving captured browser traffic.
+type PageArchiver interface {
+	WritePage(page *models.PageCapture) error
+	Close() error
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"fmt"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// UpdateLifecycle folds the current listings table into listing_lifecycle
+// after run runID has been recorded:
+//
+//   - every listing stored this run takes its current status (active or
+//     removed), last_seen_run = runID and last_seen_at = the run's start;
+//     active ones become suspect instead when the run itself was flagged
+//     suspect. New listings get the same first_seen_run and first_seen_at;
+//   - active or suspect listings not seen for staleAfter healthy runs
+//     become stale (staleAfter < 1 disables this rule).
+//
+// It returns the resulting totals by status.
+func (pw *PostgresWriter) UpdateLifecycle(runID int64, suspect bool, staleAfter int) (map[string]int, error) {
+	tx, err := pw.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("postgres: begin lifecycle: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO listing_lifecycle AS lc
+			(url, platform, title, location, status, first_seen_run, last_seen_run, first_seen_at, last_seen_at)
+		SELECT url, platform, title, location,
+		       CASE WHEN $2 AND status = 'active' THEN 'suspect' ELSE status END,
+		       $1, $1, run.started_at, run.started_at
+		FROM listings,
+		     (SELECT COALESCE((SELECT started_at FROM runs WHERE id = $1), NOW()) AS started_at) run
+		ON CONFLICT (url) DO UPDATE SET
+			platform          = EXCLUDED.platform,
+			title             = COALESCE(NULLIF(EXCLUDED.title, ''), lc.title),
+			location          = COALESCE(NULLIF(EXCLUDED.location, ''), lc.location),
+			status            = EXCLUDED.status,
+			last_seen_run     = EXCLUDED.last_seen_run,
+			first_seen_at     = COALESCE(lc.first_seen_at, EXCLUDED.first_seen_at),
+			last_seen_at      = EXCLUDED.last_seen_at,
+			status_changed_at = CASE WHEN lc.status <> EXCLUDED.status
+			                         THEN NOW() ELSE lc.status_changed_at END
+	`, runID, suspect); err != nil {
+		return nil, fmt.Errorf("postgres: update lifecycle: %w", err)
+	}
+
+	if staleAfter > 0 {
+		if _, err := tx.Exec(`
+			UPDATE listing_lifecycle lc
+			SET status = 'stale', status_changed_at = NOW()
+			WHERE lc.status IN ('active', 'suspect')
+			  AND (SELECT COUNT(*) FROM runs r
+			       WHERE r.id > lc.last_seen_run AND r.id <= $1 AND r.status <> 'suspect') >= $2
+		`, runID, staleAfter); err != nil {
+			return nil, fmt.Errorf("postgres: mark stale listings: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("postgres: commit lifecycle: %w", err)
+	}
+	return pw.LifecycleCounts()
+}
+
+// LifecycleCounts returns the number of tracked listings per status.
+func (pw *PostgresWriter) LifecycleCounts() (map[string]int, error) {
+	rows, err := pw.db.Query(`SELECT status, COUNT(*) FROM listing_lifecycle GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: lifecycle counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, fmt.Errorf("postgres: scan lifecycle count: %w", err)
+		}
+		counts[status] = n
+	}
+	return counts, rows.Err()
+}
+
+// FetchLifecycle returns lifecycle records, most recently changed first,
+// optionally restricted to one status ("" = all).
+func (pw *PostgresWriter) FetchLifecycle(status string) ([]*models.LifecycleEntry, error) {
+	rows, err := pw.db.Query(`
+		SELECT url, platform, title, location, status, first_seen_run, last_seen_run,
+		       first_seen_at, last_seen_at, status_changed_at
+		FROM listing_lifecycle
+		WHERE $1 = '' OR status = $1
+		ORDER BY status_changed_at DESC, url
+	`, status)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: fetch lifecycle: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*models.LifecycleEntry
+	for rows.Next() {
+		e := &models.LifecycleEntry{}
+		if err := rows.Scan(&e.URL, &e.Platform, &e.Title, &e.Location, &e.Status,
+			&e.FirstSeenRun, &e.LastSeenRun, &e.FirstSeenAt, &e.LastSeenAt, &e.StatusChangedAt); err != nil {
+			return nil, fmt.Errorf("postgres: scan lifecycle: %w", err)
+		}
+		e.ShortID = utils.ShortID(e.Platform, e.URL)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// FetchChurn returns the inventory churn of the last weeks weeks per
+// location, newest week first: listings first seen in each week against
+// listings that went removed or stale in it. Listings of the first recorded
+// run are the baseline, not new inventory.
+func (pw *PostgresWriter) FetchChurn(weeks int) ([]*models.LocationChurn, error) {
+	rows, err := pw.db.Query(`
+		WITH events AS (
+			SELECT location, date_trunc('week', first_seen_at) AS week, 1 AS new, 0 AS gone
+			FROM listing_lifecycle
+			WHERE first_seen_run > (SELECT MIN(id) FROM runs)
+			UNION ALL
+			SELECT location, date_trunc('week', status_changed_at), 0, 1
+			FROM listing_lifecycle
+			WHERE status IN ('removed', 'stale')
+		)
+		SELECT location, week, SUM(new), SUM(gone)
+		FROM events
+		WHERE week >= date_trunc('week', NOW()) - make_interval(weeks => $1::int - 1)
+		GROUP BY location, week
+		ORDER BY week DESC, location
+	`, weeks)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: fetch churn: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*models.LocationChurn
+	for rows.Next() {
+		c := &models.LocationChurn{}
+		if err := rows.Scan(&c.Location, &c.Week, &c.New, &c.Disappeared); err != nil {
+			return nil, fmt.Errorf("postgres: scan churn: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// hotTables are rewritten or appended to on every run, so their indexes
+// bloat fastest and are the ones `db maintain` rebuilds.
+var hotTables = []string{"listings", "price_history", "listing_lifecycle"}
+
+// TableSize describes one table in the current schema.
+type TableSize struct {
+	Name       string
+	Rows       int64 // live rows, as estimated by the statistics collector
+	TableBytes int64 // heap + TOAST
+	IndexBytes int64
+}
+
+// Vacuum runs VACUUM (ANALYZE) on every table in the current schema, which
+// reclaims dead rows and refreshes planner statistics in one pass.
+func (pw *PostgresWriter) Vacuum() ([]string, error) {
+	return pw.eachTable("VACUUM (ANALYZE)")
+}
+
+// Analyze refreshes planner statistics for the current schema without
+// vacuuming.
+func (pw *PostgresWriter) Analyze() ([]string, error) {
+	return pw.eachTable("ANALYZE")
+}
+
+// eachTable runs "<stmt> <table>" for every table in the current schema.
+func (pw *PostgresWriter) eachTable(stmt string) ([]string, error) {
+	tables, err := pw.tableNames()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tables {
+		if _, err := pw.db.Exec(stmt + " " + pq.QuoteIdentifier(t)); err != nil {
+			return nil, fmt.Errorf("postgres: %s %s: %w", strings.ToLower(stmt), t, err)
+		}
+	}
+	return tables, nil
+}
+
+// ReindexHot rebuilds the indexes of the hot tables that exist.
+func (pw *PostgresWriter) ReindexHot() ([]string, error) {
+	tables, err := pw.tableNames()
+	if err != nil {
+		return nil, err
+	}
+	var done []string
+	for _, t := range hotTables {
+		if !containsString(tables, t) {
+			continue
+		}
+		if _, err := pw.db.Exec(`REINDEX TABLE ` + pq.QuoteIdentifier(t)); err != nil {
+			return done, fmt.Errorf("postgres: reindex %s: %w", t, err)
+		}
+		done = append(done, t)
+	}
+	return done, nil
+}
+
+// RefreshMaterializedViews refreshes every materialized view in the current
+// schema and returns their names.
+func (pw *PostgresWriter) RefreshMaterializedViews() ([]string, error) {
+	views, err := pw.queryNames(`
+		SELECT matviewname FROM pg_matviews
+		WHERE schemaname = current_schema()
+		ORDER BY matviewname
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: list materialized views: %w", err)
+	}
+	for _, v := range views {
+		if _, err := pw.db.Exec(`REFRESH MATERIALIZED VIEW ` + pq.QuoteIdentifier(v)); err != nil {
+			return nil, fmt.Errorf("postgres: refresh %s: %w", v, err)
+		}
+	}
+	return views, nil
+}
+
+// TableSizes reports row counts and on-disk sizes for the current schema,
+// largest first.
+func (pw *PostgresWriter) TableSizes() ([]TableSize, error) {
+	rows, err := pw.db.Query(`
+		SELECT relname, n_live_tup,
+		       pg_table_size(relid), pg_indexes_size(relid)
+		FROM pg_stat_user_tables
+		WHERE schemaname = current_schema()
+		ORDER BY pg_total_relation_size(relid) DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: table sizes: %w", err)
+	}
+	defer rows.Close()
+
+	var out []TableSize
+	for rows.Next() {
+		var ts TableSize
+		if err := rows.Scan(&ts.Name, &ts.Rows, &ts.TableBytes, &ts.IndexBytes); err != nil {
+			return nil, fmt.Errorf("postgres: scan table size: %w", err)
+		}
+		out = append(out, ts)
+	}
+	return out, rows.Err()
+}
+
+func (pw *PostgresWriter) tableNames() ([]string, error) {
+	names, err := pw.queryNames(`
+		SELECT tablename FROM pg_tables
+		WHERE schemaname = current_schema()
+		ORDER BY tablename
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: list tables: %w", err)
+	}
+	return names, nil
+}
+
+func (pw *PostgresWriter) queryNames(query string) ([]string, error) {
+	rows, err := pw.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// SinkStats summarises what a PostgresSink did over its lifetime.
+type SinkStats struct {
+	Received     int
+	Stored       int
+	DeadLettered int
+	Flushes      int
+}
+
+// PostgresSink buffers cleaned listings and writes them to a ListingWriter
+// (normally the PostgresWriter) when the buffer reaches batchSize rows or
+// interval elapses, whichever is first.
+//
+// Intake and writing run on separate goroutines joined by a queue of
+// queueDepth pending flushes, so a slow or retrying database does not block
+// upstream stages until that queue is full; past that point intake blocks and
+// the backpressure reaches the scraper. The sink is the only layer that
+// retries: a batch that still fails after the retry budget is re-written row
+// by row while the database is reachable, and only the rows that fail on
+// their own go to the dead-letter handler instead of aborting the run.
+type PostgresSink struct {
+	w          ListingWriter
+	batchSize  int
+	interval   time.Duration
+	queueDepth int
+	retry      *utils.RetryConfig
+	logger     *utils.Logger
+	deadLetter func(rows []*models.Listing, err error)
+}
+
+// NewPostgresSink creates a sink over w. Non-positive sizes fall back to
+// sensible defaults (100 rows, 2s, 4 queued flushes).
+func NewPostgresSink(w ListingWriter, batchSize int, interval time.Duration, queueDepth, maxRetries int, logger *utils.Logger) *PostgresSink {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	if queueDepth <= 0 {
+		queueDepth = 4
+	}
+	if maxRetries < 1 {
+		maxRetries = 1
+	}
+	return &PostgresSink{
+		w:          w,
+		batchSize:  batchSize,
+		interval:   interval,
+		queueDepth: queueDepth,
+		retry:      &utils.RetryConfig{MaxAttempts: maxRetries, BaseDelay: time.Second, Logger: logger},
+		logger:     logger,
+		deadLetter: func(rows []*models.Listing, err error) {
+			logger.Error("[pg-sink] Dropped %d rows after retries: %v", len(rows), err)
+		},
+	}
+}
+
+// SetDeadLetter replaces the handler for rows that could not be written.
+func (s *PostgresSink) SetDeadLetter(fn func(rows []*models.Listing, err error)) {
+	s.deadLetter = fn
+}
+
+// Run consumes in until it is closed, flushes what is left and returns once
+// every queued batch has been written or dead-lettered.
+func (s *PostgresSink) Run(in <-chan []*models.Listing) SinkStats {
+	var (
+		stats SinkStats
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+	)
+	queue := make(chan []*models.Listing, s.queueDepth)
+
+	// Writer: drains the flush queue.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for rows := range queue {
+			err := s.retry.Do("postgres-flush", func() error {
+				return s.w.Write(rows)
+			})
+			var failed []failedRow
+			if err != nil {
+				failed = s.isolate(rows, err)
+			}
+			mu.Lock()
+			stats.Flushes++
+			stats.Stored += len(rows) - len(failed)
+			stats.DeadLettered += len(failed)
+			mu.Unlock()
+			for _, f := range failed {
+				s.deadLetter([]*models.Listing{f.listing}, f.err)
+			}
+		}
+	}()
+
+	// Intake: buffers rows and hands full or stale buffers to the writer.
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	var buf []*models.Listing
+	flush := func() {
+		if len(buf) == 0 {
+			return
+		}
+		queue <- buf
+		buf = nil
+	}
+
+intake:
+	for {
+		select {
+		case batch, ok := <-in:
+			if !ok {
+				break intake
+			}
+			stats.Received += len(batch)
+			buf = append(buf, batch...)
+			for len(buf) >= s.batchSize {
+				chunk := buf[:s.batchSize:s.batchSize]
+				buf = buf[s.batchSize:]
+				queue <- chunk
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+	flush()
+	close(queue)
+	wg.Wait()
+	return stats
+}
+
+// pinger is a ListingWriter that can tell whether its database is reachable.
+type pinger interface {
+	Ping() error
+}
+
+// isolate handles a flush that failed after retries: while the database is
+// reachable it re-writes rows one at a time and returns those that fail;
+// otherwise every row fails with err.
+func (s *PostgresSink) isolate(rows []*models.Listing, err error) []failedRow {
+	if p, ok := s.w.(pinger); ok && p.Ping() != nil {
+		failed := make([]failedRow, len(rows))
+		for i, l := range rows {
+			failed[i] = failedRow{listing: l, err: err}
+		}
+		return failed
+	}
+	return isolateFailures(rows, s.w.Write)
+}
+
+// failedRow is a listing that could not be written on its own.
+type failedRow struct {
+	listing *models.Listing
+	err     error
+}
+
+// isolateFailures writes rows one at a time and returns those that fail.
+func isolateFailures(batch []*models.Listing, insert func([]*models.Listing) error) []failedRow {
+	var failed []failedRow
+	for _, l := range batch {
+		if err := insert([]*models.Listing{l}); err != nil {
+			failed = append(failed, failedRow{listing: l, err: err})
+		}
+	}
+	return failed
+}
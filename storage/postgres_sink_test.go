@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+)
+
+// flakyWriter fails the first failures calls, and always fails batches
+// containing the poison URL.
+type flakyWriter struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	written  []*models.Listing
+}
+
+func (w *flakyWriter) Write(rows []*models.Listing) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.calls++
+	if w.calls <= w.failures {
+		return errors.New("connection reset")
+	}
+	for _, l := range rows {
+		if l.URL == "poison" {
+			return errors.New("invalid byte sequence")
+		}
+	}
+	w.written = append(w.written, rows...)
+	return nil
+}
+
+func (w *flakyWriter) Close() error { return nil }
+
+func listingsN(n int, prefix string) []*models.Listing {
+	out := make([]*models.Listing, n)
+	for i := range out {
+		out[i] = &models.Listing{URL: prefix}
+	}
+	return out
+}
+
+func TestPostgresSinkRetriesAndDeadLetters(t *testing.T) {
+	w := &flakyWriter{failures: 1}
+	sink := NewPostgresSink(w, 10, time.Hour, 2, 2, utils.NewLogger())
+	sink.retry.BaseDelay = time.Millisecond
+
+	var dead []*models.Listing
+	sink.SetDeadLetter(func(rows []*models.Listing, err error) { dead = append(dead, rows...) })
+
+	in := make(chan []*models.Listing, 3)
+	in <- listingsN(7, "ok")
+	in <- listingsN(8, "ok") // 15 buffered → one flush of 10, 5 left
+	in <- listingsN(1, "poison")
+	close(in)
+
+	stats := sink.Run(in)
+
+	if stats.Received != 16 || stats.Flushes != 2 {
+		t.Errorf("stats = %+v, want 16 received over 2 flushes", stats)
+	}
+	if stats.Stored != 15 || len(w.written) != 15 {
+		t.Errorf("stored %d (writer saw %d), want 15 after retrying the first flush and isolating the poison row", stats.Stored, len(w.written))
+	}
+	if stats.DeadLettered != 1 || len(dead) != 1 || dead[0].URL != "poison" {
+		t.Errorf("dead-lettered %d (handler saw %v), want only the poison row", stats.DeadLettered, dead)
+	}
+}
+
+// downWriter is a database that cannot be reached.
+type downWriter struct{ flakyWriter }
+
+func (w *downWriter) Ping() error { return errors.New("connection refused") }
+
+func TestPostgresSinkSkipsIsolationWhenDown(t *testing.T) {
+	w := &downWriter{flakyWriter{failures: 1 << 30}}
+	sink := NewPostgresSink(w, 10, time.Hour, 1, 2, utils.NewLogger())
+	sink.retry.BaseDelay = time.Millisecond
+
+	in := make(chan []*models.Listing, 1)
+	in <- listingsN(4, "ok")
+	close(in)
+	stats := sink.Run(in)
+
+	if stats.DeadLettered != 4 || stats.Stored != 0 {
+		t.Errorf("stats = %+v, want all 4 rows dead-lettered", stats)
+	}
+	if w.calls != 2 {
+		t.Errorf("writer called %d times, want only the 2 retried flushes", w.calls)
+	}
+}
+
+func TestPostgresSinkFlushesOnInterval(t *testing.T) {
+	w := &flakyWriter{}
+	sink := NewPostgresSink(w, 1000, 10*time.Millisecond, 1, 1, utils.NewLogger())
+
+	in := make(chan []*models.Listing)
+	done := make(chan SinkStats)
+	go func() { done <- sink.Run(in) }()
+
+	in <- listingsN(3, "ok")
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		w.mu.Lock()
+		n := len(w.written)
+		w.mu.Unlock()
+		if n == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("buffered rows were not flushed on the interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(in)
+	if stats := <-done; stats.Stored != 3 {
+		t.Errorf("Stored = %d, want 3", stats.Stored)
+	}
+}
@@ -1,62 +1,130 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 
 	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
 )
 
 // PostgresWriter persists cleaned listings to PostgreSQL.
 type PostgresWriter struct {
-	db *sql.DB
+	db      *sql.DB
+	version string // stamped on recorded runs
 }
 
 // NewPostgresWriter opens a connection to PostgreSQL, runs schema migrations,
 // and returns a ready-to-use PostgresWriter.
 func NewPostgresWriter(dsn string) (*PostgresWriter, error) {
+	pw, err := OpenPostgres(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := pw.migrate(); err != nil {
+		pw.Close()
+		return nil, fmt.Errorf("postgres: migrate: %w", err)
+	}
+	return pw, nil
+}
+
+// connectWait is how long OpenPostgres waits for the server to accept
+// connections, and who hears about it; see SetConnectWait.
+var connectWait = struct {
+	backoff utils.Backoff
+	logger  *utils.Logger
+}{backoff: utils.Backoff{Initial: 2 * time.Second, Max: 2 * time.Second, Timeout: 20 * time.Second}}
+
+// SetConnectWait sets the back-off OpenPostgres retries a server that is
+// not up yet with, e.g. while its container starts; logger, when non-nil,
+// logs each failed attempt. Call it before opening connections.
+func SetConnectWait(b utils.Backoff, logger *utils.Logger) {
+	connectWait.backoff, connectWait.logger = b, logger
+}
+
+// OpenPostgres connects to PostgreSQL without touching the schema. Used by
+// read-only commands that query the listings left by the last run.
+func OpenPostgres(dsn string) (*PostgresWriter, error) {
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("postgres: open: %w", err)
 	}
 
-	for i := 0; i < 10; i++ {
-		if err = db.Ping(); err == nil {
-			break
-		}
-		time.Sleep(2 * time.Second)
-	}
-	if err != nil {
-		return nil, fmt.Errorf("postgres: ping failed after retries: %w", err)
+	dep := utils.Dependency{Name: "postgres", Check: db.PingContext}
+	if err := utils.WaitFor(context.Background(), connectWait.backoff, connectWait.logger, dep); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("postgres: ping: %w", err)
 	}
 
-	pw := &PostgresWriter{db: db}
-	if err := pw.migrate(); err != nil {
-		return nil, fmt.Errorf("postgres: migrate: %w", err)
+	return &PostgresWriter{db: db}, nil
+}
+
+// PostgresDependency is ready once the server at dsn answers a ping, for
+// waiting on it without keeping a connection.
+func PostgresDependency(dsn string) utils.Dependency {
+	return utils.Dependency{
+		Name: "postgres",
+		Check: func(ctx context.Context) error {
+			db, err := sql.Open("postgres", dsn)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			return db.PingContext(ctx)
+		},
 	}
+}
 
-	return pw, nil
+// SetScraperVersion sets the scraper version RecordRun stamps on each run,
+// so history rows can be traced to the code that produced them.
+func (pw *PostgresWriter) SetScraperVersion(v string) {
+	pw.version = v
+}
+
+// Ping reports whether the database is reachable, so the sink can tell
+// rows PostgreSQL rejects from a connection that is down.
+func (pw *PostgresWriter) Ping() error {
+	return pw.db.Ping()
 }
 
 // migrate drops and recreates the listings table fresh on every run.
-// This ensures serial IDs always start from 1.
+// This ensures serial IDs always start from 1. The history tables are only
+// created if missing so they accumulate across runs.
 func (pw *PostgresWriter) migrate() error {
+	if err := pw.ensureSchema(); err != nil {
+		return err
+	}
 	_, err := pw.db.Exec(`
 		DROP TABLE IF EXISTS listings;
-
+` + hostsTable + backfillTable + `
 		CREATE TABLE listings (
 			id          SERIAL PRIMARY KEY,
 			platform    VARCHAR(50)   NOT NULL,
 			title       TEXT          NOT NULL,
-			price       NUMERIC(10,2) NOT NULL DEFAULT 0,
+			price       NUMERIC(10,2),
 			location    TEXT          NOT NULL DEFAULT '',
-			rating      NUMERIC(4,2)  NOT NULL DEFAULT 0,
+			rating      NUMERIC(4,2),
+			review_count INT          NOT NULL DEFAULT 0,
+			latitude    DOUBLE PRECISION NOT NULL DEFAULT 0,
+			longitude   DOUBLE PRECISION NOT NULL DEFAULT 0,
+			score       NUMERIC(5,2)  NOT NULL DEFAULT 0,
 			url         TEXT          UNIQUE NOT NULL,
 			description TEXT          NOT NULL DEFAULT '',
+			target_city TEXT          NOT NULL DEFAULT '',
+			status      VARCHAR(20)   NOT NULL DEFAULT 'active',
+			price_confidence    REAL  NOT NULL DEFAULT 0,
+			rating_confidence   REAL  NOT NULL DEFAULT 0,
+			location_confidence REAL  NOT NULL DEFAULT 0,
+			original_description TEXT NOT NULL DEFAULT '',
+			amenities   TEXT[]        NOT NULL DEFAULT '{}',
+			host_id     TEXT          REFERENCES hosts(id),
+			is_new      BOOLEAN       NOT NULL DEFAULT FALSE,
+			images      TEXT[]        NOT NULL DEFAULT '{}',
 			created_at  TIMESTAMPTZ   NOT NULL DEFAULT NOW()
 		);
 
@@ -64,6 +132,65 @@ func (pw *PostgresWriter) migrate() error {
 		CREATE INDEX idx_listings_location ON listings(location);
 		CREATE INDEX idx_listings_platform ON listings(platform);
 		CREATE INDEX idx_listings_rating   ON listings(rating);
+		CREATE INDEX idx_listings_target_city ON listings(target_city);
+		CREATE INDEX idx_listings_score    ON listings(score);
+		CREATE INDEX idx_listings_status   ON listings(status);
+		CREATE INDEX idx_listings_amenities ON listings USING GIN (amenities);
+		CREATE INDEX idx_listings_host_id  ON listings(host_id);
+
+		CREATE TABLE IF NOT EXISTS runs (
+			id            SERIAL PRIMARY KEY,
+			started_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			listing_count INT         NOT NULL DEFAULT 0
+		);
+
+		CREATE TABLE IF NOT EXISTS price_history (
+			id          BIGSERIAL PRIMARY KEY,
+			run_id      INT           NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+			url         TEXT          NOT NULL,
+			location    TEXT          NOT NULL DEFAULT '',
+			price       NUMERIC(10,2) NOT NULL,
+			recorded_at TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+		);
+
+		ALTER TABLE runs ADD COLUMN IF NOT EXISTS status    VARCHAR(20) NOT NULL DEFAULT 'ok';
+		ALTER TABLE runs ADD COLUMN IF NOT EXISTS anomalies TEXT        NOT NULL DEFAULT '';
+		ALTER TABLE runs ADD COLUMN IF NOT EXISTS scraper_version TEXT  NOT NULL DEFAULT '';
+		ALTER TABLE price_history ADD COLUMN IF NOT EXISTS review_count INT NOT NULL DEFAULT 0;
+
+		CREATE INDEX IF NOT EXISTS idx_price_history_location ON price_history(location, recorded_at);
+		CREATE INDEX IF NOT EXISTS idx_price_history_url      ON price_history(url);
+
+		CREATE TABLE IF NOT EXISTS listing_lifecycle (
+			url               TEXT PRIMARY KEY,
+			platform          VARCHAR(50) NOT NULL DEFAULT '',
+			title             TEXT        NOT NULL DEFAULT '',
+			location          TEXT        NOT NULL DEFAULT '',
+			status            VARCHAR(20) NOT NULL DEFAULT 'active',
+			first_seen_run    INT         NOT NULL,
+			last_seen_run     INT         NOT NULL,
+			status_changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_listing_lifecycle_status ON listing_lifecycle(status);
+
+		ALTER TABLE listing_lifecycle ADD COLUMN IF NOT EXISTS first_seen_at TIMESTAMPTZ;
+		ALTER TABLE listing_lifecycle ADD COLUMN IF NOT EXISTS last_seen_at  TIMESTAMPTZ;
+		-- Listings tracked before the timestamps existed take their runs' start.
+		UPDATE listing_lifecycle lc SET
+			first_seen_at = COALESCE((SELECT started_at FROM runs WHERE id = lc.first_seen_run), lc.status_changed_at),
+			last_seen_at  = COALESCE((SELECT started_at FROM runs WHERE id = lc.last_seen_run), lc.status_changed_at)
+		WHERE lc.first_seen_at IS NULL;
+		CREATE INDEX IF NOT EXISTS idx_listing_lifecycle_first_seen ON listing_lifecycle(first_seen_at);
+
+		-- Weekly aggregates of price_history rows rolled up by retention.
+		CREATE TABLE IF NOT EXISTS price_history_weekly (
+			location  TEXT          NOT NULL,
+			week      TIMESTAMPTZ   NOT NULL,
+			price_sum NUMERIC(14,2) NOT NULL,
+			samples   INT           NOT NULL,
+			PRIMARY KEY (location, week)
+		);
+		CREATE INDEX IF NOT EXISTS idx_price_history_recorded ON price_history(recorded_at);
 	`)
 	return err
 }
@@ -78,6 +205,10 @@ func (pw *PostgresWriter) Clear() error {
 }
 
 // Write batch-inserts ALL cleaned listings. Table is already fresh from migrate().
+//
+// A failed batch is returned as is: PostgresSink retries the write and
+// isolates rejected rows. Rows already inserted by an earlier attempt are
+// skipped by ON CONFLICT, so a retry does not duplicate them.
 func (pw *PostgresWriter) Write(listings []*models.Listing) error {
 	if len(listings) == 0 {
 		return nil
@@ -89,62 +220,162 @@ func (pw *PostgresWriter) Write(listings []*models.Listing) error {
 		if end > len(listings) {
 			end = len(listings)
 		}
-		if err := pw.insertBatch(listings[i:end]); err != nil {
-			return err
+		batch := listings[i:end]
+
+		if err := pw.insertBatch(batch); err != nil {
+			return fmt.Errorf("postgres: insert batch: %w", err)
 		}
 	}
 	return nil
 }
 
+// listingColumns lists the inserted columns; listingValues must match its order.
+var listingColumns = []string{
+	"platform", "title", "price", "location", "rating", "review_count",
+	"latitude", "longitude", "score", "url", "description", "target_city",
+	"price_confidence", "rating_confidence", "location_confidence", "status",
+	"original_description", "amenities", "host_id", "is_new", "images",
+}
+
+func listingValues(l *models.Listing) []interface{} {
+	return []interface{}{
+		l.Platform, l.Title, sql.NullFloat64{Float64: l.Price, Valid: l.HasPrice()}, l.Location,
+		sql.NullFloat64{Float64: l.Rating, Valid: l.Rating > 0}, l.ReviewCount,
+		l.Latitude, l.Longitude, l.Score, l.URL, l.Description, l.TargetCity,
+		l.PriceConfidence, l.RatingConfidence, l.LocationConfidence, listingStatus(l),
+		l.OriginalDescription, textArray(l.Amenities), hostID(l), l.IsNewListing,
+		textArray(l.Images),
+	}
+}
+
+// textArray binds s as a TEXT[] value; nil binds as an empty array.
+func textArray(s []string) interface{} {
+	if s == nil {
+		s = []string{}
+	}
+	return pq.Array(s)
+}
+
+// listingStatus defaults an unset status to active.
+func listingStatus(l *models.Listing) string {
+	if l.Status == "" {
+		return models.ListingStatusActive
+	}
+	return l.Status
+}
+
 func (pw *PostgresWriter) insertBatch(batch []*models.Listing) error {
+	if err := pw.upsertHosts(batch); err != nil {
+		return err
+	}
+	n := len(listingColumns)
 	valueStrings := make([]string, 0, len(batch))
-	valueArgs := make([]interface{}, 0, len(batch)*7)
+	valueArgs := make([]interface{}, 0, len(batch)*n)
 
 	for idx, l := range batch {
-		base := idx * 7
-		valueStrings = append(valueStrings,
-			fmt.Sprintf("($%d,$%d,$%d,$%d,$%d,$%d,$%d)",
-				base+1, base+2, base+3, base+4, base+5, base+6, base+7))
-		valueArgs = append(valueArgs,
-			l.Platform, l.Title, l.Price, l.Location, l.Rating, l.URL, l.Description)
+		placeholders := make([]string, n)
+		for c := range placeholders {
+			placeholders[c] = fmt.Sprintf("$%d", idx*n+c+1)
+		}
+		valueStrings = append(valueStrings, "("+strings.Join(placeholders, ",")+")")
+		valueArgs = append(valueArgs, listingValues(l)...)
 	}
 
 	query := fmt.Sprintf(`
-		INSERT INTO listings (platform, title, price, location, rating, url, description)
+		INSERT INTO listings (%s)
 		VALUES %s
 		ON CONFLICT (url) DO NOTHING
-	`, strings.Join(valueStrings, ","))
+	`, strings.Join(listingColumns, ", "), strings.Join(valueStrings, ","))
 
 	_, err := pw.db.Exec(query, valueArgs...)
 	return err
 }
 
+// UpdateScores writes the Score of already-stored listings, matched by ID.
+// Scores are normalised over the whole dataset, so they are computed after
+// the streaming pipeline has stored every row.
+func (pw *PostgresWriter) UpdateScores(listings []*models.Listing) error {
+	const batchSize = 500
+	for i := 0; i < len(listings); i += batchSize {
+		end := i + batchSize
+		if end > len(listings) {
+			end = len(listings)
+		}
+		batch := listings[i:end]
+
+		values := make([]string, 0, len(batch))
+		args := make([]interface{}, 0, len(batch)*2)
+		for idx, l := range batch {
+			values = append(values, fmt.Sprintf("($%d::int, $%d::numeric)", idx*2+1, idx*2+2))
+			args = append(args, l.ID, l.Score)
+		}
+		query := fmt.Sprintf(`
+			UPDATE listings SET score = v.score
+			FROM (VALUES %s) AS v(id, score)
+			WHERE listings.id = v.id
+		`, strings.Join(values, ","))
+		if _, err := pw.db.Exec(query, args...); err != nil {
+			return fmt.Errorf("postgres: update scores: %w", err)
+		}
+	}
+	return nil
+}
+
 func (pw *PostgresWriter) Close() error {
 	return pw.db.Close()
 }
 
-// FetchAll retrieves all stored listings — used by the insight service.
+// FetchAll retrieves all active stored listings — used by the insight
+// service. Removed listings are kept in the table but left out here.
 func (pw *PostgresWriter) FetchAll() ([]*models.Listing, error) {
-	rows, err := pw.db.Query(`
-		SELECT id, platform, title, price, location, rating, url, description, created_at
-		FROM listings
+	rows, err := pw.db.Query(listingSelect + `
+		WHERE status = 'active'
 		ORDER BY id
 	`)
 	if err != nil {
 		return nil, fmt.Errorf("postgres: fetch all: %w", err)
 	}
+	return pw.scanListings(rows)
+}
+
+// listingSelect selects the columns scanListings reads.
+const listingSelect = `
+		SELECT id, platform, title, price, location, rating, review_count,
+		       latitude, longitude, score, url, description, target_city, created_at,
+		       price_confidence, rating_confidence, location_confidence, status,
+		       original_description, amenities, host_id, is_new, images
+		FROM listings`
+
+// scanListings reads and closes rows selected with listingSelect, then
+// attaches their hosts.
+func (pw *PostgresWriter) scanListings(rows *sql.Rows) ([]*models.Listing, error) {
 	defer rows.Close()
 
 	var listings []*models.Listing
 	for rows.Next() {
 		l := &models.Listing{}
+		var price, rating sql.NullFloat64
+		var host sql.NullString
 		if err := rows.Scan(
-			&l.ID, &l.Platform, &l.Title, &l.Price, &l.Location,
-			&l.Rating, &l.URL, &l.Description, &l.CreatedAt,
+			&l.ID, &l.Platform, &l.Title, &price, &l.Location,
+			&rating, &l.ReviewCount, &l.Latitude, &l.Longitude, &l.Score,
+			&l.URL, &l.Description, &l.TargetCity, &l.CreatedAt,
+			&l.PriceConfidence, &l.RatingConfidence, &l.LocationConfidence, &l.Status,
+			&l.OriginalDescription, pq.Array(&l.Amenities), &host, &l.IsNewListing,
+			pq.Array(&l.Images),
 		); err != nil {
 			return nil, fmt.Errorf("postgres: scan row: %w", err)
 		}
+		l.Price, l.PriceKnown = price.Float64, price.Valid
+		l.Rating = rating.Float64 // NULL: new or not shown
+		if host.Valid {
+			l.Host = &models.Host{ID: host.String}
+		}
+		l.ShortID = utils.ShortID(l.Platform, l.URL)
 		listings = append(listings, l)
 	}
-	return listings, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return listings, pw.attachHosts(listings)
 }
\ No newline at end of file
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"airbnb-scraper/models"
+)
+
+// ListingQuery filters and orders stored listings. Zero values disable a
+// filter. Saved views store it as JSON.
+type ListingQuery struct {
+	Location   string  `json:"location,omitempty"` // case-insensitive substring of location
+	City       string  `json:"city,omitempty"`     // target city, case-insensitive
+	Text       string  `json:"text,omitempty"`     // case-insensitive substring of title or description
+	MinPrice   float64 `json:"min_price,omitempty"`
+	MaxPrice   float64 `json:"max_price,omitempty"`
+	MinRating  float64 `json:"min_rating,omitempty"`
+	MinReviews int     `json:"min_reviews,omitempty"`
+	Tag        string  `json:"tag,omitempty"`  // tagged with this tag (see NormalizeTag)
+	Sort       string  `json:"sort,omitempty"` // a QuerySortKeys key, "-" prefix for descending
+	Limit      int     `json:"limit,omitempty"`
+}
+
+// String summarises the query's filters for listings of saved views, e.g.
+// `location~Bangkok price<=60 rating>=4.7 sort=price`.
+func (q ListingQuery) String() string {
+	var parts []string
+	add := func(cond bool, format string, arg any) {
+		if cond {
+			parts = append(parts, fmt.Sprintf(format, arg))
+		}
+	}
+	add(q.Location != "", "location~%s", q.Location)
+	add(q.City != "", "city=%s", q.City)
+	add(q.Text != "", "text~%q", q.Text)
+	add(q.MinPrice > 0, "price>=%g", q.MinPrice)
+	add(q.MaxPrice > 0, "price<=%g", q.MaxPrice)
+	add(q.MinRating > 0, "rating>=%g", q.MinRating)
+	add(q.MinReviews > 0, "reviews>=%d", q.MinReviews)
+	add(q.Tag != "", "tag=%s", q.Tag)
+	add(q.Sort != "", "sort=%s", q.Sort)
+	add(q.Limit > 0, "limit=%d", q.Limit)
+	if len(parts) == 0 {
+		return "all active listings"
+	}
+	return strings.Join(parts, " ")
+}
+
+// QuerySortKeys maps --sort keys to columns. Each has a natural direction
+// (cheapest, best rated, most reviewed first) that a "-" prefix reverses.
+var QuerySortKeys = map[string]string{
+	"price":   "price ASC",
+	"rating":  "rating DESC",
+	"reviews": "review_count DESC",
+	"score":   "score DESC",
+	"title":   "title ASC",
+}
+
+// SQL renders the query's WHERE, ORDER BY and LIMIT clauses with their
+// arguments. Only active listings are matched.
+func (q ListingQuery) SQL() (string, []any, error) {
+	where := []string{"status = 'active'"}
+	var args []any
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if q.Location != "" {
+		add("location ILIKE $%d", "%"+likeEscape(q.Location)+"%")
+	}
+	if q.City != "" {
+		add("lower(target_city) = lower($%d)", q.City)
+	}
+	if q.Text != "" {
+		args = append(args, "%"+likeEscape(q.Text)+"%")
+		where = append(where, fmt.Sprintf("(title ILIKE $%[1]d OR description ILIKE $%[1]d)", len(args)))
+	}
+	if q.MinPrice > 0 {
+		add("price >= $%d", q.MinPrice)
+	}
+	if q.MaxPrice > 0 {
+		add("price <= $%d", q.MaxPrice) // unknown prices are NULL and never match
+	}
+	if q.MinRating > 0 {
+		add("rating >= $%d", q.MinRating)
+	}
+	if q.MinReviews > 0 {
+		add("review_count >= $%d", q.MinReviews)
+	}
+	if q.Tag != "" {
+		add("url IN (SELECT url FROM listing_tags WHERE tag = $%d)", q.Tag)
+	}
+
+	order := "score DESC"
+	if q.Sort != "" {
+		key := strings.TrimPrefix(q.Sort, "-")
+		col, ok := QuerySortKeys[key]
+		if !ok {
+			keys := make([]string, 0, len(QuerySortKeys))
+			for k := range QuerySortKeys {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			return "", nil, fmt.Errorf("unknown sort key %q (available: %s)", key, strings.Join(keys, ", "))
+		}
+		if key != q.Sort {
+			col = reverseOrder(col)
+		}
+		if key == "price" || key == "rating" {
+			col += " NULLS LAST" // unknown prices and unrated listings after the rest either way
+		}
+		order = col
+	}
+
+	clause := "WHERE " + strings.Join(where, " AND ") + "\nORDER BY " + order + ", id"
+	if q.Limit > 0 {
+		clause += fmt.Sprintf("\nLIMIT %d", q.Limit)
+	}
+	return clause, args, nil
+}
+
+// QueryListings returns the stored listings matching q.
+func (pw *PostgresWriter) QueryListings(q ListingQuery) ([]*models.Listing, error) {
+	clause, args, err := q.SQL()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := pw.db.Query(listingSelect+"\n"+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: query listings: %w", err)
+	}
+	return pw.scanListings(rows)
+}
+
+func reverseOrder(col string) string {
+	if strings.HasSuffix(col, " ASC") {
+		return strings.TrimSuffix(col, " ASC") + " DESC"
+	}
+	return strings.TrimSuffix(col, " DESC") + " ASC"
+}
+
+// likeEscape escapes LIKE wildcards in user input.
+func likeEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
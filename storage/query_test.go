@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestListingQuerySQL(t *testing.T) {
+	clause, args, err := ListingQuery{
+		Location: "Bang_kok", MaxPrice: 80, MinRating: 4.5, Text: "pool", Sort: "price", Limit: 10,
+	}.SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "WHERE status = 'active' AND location ILIKE $1 AND (title ILIKE $2 OR description ILIKE $2)" +
+		" AND price <= $3 AND rating >= $4\nORDER BY price ASC NULLS LAST, id\nLIMIT 10"
+	if clause != want {
+		t.Errorf("clause =\n%s\nwant\n%s", clause, want)
+	}
+	if wantArgs := []any{`%Bang\_kok%`, "%pool%", 80.0, 4.5}; !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestListingQueryTag(t *testing.T) {
+	clause, args, err := ListingQuery{Tag: "shortlisted", MinReviews: 5}.SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "WHERE status = 'active' AND review_count >= $1 AND url IN (SELECT url FROM listing_tags WHERE tag = $2)"
+	if !strings.HasPrefix(clause, want) || !reflect.DeepEqual(args, []any{5, "shortlisted"}) {
+		t.Errorf("clause = %q, args = %v", clause, args)
+	}
+}
+
+func TestListingQueryString(t *testing.T) {
+	q := ListingQuery{Location: "Bangkok", MaxPrice: 60, MinRating: 4.7, Sort: "price"}
+	if got, want := q.String(), "location~Bangkok price<=60 rating>=4.7 sort=price"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := (ListingQuery{}).String(); got != "all active listings" {
+		t.Errorf("empty query = %q", got)
+	}
+}
+
+func TestListingQuerySort(t *testing.T) {
+	clause, args, err := ListingQuery{Sort: "-price"}.SQL()
+	if err != nil || len(args) != 0 || !strings.Contains(clause, "ORDER BY price DESC NULLS LAST, id") {
+		t.Errorf("-price = %q %v %v", clause, args, err)
+	}
+	if clause, _, _ := (ListingQuery{Sort: "rating"}).SQL(); !strings.Contains(clause, "ORDER BY rating DESC NULLS LAST, id") {
+		t.Errorf("rating = %q", clause)
+	}
+	if clause, _, _ := (ListingQuery{}).SQL(); !strings.Contains(clause, "ORDER BY score DESC") {
+		t.Errorf("default order = %q", clause)
+	}
+	if _, _, err := (ListingQuery{Sort: "id; DROP TABLE listings"}).SQL(); err == nil {
+		t.Error("unknown sort key accepted")
+	}
+}
@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetentionRule prunes or rolls up one table (or the archive files) once its
+// rows are older than MaxAge.
+type RetentionRule struct {
+	Target string // price_history, runs, listing_lifecycle or archives
+	Action string // delete or rollup
+	MaxAge time.Duration
+}
+
+// retentionActions lists the actions each target supports.
+var retentionActions = map[string][]string{
+	"price_history":     {"delete", "rollup"},
+	"runs":              {"delete"},
+	"listing_lifecycle": {"delete"},
+	"archives":          {"delete"},
+}
+
+// RetentionResult reports what one rule did.
+type RetentionResult struct {
+	Target   string
+	Action   string
+	Affected int64 // rows or files
+}
+
+// ParseRetentionPolicy reads "target=action:age,…", e.g.
+// "price_history=rollup:180d,runs=delete:365d". Ages take a d (days) suffix
+// or any Go duration. An empty spec yields no rules.
+func ParseRetentionPolicy(spec string) ([]RetentionRule, error) {
+	var rules []RetentionRule
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		target, rest, ok := strings.Cut(entry, "=")
+		action, age, ok2 := strings.Cut(rest, ":")
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("retention: %q: want target=action:age", entry)
+		}
+		target, action = strings.TrimSpace(target), strings.TrimSpace(action)
+
+		actions, known := retentionActions[target]
+		if !known {
+			return nil, fmt.Errorf("retention: unknown target %q (want %s)", target, strings.Join(retentionTargets(), ", "))
+		}
+		if !containsString(actions, action) {
+			return nil, fmt.Errorf("retention: %s does not support %q (want %s)", target, action, strings.Join(actions, " or "))
+		}
+		maxAge, err := parseAge(strings.TrimSpace(age))
+		if err != nil {
+			return nil, fmt.Errorf("retention: %s: %w", target, err)
+		}
+		rules = append(rules, RetentionRule{Target: target, Action: action, MaxAge: maxAge})
+	}
+	return rules, nil
+}
+
+func parseAge(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age %q (e.g. 90d or 720h)", s)
+	}
+	return d, nil
+}
+
+func retentionTargets() []string {
+	out := make([]string, 0, len(retentionActions))
+	for t := range retentionActions {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyRetention runs every database rule in order and returns what each one
+// affected. Archive rules are skipped here; see PruneArchives.
+func (pw *PostgresWriter) ApplyRetention(rules []RetentionRule, now time.Time) ([]RetentionResult, error) {
+	var results []RetentionResult
+	for _, r := range rules {
+		if r.Target == "archives" {
+			continue
+		}
+		cutoff := now.Add(-r.MaxAge)
+		var query string
+		switch r.Target + "/" + r.Action {
+		case "price_history/delete":
+			query = `DELETE FROM price_history WHERE recorded_at < $1`
+		case "price_history/rollup":
+			query = `
+				WITH old AS (
+					DELETE FROM price_history WHERE recorded_at < $1
+					RETURNING location, recorded_at, price
+				)
+				INSERT INTO price_history_weekly (location, week, price_sum, samples)
+				SELECT location, date_trunc('week', recorded_at), SUM(price), COUNT(*)
+				FROM old
+				GROUP BY 1, 2
+				ON CONFLICT (location, week) DO UPDATE SET
+					price_sum = price_history_weekly.price_sum + EXCLUDED.price_sum,
+					samples   = price_history_weekly.samples + EXCLUDED.samples`
+		case "runs/delete":
+			query = `DELETE FROM runs WHERE started_at < $1`
+		case "listing_lifecycle/delete":
+			// Only listings already gone from the market; active ones stay.
+			query = `DELETE FROM listing_lifecycle
+			         WHERE status IN ('removed', 'stale') AND status_changed_at < $1`
+		default:
+			return results, fmt.Errorf("retention: unsupported rule %s=%s", r.Target, r.Action)
+		}
+
+		res, err := pw.db.Exec(query, cutoff)
+		if err != nil {
+			return results, fmt.Errorf("postgres: retention %s=%s: %w", r.Target, r.Action, err)
+		}
+		n, _ := res.RowsAffected()
+		results = append(results, RetentionResult{Target: r.Target, Action: r.Action, Affected: n})
+	}
+	return results, nil
+}
+
+// PruneArchives deletes WARC archives in dir older than maxAge: per-run
+// archives (see RunArchivePath) by the run time in their name, other
+// archives by their modification time.
+func PruneArchives(dir string, maxAge time.Duration, now time.Time) (int64, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("retention: read %s: %w", dir, err)
+	}
+	cutoff := now.Add(-maxAge)
+	var removed int64
+	for _, e := range entries {
+		if e.IsDir() || !isArchive(e.Name()) {
+			continue
+		}
+		at, ok := archiveRunTime(e.Name())
+		if !ok {
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			at = info.ModTime()
+		}
+		if !at.Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			return removed, fmt.Errorf("retention: remove %s: %w", e.Name(), err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func isArchive(name string) bool {
+	return strings.HasSuffix(name, ".warc") || strings.HasSuffix(name, ".warc.gz")
+}
+
+// ListArchives returns the WARC archives in dir, sorted by name, so the
+// per-run archives of one base come oldest first.
+func ListArchives(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("warc: read %s: %w", dir, err)
+	}
+	var paths []string
+	for _, e := range entries {
+		if !e.IsDir() && isArchive(e.Name()) {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseRetentionPolicy(t *testing.T) {
+	rules, err := ParseRetentionPolicy("price_history=rollup:180d, runs=delete:720h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RetentionRule{
+		{Target: "price_history", Action: "rollup", MaxAge: 180 * 24 * time.Hour},
+		{Target: "runs", Action: "delete", MaxAge: 720 * time.Hour},
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("got %d rules, want %d", len(rules), len(want))
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, rules[i], want[i])
+		}
+	}
+
+	if rules, err := ParseRetentionPolicy(""); err != nil || len(rules) != 0 {
+		t.Errorf("empty spec = %v, %v", rules, err)
+	}
+	for _, bad := range []string{"listings=delete:30d", "runs=rollup:30d", "runs=delete:0d", "runs=delete", "runs"} {
+		if _, err := ParseRetentionPolicy(bad); err == nil {
+			t.Errorf("ParseRetentionPolicy(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestPruneArchives(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	files := map[string]time.Time{
+		"old.warc":    old,
+		"old.warc.gz": old,
+		"old.csv":     old,
+		"new.warc":    now,
+	}
+	for name, mtime := range files {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := PruneArchives(dir, 24*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("removed %d files, want 2", n)
+	}
+	for name, kept := range map[string]bool{"old.warc": false, "old.warc.gz": false, "old.csv": true, "new.warc": true} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if exists := err == nil; exists != kept {
+			t.Errorf("%s exists = %v, want %v", name, exists, kept)
+		}
+	}
+
+	if n, err := PruneArchives(filepath.Join(dir, "missing"), time.Hour, now); n != 0 || err != nil {
+		t.Errorf("missing dir = %d, %v", n, err)
+	}
+}
+
+func TestPruneArchivesPerRun(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "pages.warc")
+	now := time.Now()
+	oldRun := RunArchivePath(base, now.Add(-72*time.Hour))
+	lastRun := RunArchivePath(base, now.Add(-time.Hour))
+	for _, p := range []string{oldRun, lastRun} {
+		w, err := NewWARCWriter(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Both files were just written; the old run is pruned by its name.
+	n, err := PruneArchives(dir, 24*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("removed %d files, want 1", n)
+	}
+	if _, err := os.Stat(oldRun); !os.IsNotExist(err) {
+		t.Errorf("old run's archive still exists (%v)", err)
+	}
+	if _, err := os.Stat(lastRun); err != nil {
+		t.Errorf("last run's archive: %v", err)
+	}
+}
+
+func TestListArchives(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"pages-20261016T120000.warc", "pages-20261015T120000.warc.gz", "run.json", "pages.warc"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := ListArchives(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"pages-20261015T120000.warc.gz", "pages-20261016T120000.warc", "pages.warc"}
+	if len(got) != len(want) {
+		t.Fatalf("ListArchives = %v, want %v", got, want)
+	}
+	for i := range want {
+		if filepath.Base(got[i]) != want[i] {
+			t.Errorf("ListArchives[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
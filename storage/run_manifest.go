@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"airbnb-scraper/models"
+)
+
+// SaveRunManifest writes m to path as indented JSON. The file is written
+// next to path and renamed into place, so a tool polling for it never reads
+// a partial manifest.
+func SaveRunManifest(path string, m *models.RunManifest) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("run manifest: create output dir: %w", err)
+	}
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("run manifest: encode: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("run manifest: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("run manifest: write: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("run manifest: write: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("run manifest: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("run manifest: write %q: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+func TestSaveRunManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output", "run.json")
+	m := &models.RunManifest{
+		Version:   "abc123",
+		Status:    "ok",
+		StartedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Stages:    []models.StageTiming{{Stage: "scrape", Seconds: 1.5}},
+		Counts:    map[string]int{"raw": 10, "stored": 9},
+		Outputs:   map[string]string{"raw_csv": "./output/raw_listings.csv"},
+		Config:    map[string]string{"PostgresHost": "localhost"},
+	}
+	for i := 0; i < 2; i++ { // the second save replaces the first
+		if err := SaveRunManifest(path, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got models.RunManifest
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("decode: %v\n%s", err, b)
+	}
+	if got.Version != "abc123" || got.Counts["stored"] != 9 || got.Stages[0].Stage != "scrape" {
+		t.Errorf("round trip = %+v", got)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("output dir has %d entries, want only run.json", len(entries))
+	}
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// formulaPrefixes are leading characters spreadsheet apps treat as the start
+// of a formula (CSV/formula injection).
+const formulaPrefixes = "=+-@\t\r"
+
+// SanitizeCSVField makes scraped text safe to open in a spreadsheet: control
+// characters other than newline and tab are stripped, and a value starting
+// with a formula character is prefixed with a single quote. Plain signed
+// numbers such as "-33.86" are left untouched.
+func SanitizeCSVField(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+
+	if s == "" || !strings.ContainsRune(formulaPrefixes, rune(s[0])) {
+		return s
+	}
+	if s[0] == '-' || s[0] == '+' {
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			return s
+		}
+	}
+	return "'" + s
+}
+
+// sanitizeRow applies SanitizeCSVField to every field when enabled.
+func sanitizeRow(row []string, enabled bool) []string {
+	if !enabled {
+		return row
+	}
+	for i, f := range row {
+		row[i] = SanitizeCSVField(f)
+	}
+	return row
+}
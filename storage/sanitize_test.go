@@ -0,0 +1,27 @@
+package storage
+
+import "testing"
+
+func TestSanitizeCSVField(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Cosy loft", "Cosy loft"},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+1 555 0100 call now", "'+1 555 0100 call now"},
+		{"@SUM(A1:A2)", "'@SUM(A1:A2)"},
+		{"-2+3", "'-2+3"},
+		{"-33.8688", "-33.8688"},
+		{"+4.5", "+4.5"},
+		{"\tleading tab", "'\tleading tab"},
+		{"bell\x07 and nul\x00 gone", "bell and nul gone"},
+		{"line one\nline two", "line one\nline two"},
+		{"\x1b=cmd", "'=cmd"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := SanitizeCSVField(tt.in); got != tt.want {
+			t.Errorf("SanitizeCSVField(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
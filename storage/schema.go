@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// ensureSchema creates the first schema on the connection's search_path,
+// which is where migrate creates its tables. Projects get their own schema
+// this way without every statement having to qualify table names.
+func (pw *PostgresWriter) ensureSchema() error {
+	var path string
+	if err := pw.db.QueryRow(`SELECT current_setting('search_path')`).Scan(&path); err != nil {
+		return fmt.Errorf("read search_path: %w", err)
+	}
+	schema := firstSchema(path)
+	if schema == "" || schema == "public" || schema == "$user" {
+		return nil
+	}
+	if _, err := pw.db.Exec(`CREATE SCHEMA IF NOT EXISTS ` + pq.QuoteIdentifier(schema)); err != nil {
+		return fmt.Errorf("create schema %s: %w", schema, err)
+	}
+	return nil
+}
+
+// firstSchema returns the first entry of a search_path setting, unquoted.
+func firstSchema(path string) string {
+	first := strings.TrimSpace(strings.Split(path, ",")[0])
+	return strings.Trim(first, `"`)
+}
+
+// Schemas lists the schemas that contain a listings table — one per project
+// that has completed at least one run.
+func (pw *PostgresWriter) Schemas() ([]string, error) {
+	rows, err := pw.db.Query(`
+		SELECT table_schema FROM information_schema.tables
+		WHERE table_name = 'listings'
+		ORDER BY table_schema
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: list schemas: %w", err)
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, fmt.Errorf("postgres: scan schema: %w", err)
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// CheckWritable creates and fills a scratch table in the project's schema
+// inside a transaction it rolls back, so it proves the credentials may
+// write there without leaving anything behind.
+func (pw *PostgresWriter) CheckWritable() error {
+	tx, err := pw.db.Begin()
+	if err != nil {
+		return fmt.Errorf("postgres: begin: %w", err)
+	}
+	defer tx.Rollback()
+	var path string
+	if err := tx.QueryRow(`SELECT current_setting('search_path')`).Scan(&path); err != nil {
+		return fmt.Errorf("postgres: read search_path: %w", err)
+	}
+	if schema := firstSchema(path); schema != "" && schema != "public" && schema != "$user" {
+		if _, err := tx.Exec(`CREATE SCHEMA IF NOT EXISTS ` + pq.QuoteIdentifier(schema)); err != nil {
+			return fmt.Errorf("postgres: create schema %s: %w", schema, err)
+		}
+	}
+	if _, err := tx.Exec(`CREATE TABLE preflight_check (id INT)`); err != nil {
+		return fmt.Errorf("postgres: create table: %w", err)
+	}
+	if _, err := tx.Exec(`INSERT INTO preflight_check VALUES (1)`); err != nil {
+		return fmt.Errorf("postgres: insert: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package storage
+
+import "testing"
+
+func TestFirstSchema(t *testing.T) {
+	tests := map[string]string{
+		"project_bali":          "project_bali",
+		`"$user", public`:       "$user",
+		` "project_x" , public`: "project_x",
+		"":                      "",
+	}
+	for in, want := range tests {
+		if got := firstSchema(in); got != want {
+			t.Errorf("firstSchema(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"airbnb-scraper/models"
+)
+
+// WriteShortlistCSV writes budget-finder results to a fresh CSV file.
+// columns selects and orders the ShortlistFields; empty means
+// DefaultShortlistColumns.
+func WriteShortlistCSV(path string, entries []*models.ShortlistEntry, format CSVFormat, columns []string) error {
+	if len(columns) == 0 {
+		columns = DefaultShortlistColumns
+	}
+	fields, err := SelectFields(ShortlistFields, columns)
+	if err != nil {
+		return fmt.Errorf("csv: shortlist columns: %w", err)
+	}
+	return writeFieldsCSV(path, fields, entries, format)
+}
+
+// WriteListingsCSV writes stored listings to a fresh CSV file with the given
+// fields, e.g. a ListingProfiles layout or a SelectFields choice.
+func WriteListingsCSV(path string, listings []*models.Listing, format CSVFormat, fields []Field[*models.Listing]) error {
+	return writeFieldsCSV(path, fields, listings, format)
+}
+
+// EncodeListingsCSV writes listings as CSV to w, e.g. standard output.
+func EncodeListingsCSV(w io.Writer, listings []*models.Listing, format CSVFormat, fields []Field[*models.Listing]) error {
+	return encodeFieldsCSV(w, fields, listings, format)
+}
+
+func writeFieldsCSV[T any](path string, fields []Field[T], rows []T, format CSVFormat) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("csv: create output dir: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("csv: create file %q: %w", path, err)
+	}
+	defer f.Close()
+	return encodeFieldsCSV(f, fields, rows, format)
+}
+
+func encodeFieldsCSV[T any](out io.Writer, fields []Field[T], rows []T, format CSVFormat) error {
+	w, err := newCSVRowWriter(out, format)
+	if err != nil {
+		return fmt.Errorf("csv: write BOM: %w", err)
+	}
+	if err := w.Write(fieldNames(fields), true); err != nil {
+		return fmt.Errorf("csv: write header: %w", err)
+	}
+	for _, v := range rows {
+		if err := w.Write(csvRow(fields, v), false); err != nil {
+			return fmt.Errorf("csv: write row: %w", err)
+		}
+	}
+	return w.Flush()
+}
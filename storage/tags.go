@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/lib/pq"
+
+	"airbnb-scraper/models"
+)
+
+// EnsureTagTables creates the listing_tags and listing_notes tables. They
+// are keyed by listing URL, so annotations survive the listings table being
+// rebuilt every run.
+func (pw *PostgresWriter) EnsureTagTables() error {
+	_, err := pw.db.Exec(`
+		CREATE TABLE IF NOT EXISTS listing_tags (
+			url        TEXT        NOT NULL,
+			tag        TEXT        NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (url, tag)
+		);
+		CREATE INDEX IF NOT EXISTS idx_listing_tags_tag ON listing_tags(tag);
+
+		CREATE TABLE IF NOT EXISTS listing_notes (
+			url        TEXT PRIMARY KEY,
+			note       TEXT        NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("postgres: create tag tables: %w", err)
+	}
+	return nil
+}
+
+// NormalizeTag lowercases and trims a tag. Tags are single words: empty
+// tags and tags with spaces or commas are rejected.
+func NormalizeTag(tag string) (string, error) {
+	t := strings.ToLower(strings.TrimSpace(tag))
+	if t == "" || strings.ContainsFunc(t, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		return "", fmt.Errorf("invalid tag %q (want one word without commas)", tag)
+	}
+	return t, nil
+}
+
+// AddTags tags the listing at url; tags it already has are kept as they are.
+func (pw *PostgresWriter) AddTags(url string, tags []string) error {
+	for _, tag := range tags {
+		if _, err := pw.db.Exec(`
+			INSERT INTO listing_tags (url, tag) VALUES ($1, $2)
+			ON CONFLICT (url, tag) DO NOTHING
+		`, url, tag); err != nil {
+			return fmt.Errorf("postgres: add tag %q: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// RemoveTags removes tags from the listing at url and returns how many it
+// had.
+func (pw *PostgresWriter) RemoveTags(url string, tags []string) (int64, error) {
+	res, err := pw.db.Exec(`DELETE FROM listing_tags WHERE url = $1 AND tag = ANY($2)`, url, pq.Array(tags))
+	if err != nil {
+		return 0, fmt.Errorf("postgres: remove tags: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// SetNote replaces the note on the listing at url; an empty note deletes it.
+func (pw *PostgresWriter) SetNote(url, note string) error {
+	var err error
+	if strings.TrimSpace(note) == "" {
+		_, err = pw.db.Exec(`DELETE FROM listing_notes WHERE url = $1`, url)
+	} else {
+		_, err = pw.db.Exec(`
+			INSERT INTO listing_notes (url, note) VALUES ($1, $2)
+			ON CONFLICT (url) DO UPDATE SET note = EXCLUDED.note, updated_at = NOW()
+		`, url, note)
+	}
+	if err != nil {
+		return fmt.Errorf("postgres: set note: %w", err)
+	}
+	return nil
+}
+
+// AttachTags fills in the tags, sorted, and note of each listing.
+func (pw *PostgresWriter) AttachTags(listings []*models.Listing) error {
+	byURL := make(map[string]*models.Listing, len(listings))
+	for _, l := range listings {
+		l.Tags, l.Note = nil, ""
+		byURL[l.URL] = l
+	}
+
+	rows, err := pw.db.Query(`SELECT url, tag FROM listing_tags ORDER BY url, tag`)
+	if err != nil {
+		return fmt.Errorf("postgres: fetch tags: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var url, tag string
+		if err := rows.Scan(&url, &tag); err != nil {
+			return fmt.Errorf("postgres: scan tag: %w", err)
+		}
+		if l := byURL[url]; l != nil {
+			l.Tags = append(l.Tags, tag)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	notes, err := pw.db.Query(`SELECT url, note FROM listing_notes`)
+	if err != nil {
+		return fmt.Errorf("postgres: fetch notes: %w", err)
+	}
+	defer notes.Close()
+	for notes.Next() {
+		var url, note string
+		if err := notes.Scan(&url, &note); err != nil {
+			return fmt.Errorf("postgres: scan note: %w", err)
+		}
+		if l := byURL[url]; l != nil {
+			l.Note = note
+		}
+	}
+	return notes.Err()
+}
+
+// TagCounts returns how many listings carry each tag.
+func (pw *PostgresWriter) TagCounts() (map[string]int, error) {
+	rows, err := pw.db.Query(`SELECT tag, COUNT(*) FROM listing_tags GROUP BY tag`)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: count tags: %w", err)
+	}
+	defer rows.Close()
+	counts := make(map[string]int)
+	for rows.Next() {
+		var tag string
+		var n int
+		if err := rows.Scan(&tag, &n); err != nil {
+			return nil, fmt.Errorf("postgres: scan tag count: %w", err)
+		}
+		counts[tag] = n
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+// ErrViewNotFound is returned for a saved view name that does not exist.
+var ErrViewNotFound = errors.New("saved view not found")
+
+// viewNameRegexp is the shape of a saved view name: lowercase letters,
+// digits, "-" and "_", so names are safe in flags, URLs and .env lists.
+var viewNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// SavedView is a named ListingQuery that reports, exports and watch refer
+// to instead of repeating filter flags.
+type SavedView struct {
+	Name      string
+	Query     ListingQuery
+	UpdatedAt time.Time
+}
+
+// CheckViewName validates a saved view name.
+func CheckViewName(name string) error {
+	if !viewNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid view name %q (want lowercase letters, digits, - and _)", name)
+	}
+	return nil
+}
+
+// EnsureViewTable creates saved_views, the named listing filters.
+func (pw *PostgresWriter) EnsureViewTable() error {
+	_, err := pw.db.Exec(`
+		CREATE TABLE IF NOT EXISTS saved_views (
+			name       TEXT PRIMARY KEY,
+			query      JSONB       NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("postgres: create saved_views: %w", err)
+	}
+	return nil
+}
+
+// SaveView creates or replaces the view name. The query's sort key is
+// checked first, so a saved view always renders.
+func (pw *PostgresWriter) SaveView(name string, q ListingQuery) error {
+	if err := CheckViewName(name); err != nil {
+		return err
+	}
+	if _, _, err := q.SQL(); err != nil {
+		return err
+	}
+	doc, err := json.Marshal(q)
+	if err != nil {
+		return fmt.Errorf("postgres: encode view %q: %w", name, err)
+	}
+	if _, err := pw.db.Exec(`
+		INSERT INTO saved_views (name, query) VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET query = EXCLUDED.query, updated_at = NOW()
+	`, name, string(doc)); err != nil {
+		return fmt.Errorf("postgres: save view %q: %w", name, err)
+	}
+	return nil
+}
+
+// View returns the query of the saved view name, or ErrViewNotFound.
+func (pw *PostgresWriter) View(name string) (ListingQuery, error) {
+	var q ListingQuery
+	var doc []byte
+	err := pw.db.QueryRow(`SELECT query FROM saved_views WHERE name = $1`, name).Scan(&doc)
+	if errors.Is(err, sql.ErrNoRows) {
+		return q, fmt.Errorf("%w: %q", ErrViewNotFound, name)
+	}
+	if err != nil {
+		return q, fmt.Errorf("postgres: fetch view %q: %w", name, err)
+	}
+	if err := json.Unmarshal(doc, &q); err != nil {
+		return q, fmt.Errorf("postgres: decode view %q: %w", name, err)
+	}
+	return q, nil
+}
+
+// Views returns every saved view by name.
+func (pw *PostgresWriter) Views() ([]SavedView, error) {
+	rows, err := pw.db.Query(`SELECT name, query, updated_at FROM saved_views ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: fetch views: %w", err)
+	}
+	defer rows.Close()
+
+	var views []SavedView
+	for rows.Next() {
+		var v SavedView
+		var doc []byte
+		if err := rows.Scan(&v.Name, &doc, &v.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("postgres: scan view: %w", err)
+		}
+		if err := json.Unmarshal(doc, &v.Query); err != nil {
+			return nil, fmt.Errorf("postgres: decode view %q: %w", v.Name, err)
+		}
+		views = append(views, v)
+	}
+	return views, rows.Err()
+}
+
+// DeleteView deletes the saved view name, or returns ErrViewNotFound.
+func (pw *PostgresWriter) DeleteView(name string) error {
+	res, err := pw.db.Exec(`DELETE FROM saved_views WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("postgres: delete view %q: %w", name, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %q", ErrViewNotFound, name)
+	}
+	return nil
+}
+
+// ViewListings returns the listings matching the saved view name, with
+// their tags and notes.
+func (pw *PostgresWriter) ViewListings(name string) ([]*models.Listing, error) {
+	q, err := pw.View(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := pw.EnsureTagTables(); err != nil {
+		return nil, err
+	}
+	listings, err := pw.QueryListings(q)
+	if err != nil {
+		return nil, err
+	}
+	return listings, pw.AttachTags(listings)
+}
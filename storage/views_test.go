@@ -0,0 +1,16 @@
+package storage
+
+import "testing"
+
+func TestCheckViewName(t *testing.T) {
+	for _, name := range []string{"bangkok-cheap", "q3_review", "2024"} {
+		if err := CheckViewName(name); err != nil {
+			t.Errorf("%q rejected: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "Bangkok", "under $60", "-x", "a,b"} {
+		if err := CheckViewName(name); err == nil {
+			t.Errorf("%q accepted", name)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+// ReadWARC returns the response records of a WARC file as page captures, in
+// file order. Plain and gzip-compressed (.warc.gz) archives are accepted.
+// Request headers are not restored; only the URL, status, response headers,
+// body and capture time are.
+func ReadWARC(path string) ([]*models.PageCapture, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("warc: open %q: %w", path, err)
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("warc: gzip %q: %w", path, err)
+		}
+		defer gz.Close()
+		br = bufio.NewReader(gz)
+	}
+
+	var pages []*models.PageCapture
+	for {
+		headers, block, err := readWARCRecord(br)
+		if err == io.EOF {
+			return pages, nil
+		}
+		if err != nil {
+			return pages, fmt.Errorf("warc: %s: record %d: %w", path, len(pages)+1, err)
+		}
+		if headers["warc-type"] != "response" {
+			continue
+		}
+		page, err := parseResponseBlock(block)
+		if err != nil {
+			return pages, fmt.Errorf("warc: %s: response for %s: %w", path, headers["warc-target-uri"], err)
+		}
+		page.URL = headers["warc-target-uri"]
+		page.CapturedAt, _ = time.Parse("2006-01-02T15:04:05Z", headers["warc-date"])
+		pages = append(pages, page)
+	}
+}
+
+// readWARCRecord reads one record and returns its headers (lower-cased
+// names) and content block. io.EOF means the archive ended cleanly.
+func readWARCRecord(br *bufio.Reader) (map[string]string, []byte, error) {
+	// Skip the blank lines that terminate the previous record.
+	var version string
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && strings.TrimSpace(line) == "" {
+				return nil, nil, io.EOF
+			}
+			return nil, nil, err
+		}
+		if version = strings.TrimSpace(line); version != "" {
+			break
+		}
+	}
+	if !strings.HasPrefix(version, "WARC/") {
+		return nil, nil, fmt.Errorf("expected WARC version line, got %q", version)
+	}
+
+	headers := make(map[string]string)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, nil, fmt.Errorf("headers: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, nil, fmt.Errorf("malformed header %q", line)
+		}
+		headers[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+
+	length, err := strconv.Atoi(headers["content-length"])
+	if err != nil || length < 0 {
+		return nil, nil, fmt.Errorf("invalid Content-Length %q", headers["content-length"])
+	}
+	block := make([]byte, length)
+	if _, err := io.ReadFull(br, block); err != nil {
+		return nil, nil, fmt.Errorf("block: %w", err)
+	}
+	return headers, block, nil
+}
+
+// parseResponseBlock parses an application/http response block.
+func parseResponseBlock(block []byte) (*models.PageCapture, error) {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(block)), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(map[string]string, len(resp.Header))
+	for k, v := range resp.Header {
+		headers[k] = strings.Join(v, "\n")
+	}
+	return &models.PageCapture{
+		Method:          http.MethodGet,
+		StatusCode:      resp.StatusCode,
+		StatusText:      strings.TrimSpace(strings.TrimPrefix(resp.Status, strconv.Itoa(resp.StatusCode))),
+		Protocol:        resp.Proto,
+		ResponseHeaders: headers,
+		Body:            body,
+	}, nil
+}
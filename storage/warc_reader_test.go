@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+func TestReadWARCRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pages.warc")
+	w, err := NewWARCWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	captured := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	pages := []*models.PageCapture{
+		{URL: "https://www.airbnb.com/rooms/1", StatusCode: 200,
+			ResponseHeaders: map[string]string{"Content-Type": "text/html"},
+			Body:            []byte("<html><h1>One</h1></html>"), CapturedAt: captured},
+		{URL: "https://www.airbnb.com/rooms/2", StatusCode: 404,
+			Body: []byte("gone\r\n\r\nWARC/1.1 lookalike"), CapturedAt: captured},
+	}
+	for _, p := range pages {
+		if err := w.WritePage(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Close()
+
+	got, err := ReadWARC(path)
+	if err != nil {
+		t.Fatalf("ReadWARC: %v", err)
+	}
+	if len(got) != len(pages) {
+		t.Fatalf("got %d pages, want %d", len(got), len(pages))
+	}
+	for i, want := range pages {
+		g := got[i]
+		if g.URL != want.URL || g.StatusCode != want.StatusCode || string(g.Body) != string(want.Body) {
+			t.Errorf("page %d = %s %d %q, want %s %d %q", i, g.URL, g.StatusCode, g.Body, want.URL, want.StatusCode, want.Body)
+		}
+		if !g.CapturedAt.Equal(captured) {
+			t.Errorf("page %d captured at %v, want %v", i, g.CapturedAt, captured)
+		}
+	}
+	if ct := got[0].ResponseHeaders["Content-Type"]; ct != "text/html" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestReadWARCGzip(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "pages.warc")
+	w, err := NewWARCWriter(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WritePage(&models.PageCapture{URL: "https://www.airbnb.com/rooms/1", StatusCode: 200, Body: []byte("ok")})
+	w.Close()
+
+	data, err := os.ReadFile(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzPath := plain + ".gz"
+	f, err := os.Create(gzPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := gzip.NewWriter(f)
+	zw.Write(data)
+	zw.Close()
+	f.Close()
+
+	got, err := ReadWARC(gzPath)
+	if err != nil {
+		t.Fatalf("ReadWARC: %v", err)
+	}
+	if len(got) != 1 || string(got[0].Body) != "ok" {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestReadWARCRejectsGarbage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.warc")
+	os.WriteFile(path, []byte("not a warc\n"), 0644)
+	if _, err := ReadWARC(path); err == nil {
+		t.Fatal("expected an error")
+	}
+}
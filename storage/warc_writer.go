@@ -0,0 +1,244 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+const warcVersion = "WARC/1.1"
+
+// runStampLayout is the run timestamp RunArchivePath puts in file names.
+const runStampLayout = "20060102T150405"
+
+// RunArchivePath is the archive file of the run started at the given time:
+// base with the run's UTC timestamp before its extension, e.g.
+// output/pages.warc.gz → output/pages-20261016T141200.warc.gz. Each run
+// writes its own file, so earlier runs' captures are kept.
+func RunArchivePath(base string, at time.Time) string {
+	ext := ".warc"
+	switch {
+	case strings.HasSuffix(base, ".warc.gz"):
+		ext = ".warc.gz"
+	case strings.HasSuffix(base, ".warc"):
+	default:
+		ext = filepath.Ext(base)
+	}
+	return strings.TrimSuffix(base, ext) + "-" + at.UTC().Format(runStampLayout) + ext
+}
+
+// archiveRunTime is the run time RunArchivePath encoded in name, if any.
+func archiveRunTime(name string) (time.Time, bool) {
+	stem := strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ".warc")
+	i := strings.LastIndexByte(stem, '-')
+	if i < 0 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(runStampLayout, stem[i+1:])
+	return t, err == nil
+}
+
+// WARCWriter appends captured request/response pairs to a WARC file.
+// It is safe for concurrent use.
+type WARCWriter struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewWARCWriter creates (or truncates) the WARC file at the given path and
+// writes the leading warcinfo record. Intermediate directories are created
+// automatically.
+func NewWARCWriter(path string) (*WARCWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("warc: create output dir: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("warc: create file %q: %w", path, err)
+	}
+
+	w := &WARCWriter{file: f}
+	info := "software: airbnb-scraper\r\nformat: WARC File Format 1.1\r\n"
+	if err := w.writeRecord([][2]string{
+		{"WARC-Type", "warcinfo"},
+		{"WARC-Record-ID", newRecordID()},
+		{"WARC-Date", warcDate(time.Now())},
+		{"WARC-Filename", filepath.Base(path)},
+		{"Content-Type", "application/warc-fields"},
+	}, []byte(info)); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("warc: write warcinfo: %w", err)
+	}
+
+	return w, nil
+}
+
+// WritePage writes a request record followed by its response record.
+func (w *WARCWriter) WritePage(p *models.PageCapture) error {
+	date := warcDate(p.CapturedAt)
+	requestID := newRecordID()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.writeRecord([][2]string{
+		{"WARC-Type", "request"},
+		{"WARC-Record-ID", requestID},
+		{"WARC-Date", date},
+		{"WARC-Target-URI", p.URL},
+		{"Content-Type", "application/http;msgtype=request"},
+	}, httpRequestBlock(p)); err != nil {
+		return fmt.Errorf("warc: write request record: %w", err)
+	}
+
+	if err := w.writeRecord([][2]string{
+		{"WARC-Type", "response"},
+		{"WARC-Record-ID", newRecordID()},
+		{"WARC-Date", date},
+		{"WARC-Target-URI", p.URL},
+		{"WARC-Concurrent-To", requestID},
+		{"Content-Type", "application/http;msgtype=response"},
+	}, httpResponseBlock(p)); err != nil {
+		return fmt.Errorf("warc: write response record: %w", err)
+	}
+	return nil
+}
+
+// Close flushes and closes the underlying file.
+func (w *WARCWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+func (w *WARCWriter) writeRecord(headers [][2]string, block []byte) error {
+	var buf bytes.Buffer
+	buf.WriteString(warcVersion + "\r\n")
+	for _, h := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", h[0], h[1])
+	}
+	fmt.Fprintf(&buf, "Content-Length: %d\r\n\r\n", len(block))
+	buf.Write(block)
+	buf.WriteString("\r\n\r\n")
+
+	_, err := w.file.Write(buf.Bytes())
+	return err
+}
+
+// ── Helpers ──────────────────────────────────────────────────────────────────
+
+func httpRequestBlock(p *models.PageCapture) []byte {
+	method := p.Method
+	if method == "" {
+		method = "GET"
+	}
+	target := p.URL
+	host := ""
+	if u, err := url.Parse(p.URL); err == nil {
+		target = u.RequestURI()
+		host = u.Host
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s HTTP/1.1\r\n", method, target)
+	headers := p.RequestHeaders
+	if _, ok := lookupHeader(headers, "Host"); !ok && host != "" {
+		fmt.Fprintf(&buf, "Host: %s\r\n", host)
+	}
+	writeHeaders(&buf, headers, nil)
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
+
+func httpResponseBlock(p *models.PageCapture) []byte {
+	status := p.StatusText
+	if status == "" {
+		status = httpStatusText(p.StatusCode)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "HTTP/1.1 %d %s\r\n", p.StatusCode, status)
+	// The browser hands us the decoded body, so transfer-level headers would
+	// no longer describe the payload accurately.
+	writeHeaders(&buf, p.ResponseHeaders, map[string]bool{
+		"content-encoding":  true,
+		"content-length":    true,
+		"transfer-encoding": true,
+	})
+	fmt.Fprintf(&buf, "Content-Length: %d\r\n\r\n", len(p.Body))
+	buf.Write(p.Body)
+	return buf.Bytes()
+}
+
+func writeHeaders(buf *bytes.Buffer, headers map[string]string, skip map[string]bool) {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		if skip[strings.ToLower(k)] || strings.HasPrefix(k, ":") {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		// CDP joins repeated headers with newlines; WARC needs one per line.
+		for _, v := range strings.Split(headers[k], "\n") {
+			fmt.Fprintf(buf, "%s: %s\r\n", k, v)
+		}
+	}
+}
+
+func lookupHeader(headers map[string]string, name string) (string, bool) {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func httpStatusText(code int) string {
+	switch code {
+	case 200:
+		return "OK"
+	case 301:
+		return "Moved Permanently"
+	case 302:
+		return "Found"
+	case 304:
+		return "Not Modified"
+	case 403:
+		return "Forbidden"
+	case 404:
+		return "Not Found"
+	case 410:
+		return "Gone"
+	case 429:
+		return "Too Many Requests"
+	}
+	return ""
+}
+
+func warcDate(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+func newRecordID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("<urn:uuid:%x-%x-%x-%x-%x>", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
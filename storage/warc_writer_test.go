@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"airbnb-scraper/models"
+)
+
+func TestWARCWriterWritesRequestResponsePair(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pages.warc")
+	w, err := NewWARCWriter(path)
+	if err != nil {
+		t.Fatalf("NewWARCWriter: %v", err)
+	}
+
+	err = w.WritePage(&models.PageCapture{
+		URL:             "https://www.airbnb.com/rooms/1?adults=2",
+		Method:          "GET",
+		StatusCode:      200,
+		ResponseHeaders: map[string]string{"Content-Type": "text/html", "Content-Encoding": "br"},
+		Body:            []byte("<html>ok</html>"),
+		CapturedAt:      time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("WritePage: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+
+	for _, want := range []string{
+		"WARC-Type: warcinfo",
+		"WARC-Type: request",
+		"WARC-Type: response",
+		"WARC-Date: 2024-05-01T10:00:00Z",
+		"GET /rooms/1?adults=2 HTTP/1.1\r\nHost: www.airbnb.com",
+		"HTTP/1.1 200 OK\r\n",
+		"Content-Length: 15\r\n\r\n<html>ok</html>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WARC output missing %q", want)
+		}
+	}
+	if strings.Contains(out, "Content-Encoding") {
+		t.Error("Content-Encoding should be dropped for decoded bodies")
+	}
+	if got := strings.Count(out, "WARC/1.1\r\n"); got != 3 {
+		t.Errorf("record count: got %d, want 3", got)
+	}
+}
+
+func TestRunArchivePath(t *testing.T) {
+	at := time.Date(2026, 10, 16, 14, 12, 0, 0, time.FixedZone("CEST", 2*3600))
+	tests := []struct{ base, want string }{
+		{"output/pages.warc", "output/pages-20261016T121200.warc"},
+		{"output/pages.warc.gz", "output/pages-20261016T121200.warc.gz"},
+		{"archive", "archive-20261016T121200"},
+	}
+	for _, tt := range tests {
+		got := RunArchivePath(tt.base, at)
+		if got != tt.want {
+			t.Errorf("RunArchivePath(%q) = %q, want %q", tt.base, got, tt.want)
+		}
+		if run, ok := archiveRunTime(filepath.Base(got)); !ok || !run.Equal(at) {
+			t.Errorf("archiveRunTime(%q) = %v, %v; want %v", got, run, ok, at)
+		}
+	}
+	if _, ok := archiveRunTime("pages.warc"); ok {
+		t.Error("archiveRunTime found a run time in a name without one")
+	}
+}
@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"airbnb-scraper/models"
+)
+
+// EnsureWatchTable creates watch_history, the per-listing log of watch
+// checks that changed something, and watch_calendar, the latest known
+// availability of each watched listing per day, if they are missing. They
+// live outside migrate so watching works without (and survives) scraping
+// runs.
+func (pw *PostgresWriter) EnsureWatchTable() error {
+	if err := pw.ensureSchema(); err != nil {
+		return err
+	}
+	_, err := pw.db.Exec(`
+		CREATE TABLE IF NOT EXISTS watch_history (
+			id              BIGSERIAL PRIMARY KEY,
+			url             TEXT          NOT NULL,
+			title           TEXT          NOT NULL DEFAULT '',
+			price           NUMERIC(10,2) NOT NULL DEFAULT 0,
+			status          VARCHAR(20)   NOT NULL,
+			previous_price  NUMERIC(10,2),
+			previous_status VARCHAR(20),
+			checked_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_watch_history_url ON watch_history(url, checked_at);
+		ALTER TABLE watch_history ADD COLUMN IF NOT EXISTS rating NUMERIC(4,2) NOT NULL DEFAULT 0;
+		ALTER TABLE watch_history ADD COLUMN IF NOT EXISTS location TEXT NOT NULL DEFAULT '';
+
+		CREATE TABLE IF NOT EXISTS watch_calendar (
+			url        TEXT        NOT NULL,
+			day        DATE        NOT NULL,
+			blocked    BOOLEAN     NOT NULL,
+			checked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (url, day)
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("postgres: create watch tables: %w", err)
+	}
+	return nil
+}
+
+// LatestWatchStates returns the last recorded state of every watched URL.
+func (pw *PostgresWriter) LatestWatchStates() (map[string]*models.WatchState, error) {
+	rows, err := pw.db.Query(`
+		SELECT DISTINCT ON (url) url, title, location, price::float8, status, rating::float8, checked_at
+		FROM watch_history
+		ORDER BY url, checked_at DESC, id DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: fetch watch states: %w", err)
+	}
+	defer rows.Close()
+
+	states := make(map[string]*models.WatchState)
+	for rows.Next() {
+		s := &models.WatchState{}
+		if err := rows.Scan(&s.URL, &s.Title, &s.Location, &s.Price, &s.Status, &s.Rating, &s.CheckedAt); err != nil {
+			return nil, fmt.Errorf("postgres: scan watch state: %w", err)
+		}
+		states[s.URL] = s
+	}
+	return states, rows.Err()
+}
+
+// RecordWatchChanges stores one watch_history row per change, with the
+// previous price and status alongside the new ones, in a single
+// transaction.
+func (pw *PostgresWriter) RecordWatchChanges(changes []*models.WatchChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	tx, err := pw.db.Begin()
+	if err != nil {
+		return fmt.Errorf("postgres: begin watch history: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO watch_history (url, title, price, status, previous_price, previous_status, checked_at, rating, location)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`)
+	if err != nil {
+		return fmt.Errorf("postgres: prepare watch history: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, c := range changes {
+		var prevPrice, prevStatus interface{}
+		if c.Previous != nil {
+			prevPrice, prevStatus = c.Previous.Price, c.Previous.Status
+		}
+		s := c.Current
+		if _, err := stmt.Exec(s.URL, s.Title, s.Price, s.Status, prevPrice, prevStatus, s.CheckedAt, s.Rating, s.Location); err != nil {
+			return fmt.Errorf("postgres: insert watch history: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("postgres: commit watch history: %w", err)
+	}
+	return nil
+}
+
+// RecordWatchCalendar upserts the calendar days a check of url read, so
+// each day keeps the availability of the latest check that showed it.
+func (pw *PostgresWriter) RecordWatchCalendar(url string, days []models.CalendarDay, checkedAt time.Time) error {
+	if len(days) == 0 {
+		return nil
+	}
+	tx, err := pw.db.Begin()
+	if err != nil {
+		return fmt.Errorf("postgres: begin watch calendar: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO watch_calendar (url, day, blocked, checked_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (url, day) DO UPDATE SET blocked = EXCLUDED.blocked, checked_at = EXCLUDED.checked_at
+	`)
+	if err != nil {
+		return fmt.Errorf("postgres: prepare watch calendar: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, d := range days {
+		if _, err := stmt.Exec(url, d.Date.Format("2006-01-02"), d.Blocked, checkedAt); err != nil {
+			return fmt.Errorf("postgres: upsert watch calendar: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("postgres: commit watch calendar: %w", err)
+	}
+	return nil
+}
+
+// WatchCalendars returns the stored calendar of every watched listing from
+// the given day on, sorted by day.
+func (pw *PostgresWriter) WatchCalendars(from time.Time) (map[string][]models.CalendarDay, error) {
+	rows, err := pw.db.Query(`
+		SELECT url, day, blocked FROM watch_calendar
+		WHERE day >= $1
+		ORDER BY url, day
+	`, from.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("postgres: fetch watch calendars: %w", err)
+	}
+	defer rows.Close()
+
+	calendars := make(map[string][]models.CalendarDay)
+	for rows.Next() {
+		var url string
+		var d models.CalendarDay
+		if err := rows.Scan(&url, &d.Date, &d.Blocked); err != nil {
+			return nil, fmt.Errorf("postgres: scan watch calendar: %w", err)
+		}
+		d.Date = d.Date.UTC()
+		calendars[url] = append(calendars[url], d)
+	}
+	return calendars, rows.Err()
+}
+
+// WatchHistory returns every recorded state of the given URLs, oldest
+// first: the listing's state at any time is its last row before it.
+func (pw *PostgresWriter) WatchHistory(urls []string) (map[string][]models.WatchState, error) {
+	rows, err := pw.db.Query(`
+		SELECT url, title, location, price::float8, status, rating::float8, checked_at
+		FROM watch_history
+		WHERE url = ANY($1)
+		ORDER BY url, checked_at, id
+	`, pq.Array(urls))
+	if err != nil {
+		return nil, fmt.Errorf("postgres: fetch watch history: %w", err)
+	}
+	defer rows.Close()
+
+	history := make(map[string][]models.WatchState)
+	for rows.Next() {
+		var s models.WatchState
+		if err := rows.Scan(&s.URL, &s.Title, &s.Location, &s.Price, &s.Status, &s.Rating, &s.CheckedAt); err != nil {
+			return nil, fmt.Errorf("postgres: scan watch history: %w", err)
+		}
+		history[s.URL] = append(history[s.URL], s)
+	}
+	return history, rows.Err()
+}
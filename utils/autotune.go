@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// AutoTuner finds a detail-page concurrency by probing. It starts low and,
+// after every window of recorded pages, raises the level by one while the
+// success rate stays at or above the threshold and throughput (pages per
+// second: level over mean latency) keeps improving. A level that fails the
+// threshold steps back down; a level that gains less than 5% throughput is
+// undone and the tuner settles there, so extra tabs that only add latency
+// are not kept. It is safe for concurrent use.
+type AutoTuner struct {
+	max        int
+	minSuccess float64
+	window     int
+
+	mu      sync.Mutex
+	level   int
+	pages   int
+	ok      int
+	total   time.Duration
+	best    float64 // throughput at the level before the last raise
+	settled bool
+}
+
+// AutoTuneStep describes one level change, for logging.
+type AutoTuneStep struct {
+	From, To int
+	Success  float64       // share of successful pages in the window
+	Latency  time.Duration // mean page latency in the window
+	Reason   string        // "raise", "plateau" or "failures"
+}
+
+// NewAutoTuner returns a tuner starting at start concurrency and never
+// exceeding ceiling, evaluating every window pages.
+func NewAutoTuner(start, ceiling int, minSuccess float64, window int) *AutoTuner {
+	ceiling = max(ceiling, 1)
+	return &AutoTuner{
+		max:        ceiling,
+		minSuccess: minSuccess,
+		window:     max(window, 1),
+		level:      min(max(start, 1), ceiling),
+	}
+}
+
+// Level is the current concurrency.
+func (a *AutoTuner) Level() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.level
+}
+
+// Record adds one page outcome. At the end of a window it may change the
+// level, reporting the change.
+func (a *AutoTuner) Record(ok bool, latency time.Duration) (AutoTuneStep, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.pages++
+	a.total += latency
+	if ok {
+		a.ok++
+	}
+	if a.pages < a.window {
+		return AutoTuneStep{}, false
+	}
+
+	step := AutoTuneStep{
+		From:    a.level,
+		To:      a.level,
+		Success: float64(a.ok) / float64(a.pages),
+		Latency: a.total / time.Duration(a.pages),
+	}
+	a.pages, a.ok, a.total = 0, 0, 0
+	throughput := float64(a.level) / max(step.Latency.Seconds(), 1e-3)
+
+	switch {
+	case step.Success < a.minSuccess:
+		step.To, step.Reason = max(a.level-1, 1), "failures"
+		a.settled = true
+	case a.settled || a.level >= a.max:
+	case a.best > 0 && throughput < a.best*1.05:
+		step.To, step.Reason = a.level-1, "plateau"
+		a.settled = true
+	default:
+		a.best = throughput
+		step.To, step.Reason = a.level+1, "raise"
+	}
+	if step.To == step.From {
+		return step, false
+	}
+	a.level = step.To
+	return step, true
+}
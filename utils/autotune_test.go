@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// feed records a window of pages at the given success count and latency.
+func feed(a *AutoTuner, pages, ok int, latency time.Duration) (AutoTuneStep, bool) {
+	var step AutoTuneStep
+	var changed bool
+	for i := 0; i < pages; i++ {
+		step, changed = a.Record(i < ok, latency)
+	}
+	return step, changed
+}
+
+func TestAutoTunerRaisesUntilPlateau(t *testing.T) {
+	a := NewAutoTuner(1, 8, 0.9, 5)
+	if a.Level() != 1 {
+		t.Fatalf("start level = %d, want 1", a.Level())
+	}
+	// Latency flat while concurrency grows: throughput improves each step.
+	for want := 2; want <= 3; want++ {
+		step, changed := feed(a, 5, 5, 2*time.Second)
+		if !changed || step.Reason != "raise" || a.Level() != want {
+			t.Fatalf("step %+v changed=%v, level %d; want raise to %d", step, changed, a.Level(), want)
+		}
+	}
+	// At 3 the latency grows in step: no throughput gain, back to 2.
+	step, changed := feed(a, 5, 5, 3*time.Second)
+	if !changed || step.Reason != "plateau" || a.Level() != 2 {
+		t.Fatalf("step %+v changed=%v, level %d; want plateau back to 2", step, changed, a.Level())
+	}
+	// Settled: further good windows keep the level.
+	if _, changed := feed(a, 5, 5, time.Second); changed || a.Level() != 2 {
+		t.Errorf("settled tuner moved to %d", a.Level())
+	}
+}
+
+func TestAutoTunerBacksOffOnFailures(t *testing.T) {
+	a := NewAutoTuner(3, 4, 0.9, 10)
+	step, changed := feed(a, 10, 8, time.Second)
+	if !changed || step.Reason != "failures" || a.Level() != 2 || step.Success != 0.8 {
+		t.Fatalf("step %+v changed=%v, level %d; want failures down to 2", step, changed, a.Level())
+	}
+	feed(a, 10, 0, time.Second)
+	feed(a, 10, 0, time.Second)
+	if a.Level() != 1 {
+		t.Errorf("level = %d, want floor of 1", a.Level())
+	}
+}
+
+func TestAutoTunerCapsAtMax(t *testing.T) {
+	a := NewAutoTuner(5, 2, 0.9, 1)
+	if a.Level() != 2 {
+		t.Fatalf("start above max: level %d, want 2", a.Level())
+	}
+	if _, changed := a.Record(true, time.Second); changed {
+		t.Errorf("raised past max to %d", a.Level())
+	}
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// SeenSet is a thread-safe set of visited URLs: the exact URLSet or the
+// memory-bounded BloomFilter.
+type SeenSet interface {
+	// Add returns true if the URL was newly added, false if already present.
+	Add(url string) bool
+	Contains(url string) bool
+	Size() int
+}
+
+// BloomFilter is a SeenSet whose memory is fixed up front, for crawls that
+// track millions of URLs. Contains never misses an added URL but reports a
+// URL it never saw with probability up to the configured false-positive
+// rate, once the expected number of URLs has been added; the crawl then
+// skips that URL as a duplicate.
+type BloomFilter struct {
+	mu    sync.Mutex
+	bits  []uint64
+	m     uint64 // number of bits
+	k     uint64 // hash functions per URL
+	added int
+}
+
+// NewBloomFilter sizes a filter for expected URLs at false-positive rate
+// fpRate (e.g. 0.001). Out-of-range arguments are clamped.
+func NewBloomFilter(expected int, fpRate float64) *BloomFilter {
+	n := float64(max(expected, 1))
+	p := min(max(fpRate, 1e-9), 0.5)
+	m := math.Ceil(-n * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := max(math.Round(m/n*math.Ln2), 1)
+	words := (uint64(m) + 63) / 64
+	return &BloomFilter{bits: make([]uint64, words), m: words * 64, k: uint64(k)}
+}
+
+// positions calls fn with each of url's k bit positions, derived by
+// double hashing one mixed 64-bit FNV-1a sum, until fn returns false.
+func (b *BloomFilter) positions(url string, fn func(pos uint64) bool) bool {
+	h := fnv.New64a()
+	h.Write([]byte(url))
+	sum := h.Sum64()
+	// FNV barely changes the high bits for URLs differing only at the end;
+	// the murmur3 finalizer spreads them before the sum is split.
+	sum ^= sum >> 33
+	sum *= 0xff51afd7ed558ccd
+	sum ^= sum >> 33
+	sum *= 0xc4ceb9fe1a85ec53
+	sum ^= sum >> 33
+	h1, h2 := sum&0xffffffff, sum>>32|1
+	for i := uint64(0); i < b.k; i++ {
+		if !fn((h1 + i*h2) % b.m) {
+			return false
+		}
+	}
+	return true
+}
+
+// Add sets url's bits and returns true if at least one was unset.
+func (b *BloomFilter) Add(url string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	fresh := false
+	b.positions(url, func(pos uint64) bool {
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			b.bits[pos/64] |= 1 << (pos % 64)
+			fresh = true
+		}
+		return true
+	})
+	if fresh {
+		b.added++
+	}
+	return fresh
+}
+
+// Contains reports whether url may have been added.
+func (b *BloomFilter) Contains(url string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.positions(url, func(pos uint64) bool {
+		return b.bits[pos/64]&(1<<(pos%64)) != 0
+	})
+}
+
+// Size returns the number of URLs added, less any that collided with
+// earlier ones.
+func (b *BloomFilter) Size() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.added
+}
+
+// Bytes is the filter's memory footprint.
+func (b *BloomFilter) Bytes() int {
+	return len(b.bits) * 8
+}
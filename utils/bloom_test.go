@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBloomFilterNoFalseNegatives(t *testing.T) {
+	b := NewBloomFilter(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		url := fmt.Sprintf("https://www.airbnb.com/rooms/%d", i)
+		b.Add(url) // may collide with an earlier URL, rarely
+		if b.Add(url) {
+			t.Fatalf("second Add of %s reported new", url)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		if !b.Contains(fmt.Sprintf("https://www.airbnb.com/rooms/%d", i)) {
+			t.Fatalf("added URL %d not found", i)
+		}
+	}
+	if b.Size() < 980 {
+		t.Errorf("size = %d, want ~1000", b.Size())
+	}
+}
+
+func TestBloomFilterFalsePositiveRate(t *testing.T) {
+	const n = 20000
+	b := NewBloomFilter(n, 0.01)
+	for i := 0; i < n; i++ {
+		b.Add(fmt.Sprintf("https://www.airbnb.com/rooms/%d", i))
+	}
+	fp := 0
+	for i := n; i < 2*n; i++ {
+		if b.Contains(fmt.Sprintf("https://www.airbnb.com/rooms/%d", i)) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / n; rate > 0.02 {
+		t.Errorf("false-positive rate %.4f, want about 0.01", rate)
+	}
+	// ~9.6 bits per URL at 1%.
+	if b.Bytes() > n*10/8+8 {
+		t.Errorf("filter uses %d bytes for %d URLs", b.Bytes(), n)
+	}
+}
+
+var _ SeenSet = (*URLSet)(nil)
+var _ SeenSet = (*BloomFilter)(nil)
@@ -1,42 +1,191 @@
 package utils
 
 import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
 	"sync"
 	"time"
 )
 
-// WorkerPool manages a pool of goroutines with rate limiting.
+// ErrJobTimeout is reported for a job still running after the pool's job
+// timeout.
+var ErrJobTimeout = errors.New("worker pool: job timed out")
+
+// PanicError is a job panic recovered by the pool.
+type PanicError struct {
+	Value any
+	Stack []byte // stack of the panicking goroutine
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("worker pool: job panicked: %v", e.Value)
+}
+
+// WorkerPool manages a pool of goroutines with rate limiting. Jobs wait for
+// a free worker in a bounded queue, in priority order, then in submission
+// order; only running jobs hold a goroutine.
 type WorkerPool struct {
 	maxWorkers  int
 	rateLimitMs int
-	semaphore   chan struct{}
+	throttle    *Throttle
+	jitter      func(time.Duration) time.Duration
+	pages       *PageBudget
+	cooldown    *Cooldown
+	tuner       *AutoTuner
+	jobTimeout  time.Duration
+	beforeJob   func()
+	onError     func(error)
 	wg          sync.WaitGroup
 	mu          sync.Mutex
 	lastRequest time.Time
+
+	slotMu  sync.Mutex
+	notFull *sync.Cond // signalled, under slotMu, when a job leaves the queue
+	active  int
+	waiting waitQueue
+	seq     uint64
+	drained chan struct{} // closed when the queue empties, ending the throttle watch
 }
 
 // NewWorkerPool creates a WorkerPool with the given concurrency and rate limit.
 func NewWorkerPool(maxWorkers, rateLimitMs int) *WorkerPool {
-	return &WorkerPool{
+	wp := &WorkerPool{
 		maxWorkers:  maxWorkers,
 		rateLimitMs: rateLimitMs,
-		semaphore:   make(chan struct{}, maxWorkers),
 		lastRequest: time.Now(),
 	}
+	wp.notFull = sync.NewCond(&wp.slotMu)
+	return wp
+}
+
+// SetThrottle makes the pool take its concurrency and rate limit from t, so
+// changes apply to jobs submitted or started after the update.
+func (wp *WorkerPool) SetThrottle(t *Throttle) {
+	wp.throttle = t
+}
+
+// SetJitter makes each rate-limit interval vary by fn (see Random.Jitter),
+// so requests do not arrive on a fixed beat.
+func (wp *WorkerPool) SetJitter(fn func(time.Duration) time.Duration) {
+	wp.jitter = fn
+}
+
+// SetPageBudget makes the pool drop jobs once b is exhausted, so a run winds
+// down instead of loading pages past its cost cap. Each job still charges
+// its own page loads to b.
+func (wp *WorkerPool) SetPageBudget(b *PageBudget) {
+	wp.pages = b
+}
+
+// SetCooldown makes every job wait out c's pause before it starts, so one
+// rate-limit response slows the whole pool, not just the request that got it.
+func (wp *WorkerPool) SetCooldown(c *Cooldown) {
+	wp.cooldown = c
+}
+
+// SetAutoTune caps the pool's concurrency at a's current level, which the
+// caller raises or lowers by recording job outcomes. The configured or
+// throttled maximum still applies.
+func (wp *WorkerPool) SetAutoTune(a *AutoTuner) {
+	wp.tuner = a
+}
+
+// SetJobTimeout bounds how long a job may hold its worker slot. A job
+// still running after d is reported as ErrJobTimeout and its slot freed;
+// the job itself cannot be stopped and finishes in the background. Zero
+// disables the limit.
+func (wp *WorkerPool) SetJobTimeout(d time.Duration) {
+	wp.jobTimeout = d
+}
+
+// SetBeforeJob runs fn in the worker before each job, outside the job
+// timeout — e.g. to wait for a scrape window while holding the slot.
+func (wp *WorkerPool) SetBeforeJob(fn func()) {
+	wp.beforeJob = fn
+}
+
+// SetErrorHandler receives job panics (*PanicError) and timeouts
+// (ErrJobTimeout). Without one they go to the standard logger. Either way
+// a panic no longer takes the process down.
+func (wp *WorkerPool) SetErrorHandler(fn func(error)) {
+	wp.onError = fn
 }
 
-// Submit enqueues a job for execution in the pool.
+// Submit enqueues a job at priority 0. See SubmitPriority.
 func (wp *WorkerPool) Submit(job func()) {
+	wp.SubmitPriority(0, job)
+}
+
+// SubmitPriority enqueues a job, blocking while queuePerWorker jobs per
+// worker are already waiting, so a producer cannot queue a whole sitemap
+// ahead of the workers. Queued jobs with a higher priority start first;
+// equal priorities start in submission order. With a page budget set, jobs
+// that start after it is exhausted are skipped, so the high-priority ones
+// are the last to be dropped. The returned channel is closed once the pool
+// is done with the job: it returned, panicked, timed out or was skipped.
+func (wp *WorkerPool) SubmitPriority(priority int, job func()) <-chan struct{} {
+	done := make(chan struct{})
 	wp.wg.Add(1)
-	wp.semaphore <- struct{}{}
 
-	go func() {
-		defer wp.wg.Done()
-		defer func() { <-wp.semaphore }()
+	wp.slotMu.Lock()
+	defer wp.slotMu.Unlock()
+	for len(wp.waiting) >= wp.queueLimit() {
+		wp.notFull.Wait()
+	}
+	heap.Push(&wp.waiting, &queuedJob{priority: priority, seq: wp.seq, job: job, done: done})
+	wp.seq++
+	wp.dispatchLocked()
+	return done
+}
 
-		wp.enforceRateLimit()
+// work runs one dequeued job in its worker slot.
+func (wp *WorkerPool) work(j *queuedJob) {
+	defer wp.wg.Done()
+	defer close(j.done)
+	defer wp.release()
+
+	if wp.cooldown != nil {
+		wp.cooldown.Wait()
+	}
+	if wp.beforeJob != nil {
+		wp.beforeJob()
+	}
+	if wp.pages != nil && wp.pages.Exhausted() {
+		return
+	}
+	wp.enforceRateLimit()
+	wp.run(j.job)
+}
+
+// run calls job, recovering a panic and enforcing the job timeout.
+func (wp *WorkerPool) run(job func()) {
+	err := RunWithin(wp.jobTimeout, func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
 		job()
-	}()
+		return nil
+	})
+	if errors.Is(err, ErrHung) {
+		err = fmt.Errorf("%w after %v", ErrJobTimeout, wp.jobTimeout)
+	}
+	switch {
+	case err == nil:
+	case wp.onError != nil:
+		wp.onError(err)
+	default:
+		var pe *PanicError
+		if errors.As(err, &pe) {
+			log.Printf("%v\n%s", err, pe.Stack)
+		} else {
+			log.Print(err)
+		}
+	}
 }
 
 // Wait blocks until all submitted jobs have completed.
@@ -44,52 +193,124 @@ func (wp *WorkerPool) Wait() {
 	wp.wg.Wait()
 }
 
-func (wp *WorkerPool) enforceRateLimit() {
-	wp.mu.Lock()
-	defer wp.mu.Unlock()
+// Queued is the number of submitted jobs still waiting for a worker.
+func (wp *WorkerPool) Queued() int {
+	wp.slotMu.Lock()
+	defer wp.slotMu.Unlock()
+	return len(wp.waiting)
+}
 
-	minInterval := time.Duration(wp.rateLimitMs) * time.Millisecond
-	elapsed := time.Since(wp.lastRequest)
-	if elapsed < minInterval {
-		time.Sleep(minInterval - elapsed)
+// queuePerWorker is how many jobs may wait per worker before
+// SubmitPriority blocks.
+const queuePerWorker = 8
+
+func (wp *WorkerPool) queueLimit() int {
+	return max(wp.limit(), wp.maxWorkers) * queuePerWorker
+}
+
+// dispatchLocked starts queued jobs from the head of the queue while fewer
+// than the current limit are running, and wakes one blocked submitter per
+// job it takes off the queue. While jobs are left waiting, a goroutine
+// watches the throttle so that raising its concurrency starts them.
+func (wp *WorkerPool) dispatchLocked() {
+	var changed <-chan struct{}
+	if wp.throttle != nil {
+		changed = wp.throttle.Changed()
 	}
-	wp.lastRequest = time.Now()
+	for len(wp.waiting) > 0 && wp.active < wp.limit() {
+		j := heap.Pop(&wp.waiting).(*queuedJob)
+		wp.active++
+		wp.notFull.Signal()
+		go wp.work(j)
+	}
+	switch {
+	case len(wp.waiting) > 0 && changed != nil && wp.drained == nil:
+		wp.drained = make(chan struct{})
+		go wp.watchThrottle(changed, wp.drained)
+	case len(wp.waiting) == 0 && wp.drained != nil:
+		close(wp.drained)
+		wp.drained = nil
+	}
+}
+
+// watchThrottle dispatches once the throttle changes, unless the queue
+// drains first.
+func (wp *WorkerPool) watchThrottle(changed <-chan struct{}, drained chan struct{}) {
+	select {
+	case <-drained:
+		return
+	case <-changed:
+	}
+	wp.slotMu.Lock()
+	defer wp.slotMu.Unlock()
+	if wp.drained == drained {
+		wp.drained = nil
+	}
+	wp.dispatchLocked()
 }
 
-// URLSet is a thread-safe set for tracking visited URLs.
-type URLSet struct {
-	mu   sync.RWMutex
-	seen map[string]struct{}
+func (wp *WorkerPool) release() {
+	wp.slotMu.Lock()
+	defer wp.slotMu.Unlock()
+	wp.active--
+	wp.dispatchLocked()
 }
 
-// NewURLSet creates an empty URLSet.
-func NewURLSet() *URLSet {
-	return &URLSet{seen: make(map[string]struct{})}
+// queuedJob is a job waiting for a worker.
+type queuedJob struct {
+	priority int
+	seq      uint64
+	job      func()
+	done     chan struct{}
 }
 
-// Add returns true if the URL was newly added, false if already present.
-func (s *URLSet) Add(url string) bool {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// waitQueue is a heap of queued jobs: highest priority first, then oldest.
+type waitQueue []*queuedJob
 
-	if _, exists := s.seen[url]; exists {
-		return false
+func (q waitQueue) Len() int { return len(q) }
+func (q waitQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
 	}
-	s.seen[url] = struct{}{}
-	return true
+	return q[i].seq < q[j].seq
+}
+func (q waitQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *waitQueue) Push(x any)   { *q = append(*q, x.(*queuedJob)) }
+func (q *waitQueue) Pop() any {
+	old := *q
+	j := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return j
 }
 
-// Contains returns true if the URL has already been visited.
-func (s *URLSet) Contains(url string) bool {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	_, exists := s.seen[url]
-	return exists
+func (wp *WorkerPool) limit() int {
+	n := wp.maxWorkers
+	if wp.throttle != nil {
+		n = wp.throttle.MaxConcurrency()
+	}
+	if wp.tuner != nil {
+		n = min(n, wp.tuner.Level())
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
 }
 
-// Size returns the number of unique URLs tracked.
-func (s *URLSet) Size() int {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return len(s.seen)
+func (wp *WorkerPool) enforceRateLimit() {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	minInterval := time.Duration(wp.rateLimitMs) * time.Millisecond
+	if wp.throttle != nil {
+		minInterval = wp.throttle.RateLimit()
+	}
+	if wp.jitter != nil {
+		minInterval = wp.jitter(minInterval)
+	}
+	elapsed := time.Since(wp.lastRequest)
+	if elapsed < minInterval {
+		time.Sleep(minInterval - elapsed)
+	}
+	wp.lastRequest = time.Now()
 }
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"errors"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -69,3 +71,118 @@ func TestWorkerPoolRateLimit(t *testing.T) {
 		}
 	}
 }
+
+func TestWorkerPoolPageBudget(t *testing.T) {
+	budget := NewPageBudget(3, 0)
+	pool := NewWorkerPool(1, 0)
+	pool.SetPageBudget(budget)
+
+	var ran atomic.Int32
+	for i := 0; i < 10; i++ {
+		pool.Submit(func() {
+			if budget.Take() {
+				ran.Add(1)
+			}
+		})
+	}
+	pool.Wait()
+	if ran.Load() != 3 || budget.Used() != 3 {
+		t.Errorf("ran %d jobs using %d loads, want 3 each", ran.Load(), budget.Used())
+	}
+}
+
+func TestWorkerPoolPriority(t *testing.T) {
+	pool := NewWorkerPool(1, 0)
+	gate := make(chan struct{})
+	var order []string
+	record := func(name string) func() {
+		return func() { order = append(order, name) } // one worker: no overlap
+	}
+
+	pool.Submit(func() { <-gate })
+	pool.SubmitPriority(0, record("bulk-1"))
+	pool.SubmitPriority(0, record("bulk-2"))
+	pool.SubmitPriority(2, record("canary"))
+	pool.SubmitPriority(1, record("tracked"))
+	if n := pool.Queued(); n < 4 {
+		t.Errorf("Queued() = %d, want at least 4 behind the blocked job", n)
+	}
+	close(gate)
+	pool.Wait()
+
+	if got := strings.Join(order, ","); got != "canary,tracked,bulk-1,bulk-2" {
+		t.Errorf("order = %s, want canary,tracked,bulk-1,bulk-2", got)
+	}
+}
+
+func TestWorkerPoolBoundedQueue(t *testing.T) {
+	pool := NewWorkerPool(1, 0)
+	gate := make(chan struct{})
+	pool.Submit(func() { <-gate })
+	for i := 0; i < queuePerWorker; i++ {
+		pool.Submit(func() {})
+	}
+
+	submitted := make(chan struct{})
+	go func() {
+		pool.Submit(func() {})
+		close(submitted)
+	}()
+	select {
+	case <-submitted:
+		t.Fatal("Submit did not block on a full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if n := pool.Queued(); n != queuePerWorker {
+		t.Errorf("Queued() = %d, want %d", n, queuePerWorker)
+	}
+
+	close(gate)
+	select {
+	case <-submitted:
+	case <-time.After(time.Second):
+		t.Fatal("Submit stayed blocked after the queue drained")
+	}
+	pool.Wait()
+}
+
+func TestWorkerPoolRecoversPanics(t *testing.T) {
+	pool := NewWorkerPool(1, 0)
+	var errs []error
+	pool.SetErrorHandler(func(err error) { errs = append(errs, err) })
+
+	var ran atomic.Bool
+	pool.Submit(func() { panic("boom") })
+	pool.Submit(func() { ran.Store(true) })
+	pool.Wait()
+
+	if !ran.Load() {
+		t.Error("job after the panic did not run")
+	}
+	var pe *PanicError
+	if len(errs) != 1 || !errors.As(errs[0], &pe) || pe.Value != "boom" || !strings.Contains(string(pe.Stack), "TestWorkerPoolRecoversPanics") {
+		t.Errorf("errors = %v, want one PanicError with the job's stack", errs)
+	}
+}
+
+func TestWorkerPoolJobTimeout(t *testing.T) {
+	pool := NewWorkerPool(1, 0)
+	pool.SetJobTimeout(50 * time.Millisecond)
+	errc := make(chan error, 1)
+	pool.SetErrorHandler(func(err error) { errc <- err })
+
+	hung := make(chan struct{})
+	defer close(hung)
+	var ran atomic.Bool
+	done := pool.SubmitPriority(0, func() { <-hung })
+	pool.Submit(func() { ran.Store(true) })
+	pool.Wait() // must not wait for the hung job
+	<-done
+
+	if !ran.Load() {
+		t.Error("the hung job kept its slot")
+	}
+	if err := <-errc; !errors.Is(err, ErrJobTimeout) {
+		t.Errorf("error = %v, want ErrJobTimeout", err)
+	}
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// Cooldown is a global pause shared by every worker of a run. Each Trigger
+// (a 429 or a throttle page) doubles the pause, from base up to max; after a
+// calm spell of twice the last pause the next trigger starts again at base.
+// Workers call Wait before each page load. It is safe for concurrent use.
+type Cooldown struct {
+	base, max time.Duration
+	now       func() time.Time
+	sleep     func(time.Duration)
+
+	mu     sync.Mutex
+	until  time.Time
+	last   time.Duration // most recent pause
+	count  int
+	paused time.Duration // sum of pauses triggered
+}
+
+// NewCooldown returns a Cooldown whose first pause is base, doubling up to
+// max. A base of zero or less disables it.
+func NewCooldown(base, max time.Duration) *Cooldown {
+	if max < base {
+		max = base
+	}
+	return &Cooldown{base: base, max: max, now: time.Now, sleep: time.Sleep}
+}
+
+// Trigger starts or extends the pause and returns its length; 0 when the
+// cooldown is disabled.
+func (c *Cooldown) Trigger() time.Duration {
+	if c.base <= 0 {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	next := c.base
+	if c.last > 0 && now.Before(c.until.Add(2*c.last)) {
+		next = min(2*c.last, c.max)
+	}
+	if now.Before(c.until) && c.until.Sub(now) >= next {
+		return c.until.Sub(now) // already paused long enough
+	}
+	c.until = now.Add(next)
+	c.last = next
+	c.count++
+	c.paused += next
+	return next
+}
+
+// Wait blocks until the current pause, if any, is over.
+func (c *Cooldown) Wait() {
+	for {
+		c.mu.Lock()
+		d := c.until.Sub(c.now())
+		c.mu.Unlock()
+		if d <= 0 {
+			return
+		}
+		c.sleep(d)
+	}
+}
+
+// Stats reports how many pauses were triggered and their total length.
+func (c *Cooldown) Stats() (count int, total time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count, c.paused
+}
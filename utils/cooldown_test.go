@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCooldownBackoff(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := NewCooldown(10*time.Second, 30*time.Second)
+	c.now = func() time.Time { return now }
+	var slept time.Duration
+	c.sleep = func(d time.Duration) { slept += d; now = now.Add(d) }
+
+	if d := c.Trigger(); d != 10*time.Second {
+		t.Fatalf("first pause = %v, want 10s", d)
+	}
+	c.Wait()
+	if slept != 10*time.Second {
+		t.Errorf("Wait slept %v, want 10s", slept)
+	}
+
+	// Throttled again soon after: the pause doubles, then caps at max.
+	if d := c.Trigger(); d != 20*time.Second {
+		t.Errorf("second pause = %v, want 20s", d)
+	}
+	now = now.Add(20 * time.Second)
+	if d := c.Trigger(); d != 30*time.Second {
+		t.Errorf("third pause = %v, want 30s (max)", d)
+	}
+
+	// A trigger during a long enough pause does not extend it.
+	if d := c.Trigger(); d != 30*time.Second {
+		t.Errorf("trigger mid-pause = %v, want the remaining 30s", d)
+	}
+	if n, total := c.Stats(); n != 3 || total != 60*time.Second {
+		t.Errorf("Stats() = %d, %v; want 3, 60s", n, total)
+	}
+
+	// After a calm spell the pause starts over at base.
+	now = now.Add(30*time.Second + 61*time.Second)
+	if d := c.Trigger(); d != 10*time.Second {
+		t.Errorf("pause after calm = %v, want 10s", d)
+	}
+}
+
+func TestCooldownDisabled(t *testing.T) {
+	c := NewCooldown(0, 0)
+	if d := c.Trigger(); d != 0 {
+		t.Errorf("disabled Trigger() = %v", d)
+	}
+	c.Wait() // must not block
+}
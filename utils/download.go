@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Download is one file for Downloader.Fetch: URL saved as Path.
+type Download struct {
+	URL  string
+	Path string
+}
+
+// Downloader fetches files over HTTP with at most a fixed number of requests
+// in flight. It is safe for concurrent use.
+type Downloader struct {
+	client *http.Client
+	slots  chan struct{}
+}
+
+// NewDownloader allows concurrency downloads at a time (at least one), each
+// bounded by timeout.
+func NewDownloader(concurrency int, timeout time.Duration) *Downloader {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Downloader{
+		client: &http.Client{Timeout: timeout},
+		slots:  make(chan struct{}, concurrency),
+	}
+}
+
+// Fetch downloads every job, creating the directories of their paths, and
+// returns how many files are in place and the errors of the rest. Files
+// that already exist are kept and counted, so a re-run only fetches what is
+// missing; each download is written to a temporary file and renamed once
+// complete, so an interrupted one never leaves a truncated file behind.
+func (d *Downloader) Fetch(ctx context.Context, jobs []Download) (int, []error) {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		saved int
+		errs  []error
+	)
+	for _, job := range jobs {
+		job := job
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case d.slots <- struct{}{}:
+				defer func() { <-d.slots }()
+			case <-ctx.Done():
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("download %s: %w", job.URL, ctx.Err()))
+				mu.Unlock()
+				return
+			}
+			err := d.fetch(ctx, job)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("download %s: %w", job.URL, err))
+				return
+			}
+			saved++
+		}()
+	}
+	wg.Wait()
+	return saved, errs
+}
+
+func (d *Downloader) fetch(ctx context.Context, job Download) error {
+	if _, err := os.Stat(job.Path); err == nil {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, job.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(job.Path), 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(job.Path), ".download-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), job.Path)
+}
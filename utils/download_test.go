@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDownloaderFetch(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(10 * time.Millisecond)
+		if r.URL.Path == "/missing.jpg" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("photo " + r.URL.Path))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	kept := filepath.Join(dir, "7", "00.jpg")
+	os.MkdirAll(filepath.Dir(kept), 0755)
+	os.WriteFile(kept, []byte("already here"), 0644)
+
+	jobs := []Download{
+		{srv.URL + "/a.jpg", kept},
+		{srv.URL + "/b.jpg", filepath.Join(dir, "7", "01.jpg")},
+		{srv.URL + "/c.jpg", filepath.Join(dir, "7", "02.jpg")},
+		{srv.URL + "/d.jpg", filepath.Join(dir, "8", "01.jpg")},
+		{srv.URL + "/missing.jpg", filepath.Join(dir, "8", "02.jpg")},
+	}
+	saved, errs := NewDownloader(2, time.Second).Fetch(context.Background(), jobs)
+
+	if saved != 4 || len(errs) != 1 {
+		t.Fatalf("saved %d, errors %v; want 4 and the missing one", saved, errs)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("%d downloads in flight, want at most 2", p)
+	}
+	if b, _ := os.ReadFile(kept); string(b) != "already here" {
+		t.Errorf("existing file overwritten: %q", b)
+	}
+	if b, _ := os.ReadFile(filepath.Join(dir, "8", "01.jpg")); string(b) != "photo /d.jpg" {
+		t.Errorf("downloaded file = %q", b)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "8", "02.jpg")); !os.IsNotExist(err) {
+		t.Errorf("failed download left a file: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var listingIDRegexp = regexp.MustCompile(`/rooms/(?:plus/)?(\d+)`)
+
+// ListingID extracts the numeric Airbnb room ID from a listing URL.
+// A bare numeric ID is returned unchanged; anything else yields "".
+func ListingID(s string) string {
+	s = strings.TrimSpace(s)
+	if m := listingIDRegexp.FindStringSubmatch(s); len(m) > 1 {
+		return m[1]
+	}
+	if s != "" && strings.Trim(s, "0123456789") == "" {
+		return s
+	}
+	return ""
+}
+
+// IDList is a thread-safe set of listing IDs persisted as a plain text file:
+// one ID or room URL per line, blank lines and "#" comments ignored.
+type IDList struct {
+	mu   sync.RWMutex
+	path string
+	ids  map[string]struct{}
+}
+
+// LoadIDList reads the list at path. A missing file yields an empty list
+// that will be created on Save; an empty path yields an empty in-memory list.
+func LoadIDList(path string) (*IDList, error) {
+	l := &IDList{path: path, ids: make(map[string]struct{})}
+	if path == "" {
+		return l, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("idlist: open %q: %w", path, err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if id := ListingID(line); id != "" {
+			l.ids[id] = struct{}{}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("idlist: read %q: %w", path, err)
+	}
+	return l, nil
+}
+
+// Contains reports whether the listing ID (or URL) is in the list.
+func (l *IDList) Contains(idOrURL string) bool {
+	id := ListingID(idOrURL)
+	if id == "" {
+		return false
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	_, ok := l.ids[id]
+	return ok
+}
+
+// Add inserts the listing ID (or URL). Returns false if it was already present
+// or is not a recognisable listing reference.
+func (l *IDList) Add(idOrURL string) bool {
+	id := ListingID(idOrURL)
+	if id == "" {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.ids[id]; ok {
+		return false
+	}
+	l.ids[id] = struct{}{}
+	return true
+}
+
+// IDs returns the IDs in ascending order.
+func (l *IDList) IDs() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	out := make([]string, 0, len(l.ids))
+	for id := range l.ids {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Size returns the number of IDs in the list.
+func (l *IDList) Size() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.ids)
+}
+
+// Save writes the list back to its file.
+func (l *IDList) Save() error {
+	if l.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("idlist: create dir: %w", err)
+	}
+	content := strings.Join(l.IDs(), "\n")
+	if content != "" {
+		content += "\n"
+	}
+	if err := os.WriteFile(l.path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("idlist: write %q: %w", l.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListingID(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"https://www.airbnb.com/rooms/12345", "12345"},
+		{"https://www.airbnb.com/rooms/plus/777?adults=2", "777"},
+		{"  98765 ", "98765"},
+		{"https://www.airbnb.com/", ""},
+		{"abc", ""},
+	}
+	for _, tt := range tests {
+		if got := ListingID(tt.in); got != tt.want {
+			t.Errorf("ListingID(%q) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIDListLoadAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	content := "# removed listings\n111\nhttps://www.airbnb.com/rooms/222 # spam\n\nnot-an-id\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := LoadIDList(path)
+	if err != nil {
+		t.Fatalf("LoadIDList: %v", err)
+	}
+	if l.Size() != 2 {
+		t.Fatalf("size: got %d, want 2", l.Size())
+	}
+	if !l.Contains("https://www.airbnb.com/rooms/111?check_in=x") {
+		t.Error("expected URL form of 111 to match")
+	}
+	if !l.Add("333") || l.Add("333") {
+		t.Error("Add should report only the first insertion")
+	}
+	if err := l.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reloaded, err := LoadIDList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Size() != 3 {
+		t.Errorf("reloaded size: got %d, want 3", reloaded.Size())
+	}
+}
+
+func TestIDListMissingFileIsEmpty(t *testing.T) {
+	l, err := LoadIDList(filepath.Join(t.TempDir(), "absent.txt"))
+	if err != nil || l.Size() != 0 {
+		t.Errorf("missing file: size %d, err %v", l.Size(), err)
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"time"
@@ -26,22 +27,33 @@ func NewLogger() *Logger {
 	}
 }
 
+// NewLoggerTo creates a Logger writing every level to w; io.Discard silences
+// it, e.g. for benchmarks.
+func NewLoggerTo(w io.Writer) *Logger {
+	return &Logger{
+		info:  log.New(w, "", 0),
+		warn:  log.New(w, "", 0),
+		err:   log.New(w, "", 0),
+		debug: log.New(w, "", 0),
+	}
+}
+
 func (l *Logger) timestamp() string {
 	return time.Now().Format("2006-01-02 15:04:05")
 }
 
 func (l *Logger) Info(format string, args ...any) {
-	l.info.Printf(fmt.Sprintf("[%s] \033[32mINFO\033[0m  %s\n", l.timestamp(), format), args...)
+	l.info.Printf(Style(fmt.Sprintf("[%s] \033[32mINFO\033[0m  %s\n", l.timestamp(), format)), args...)
 }
 
 func (l *Logger) Warn(format string, args ...any) {
-	l.warn.Printf(fmt.Sprintf("[%s] \033[33mWARN\033[0m  %s\n", l.timestamp(), format), args...)
+	l.warn.Printf(Style(fmt.Sprintf("[%s] \033[33mWARN\033[0m  %s\n", l.timestamp(), format)), args...)
 }
 
 func (l *Logger) Error(format string, args ...any) {
-	l.err.Printf(fmt.Sprintf("[%s] \033[31mERROR\033[0m %s\n", l.timestamp(), format), args...)
+	l.err.Printf(Style(fmt.Sprintf("[%s] \033[31mERROR\033[0m %s\n", l.timestamp(), format)), args...)
 }
 
 func (l *Logger) Debug(format string, args ...any) {
-	l.debug.Printf(fmt.Sprintf("[%s] \033[36mDEBUG\033[0m %s\n", l.timestamp(), format), args...)
+	l.debug.Printf(Style(fmt.Sprintf("[%s] \033[36mDEBUG\033[0m %s\n", l.timestamp(), format)), args...)
 }
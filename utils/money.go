@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// numberLocale is how a locale writes numbers and where it puts the
+// currency symbol.
+type numberLocale struct {
+	group, decimal string
+	symbolAfter    bool // "1.234,50 €" rather than "€1,234.50"
+}
+
+// numberLocales are keyed by language, or language-region where the
+// region differs from its language.
+var numberLocales = map[string]numberLocale{
+	"en":    {",", ".", false},
+	"ja":    {",", ".", false},
+	"zh":    {",", ".", false},
+	"ko":    {",", ".", false},
+	"th":    {",", ".", false},
+	"id":    {".", ",", false},
+	"nl":    {".", ",", false},
+	"de":    {".", ",", true},
+	"de-ch": {"'", ".", false},
+	"es":    {".", ",", true},
+	"it":    {".", ",", true},
+	"pt":    {".", ",", true},
+	"pt-br": {".", ",", false},
+	"fr":    {" ", ",", true},
+	"ru":    {" ", ",", true},
+}
+
+// currencySymbol is a display currency's symbol and usual decimals.
+type currencySymbol struct {
+	symbol   string
+	decimals int
+}
+
+var currencySymbols = map[string]currencySymbol{
+	"USD": {"$", 2}, "EUR": {"€", 2}, "GBP": {"£", 2}, "JPY": {"¥", 0},
+	"THB": {"฿", 2}, "IDR": {"Rp", 0}, "INR": {"₹", 2}, "VND": {"₫", 0},
+	"KRW": {"₩", 0}, "AUD": {"A$", 2}, "CAD": {"CA$", 2}, "SGD": {"S$", 2},
+	"MXN": {"MX$", 2}, "BRL": {"R$", 2}, "CHF": {"CHF", 2},
+}
+
+// Money formats amounts for reports: converted into a display currency at
+// a fixed rate, then written with a locale's digit grouping, decimal mark
+// and symbol placement. A nil *Money formats US dollars the en-US way.
+type Money struct {
+	locale   numberLocale
+	currency string
+	symbol   currencySymbol
+	rate     float64
+}
+
+// NewMoney returns a formatter for locale (a BCP 47 tag such as "de-DE";
+// unknown regions fall back to their language) and currency (an ISO 4217
+// code). rate converts stored amounts into currency: 0.92 shows a stored
+// $100 as €92.
+func NewMoney(locale, currency string, rate float64) (*Money, error) {
+	tag := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	loc, ok := numberLocales[tag]
+	if !ok {
+		lang, _, _ := strings.Cut(tag, "-")
+		if loc, ok = numberLocales[lang]; !ok {
+			return nil, fmt.Errorf("unsupported locale %q", locale)
+		}
+	}
+	code := strings.ToUpper(strings.TrimSpace(currency))
+	sym, ok := currencySymbols[code]
+	if !ok {
+		if len(code) != 3 || strings.IndexFunc(code, func(r rune) bool { return r < 'A' || r > 'Z' }) >= 0 {
+			return nil, fmt.Errorf("invalid currency %q (want an ISO 4217 code such as EUR)", currency)
+		}
+		sym = currencySymbol{code, 2}
+	}
+	if rate <= 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return nil, fmt.Errorf("invalid currency rate %v (want a positive number)", rate)
+	}
+	return &Money{locale: loc, currency: code, symbol: sym, rate: rate}, nil
+}
+
+var defaultMoney = &Money{locale: numberLocales["en"], currency: "USD", symbol: currencySymbols["USD"], rate: 1}
+
+// Currency is the display currency's ISO 4217 code.
+func (m *Money) Currency() string {
+	if m == nil {
+		m = defaultMoney
+	}
+	return m.currency
+}
+
+// Format converts amount and writes it with the currency symbol, e.g.
+// "$1,234.50", "1.234,50 €" or "¥1,235".
+func (m *Money) Format(amount float64) string {
+	if m == nil {
+		m = defaultMoney
+	}
+	n := m.Number(math.Abs(amount*m.rate), m.symbol.decimals)
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+	sym := m.symbol.symbol
+	if m.locale.symbolAfter {
+		return sign + n + " " + sym
+	}
+	if r := []rune(sym); unicode.IsLetter(r[len(r)-1]) {
+		sym += " "
+	}
+	return sign + sym + n
+}
+
+// Number writes v with decimals digits after the decimal mark and the
+// locale's digit grouping, without conversion or symbol.
+func (m *Money) Number(v float64, decimals int) string {
+	if m == nil {
+		m = defaultMoney
+	}
+	s := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	whole, frac, _ := strings.Cut(s, ".")
+	var b strings.Builder
+	if v < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(m.locale.group)
+		}
+		b.WriteRune(d)
+	}
+	if frac != "" {
+		b.WriteString(m.locale.decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package utils
+
+import "testing"
+
+func TestMoneyFormat(t *testing.T) {
+	cases := []struct {
+		locale, currency string
+		rate, amount     float64
+		want             string
+	}{
+		{"en-US", "USD", 1, 1234.5, "$1,234.50"},
+		{"de-DE", "EUR", 0.5, 2469, "1.234,50 €"},
+		{"de-AT", "EUR", 1, 99, "99,00 €"}, // region falls back to the language
+		{"fr_FR", "eur", 1, 1234567.891, "1 234 567,89 €"},
+		{"ja-JP", "JPY", 150, 80.3, "¥12,045"},
+		{"en-GB", "CHF", 1, -12, "-CHF 12.00"},
+		{"th-TH", "THB", 35, 100, "฿3,500.00"},
+		{"en", "NZD", 1, 10, "NZD 10.00"},
+	}
+	for _, c := range cases {
+		m, err := NewMoney(c.locale, c.currency, c.rate)
+		if err != nil {
+			t.Fatalf("NewMoney(%q, %q): %v", c.locale, c.currency, err)
+		}
+		if got := m.Format(c.amount); got != c.want {
+			t.Errorf("%s %s: Format(%v) = %q, want %q", c.locale, c.currency, c.amount, got, c.want)
+		}
+	}
+
+	var nilMoney *Money
+	if got := nilMoney.Format(80); got != "$80.00" {
+		t.Errorf("nil Money = %q", got)
+	}
+	for _, bad := range [][2]string{{"xx", "USD"}, {"en", "dollars"}} {
+		if _, err := NewMoney(bad[0], bad[1], 1); err == nil {
+			t.Errorf("NewMoney(%q, %q) accepted", bad[0], bad[1])
+		}
+	}
+	if _, err := NewMoney("en", "USD", 0); err == nil {
+		t.Error("zero rate accepted")
+	}
+}
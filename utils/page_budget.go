@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PageBudget caps browser page loads per run and per rolling hour — a cost
+// guardrail for proxies billed per request. The hourly window outlives a
+// run, so scheduled runs share it. It is safe for concurrent use.
+type PageBudget struct {
+	perRun  int
+	perHour int
+	now     func() time.Time
+
+	mu     sync.Mutex
+	used   int         // loads this run
+	recent []time.Time // load times within the last hour, oldest first
+}
+
+// NewPageBudget allows perRun loads per run and perHour per rolling hour;
+// zero or less means no limit.
+func NewPageBudget(perRun, perHour int) *PageBudget {
+	return &PageBudget{perRun: perRun, perHour: perHour, now: time.Now}
+}
+
+// StartRun resets the per-run count; the hourly window is kept.
+func (b *PageBudget) StartRun() {
+	b.mu.Lock()
+	b.used = 0
+	b.mu.Unlock()
+}
+
+// Take spends one page load and reports whether it was available.
+func (b *PageBudget) Take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	b.prune(now)
+	if b.exhausted() {
+		return false
+	}
+	b.used++
+	if b.perHour > 0 {
+		b.recent = append(b.recent, now)
+	}
+	return true
+}
+
+// Exhausted reports whether the next Take would fail.
+func (b *PageBudget) Exhausted() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.prune(b.now())
+	return b.exhausted()
+}
+
+// Used is the number of page loads spent this run.
+func (b *PageBudget) Used() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+// String describes the limits, e.g. "500/run, 200/hour".
+func (b *PageBudget) String() string {
+	limit := func(n int) string {
+		if n <= 0 {
+			return "unlimited"
+		}
+		return fmt.Sprint(n)
+	}
+	return fmt.Sprintf("%s/run, %s/hour", limit(b.perRun), limit(b.perHour))
+}
+
+func (b *PageBudget) exhausted() bool {
+	return (b.perRun > 0 && b.used >= b.perRun) ||
+		(b.perHour > 0 && len(b.recent) >= b.perHour)
+}
+
+// prune drops loads older than an hour from the window.
+func (b *PageBudget) prune(now time.Time) {
+	cutoff := now.Add(-time.Hour)
+	i := 0
+	for i < len(b.recent) && !b.recent[i].After(cutoff) {
+		i++
+	}
+	b.recent = b.recent[i:]
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPageBudgetPerRun(t *testing.T) {
+	b := NewPageBudget(2, 0)
+	if !b.Take() || !b.Take() {
+		t.Fatal("first two loads refused")
+	}
+	if b.Take() || !b.Exhausted() {
+		t.Error("third load allowed past the per-run cap")
+	}
+	if b.Used() != 2 {
+		t.Errorf("Used() = %d, want 2", b.Used())
+	}
+	b.StartRun()
+	if !b.Take() {
+		t.Error("new run did not reset the per-run cap")
+	}
+}
+
+func TestPageBudgetPerHour(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	b := NewPageBudget(0, 2)
+	b.now = func() time.Time { return now }
+
+	b.Take()
+	now = now.Add(30 * time.Minute)
+	b.Take()
+	b.StartRun() // the hourly window spans runs
+	if b.Take() {
+		t.Fatal("third load within the hour allowed")
+	}
+	now = now.Add(31 * time.Minute) // the first load left the window
+	if !b.Take() {
+		t.Error("load refused after the window moved on")
+	}
+}
+
+func TestPageBudgetUnlimited(t *testing.T) {
+	b := NewPageBudget(0, 0)
+	for i := 0; i < 1000; i++ {
+		if !b.Take() {
+			t.Fatalf("load %d refused without limits", i)
+		}
+	}
+	if got := b.String(); got != "unlimited/run, unlimited/hour" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif" // register decoders for image.Decode
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// pHash works on a 32×32 grayscale thumbnail and keeps the 8×8 lowest DCT
+// frequencies, which survive resizing, recompression and small crops.
+const (
+	phashSize = 32
+	phashKeep = 8
+)
+
+// PerceptualHash decodes a JPEG, PNG or GIF image and returns its 64-bit
+// DCT perceptual hash. Visually similar images have hashes a small Hamming
+// distance apart.
+func PerceptualHash(r io.Reader) (uint64, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return 0, fmt.Errorf("phash: decode: %w", err)
+	}
+	return PerceptualHashImage(img), nil
+}
+
+// PerceptualHashImage returns the perceptual hash of an already decoded image.
+func PerceptualHashImage(img image.Image) uint64 {
+	pixels := grayThumbnail(img, phashSize)
+	coeffs := dct2D(pixels, phashSize)
+
+	low := make([]float64, 0, phashKeep*phashKeep)
+	for y := 0; y < phashKeep; y++ {
+		for x := 0; x < phashKeep; x++ {
+			low = append(low, coeffs[y*phashSize+x])
+		}
+	}
+	// The DC term only encodes overall brightness; leave it out of the median.
+	sorted := append([]float64(nil), low[1:]...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+
+	var hash uint64
+	for i, c := range low {
+		if c > median {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// HashDistance is the number of differing bits between two perceptual hashes.
+func HashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// HashOverlap returns the share of hashes in a that have a match in b within
+// maxDistance bits — the fraction of one listing's photos that also appear
+// in another's. It is 0 when either set is empty.
+func HashOverlap(a, b []uint64, maxDistance int) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	matched := 0
+	for _, ha := range a {
+		for _, hb := range b {
+			if HashDistance(ha, hb) <= maxDistance {
+				matched++
+				break
+			}
+		}
+	}
+	return float64(matched) / float64(len(a))
+}
+
+// grayThumbnail box-samples img down to size×size luminance values.
+func grayThumbnail(img image.Image, size int) []float64 {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	out := make([]float64, size*size)
+	if w == 0 || h == 0 {
+		return out
+	}
+	for ty := 0; ty < size; ty++ {
+		y0, y1 := b.Min.Y+ty*h/size, b.Min.Y+(ty+1)*h/size
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for tx := 0; tx < size; tx++ {
+			x0, x1 := b.Min.X+tx*w/size, b.Min.X+(tx+1)*w/size
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+			var sum float64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					r, g, bl, _ := img.At(x, y).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)
+				}
+			}
+			out[ty*size+tx] = sum / float64((y1-y0)*(x1-x0)) / 257
+		}
+	}
+	return out
+}
+
+// dct2D is a separable type-II DCT over an n×n block (rows, then columns).
+func dct2D(in []float64, n int) []float64 {
+	cos := make([]float64, n*n)
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			cos[k*n+i] = math.Cos(math.Pi / float64(n) * (float64(i) + 0.5) * float64(k))
+		}
+	}
+	dct1 := func(src []float64, stride int, dst []float64, dstStride int) {
+		for k := 0; k < n; k++ {
+			var s float64
+			for i := 0; i < n; i++ {
+				s += src[i*stride] * cos[k*n+i]
+			}
+			dst[k*dstStride] = s
+		}
+	}
+
+	rows := make([]float64, n*n)
+	for y := 0; y < n; y++ {
+		dct1(in[y*n:], 1, rows[y*n:], 1)
+	}
+	out := make([]float64, n*n)
+	for x := 0; x < n; x++ {
+		dct1(rows[x:], n, out[x:], n)
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// testPhoto draws a deterministic scene: a gradient sky, a dark block and a
+// bright stripe, so the hash has real structure to latch onto.
+func testPhoto(w, h int, shift uint8) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{uint8(y * 200 / h), uint8(x * 255 / w), 180, 255}
+			if x > w/2 && y > h/3 {
+				c = color.RGBA{30, 30, 40, 255}
+			}
+			if y > h*3/4 && x < w/3 {
+				c = color.RGBA{250, 240, 200, 255}
+			}
+			c.R += shift
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestPerceptualHashSurvivesResizeAndRecompression(t *testing.T) {
+	orig := PerceptualHashImage(testPhoto(640, 480, 0))
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testPhoto(320, 240, 6), &jpeg.Options{Quality: 40}); err != nil {
+		t.Fatal(err)
+	}
+	resized, err := PerceptualHash(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := HashDistance(orig, resized); d > 6 {
+		t.Errorf("resized/recompressed copy distance = %d, want ≤ 6", d)
+	}
+
+	flipped := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	src := testPhoto(640, 480, 0)
+	for y := 0; y < 480; y++ {
+		for x := 0; x < 640; x++ {
+			flipped.Set(x, y, src.At(639-x, 479-y))
+		}
+	}
+	if d := HashDistance(orig, PerceptualHashImage(flipped)); d < 16 {
+		t.Errorf("different image distance = %d, want ≥ 16", d)
+	}
+}
+
+func TestPerceptualHashRejectsNonImage(t *testing.T) {
+	if _, err := PerceptualHash(bytes.NewReader([]byte("not an image"))); err == nil {
+		t.Error("expected decode error")
+	}
+}
+
+func TestHashOverlap(t *testing.T) {
+	a := []uint64{0x0f0f, 0xff00, 0x1234}
+	b := []uint64{0x0f0e, 0xabcdef0000}
+	if got := HashOverlap(a, b, 2); got != 1.0/3 {
+		t.Errorf("overlap = %v, want 1/3", got)
+	}
+	if got := HashOverlap(nil, b, 2); got != 0 {
+		t.Errorf("empty overlap = %v, want 0", got)
+	}
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// ErrJobSkipped is the Result error of an input whose job never ran
+// because the page budget ran out before it started.
+var ErrJobSkipped = errors.New("worker pool: job skipped")
+
+// Result is the outcome of one input of Pool.Map.
+type Result[T any] struct {
+	Input string
+	Value T
+	Err   error
+}
+
+// Pool runs typed jobs on a WorkerPool and streams their results, so
+// callers collect outputs from a channel instead of sharing a slice under a
+// mutex. Concurrency, rate limiting, priorities and timeouts are the
+// WorkerPool's.
+type Pool[T any] struct {
+	wp       *WorkerPool
+	priority func(input string) int
+}
+
+// NewPool returns a Pool submitting to wp.
+func NewPool[T any](wp *WorkerPool) *Pool[T] {
+	return &Pool[T]{wp: wp}
+}
+
+// SetPriority sets the WorkerPool priority of each input; without it every
+// input is submitted at priority 0.
+func (p *Pool[T]) SetPriority(fn func(input string) int) {
+	p.priority = fn
+}
+
+// Map submits fn for every input, blocking while the WorkerPool's queue is
+// full, and returns a channel yielding one Result per input, in completion
+// order; it is closed after the last one. A panic in fn arrives as a
+// *PanicError result, a job past the WorkerPool's job timeout as
+// ErrJobTimeout and a job dropped by the page budget as ErrJobSkipped. The
+// channel is buffered for every input, so workers never wait on a slow
+// reader.
+func (p *Pool[T]) Map(inputs []string, fn func(input string) (T, error)) <-chan Result[T] {
+	out := make(chan Result[T], len(inputs))
+	pending := int64(len(inputs))
+	if pending == 0 {
+		close(out)
+		return out
+	}
+	for _, in := range inputs {
+		in := in
+		var started atomic.Bool
+		result := make(chan Result[T], 1)
+		priority := 0
+		if p.priority != nil {
+			priority = p.priority(in)
+		}
+		done := p.wp.SubmitPriority(priority, func() {
+			started.Store(true)
+			r := Result[T]{Input: in}
+			defer func() {
+				if v := recover(); v != nil {
+					r.Err = &PanicError{Value: v, Stack: debug.Stack()}
+				}
+				result <- r
+			}()
+			r.Value, r.Err = fn(in)
+		})
+		go func() {
+			<-done
+			var r Result[T]
+			select {
+			case r = <-result:
+			default:
+				r = Result[T]{Input: in, Err: ErrJobSkipped}
+				if started.Load() {
+					r.Err = fmt.Errorf("%w after %v", ErrJobTimeout, p.wp.jobTimeout)
+				}
+			}
+			out <- r
+			if atomic.AddInt64(&pending, -1) == 0 {
+				close(out)
+			}
+		}()
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func collect[T any](ch <-chan Result[T]) map[string]Result[T] {
+	out := make(map[string]Result[T])
+	for r := range ch {
+		out[r.Input] = r
+	}
+	return out
+}
+
+func TestPoolMap(t *testing.T) {
+	p := NewPool[int](NewWorkerPool(3, 0))
+	errOdd := errors.New("odd")
+	got := collect(p.Map([]string{"a", "bb", "ccc", "dddd"}, func(in string) (int, error) {
+		if in == "ccc" {
+			panic("bad input")
+		}
+		if len(in)%2 == 1 {
+			return 0, errOdd
+		}
+		return len(in), nil
+	}))
+
+	if len(got) != 4 {
+		t.Fatalf("got %d results, want 4", len(got))
+	}
+	if got["bb"].Value != 2 || got["bb"].Err != nil || got["dddd"].Value != 4 {
+		t.Errorf("values: %+v", got)
+	}
+	if !errors.Is(got["a"].Err, errOdd) {
+		t.Errorf("a: err = %v, want errOdd", got["a"].Err)
+	}
+	var pe *PanicError
+	if !errors.As(got["ccc"].Err, &pe) || pe.Value != "bad input" {
+		t.Errorf("ccc: err = %v, want the recovered panic", got["ccc"].Err)
+	}
+
+	if _, ok := <-p.Map(nil, func(string) (int, error) { return 0, nil }); ok {
+		t.Error("Map of no inputs should return a closed channel")
+	}
+}
+
+func TestPoolMapTimeoutAndSkip(t *testing.T) {
+	wp := NewWorkerPool(1, 0)
+	wp.SetJobTimeout(50 * time.Millisecond)
+	wp.SetErrorHandler(func(error) {})
+	budget := NewPageBudget(1, 0)
+	wp.SetPageBudget(budget)
+	hung := make(chan struct{})
+	defer close(hung)
+
+	got := collect(NewPool[string](wp).Map([]string{"hang", "later"}, func(in string) (string, error) {
+		budget.Take() // the first job spends the budget
+		<-hung
+		return in, nil
+	}))
+	if !errors.Is(got["hang"].Err, ErrJobTimeout) {
+		t.Errorf("hang: err = %v, want ErrJobTimeout", got["hang"].Err)
+	}
+	if !errors.Is(got["later"].Err, ErrJobSkipped) {
+		t.Errorf("later: err = %v, want ErrJobSkipped", got["later"].Err)
+	}
+}
+
+func TestPoolMapPriority(t *testing.T) {
+	wp := NewWorkerPool(1, 0)
+	gate := make(chan struct{})
+	wp.Submit(func() { <-gate }) // hold the only worker while Map queues
+
+	p := NewPool[int](wp)
+	p.SetPriority(func(in string) int {
+		if strings.HasPrefix(in, "vip") {
+			return 1
+		}
+		return 0
+	})
+	var order []string
+	results := p.Map([]string{"bulk-1", "vip-1", "bulk-2", "vip-2"}, func(in string) (int, error) {
+		order = append(order, in) // one worker: no overlap
+		return 0, nil
+	})
+	close(gate)
+	n := 0
+	for range results {
+		n++
+	}
+	if got := fmt.Sprint(order); got != "[vip-1 vip-2 bulk-1 bulk-2]" {
+		t.Errorf("order = %s", got)
+	}
+	if n != 4 {
+		t.Errorf("got %d results, want 4", n)
+	}
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Random is the seedable source behind every random choice a scrape makes:
+// pacing jitter, user-agent rotation and card sampling. The same seed
+// replays the same choices, so tests and bug reproductions are
+// deterministic (draws from concurrent workers still interleave, so run
+// with MAX_CONCURRENCY=1 for an exact replay).
+//
+// It is safe for concurrent use. A nil *Random makes no random choices:
+// no jitter, the first option, the first items.
+type Random struct {
+	mu   sync.Mutex
+	rng  *rand.Rand
+	seed int64
+}
+
+// NewRandom returns a source seeded with seed; 0 picks a seed from the
+// clock, which Seed reports so the run can be repeated.
+func NewRandom(seed int64) *Random {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Random{rng: rand.New(rand.NewSource(seed)), seed: seed}
+}
+
+// Seed is the seed in use.
+func (r *Random) Seed() int64 {
+	if r == nil {
+		return 0
+	}
+	return r.seed
+}
+
+// Intn returns a value in [0, n).
+func (r *Random) Intn(n int) int {
+	if r == nil || n <= 1 {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Intn(n)
+}
+
+// Jitter varies d uniformly within ±frac of itself, e.g. 2s at 0.25 becomes
+// 1.5s–2.5s. frac <= 0 returns d unchanged.
+func (r *Random) Jitter(d time.Duration, frac float64) time.Duration {
+	if r == nil || frac <= 0 || d <= 0 {
+		return d
+	}
+	if frac > 1 {
+		frac = 1
+	}
+	r.mu.Lock()
+	f := r.rng.Float64()
+	r.mu.Unlock()
+	return time.Duration(float64(d) * (1 + frac*(2*f-1)))
+}
+
+// Pick returns one of options, or "" when there are none.
+func (r *Random) Pick(options []string) string {
+	if len(options) == 0 {
+		return ""
+	}
+	return options[r.Intn(len(options))]
+}
+
+// Sample returns k distinct indices from [0, n) in ascending order, so a
+// sampled subset keeps its original order. k >= n returns every index.
+func (r *Random) Sample(n, k int) []int {
+	if k > n {
+		k = n
+	}
+	if k < 0 {
+		k = 0
+	}
+	var idx []int
+	if r == nil || k == n {
+		idx = make([]int, k)
+		for i := range idx {
+			idx[i] = i
+		}
+		return idx
+	}
+	r.mu.Lock()
+	idx = r.rng.Perm(n)[:k]
+	r.mu.Unlock()
+	sort.Ints(idx)
+	return idx
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRandomSeedRepeats(t *testing.T) {
+	draw := func(r *Random) []any {
+		return []any{
+			r.Jitter(time.Second, 0.5),
+			r.Pick([]string{"a", "b", "c", "d"}),
+			r.Sample(20, 5),
+			r.Intn(1000),
+		}
+	}
+	a, b := draw(NewRandom(42)), draw(NewRandom(42))
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("same seed, different draws:\n%v\n%v", a, b)
+	}
+	if NewRandom(42).Seed() != 42 {
+		t.Error("Seed should report the given seed")
+	}
+	if NewRandom(0).Seed() == 0 {
+		t.Error("seed 0 should be replaced by a clock seed")
+	}
+}
+
+func TestRandomJitterBounds(t *testing.T) {
+	r := NewRandom(1)
+	for i := 0; i < 1000; i++ {
+		d := r.Jitter(2*time.Second, 0.25)
+		if d < 1500*time.Millisecond || d > 2500*time.Millisecond {
+			t.Fatalf("jitter %v outside 1.5s–2.5s", d)
+		}
+	}
+	if d := r.Jitter(time.Second, 0); d != time.Second {
+		t.Errorf("zero jitter changed the delay to %v", d)
+	}
+}
+
+func TestRandomSample(t *testing.T) {
+	r := NewRandom(7)
+	idx := r.Sample(50, 10)
+	if len(idx) != 10 {
+		t.Fatalf("got %d indices, want 10", len(idx))
+	}
+	for i := 1; i < len(idx); i++ {
+		if idx[i] <= idx[i-1] {
+			t.Fatalf("indices not ascending and distinct: %v", idx)
+		}
+	}
+	if got := r.Sample(3, 10); !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Errorf("k > n: %v", got)
+	}
+}
+
+func TestNilRandomIsDeterministic(t *testing.T) {
+	var r *Random
+	if r.Jitter(time.Second, 0.5) != time.Second || r.Pick([]string{"a", "b"}) != "a" || r.Intn(9) != 0 {
+		t.Error("nil Random should make no random choices")
+	}
+	if got := r.Sample(5, 2); !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Errorf("nil Sample = %v, want the first items", got)
+	}
+}
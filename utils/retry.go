@@ -1,15 +1,68 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 )
 
+// ErrRetryBudgetExhausted is returned by RetryConfig.Do when a retry was
+// needed but the run's shared retry budget is spent.
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
 // RetryConfig holds the parameters for the retry strategy.
 type RetryConfig struct {
 	MaxAttempts int
 	BaseDelay   time.Duration
 	Logger      *Logger
+	Budget      *RetryBudget // optional cap on retries shared across a run
+	// Retryable, when set, stops retrying errors it rejects — e.g. a page
+	// that is gone will not come back on the next attempt.
+	Retryable func(error) bool
+	// Jitter, when set, varies each back-off delay (see Random.Jitter).
+	Jitter func(time.Duration) time.Duration
+	// OnFailure, when set, sees every failed attempt, retryable or not —
+	// e.g. to start a global cool-down on rate-limit responses.
+	OnFailure func(error)
+}
+
+// RetryBudget caps the total number of retries across every RetryConfig that
+// shares it, so a blocking site cannot trigger a retry storm. It is safe for
+// concurrent use.
+type RetryBudget struct {
+	max  int64
+	used atomic.Int64
+}
+
+// NewRetryBudget allows max retries in total; max <= 0 means unlimited.
+func NewRetryBudget(max int) *RetryBudget {
+	return &RetryBudget{max: int64(max)}
+}
+
+// Take spends one retry and reports whether it was available.
+func (b *RetryBudget) Take() bool {
+	n := b.used.Add(1)
+	if b.max > 0 && n > b.max {
+		b.used.Add(-1)
+		return false
+	}
+	return true
+}
+
+// Used is the number of retries spent so far.
+func (b *RetryBudget) Used() int {
+	return int(b.used.Load())
+}
+
+// Limit is the configured maximum; 0 means unlimited.
+func (b *RetryBudget) Limit() int {
+	return int(b.max)
+}
+
+// Exhausted reports whether no retries remain.
+func (b *RetryBudget) Exhausted() bool {
+	return b.max > 0 && b.used.Load() >= b.max
 }
 
 // Do executes fn with exponential back-off retry logic.
@@ -22,11 +75,25 @@ func (r *RetryConfig) Do(operationName string, fn func() error) error {
 		if lastErr == nil {
 			return nil
 		}
+		if r.OnFailure != nil {
+			r.OnFailure(lastErr)
+		}
 
+		if r.Retryable != nil && !r.Retryable(lastErr) {
+			return fmt.Errorf("%s failed: %w", operationName, lastErr)
+		}
 		if attempt < r.MaxAttempts {
+			if r.Budget != nil && !r.Budget.Take() {
+				return fmt.Errorf("%s failed after %d attempts: %w: %w",
+					operationName, attempt, ErrRetryBudgetExhausted, lastErr)
+			}
+			wait := delay
+			if r.Jitter != nil {
+				wait = r.Jitter(delay)
+			}
 			r.Logger.Warn("[retry] %s failed (attempt %d/%d): %v — retrying in %v",
-				operationName, attempt, r.MaxAttempts, lastErr, delay)
-			time.Sleep(delay)
+				operationName, attempt, r.MaxAttempts, lastErr, wait)
+			time.Sleep(wait)
 			delay *= 2
 		}
 	}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestRetryBudgetSharedAcrossConfigs(t *testing.T) {
+	budget := NewRetryBudget(3)
+	newRetry := func() *RetryConfig {
+		return &RetryConfig{MaxAttempts: 5, Logger: NewLogger(), Budget: budget}
+	}
+	failing := errors.New("blocked")
+
+	calls := 0
+	err := newRetry().Do("first", func() error { calls++; return failing })
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("err = %v, want ErrRetryBudgetExhausted", err)
+	}
+	if calls != 4 { // the first attempt plus three budgeted retries
+		t.Errorf("calls = %d, want 4", calls)
+	}
+	if !budget.Exhausted() || budget.Used() != 3 {
+		t.Errorf("budget used %d, exhausted %v", budget.Used(), budget.Exhausted())
+	}
+
+	// A spent budget still allows first attempts, just no retries.
+	calls = 0
+	if err := newRetry().Do("second", func() error { calls++; return failing }); !errors.Is(err, ErrRetryBudgetExhausted) || calls != 1 {
+		t.Errorf("second: calls %d, err %v", calls, err)
+	}
+	if err := newRetry().Do("ok", func() error { return nil }); err != nil {
+		t.Errorf("success should not need budget: %v", err)
+	}
+}
+
+func TestRetryBudgetConcurrentTake(t *testing.T) {
+	budget := NewRetryBudget(100)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	granted := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if budget.Take() {
+					mu.Lock()
+					granted++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if granted != 100 || budget.Used() != 100 {
+		t.Errorf("granted %d, used %d, want 100", granted, budget.Used())
+	}
+	if NewRetryBudget(0).Exhausted() {
+		t.Error("zero budget means unlimited")
+	}
+}
+
+func TestRetryStopsOnNonRetryable(t *testing.T) {
+	permanent := errors.New("gone")
+	r := &RetryConfig{
+		MaxAttempts: 5,
+		Logger:      NewLogger(),
+		Retryable:   func(err error) bool { return !errors.Is(err, permanent) },
+	}
+	var seen []error
+	r.OnFailure = func(err error) { seen = append(seen, err) }
+	calls := 0
+	err := r.Do("page", func() error { calls++; return permanent })
+	if calls != 1 || !errors.Is(err, permanent) {
+		t.Errorf("calls %d, err %v; want a single attempt wrapping the cause", calls, err)
+	}
+	if len(seen) != 1 || seen[0] != permanent {
+		t.Errorf("OnFailure saw %v, want the non-retryable error once", seen)
+	}
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+)
+
+// shortIDAlphabet is Crockford's base32: no I, L, O or U, so IDs survive
+// being read aloud or retyped from a spreadsheet.
+const shortIDAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ShortID returns a stable, licence-plate style reference such as
+// "7K2M-Q9XD" for a listing. ref is the listing URL or its external ID; the
+// ID is derived from the platform and the external room ID only, so it stays
+// the same across runs, URL query strings and database reloads.
+func ShortID(platform, ref string) string {
+	external := ListingID(ref)
+	if external == "" {
+		external = strings.TrimSpace(ref)
+	}
+	if external == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(platform)) + ":" + external))
+	n := binary.BigEndian.Uint64(sum[:8]) >> 24 // 40 bits → 8 base32 digits
+
+	var b [9]byte
+	for i := 8; i >= 0; i-- {
+		if i == 4 {
+			b[i] = '-'
+			continue
+		}
+		b[i] = shortIDAlphabet[n&31]
+		n >>= 5
+	}
+	return string(b[:])
+}
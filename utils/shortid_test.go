@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestShortIDStable(t *testing.T) {
+	a := ShortID("airbnb", "https://www.airbnb.com/rooms/12345?check_in=2024-01-01")
+	b := ShortID("Airbnb", "https://www.airbnb.co.th/rooms/12345")
+	c := ShortID("airbnb", "12345")
+	if a != b || a != c {
+		t.Errorf("same listing gave different IDs: %q %q %q", a, b, c)
+	}
+	if !regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{4}-[0-9A-HJKMNP-TV-Z]{4}$`).MatchString(a) {
+		t.Errorf("unexpected format %q", a)
+	}
+	if ShortID("airbnb", "12346") == a {
+		t.Error("different rooms should not share an ID")
+	}
+	if ShortID("vrbo", "12345") == a {
+		t.Error("different platforms should not share an ID")
+	}
+	if ShortID("airbnb", "") != "" {
+		t.Error("empty ref should give empty ID")
+	}
+}
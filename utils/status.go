@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// StatusBoard tracks what a run is doing right now — its stage, the URLs
+// being fetched, counters and queue depths — so an operator can ask a run
+// that looks hung where it is (SIGUSR1 or GET /admin/status). It is safe for
+// concurrent use, and a nil *StatusBoard ignores every call.
+type StatusBoard struct {
+	mu       sync.Mutex
+	started  time.Time
+	stage    string
+	nextID   int
+	inFlight map[int]InFlight
+	counts   map[string]int
+	gauges   map[string]func() int
+}
+
+// InFlight is one operation under way.
+type InFlight struct {
+	URL   string    `json:"url"`
+	Since time.Time `json:"since"`
+}
+
+// StatusSnapshot is a point-in-time copy of a StatusBoard.
+type StatusSnapshot struct {
+	Started       time.Time      `json:"started"`
+	Elapsed       string         `json:"elapsed"`
+	Stage         string         `json:"stage"`
+	ActiveWorkers int            `json:"active_workers"`
+	InFlight      []InFlight     `json:"in_flight"` // oldest first
+	Counts        map[string]int `json:"counts"`
+	Queues        map[string]int `json:"queues"`
+}
+
+// NewStatusBoard returns an empty board.
+func NewStatusBoard() *StatusBoard {
+	b := &StatusBoard{}
+	b.Reset()
+	return b
+}
+
+// Reset clears the board for a new run.
+func (b *StatusBoard) Reset() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.started = time.Now()
+	b.stage = ""
+	b.inFlight = make(map[int]InFlight)
+	b.counts = make(map[string]int)
+	b.gauges = make(map[string]func() int)
+}
+
+// SetStage records which part of the run is executing.
+func (b *StatusBoard) SetStage(stage string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.stage = stage
+	b.mu.Unlock()
+}
+
+// Begin marks url as being worked on; call the returned func when done.
+func (b *StatusBoard) Begin(url string) (done func()) {
+	if b == nil {
+		return func() {}
+	}
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.inFlight[id] = InFlight{URL: url, Since: time.Now()}
+	b.mu.Unlock()
+	return func() {
+		b.mu.Lock()
+		delete(b.inFlight, id)
+		b.mu.Unlock()
+	}
+}
+
+// Add increments the named counter by n.
+func (b *StatusBoard) Add(name string, n int) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.counts[name] += n
+	b.mu.Unlock()
+}
+
+// Gauge registers fn as the current value of the named queue, read on every
+// snapshot (e.g. func() int { return len(ch) }).
+func (b *StatusBoard) Gauge(name string, fn func() int) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.gauges[name] = fn
+	b.mu.Unlock()
+}
+
+// Snapshot copies the board's current state.
+func (b *StatusBoard) Snapshot() StatusSnapshot {
+	if b == nil {
+		return StatusSnapshot{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := StatusSnapshot{
+		Started:       b.started,
+		Elapsed:       time.Since(b.started).Round(time.Second).String(),
+		Stage:         b.stage,
+		ActiveWorkers: len(b.inFlight),
+		InFlight:      make([]InFlight, 0, len(b.inFlight)),
+		Counts:        make(map[string]int, len(b.counts)),
+		Queues:        make(map[string]int, len(b.gauges)),
+	}
+	for _, f := range b.inFlight {
+		s.InFlight = append(s.InFlight, f)
+	}
+	sort.Slice(s.InFlight, func(i, j int) bool { return s.InFlight[i].Since.Before(s.InFlight[j].Since) })
+	for k, v := range b.counts {
+		s.Counts[k] = v
+	}
+	for k, fn := range b.gauges {
+		s.Queues[k] = fn()
+	}
+	return s
+}
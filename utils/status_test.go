@@ -0,0 +1,44 @@
+package utils
+
+import "testing"
+
+func TestStatusBoard(t *testing.T) {
+	b := NewStatusBoard()
+	b.SetStage("scrape")
+	doneA := b.Begin("https://airbnb.com/rooms/1")
+	b.Begin("https://airbnb.com/rooms/2")
+	b.Add("raw", 3)
+	b.Add("raw", 2)
+	queue := make(chan int, 4)
+	queue <- 1
+	b.Gauge("clean", func() int { return len(queue) })
+
+	s := b.Snapshot()
+	if s.Stage != "scrape" || s.ActiveWorkers != 2 || s.Counts["raw"] != 5 || s.Queues["clean"] != 1 {
+		t.Errorf("snapshot = %+v", s)
+	}
+	if s.InFlight[0].URL != "https://airbnb.com/rooms/1" {
+		t.Errorf("in-flight not oldest first: %+v", s.InFlight)
+	}
+
+	doneA()
+	if s := b.Snapshot(); s.ActiveWorkers != 1 || s.InFlight[0].URL != "https://airbnb.com/rooms/2" {
+		t.Errorf("after done: %+v", s)
+	}
+
+	b.Reset()
+	if s := b.Snapshot(); s.ActiveWorkers != 0 || len(s.Counts) != 0 || len(s.Queues) != 0 || s.Stage != "" {
+		t.Errorf("after reset: %+v", s)
+	}
+}
+
+func TestStatusBoardNil(t *testing.T) {
+	var b *StatusBoard
+	b.SetStage("x")
+	b.Add("x", 1)
+	b.Begin("u")()
+	b.Gauge("q", func() int { return 1 })
+	if s := b.Snapshot(); s.ActiveWorkers != 0 {
+		t.Errorf("nil board snapshot = %+v", s)
+	}
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Terminal decoration — ANSI colours and emoji in banners, reports and log
+// levels — can be switched off so output captured by CI or cron stays
+// readable. Colours start off when NO_COLOR is set (https://no-color.org);
+// SetStyle overrides both.
+var (
+	noColor atomic.Bool
+	noEmoji atomic.Bool
+)
+
+func init() {
+	noColor.Store(os.Getenv("NO_COLOR") != "")
+}
+
+// SetStyle turns ANSI colours and emoji on or off for Style, Printf and
+// the Logger.
+func SetStyle(color, emoji bool) {
+	noColor.Store(!color)
+	noEmoji.Store(!emoji)
+}
+
+// ansiRegexp matches the SGR escape sequences used for colours.
+var ansiRegexp = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// plainSymbols are the ASCII stand-ins for the symbols that carry meaning;
+// other emoji are dropped with the space after them.
+var plainSymbols = strings.NewReplacer("✓", "+", "✗", "x", "★", "*", "⚠", "!")
+
+// Style applies the current style to a format string: colour escapes and
+// emoji are removed when switched off. Only the program's own text is
+// styled, so it is applied before arguments such as titles are filled in.
+func Style(format string) string {
+	if noColor.Load() {
+		format = ansiRegexp.ReplaceAllString(format, "")
+	}
+	if !noEmoji.Load() {
+		return format
+	}
+	format = plainSymbols.Replace(format)
+	var b strings.Builder
+	skipSpace := false
+	for _, r := range format {
+		if isEmoji(r) {
+			skipSpace = true
+			continue
+		}
+		if skipSpace && r == ' ' {
+			skipSpace = false
+			continue
+		}
+		skipSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Printf is fmt.Printf with the format passed through Style.
+func Printf(format string, args ...any) {
+	fmt.Printf(Style(format), args...)
+}
+
+// Fprintf is Printf to w.
+func Fprintf(w io.Writer, format string, args ...any) {
+	fmt.Fprintf(w, Style(format), args...)
+}
+
+// isEmoji reports pictographs and dingbats; box drawing, arrows and
+// punctuation such as "—" are kept.
+func isEmoji(r rune) bool {
+	return r >= 0x1F000 || (r >= 0x2600 && r <= 0x27BF) || r == 0xFE0F || r == 0x200D
+}
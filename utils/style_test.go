@@ -0,0 +1,21 @@
+package utils
+
+import "testing"
+
+func TestStyle(t *testing.T) {
+	defer SetStyle(true, true)
+	in := "\033[1;35m  📊 INSIGHTS — %s\033[0m ✓ %.2f ★ ═══"
+
+	SetStyle(true, true)
+	if got := Style(in); got != in {
+		t.Errorf("styled output changed: %q", got)
+	}
+	SetStyle(false, true)
+	if got, want := Style(in), "  📊 INSIGHTS — %s ✓ %.2f ★ ═══"; got != want {
+		t.Errorf("no colour: %q, want %q", got, want)
+	}
+	SetStyle(false, false)
+	if got, want := Style(in), "  INSIGHTS — %s + %.2f * ═══"; got != want {
+		t.Errorf("plain: %q, want %q", got, want)
+	}
+}
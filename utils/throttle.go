@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// ThrottleSettings are the scrape pacing knobs an operator can change while
+// a scrape is running.
+type ThrottleSettings struct {
+	RateLimitMs    int    `json:"rate_limit_ms"`
+	MaxConcurrency int    `json:"max_concurrency"`
+	SectionFilter  string `json:"section_filter"` // case-insensitive regex; "" = all sections
+}
+
+// Throttle holds live ThrottleSettings shared by the scraper and its worker
+// pool. Readers always see the latest values; waiters are woken on change.
+// It is safe for concurrent use.
+type Throttle struct {
+	mu       sync.RWMutex
+	settings ThrottleSettings
+	filter   *regexp.Regexp
+	changed  chan struct{}
+}
+
+// NewThrottle validates the initial settings and returns a Throttle.
+func NewThrottle(s ThrottleSettings) (*Throttle, error) {
+	t := &Throttle{changed: make(chan struct{})}
+	if err := t.Update(s); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Update replaces the settings. Concurrency below 1 and negative rate
+// limits are rejected, as is an invalid section filter; on error the
+// current settings are kept.
+func (t *Throttle) Update(s ThrottleSettings) error {
+	if s.MaxConcurrency < 1 {
+		return fmt.Errorf("throttle: max concurrency must be at least 1, got %d", s.MaxConcurrency)
+	}
+	if s.RateLimitMs < 0 {
+		return fmt.Errorf("throttle: rate limit must not be negative, got %d", s.RateLimitMs)
+	}
+	var filter *regexp.Regexp
+	if s.SectionFilter != "" {
+		re, err := regexp.Compile("(?i)" + s.SectionFilter)
+		if err != nil {
+			return fmt.Errorf("throttle: section filter: %w", err)
+		}
+		filter = re
+	}
+
+	t.mu.Lock()
+	t.settings, t.filter = s, filter
+	close(t.changed)
+	t.changed = make(chan struct{})
+	t.mu.Unlock()
+	return nil
+}
+
+// Settings returns a copy of the current settings.
+func (t *Throttle) Settings() ThrottleSettings {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.settings
+}
+
+// RateLimit is the minimum delay between requests.
+func (t *Throttle) RateLimit() time.Duration {
+	return time.Duration(t.Settings().RateLimitMs) * time.Millisecond
+}
+
+// MaxConcurrency is the number of detail pages fetched at once.
+func (t *Throttle) MaxConcurrency() int {
+	return t.Settings().MaxConcurrency
+}
+
+// SectionAllowed reports whether a discovered section should be scraped.
+func (t *Throttle) SectionAllowed(name string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.filter == nil || t.filter.MatchString(name)
+}
+
+// Changed returns a channel closed at the next Update.
+func (t *Throttle) Changed() <-chan struct{} {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.changed
+}
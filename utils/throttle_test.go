@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottleUpdateValidates(t *testing.T) {
+	th, err := NewThrottle(ThrottleSettings{RateLimitMs: 100, MaxConcurrency: 2, SectionFilter: "beach"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !th.SectionAllowed("Popular BEACH homes") || th.SectionAllowed("Cabins") {
+		t.Error("section filter should be a case-insensitive match")
+	}
+
+	for _, bad := range []ThrottleSettings{
+		{RateLimitMs: 100, MaxConcurrency: 0},
+		{RateLimitMs: -1, MaxConcurrency: 1},
+		{RateLimitMs: 100, MaxConcurrency: 1, SectionFilter: "("},
+	} {
+		if err := th.Update(bad); err == nil {
+			t.Errorf("Update(%+v) should fail", bad)
+		}
+	}
+	if got := th.Settings(); got.MaxConcurrency != 2 || got.SectionFilter != "beach" {
+		t.Errorf("failed update changed settings: %+v", got)
+	}
+}
+
+func TestWorkerPoolFollowsThrottle(t *testing.T) {
+	th, _ := NewThrottle(ThrottleSettings{MaxConcurrency: 1})
+	pool := NewWorkerPool(1, 0)
+	pool.SetThrottle(th)
+
+	release := make(chan struct{})
+	started := make(chan int, 2)
+	submit := func(id int) {
+		pool.Submit(func() {
+			started <- id
+			<-release
+		})
+	}
+
+	submit(1)
+	<-started
+	go submit(2)
+
+	select {
+	case <-started:
+		t.Fatal("second job started above the concurrency limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := th.Update(ThrottleSettings{MaxConcurrency: 2}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("raising concurrency did not start the waiting job")
+	}
+	close(release)
+	pool.Wait()
+}
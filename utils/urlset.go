@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// URLSet is a thread-safe set for tracking visited URLs. A set loaded with
+// LoadURLSet survives restarts through Save, and with a TTL its entries
+// expire, so a URL counts as new again once it was last visited that long
+// ago.
+type URLSet struct {
+	mu   sync.RWMutex
+	seen map[string]time.Time // URL → when it was added
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// NewURLSet creates an empty in-memory URLSet whose entries never expire.
+func NewURLSet() *URLSet {
+	return &URLSet{seen: make(map[string]time.Time), now: time.Now}
+}
+
+// LoadURLSet reads the set persisted at path: one "<url>\t<RFC 3339 time>"
+// line per entry. A missing file yields an empty set that will be created
+// on Save; an empty path yields an in-memory set. Entries older than ttl
+// are dropped; ttl 0 keeps them forever.
+func LoadURLSet(path string, ttl time.Duration) (*URLSet, error) {
+	s := NewURLSet()
+	s.path, s.ttl = path, ttl
+	if path == "" {
+		return s, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("urlset: open %q: %w", path, err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		url, stamp, ok := strings.Cut(line, "\t")
+		at, err := time.Parse(time.RFC3339, stamp)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("urlset: %s:%d: want \"<url>\\t<time>\", got %q", path, n, line)
+		}
+		if !s.expired(at) {
+			s.seen[url] = at
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("urlset: read %q: %w", path, err)
+	}
+	return s, nil
+}
+
+// expired reports whether an entry added at t has outlived the TTL.
+func (s *URLSet) expired(t time.Time) bool {
+	return s.ttl > 0 && s.now().Sub(t) >= s.ttl
+}
+
+// Add returns true if the URL was newly added — or its entry had expired
+// and now starts over — and false if already present.
+func (s *URLSet) Add(url string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if at, exists := s.seen[url]; exists && !s.expired(at) {
+		return false
+	}
+	s.seen[url] = s.now()
+	return true
+}
+
+// Contains returns true if the URL has already been visited.
+func (s *URLSet) Contains(url string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	at, exists := s.seen[url]
+	return exists && !s.expired(at)
+}
+
+// Size returns the number of unique URLs tracked and not expired.
+func (s *URLSet) Size() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := 0
+	for _, at := range s.seen {
+		if !s.expired(at) {
+			n++
+		}
+	}
+	return n
+}
+
+// Save writes the unexpired entries back to the set's file, sorted by URL.
+// The file is written under a temporary name and renamed into place, so a
+// crash mid-save leaves the previous set intact.
+func (s *URLSet) Save() error {
+	if s.path == "" {
+		return nil
+	}
+	s.mu.RLock()
+	lines := make([]string, 0, len(s.seen))
+	for url, at := range s.seen {
+		if !s.expired(at) {
+			lines = append(lines, url+"\t"+at.UTC().Format(time.RFC3339))
+		}
+	}
+	s.mu.RUnlock()
+	sort.Strings(lines)
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("urlset: create dir: %w", err)
+	}
+	content := strings.Join(lines, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.path), ".urlset-*")
+	if err != nil {
+		return fmt.Errorf("urlset: write %q: %w", s.path, err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("urlset: write %q: %w", s.path, err)
+	}
+	if err := f.Chmod(0644); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("urlset: write %q: %w", s.path, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("urlset: write %q: %w", s.path, err)
+	}
+	if err := os.Rename(f.Name(), s.path); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("urlset: write %q: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestURLSetTTL(t *testing.T) {
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s, err := LoadURLSet("", 48*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return clock }
+
+	if !s.Add("https://example.com/1") || s.Add("https://example.com/1") {
+		t.Fatal("Add should report only the first insertion")
+	}
+	clock = clock.Add(47 * time.Hour)
+	if !s.Contains("https://example.com/1") || s.Add("https://example.com/1") {
+		t.Error("entry should still be live before the TTL")
+	}
+	clock = clock.Add(time.Hour)
+	if s.Contains("https://example.com/1") || s.Size() != 0 {
+		t.Error("entry should expire after the TTL")
+	}
+	if !s.Add("https://example.com/1") || s.Size() != 1 {
+		t.Error("expired entry should be added again")
+	}
+}
+
+func TestURLSetLoadAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "visited.tsv")
+	now := time.Now().UTC().Truncate(time.Second)
+	content := "https://example.com/fresh\t" + now.Add(-time.Hour).Format(time.RFC3339) + "\n" +
+		"\n" +
+		"https://example.com/stale\t" + now.Add(-10*24*time.Hour).Format(time.RFC3339) + "\n"
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := LoadURLSet(path, 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("LoadURLSet: %v", err)
+	}
+	if !s.Contains("https://example.com/fresh") || s.Contains("https://example.com/stale") {
+		t.Errorf("loaded entries: fresh=%v stale=%v",
+			s.Contains("https://example.com/fresh"), s.Contains("https://example.com/stale"))
+	}
+	s.Add("https://example.com/new")
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("state dir holds %d files after Save, want only the set", len(entries))
+	}
+
+	again, err := LoadURLSet(path, 0)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if again.Size() != 2 || !again.Contains("https://example.com/new") {
+		t.Errorf("reloaded %d entries, want fresh and new", again.Size())
+	}
+}
+
+func TestLoadURLSetMissingAndMalformed(t *testing.T) {
+	dir := t.TempDir()
+	s, err := LoadURLSet(filepath.Join(dir, "none.tsv"), 0)
+	if err != nil || s.Size() != 0 {
+		t.Fatalf("missing file: size %d, err %v", s.Size(), err)
+	}
+
+	bad := filepath.Join(dir, "bad.tsv")
+	if err := os.WriteFile(bad, []byte("https://example.com/1 yesterday\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadURLSet(bad, 0); err == nil || !strings.Contains(err.Error(), "bad.tsv:1") {
+		t.Errorf("malformed line: err = %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Build metadata, stamped at link time:
+//
+//	go build -ldflags "-X airbnb-scraper/utils.BuildVersion=v1.4.0 \
+//	  -X airbnb-scraper/utils.BuildCommit=$(git rev-parse --short HEAD) \
+//	  -X airbnb-scraper/utils.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Unset values are filled from the VCS stamp `go build` embeds, or from git.
+var (
+	BuildVersion string
+	BuildCommit  string
+	BuildDate    string
+)
+
+// BuildInfo identifies the code that is running.
+type BuildInfo struct {
+	Version string `json:"version"` // release tag; "dev" when not stamped
+	Commit  string `json:"commit"`  // VCS revision, "+dirty" for modified sources
+	Date    string `json:"date"`    // build (or commit) time, RFC 3339
+}
+
+// String renders the build as "v1.4.0 (abc1234, 2024-05-01T12:00:00Z)".
+func (b BuildInfo) String() string {
+	var extra []string
+	for _, s := range []string{b.Commit, b.Date} {
+		if s != "" {
+			extra = append(extra, s)
+		}
+	}
+	if len(extra) == 0 {
+		return b.Version
+	}
+	return fmt.Sprintf("%s (%s)", b.Version, strings.Join(extra, ", "))
+}
+
+var (
+	buildOnce sync.Once
+	build     BuildInfo
+)
+
+// Build returns the build metadata: the ldflags values where set, else the
+// revision and commit time `go build` stamps into the binary, else (for
+// `go run`) `git describe` in the working directory. Computed once.
+func Build() BuildInfo {
+	buildOnce.Do(func() { build = detectBuild() })
+	return build
+}
+
+// Version is Build().String(), for logs and run manifests.
+func Version() string {
+	return Build().String()
+}
+
+func detectBuild() BuildInfo {
+	b := BuildInfo{Version: BuildVersion, Commit: BuildCommit, Date: BuildDate}
+	if b.Version == "" {
+		b.Version = "dev"
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		var rev, at string
+		var dirty bool
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				rev = s.Value
+			case "vcs.time":
+				at = s.Value
+			case "vcs.modified":
+				dirty = s.Value == "true"
+			}
+		}
+		if b.Commit == "" && rev != "" {
+			if len(rev) > 12 {
+				rev = rev[:12]
+			}
+			if dirty {
+				rev += "+dirty"
+			}
+			b.Commit = rev
+		}
+		if b.Date == "" {
+			b.Date = at
+		}
+	}
+	if b.Commit == "" {
+		b.Commit = gitDescribe()
+	}
+	return b
+}
+
+// gitDescribe asks git for the working tree's revision; "" when git or the
+// repository is unavailable.
+func gitDescribe() string {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", "describe", "--always", "--dirty").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,29 @@
+package utils
+
+import "testing"
+
+func TestBuildInfoString(t *testing.T) {
+	cases := []struct {
+		in   BuildInfo
+		want string
+	}{
+		{BuildInfo{Version: "v1.4.0", Commit: "abc1234", Date: "2024-05-01T12:00:00Z"}, "v1.4.0 (abc1234, 2024-05-01T12:00:00Z)"},
+		{BuildInfo{Version: "dev", Commit: "abc1234+dirty"}, "dev (abc1234+dirty)"},
+		{BuildInfo{Version: "dev"}, "dev"},
+	}
+	for _, c := range cases {
+		if got := c.in.String(); got != c.want {
+			t.Errorf("%+v.String() = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestBuildPrefersLinkerValues(t *testing.T) {
+	defer func(v, c, d string) { BuildVersion, BuildCommit, BuildDate = v, c, d }(BuildVersion, BuildCommit, BuildDate)
+	BuildVersion, BuildCommit, BuildDate = "v2.0.0", "deadbeef", "2024-06-01T00:00:00Z"
+	got := detectBuild()
+	want := BuildInfo{Version: "v2.0.0", Commit: "deadbeef", Date: "2024-06-01T00:00:00Z"}
+	if got != want {
+		t.Errorf("detectBuild() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Backoff is the delay schedule of WaitFor: Initial after the first failed
+// check, doubling up to Max, giving up once Timeout has passed.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Timeout time.Duration
+}
+
+// Delay is the wait after the given failed attempt (1-based).
+func (b Backoff) Delay(attempt int) time.Duration {
+	d := max(b.Initial, time.Millisecond)
+	for i := 1; i < attempt && d < b.Max; i++ {
+		d *= 2
+	}
+	if b.Max > 0 {
+		d = min(d, b.Max)
+	}
+	return d
+}
+
+// Dependency is something a process needs before it can start, e.g. its
+// database. Check returns nil once it is ready.
+type Dependency struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// TCPDependency is ready once addr (host:port) accepts connections.
+func TCPDependency(addr string) Dependency {
+	return Dependency{
+		Name: addr,
+		Check: func(ctx context.Context) error {
+			var d net.Dialer
+			conn, err := d.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		},
+	}
+}
+
+// WaitFor checks each dependency in turn, retrying a failing one on b's
+// schedule until it is ready, ctx is done or b.Timeout has passed since the
+// call. The timeout spans all dependencies. A nil logger waits silently.
+func WaitFor(ctx context.Context, b Backoff, logger *Logger, deps ...Dependency) error {
+	if b.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.Timeout)
+		defer cancel()
+	}
+	for _, dep := range deps {
+		for attempt := 1; ; attempt++ {
+			err := dep.Check(ctx)
+			if err == nil {
+				if attempt > 1 && logger != nil {
+					logger.Info("[wait] %s is ready", dep.Name)
+				}
+				break
+			}
+			wait := b.Delay(attempt)
+			if logger != nil {
+				logger.Warn("[wait] %s not ready (attempt %d): %v — retrying in %v", dep.Name, attempt, err, wait)
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("%s not ready after %d attempts: %w", dep.Name, attempt, err)
+			case <-timer.C:
+			}
+		}
+	}
+	return nil
+}
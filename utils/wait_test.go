@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Initial: time.Second, Max: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := b.Delay(i + 1); got != w {
+			t.Errorf("Delay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	b := Backoff{Initial: time.Millisecond, Max: 2 * time.Millisecond, Timeout: time.Second}
+	calls := 0
+	flaky := Dependency{Name: "db", Check: func(context.Context) error {
+		if calls++; calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}}
+	if err := WaitFor(context.Background(), b, nil, flaky); err != nil {
+		t.Fatalf("WaitFor: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("checks = %d, want 3", calls)
+	}
+
+	down := errors.New("connection refused")
+	b.Timeout = 20 * time.Millisecond
+	start := time.Now()
+	err := WaitFor(context.Background(), b, nil, Dependency{Name: "db", Check: func(context.Context) error { return down }})
+	if !errors.Is(err, down) {
+		t.Errorf("never ready: err = %v, want %v", err, down)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("WaitFor waited %v past a 20ms timeout", elapsed)
+	}
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrHung is returned by RunWithin when fn outlives its ceiling.
+var ErrHung = errors.New("operation exceeded its hard ceiling")
+
+// RunWithin runs fn and returns its error, or ErrHung once ceiling passes
+// without fn returning. fn then keeps running in the background; the caller
+// is expected to tear down whatever it is blocked on, and its eventual
+// result is discarded. A ceiling of zero or less waits for fn.
+func RunWithin(ceiling time.Duration, fn func() error) error {
+	if ceiling <= 0 {
+		return fn()
+	}
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+
+	timer := time.NewTimer(ceiling)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return ErrHung
+	}
+}
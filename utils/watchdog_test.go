@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunWithin(t *testing.T) {
+	want := errors.New("boom")
+	if err := RunWithin(time.Second, func() error { return want }); err != want {
+		t.Errorf("fast fn: err = %v, want %v", err, want)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	start := time.Now()
+	err := RunWithin(20*time.Millisecond, func() error { <-release; return nil })
+	if !errors.Is(err, ErrHung) {
+		t.Errorf("stuck fn: err = %v, want ErrHung", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("RunWithin waited %v for a stuck fn", elapsed)
+	}
+
+	if err := RunWithin(0, func() error { time.Sleep(5 * time.Millisecond); return nil }); err != nil {
+		t.Errorf("no ceiling: err = %v", err)
+	}
+}
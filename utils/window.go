@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeWindow is a daily local-time window ("01:00-06:00") during which
+// scraping is allowed. Windows may wrap past midnight ("22:00-04:00").
+// A nil *TimeWindow means scraping is allowed at any time.
+type TimeWindow struct {
+	start time.Duration // offset from local midnight
+	end   time.Duration
+}
+
+// ParseTimeWindow parses "HH:MM-HH:MM". An empty string yields nil (no window).
+func ParseTimeWindow(s string) (*TimeWindow, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("time window %q: expected HH:MM-HH:MM", s)
+	}
+	start, err := parseClock(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("time window %q: %w", s, err)
+	}
+	end, err := parseClock(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("time window %q: %w", s, err)
+	}
+	if start == end {
+		return nil, fmt.Errorf("time window %q: start and end are equal", s)
+	}
+	return &TimeWindow{start: start, end: end}, nil
+}
+
+// Contains reports whether t falls inside the window.
+func (w *TimeWindow) Contains(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+	offset := sinceMidnight(t)
+	if w.start < w.end {
+		return offset >= w.start && offset < w.end
+	}
+	return offset >= w.start || offset < w.end
+}
+
+// NextOpen returns t itself when inside the window, otherwise the next time
+// the window opens.
+func (w *TimeWindow) NextOpen(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+	midnight := t.Add(-sinceMidnight(t))
+	open := midnight.Add(w.start)
+	if !open.After(t) {
+		open = open.AddDate(0, 0, 1)
+	}
+	return open
+}
+
+// Wait blocks until the window is open, logging the pause once. It returns
+// ctx's error if ctx is done first.
+func (w *TimeWindow) Wait(ctx context.Context, logger *Logger) error {
+	now := time.Now()
+	if w.Contains(now) {
+		return ctx.Err()
+	}
+	open := w.NextOpen(now)
+	logger.Warn("[window] Outside scraping window %s — pausing until %s",
+		w, open.Format("2006-01-02 15:04"))
+	timer := time.NewTimer(time.Until(open))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+	logger.Info("[window] Scraping window open — resuming")
+	return nil
+}
+
+func (w *TimeWindow) String() string {
+	if w == nil {
+		return "always"
+	}
+	return fmt.Sprintf("%s-%s", formatClock(w.start), formatClock(w.end))
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("bad clock time %q", strings.TrimSpace(s))
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	h, m, s := t.Clock()
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(s)*time.Second + time.Duration(t.Nanosecond())
+}
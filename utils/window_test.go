@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func at(hour, minute int) time.Time {
+	return time.Date(2024, 3, 10, hour, minute, 0, 0, time.Local)
+}
+
+func TestTimeWindowContains(t *testing.T) {
+	w, err := ParseTimeWindow("01:00-06:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !w.Contains(at(3, 0)) || w.Contains(at(6, 0)) || w.Contains(at(0, 59)) {
+		t.Error("01:00-06:00 boundaries not respected")
+	}
+
+	wrap, err := ParseTimeWindow("22:00-04:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !wrap.Contains(at(23, 30)) || !wrap.Contains(at(2, 0)) || wrap.Contains(at(12, 0)) {
+		t.Error("midnight-wrapping window not respected")
+	}
+
+	var none *TimeWindow
+	if !none.Contains(at(12, 0)) {
+		t.Error("nil window should always be open")
+	}
+}
+
+func TestTimeWindowNextOpen(t *testing.T) {
+	w, _ := ParseTimeWindow("01:00-06:00")
+	if got := w.NextOpen(at(12, 0)); !got.Equal(at(1, 0).AddDate(0, 0, 1)) {
+		t.Errorf("NextOpen after window: got %v", got)
+	}
+	if got := w.NextOpen(at(0, 30)); !got.Equal(at(1, 0)) {
+		t.Errorf("NextOpen before window: got %v", got)
+	}
+}
+
+func TestParseTimeWindowErrors(t *testing.T) {
+	for _, in := range []string{"01:00", "25:00-06:00", "05:00-05:00"} {
+		if _, err := ParseTimeWindow(in); err == nil {
+			t.Errorf("ParseTimeWindow(%q) should fail", in)
+		}
+	}
+	if w, err := ParseTimeWindow(""); w != nil || err != nil {
+		t.Error("empty window should parse to nil")
+	}
+}
+
+func TestTimeWindowWaitCancelled(t *testing.T) {
+	// A window opening two hours from now is closed for the whole test.
+	start := sinceMidnight(time.Now()) + 2*time.Hour
+	w := &TimeWindow{start: start % (24 * time.Hour), end: (start + time.Hour) % (24 * time.Hour)}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	began := time.Now()
+	if err := w.Wait(ctx, NewLogger()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait = %v, want the context's error", err)
+	}
+	if elapsed := time.Since(began); elapsed > time.Second {
+		t.Errorf("Wait returned %v after its context was done", elapsed)
+	}
+
+	var none *TimeWindow
+	if err := none.Wait(context.Background(), NewLogger()); err != nil {
+		t.Errorf("no window: Wait = %v", err)
+	}
+}